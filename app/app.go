@@ -58,6 +58,7 @@ import (
 	upgradekeeper "github.com/cosmos/cosmos-sdk/x/upgrade/keeper"
 	"github.com/gorilla/mux"
 	"github.com/rakyll/statik/fs"
+	"github.com/regen-network/regen-ledger/orm"
 	"github.com/regen-network/regen-ledger/types/module/server"
 	"github.com/spf13/cast"
 	abci "github.com/tendermint/tendermint/abci/types"
@@ -89,6 +90,11 @@ import (
 
 const (
 	appName = "regen"
+
+	// FlagMaxPageLimit is the app option of the maximum page limit of the queries paginated by the orm
+	// package, see orm.SetDefaultMaxLimit. It is set in the orm section of the app.toml or with the flag
+	// of the start command. The default 0 means no maximum.
+	FlagMaxPageLimit = "orm.max-page-limit"
 )
 
 var (
@@ -318,6 +324,8 @@ func NewRegenApp(logger log.Logger, db dbm.DB, traceStore io.Writer, loadLatest
 		&stakingKeeper, govRouter,
 	)
 
+	orm.SetDefaultMaxLimit(cast.ToUint64(appOpts.Get(FlagMaxPageLimit)))
+
 	// register experimental modules here
 	app.smm = setCustomModules(app, interfaceRegistry)
 
@@ -45,3 +45,17 @@ func TestGetMaccPerms(t *testing.T) {
 	dup := GetMaccPerms()
 	require.Equal(t, maccPerms, dup, "duplicated module account permissions differed from actual module account permissions")
 }
+
+func TestGenesisStateValidate(t *testing.T) {
+	encCfg := MakeEncodingConfig()
+
+	genesisState := NewDefaultGenesisState(encCfg.Marshaler)
+	require.Empty(t, genesisState.Validate(encCfg.Marshaler, encCfg.TxConfig))
+
+	delete(genesisState, "bank")
+	genesisState["auth"] = json.RawMessage(`{invalid}`)
+	errs := genesisState.Validate(encCfg.Marshaler, encCfg.TxConfig)
+	require.Len(t, errs, 2)
+	require.Contains(t, errs[0].Error(), "auth: ")
+	require.Contains(t, errs[1].Error(), "bank: ")
+}
@@ -3,7 +3,10 @@ package app
 
 import (
 	"encoding/json"
+	"fmt"
+	"sort"
 
+	"github.com/cosmos/cosmos-sdk/client"
 	"github.com/cosmos/cosmos-sdk/codec"
 )
 
@@ -20,3 +23,23 @@ type GenesisState map[string]json.RawMessage
 func NewDefaultGenesisState(cdc codec.JSONMarshaler) GenesisState {
 	return ModuleBasics.DefaultGenesis(cdc)
 }
+
+// Validate runs the genesis validation of every module against its state. Unlike
+// ModuleBasics.ValidateGenesis it does not stop at the first failure but returns the
+// errors of all modules, ordered by module name. This can be used to check generated
+// genesis states, as in the simulations, before the chain is initialized with them.
+func (gs GenesisState) Validate(cdc codec.JSONMarshaler, txConfig client.TxEncodingConfig) []error {
+	names := make([]string, 0, len(ModuleBasics))
+	for name := range ModuleBasics {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var errs []error
+	for _, name := range names {
+		if err := ModuleBasics[name].ValidateGenesis(cdc, txConfig, gs[name]); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+		}
+	}
+	return errs
+}
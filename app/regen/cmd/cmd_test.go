@@ -2,10 +2,14 @@ package regen_test
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/cosmos/cosmos-sdk/client/flags"
 	"github.com/cosmos/cosmos-sdk/x/genutil/client/cli"
 	cmd "github.com/regen-network/regen-ledger/app/regen/cmd"
 )
@@ -21,3 +25,23 @@ func TestInitCmd(t *testing.T) {
 	err := cmd.Execute(rootCmd)
 	require.NoError(t, err)
 }
+
+func TestInitCmdORMConfig(t *testing.T) {
+	home := t.TempDir()
+	for i := 0; i < 2; i++ {
+		rootCmd, _ := cmd.NewRootCmd()
+		rootCmd.SetArgs([]string{
+			"init",
+			"regenapp-test",
+			fmt.Sprintf("--%s=%s", cli.FlagOverwrite, "true"),
+			fmt.Sprintf("--%s=%s", flags.FlagHome, home),
+		})
+		require.NoError(t, cmd.Execute(rootCmd))
+	}
+
+	// the orm section is added to the app.toml once
+	bz, err := os.ReadFile(filepath.Join(home, "config", "app.toml"))
+	require.NoError(t, err)
+	require.Equal(t, 1, strings.Count(string(bz), "\n[orm]\n"))
+	require.Contains(t, string(bz), "max-page-limit = 0")
+}
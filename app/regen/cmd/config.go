@@ -0,0 +1,54 @@
+package regen
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/cosmos/cosmos-sdk/server"
+	"github.com/spf13/cobra"
+)
+
+// ormConfigTemplate is the section of the app.toml with the app options of the orm package, see
+// app.FlagMaxPageLimit.
+const ormConfigTemplate = `
+###############################################################################
+###                           ORM Configuration                             ###
+###############################################################################
+
+[orm]
+
+# max-page-limit is the maximum page limit of the queries paginated by the orm package. The limit of a
+# request above the maximum is clamped to it. The default 0 means no maximum.
+max-page-limit = 0
+`
+
+// ensureORMConfig appends the orm section to the app.toml of the node home, when it does not have it. The
+// app.toml template of the cosmos-sdk can not be extended, so that the section is missing in the app.toml
+// written by server.InterceptConfigsPreRunHandler.
+func ensureORMConfig(cmd *cobra.Command) error {
+	serverCtx := server.GetServerContextFromCmd(cmd)
+	if serverCtx.Config == nil {
+		return nil
+	}
+	appCfgFile := filepath.Join(serverCtx.Config.RootDir, "config", "app.toml")
+	bz, err := os.ReadFile(appCfgFile)
+	switch {
+	case os.IsNotExist(err):
+		return nil
+	case err != nil:
+		return err
+	case bytes.Contains(bz, []byte("\n[orm]\n")):
+		return nil
+	}
+	f, err := os.OpenFile(appCfgFile, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		return err
+	}
+	if _, err := f.WriteString(ormConfigTemplate); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write the orm config to %s: %w", appCfgFile, err)
+	}
+	return f.Close()
+}
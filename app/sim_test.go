@@ -6,6 +6,7 @@ import (
 	"math/rand"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	abci "github.com/tendermint/tendermint/abci/types"
@@ -88,7 +89,7 @@ func TestFullAppSimulation(t *testing.T) {
 func simulateFromSeed(t *testing.T, app *RegenApp, config simtypes.Config) (bool, simulation.Params, error) {
 	return simulation.SimulateFromSeed(
 		t, os.Stdout, app.BaseApp,
-		simapp.AppStateFn(app.AppCodec(), app.SimulationManager()),
+		validatedAppStateFn(t, app),
 		simtypes.RandomAccounts, // Replace with own random account function if using keys other than secp256k1
 		simapp.SimulationOperations(app, app.AppCodec(), config),
 		app.ModuleAccountAddrs(),
@@ -97,6 +98,21 @@ func simulateFromSeed(t *testing.T, app *RegenApp, config simtypes.Config) (bool
 	)
 }
 
+// validatedAppStateFn returns the simulation genesis state generator that fails the test
+// when the generated genesis state is invalid, before the chain is initialized with it.
+func validatedAppStateFn(t *testing.T, app *RegenApp) simtypes.AppStateFn {
+	appStateFn := simapp.AppStateFn(app.AppCodec(), app.SimulationManager())
+	return func(r *rand.Rand, accs []simtypes.Account, config simtypes.Config) (json.RawMessage, []simtypes.Account, string, time.Time) {
+		appState, simAccs, chainID, genesisTimestamp := appStateFn(r, accs, config)
+
+		var genesisState GenesisState
+		require.NoError(t, json.Unmarshal(appState, &genesisState))
+		require.Empty(t, genesisState.Validate(app.AppCodec(), MakeEncodingConfig().TxConfig))
+
+		return appState, simAccs, chainID, genesisTimestamp
+	}
+}
+
 func TestAppImportExport(t *testing.T) {
 	config, db, dir, logger, skip, err := simapp.SetupSimulation("leveldb-app-sim", "Simulation")
 	if skip {
@@ -242,7 +258,7 @@ func TestAppSimulationAfterImport(t *testing.T) {
 		t,
 		os.Stdout,
 		newApp.BaseApp,
-		simapp.AppStateFn(app.AppCodec(), app.SimulationManager()),
+		validatedAppStateFn(t, app),
 		simtypes.RandomAccounts, // Replace with own random account function if using keys other than secp256k1
 		simapp.SimulationOperations(newApp, newApp.AppCodec(), config),
 		app.ModuleAccountAddrs(),
@@ -0,0 +1,149 @@
+package orm
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/cosmos/cosmos-sdk/types/query"
+)
+
+// LengthPrefixAddress returns the address prefixed with its length in one byte, so that an address in an
+// index key is never ambiguous, even when it is followed by other fields or when one address is a byte
+// prefix of another. This mirrors `address.MustLengthPrefix` of newer SDK versions.
+// An `ErrArgument` error is returned for an empty address and an `ErrIndexKeyMaxLength` error for addresses
+// that are longer than 255 bytes.
+func LengthPrefixAddress(addr sdk.AccAddress) ([]byte, error) {
+	switch n := len(addr); {
+	case n == 0:
+		return nil, errors.Wrap(ErrArgument, "empty address")
+	case n > 255:
+		return nil, errors.Wrap(ErrIndexKeyMaxLength, "address exceeds 255 bytes")
+	}
+	return append([]byte{byte(len(addr))}, addr...), nil
+}
+
+// MustLengthPrefixAddress returns the address prefixed with its length like LengthPrefixAddress and panics
+// on an invalid address.
+func MustLengthPrefixAddress(addr sdk.AccAddress) []byte {
+	res, err := LengthPrefixAddress(addr)
+	if err != nil {
+		panic(err)
+	}
+	return res
+}
+
+// AddressKey returns the index key of an address followed by other fields, like the key of the balances of
+// an account by denom. The address is prefixed with its length, see `LengthPrefixAddress`, so that the
+// objects of an address can be scanned with the prefix of `LengthPrefixAddress` only.
+func AddressKey(addr sdk.AccAddress, fields ...[]byte) ([]byte, error) {
+	key, err := LengthPrefixAddress(addr)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range fields {
+		key = append(key, f...)
+	}
+	return key, nil
+}
+
+// DecodeAddressKey returns the address of an index key created with `AddressKey` and the fields that follow it.
+// An `ErrArgument` error is returned for keys without a complete address.
+func DecodeAddressKey(key []byte) (sdk.AccAddress, []byte, error) {
+	if len(key) == 0 || key[0] == 0 || len(key) < 1+int(key[0]) {
+		return nil, nil, errors.Wrap(ErrArgument, "invalid length prefixed address")
+	}
+	n := 1 + int(key[0])
+	return sdk.AccAddress(key[1:n]), key[n:], nil
+}
+
+// DecodeAddressCursor returns the address, the fields that follow it and the RowID of a page key that
+// was returned as NextKey for a paginated query of an index with `AddressKey` keys, like an `AddressIndex`.
+func DecodeAddressCursor(cursor []byte) (sdk.AccAddress, []byte, RowID, error) {
+	indexKey, rowID, err := DecodeCursor(cursor)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	addr, fields, err := DecodeAddressKey(indexKey)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return addr, fields, rowID, nil
+}
+
+// AddressIndexerFunc creates one or multiple multiKeyIndex keys of type sdk.AccAddress for the source object.
+type AddressIndexerFunc func(value interface{}) ([]sdk.AccAddress, error)
+
+// AddressMultiKeyAdapter converts AddressIndexerFunc to IndexerFunc
+func AddressMultiKeyAdapter(indexer AddressIndexerFunc) IndexerFunc {
+	return func(value interface{}) ([]RowID, error) {
+		d, err := indexer(value)
+		if err != nil {
+			return nil, err
+		}
+		r := make([]RowID, len(d))
+		for i, v := range d {
+			if r[i], err = LengthPrefixAddress(v); err != nil {
+				return nil, err
+			}
+		}
+		return r, nil
+	}
+}
+
+// AddressIndex is a typed index with address keys. The keys are prefixed with their length, see
+// `LengthPrefixAddress`, so that a Get for an address never returns the objects of a longer address that
+// starts with the same bytes. An index with unprefixed address keys can be migrated to an AddressIndex
+// on the same prefix with `Rebuild`.
+type AddressIndex struct {
+	multiKeyIndex MultiKeyIndex
+}
+
+// NewAddressIndex creates a typed secondary index
+func NewAddressIndex(builder Indexable, prefix byte, indexer AddressIndexerFunc) AddressIndex {
+	return AddressIndex{
+		multiKeyIndex: NewIndex(builder, prefix, AddressMultiKeyAdapter(indexer)),
+	}
+}
+
+// Has checks if a key exists.
+func (i AddressIndex) Has(ctx HasKVStore, key sdk.AccAddress) (bool, error) {
+	bz, err := LengthPrefixAddress(key)
+	if err != nil {
+		return false, err
+	}
+	return i.multiKeyIndex.Has(ctx, bz), nil
+}
+
+// Get returns a result iterator for the searchKey.
+func (i AddressIndex) Get(ctx HasKVStore, searchKey sdk.AccAddress) (Iterator, error) {
+	bz, err := LengthPrefixAddress(searchKey)
+	if err != nil {
+		return NewInvalidIterator(), err
+	}
+	return i.multiKeyIndex.Get(ctx, bz)
+}
+
+// ReverseGet returns a result iterator for the searchKey in descending order.
+func (i AddressIndex) ReverseGet(ctx HasKVStore, searchKey sdk.AccAddress) (Iterator, error) {
+	bz, err := LengthPrefixAddress(searchKey)
+	if err != nil {
+		return NewInvalidIterator(), err
+	}
+	return i.multiKeyIndex.ReverseGet(ctx, bz)
+}
+
+// GetPaginated creates an iterator for the searchKey
+// starting from pageRequest.Key if provided.
+// The pageRequest.Key is a cursor, see `MultiKeyIndex.GetPaginated`, that `DecodeAddressCursor` decodes.
+func (i AddressIndex) GetPaginated(ctx HasKVStore, searchKey sdk.AccAddress, pageRequest *query.PageRequest) (Iterator, error) {
+	bz, err := LengthPrefixAddress(searchKey)
+	if err != nil {
+		return NewInvalidIterator(), err
+	}
+	return i.multiKeyIndex.GetPaginated(ctx, bz, pageRequest)
+}
+
+// Rebuild removes all entries of the index and creates them again for all rows of the table, see
+// `MultiKeyIndex.Rebuild`.
+func (i AddressIndex) Rebuild(ctx HasKVStore, t TableExportable) error {
+	return i.multiKeyIndex.Rebuild(ctx, t)
+}
@@ -0,0 +1,206 @@
+package orm_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	"github.com/cosmos/cosmos-sdk/codec/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/cosmos/cosmos-sdk/types/query"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/regen-network/regen-ledger/orm"
+	"github.com/regen-network/regen-ledger/testutil/testdata"
+)
+
+func TestAddressKey(t *testing.T) {
+	specs := map[string]struct {
+		addr      sdk.AccAddress
+		fields    [][]byte
+		expKey    []byte
+		expErr    *errors.Error
+		expFields []byte
+	}{
+		"address only": {
+			addr:   sdk.AccAddress("ab"),
+			expKey: []byte{2, 'a', 'b'},
+		},
+		"with fields": {
+			addr:      sdk.AccAddress("ab"),
+			fields:    [][]byte{[]byte("c"), []byte("de")},
+			expKey:    []byte{2, 'a', 'b', 'c', 'd', 'e'},
+			expFields: []byte("cde"),
+		},
+		"max length": {
+			addr:   sdk.AccAddress(bytes.Repeat([]byte{1}, 255)),
+			expKey: append([]byte{255}, bytes.Repeat([]byte{1}, 255)...),
+		},
+		"too long": {
+			addr:   sdk.AccAddress(bytes.Repeat([]byte{1}, 256)),
+			expErr: orm.ErrIndexKeyMaxLength,
+		},
+		"empty": {
+			addr:   sdk.AccAddress{},
+			expErr: orm.ErrArgument,
+		},
+	}
+	for msg, spec := range specs {
+		t.Run(msg, func(t *testing.T) {
+			key, err := orm.AddressKey(spec.addr, spec.fields...)
+			if spec.expErr != nil {
+				assert.True(t, spec.expErr.Is(err), err)
+				assert.Panics(t, func() { orm.MustLengthPrefixAddress(spec.addr) })
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, spec.expKey, key)
+
+			addr, fields, err := orm.DecodeAddressKey(key)
+			require.NoError(t, err)
+			assert.Equal(t, spec.addr, addr)
+			assert.Equal(t, string(spec.expFields), string(fields))
+		})
+	}
+
+	t.Run("a byte prefix of another address", func(t *testing.T) {
+		short := orm.MustLengthPrefixAddress(sdk.AccAddress("ab"))
+		long := orm.MustLengthPrefixAddress(sdk.AccAddress("abc"))
+		assert.False(t, bytes.HasPrefix(long, short))
+		// the short address followed by a field is not the long address followed by another one
+		k1, err := orm.AddressKey(sdk.AccAddress("ab"), []byte("cd"))
+		require.NoError(t, err)
+		k2, err := orm.AddressKey(sdk.AccAddress("abc"), []byte("d"))
+		require.NoError(t, err)
+		assert.NotEqual(t, k1, k2)
+	})
+
+	for msg, key := range map[string][]byte{
+		"empty":          {},
+		"zero length":    {0},
+		"length exceeds": {3, 'a', 'b'},
+	} {
+		t.Run("decode "+msg, func(t *testing.T) {
+			_, _, err := orm.DecodeAddressKey(key)
+			assert.True(t, orm.ErrArgument.Is(err), err)
+		})
+	}
+}
+
+func TestAddressIndex(t *testing.T) {
+	interfaceRegistry := types.NewInterfaceRegistry()
+	cdc := codec.NewProtoCodec(interfaceRegistry)
+
+	storeKey := sdk.NewKVStoreKey("test")
+	const (
+		testTablePrefix = iota
+		testTableSeqPrefix
+	)
+	adminIndexer := func(val interface{}) ([]sdk.AccAddress, error) {
+		return []sdk.AccAddress{val.(*testdata.GroupInfo).Admin}, nil
+	}
+
+	// the previous version of the table with unprefixed keys
+	oldBuilder := orm.NewAutoUInt64TableBuilder(testTablePrefix, testTableSeqPrefix, storeKey, &testdata.GroupInfo{}, cdc)
+	oldIdx := orm.NewIndex(oldBuilder, GroupByAdminIndexPrefix, func(val interface{}) ([]orm.RowID, error) {
+		return []orm.RowID{orm.RowID(val.(*testdata.GroupInfo).Admin)}, nil
+	})
+	oldTable := oldBuilder.Build()
+
+	ctx := orm.NewMockContext()
+	// the short address is a byte prefix of the long one
+	short, long := sdk.AccAddress("admin"), sdk.AccAddress("admin-long")
+	g1 := testdata.GroupInfo{GroupId: 1, Description: "my test 1", Admin: short}
+	g2 := testdata.GroupInfo{GroupId: 2, Description: "my test 2", Admin: long}
+	g3 := testdata.GroupInfo{GroupId: 3, Description: "my test 3", Admin: short}
+	for _, g := range []testdata.GroupInfo{g1, g2, g3} {
+		_, err := oldTable.Create(ctx, &g)
+		require.NoError(t, err)
+	}
+	// the unprefixed keys are ambiguous
+	it, err := oldIdx.Get(ctx, short)
+	require.NoError(t, err)
+	var loaded []testdata.GroupInfo
+	_, err = orm.ReadAll(it, &loaded)
+	require.NoError(t, err)
+	assert.Equal(t, []testdata.GroupInfo{g1, g3, g2}, loaded)
+
+	// the new version with an address index on the same prefix
+	builder := orm.NewAutoUInt64TableBuilder(testTablePrefix, testTableSeqPrefix, storeKey, &testdata.GroupInfo{}, cdc)
+	idx := orm.NewAddressIndex(builder, GroupByAdminIndexPrefix, adminIndexer)
+	table := builder.Build()
+	require.NoError(t, idx.Rebuild(ctx, table))
+
+	// the old entries are removed
+	assert.False(t, oldIdx.Has(ctx, short))
+	it, err = oldIdx.PrefixScan(ctx, nil, nil)
+	require.NoError(t, err)
+	n, err := orm.Count(it)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), n, "one entry per row")
+
+	specs := map[string]struct {
+		addr sdk.AccAddress
+		exp  []testdata.GroupInfo
+	}{
+		"short":   {addr: short, exp: []testdata.GroupInfo{g1, g3}},
+		"long":    {addr: long, exp: []testdata.GroupInfo{g2}},
+		"unknown": {addr: sdk.AccAddress("adm"), exp: []testdata.GroupInfo{}},
+	}
+	for msg, spec := range specs {
+		t.Run(msg, func(t *testing.T) {
+			exists, err := idx.Has(ctx, spec.addr)
+			require.NoError(t, err)
+			assert.Equal(t, len(spec.exp) != 0, exists)
+
+			it, err := idx.Get(ctx, spec.addr)
+			require.NoError(t, err)
+			var loaded []testdata.GroupInfo
+			_, err = orm.ReadAll(it, &loaded)
+			require.NoError(t, err)
+			assert.Equal(t, spec.exp, loaded)
+
+			it, err = idx.ReverseGet(ctx, spec.addr)
+			require.NoError(t, err)
+			loaded = nil
+			_, err = orm.ReadAll(it, &loaded)
+			require.NoError(t, err)
+			assert.Len(t, loaded, len(spec.exp))
+		})
+	}
+
+	// new rows are indexed with prefixed keys
+	g4 := testdata.GroupInfo{GroupId: 4, Description: "my test 4", Admin: long}
+	_, err = table.Create(ctx, &g4)
+	require.NoError(t, err)
+	it, err = idx.Get(ctx, long)
+	require.NoError(t, err)
+	loaded = nil
+	_, err = orm.ReadAll(it, &loaded)
+	require.NoError(t, err)
+	assert.Equal(t, []testdata.GroupInfo{g2, g4}, loaded)
+
+	// the NextKey of a page decodes to the address
+	pageReq := &query.PageRequest{Limit: 1}
+	it, err = idx.GetPaginated(ctx, long, pageReq)
+	require.NoError(t, err)
+	loaded = nil
+	res, err := orm.Paginate(it, pageReq, &loaded)
+	require.NoError(t, err)
+	assert.Equal(t, []testdata.GroupInfo{g2}, loaded)
+	addr, fields, rowID, err := orm.DecodeAddressCursor(res.NextKey)
+	require.NoError(t, err)
+	assert.Equal(t, long, addr)
+	assert.Empty(t, fields)
+	assert.Equal(t, orm.RowID(orm.EncodeSequence(4)), rowID)
+
+	_, err = idx.Get(ctx, nil)
+	assert.True(t, orm.ErrArgument.Is(err), err)
+
+	// the table must be in the store of the index
+	otherBuilder := orm.NewAutoUInt64TableBuilder(testTablePrefix, testTableSeqPrefix, sdk.NewKVStoreKey("other"), &testdata.GroupInfo{}, cdc)
+	err = idx.Rebuild(ctx, otherBuilder.Build())
+	assert.True(t, orm.ErrArgument.Is(err), err)
+}
@@ -1,8 +1,11 @@
 package orm
 
 import (
+	"bytes"
+
 	"github.com/cosmos/cosmos-sdk/codec"
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/errors"
 )
 
 var _ Indexable = &AutoUInt64TableBuilder{}
@@ -22,14 +25,45 @@ func NewAutoUInt64TableBuilder(prefixData byte, prefixSeq byte, storeKey sdk.Sto
 
 type AutoUInt64TableBuilder struct {
 	*TableBuilder
-	seq Sequence
+	seq        Sequence
+	seqKeyFunc SequenceKeyFunc
+}
+
+// SequenceKeyFunc returns the key of the sequence that generates the ID of a new row of an `AutoUInt64Table`
+// from the model, like the ID of the project of a credit batch.
+type SequenceKeyFunc func(model codec.ProtoMarshaler) []byte
+
+// WithRowCounter enables a persistent counter of the table rows. See `TableBuilder.WithRowCounter`.
+func (a *AutoUInt64TableBuilder) WithRowCounter(prefixCounter byte) {
+	if prefixCounter == a.seq.prefix {
+		panic("prefixSeq and prefixCounter must be unique")
+	}
+	a.TableBuilder.WithRowCounter(prefixCounter)
+}
+
+// WithSequenceKeyFunc makes the table generate the IDs of new rows from a sequence per key, that the given
+// function returns for the model, instead of the table sequence. The IDs start at 1 for every key and the
+// RowID of a row is the length of the key, the key and the encoded ID, so that the RowIDs of a key never
+// share a prefix with the ones of a longer key. The rows of a key are addressed via `AutoUInt64Table.Scope`,
+// the methods that take a uint64 key fail with an `ErrArgument` on a table that is not scoped.
+// It must be called before any index is added, as the indexes must support RowIDs of dynamic length.
+func (a *AutoUInt64TableBuilder) WithSequenceKeyFunc(fn SequenceKeyFunc) {
+	if fn == nil {
+		panic("SequenceKeyFunc must not be nil")
+	}
+	if len(a.indexPrefixes) != 0 {
+		panic("SequenceKeyFunc must be set before any index is added")
+	}
+	a.indexKeyCodec = Max255DynamicLengthIndexKeyCodec{}
+	a.seqKeyFunc = fn
 }
 
 // Build create the AutoUInt64Table object.
 func (a AutoUInt64TableBuilder) Build() AutoUInt64Table {
 	return AutoUInt64Table{
-		table: a.TableBuilder.Build(),
-		seq:   a.seq,
+		table:      a.TableBuilder.Build(),
+		seq:        a.seq,
+		seqKeyFunc: a.seqKeyFunc,
 	}
 }
 
@@ -38,28 +72,167 @@ var _ TableExportable = &AutoUInt64Table{}
 
 // AutoUInt64Table is the table type which an auto incrementing ID.
 type AutoUInt64Table struct {
-	table Table
-	seq   Sequence
+	table      Table
+	seq        Sequence
+	seqKeyFunc SequenceKeyFunc
+	scope      []byte
 }
 
 // Create a new persistent object with an auto generated uint64 primary key. They key is returned.
 // Create iterates though the registered callbacks and may add secondary index keys by them.
+//
+// With a `SequenceKeyFunc` the key is generated by the sequence of the key of the object and is only
+// unique within the rows of that key. On a table scoped to a key, the key of the object must match it.
 func (a AutoUInt64Table) Create(ctx HasKVStore, obj codec.ProtoMarshaler) (uint64, error) {
-	autoIncID := a.seq.NextVal(ctx)
-	err := a.table.Create(ctx, EncodeSequence(autoIncID), obj)
+	if a.seqKeyFunc == nil {
+		autoIncID := a.seq.NextVal(ctx)
+		err := a.table.Create(ctx, EncodeSequence(autoIncID), obj)
+		if err != nil {
+			return 0, err
+		}
+		return autoIncID, nil
+	}
+	seqKey, err := a.sequenceKey(obj)
+	if err != nil {
+		return 0, err
+	}
+	autoIncID := a.seq.nextScopedVal(ctx, seqKey)
+	err = a.table.Create(ctx, scopedRowID(seqKey, autoIncID), obj)
 	if err != nil {
 		return 0, err
 	}
 	return autoIncID, nil
 }
 
+// BulkCreate persists the given objects with auto generated uint64 primary keys like Create and returns
+// their RowIDs, for example for a genesis import of many rows. The keys are reserved from the sequence at
+// once. See `Table.BulkCreate`.
+func (a AutoUInt64Table) BulkCreate(ctx HasKVStore, objs []codec.ProtoMarshaler) ([]RowID, error) {
+	rowIDs := make([]RowID, len(objs))
+	if a.seqKeyFunc == nil {
+		first := a.seq.nextVals(ctx, uint64(len(objs)))
+		for i := range objs {
+			rowIDs[i] = EncodeSequence(first + uint64(i))
+		}
+	} else {
+		for i, obj := range objs {
+			seqKey, err := a.sequenceKey(obj)
+			if err != nil {
+				return nil, errors.Wrapf(err, "object %d", i)
+			}
+			rowIDs[i] = scopedRowID(seqKey, a.seq.nextScopedVal(ctx, seqKey))
+		}
+	}
+	if err := a.table.BulkCreate(ctx, rowIDs, objs); err != nil {
+		return nil, err
+	}
+	return rowIDs, nil
+}
+
+// Scope returns a copy of the table with all methods that take a uint64 key restricted to the rows of the
+// given sequence key, for tables with a `SequenceKeyFunc`. The keys are the ones returned by Create for
+// objects of the sequence key.
+func (a AutoUInt64Table) Scope(seqKey []byte) AutoUInt64Table {
+	if a.seqKeyFunc == nil {
+		panic("table has no SequenceKeyFunc")
+	}
+	a.scope = seqKey
+	return a
+}
+
+// sequenceKey returns the sequence key of the object, that must match the scope when one is set.
+func (a AutoUInt64Table) sequenceKey(obj codec.ProtoMarshaler) ([]byte, error) {
+	seqKey := a.seqKeyFunc(obj)
+	switch {
+	case len(seqKey) == 0:
+		return nil, errors.Wrap(ErrArgument, "sequence key must not be empty")
+	case len(seqKey) > MaxScopedSequenceKeyLength:
+		return nil, errors.Wrapf(ErrArgument, "sequence key exceeds max length %d", MaxScopedSequenceKeyLength)
+	case a.scope != nil && !bytes.Equal(seqKey, a.scope):
+		return nil, errors.Wrap(ErrArgument, "sequence key does not match the scope")
+	}
+	return seqKey, nil
+}
+
+// rowID returns the RowID of the uint64 key within the scope. Tables with a `SequenceKeyFunc` must be scoped,
+// as their RowIDs contain the sequence key.
+func (a AutoUInt64Table) rowID(id uint64) (RowID, error) {
+	if a.seqKeyFunc == nil {
+		return EncodeSequence(id), nil
+	}
+	switch {
+	case a.scope == nil:
+		return nil, errors.Wrap(ErrArgument, "table with a SequenceKeyFunc must be scoped")
+	case len(a.scope) > MaxScopedSequenceKeyLength:
+		return nil, errors.Wrapf(ErrArgument, "sequence key exceeds max length %d", MaxScopedSequenceKeyLength)
+	}
+	return scopedRowID(a.scope, id), nil
+}
+
+// rowIDRange returns the RowIDs of the start and end keys within the scope.
+func (a AutoUInt64Table) rowIDRange(start, end uint64) (RowID, RowID, error) {
+	startRowID, err := a.rowID(start)
+	if err != nil {
+		return nil, nil, err
+	}
+	endRowID, err := a.rowID(end)
+	if err != nil {
+		return nil, nil, err
+	}
+	return startRowID, endRowID, nil
+}
+
+// resetScopedSequences deletes the sequences per key before an import.
+func (a AutoUInt64Table) resetScopedSequences(ctx HasKVStore) {
+	if a.seqKeyFunc != nil {
+		a.seq.clearScopedVals(ctx)
+	}
+}
+
+// importScopedRowID raises the sequence of the key of an imported row to its ID, so that new rows of
+// the key get higher IDs.
+func (a AutoUInt64Table) importScopedRowID(ctx HasKVStore, rowID RowID) error {
+	if a.seqKeyFunc == nil {
+		return nil
+	}
+	if len(rowID) == 0 || rowID[0] == 0 || len(rowID) != 1+int(rowID[0])+EncodedSeqLength {
+		return errors.Wrapf(ErrArgument, "invalid RowID %x for a sequence key", rowID)
+	}
+	n := 1 + int(rowID[0])
+	a.seq.raiseScopedVal(ctx, rowID[1:n], DecodeSequence(rowID[n:]))
+	return nil
+}
+
+// MaxScopedSequenceKeyLength is the maximum length of the keys returned by a `SequenceKeyFunc`, so that the
+// RowIDs with the length prefix can be indexed with the `Max255DynamicLengthIndexKeyCodec`.
+const MaxScopedSequenceKeyLength = 255 - 1 - EncodedSeqLength
+
+// scopedRowID returns the RowID of the uint64 key of a row of the sequence key: the length of the sequence
+// key, the sequence key and the encoded ID.
+func scopedRowID(seqKey []byte, id uint64) RowID {
+	rowID := make([]byte, 0, 1+len(seqKey)+EncodedSeqLength)
+	rowID = append(rowID, byte(len(seqKey)))
+	rowID = append(rowID, seqKey...)
+	return append(rowID, EncodeSequence(id)...)
+}
+
 // Save updates the given object under the rowID key. It expects the key to exists already
 // and fails with an `ErrNotFound` otherwise. Any caller must therefore make sure that this contract
 // is fulfilled. Parameters must not be nil.
 //
 // Save iterates though the registered callbacks and may add or remove secondary index keys by them.
 func (a AutoUInt64Table) Save(ctx HasKVStore, rowID uint64, newValue codec.ProtoMarshaler) error {
-	return a.table.Save(ctx, EncodeSequence(rowID), newValue)
+	if a.seqKeyFunc != nil {
+		// the sequence key of a row must not change
+		if _, err := a.sequenceKey(newValue); err != nil {
+			return err
+		}
+	}
+	rawRowID, err := a.rowID(rowID)
+	if err != nil {
+		return err
+	}
+	return a.table.Save(ctx, rawRowID, newValue)
 }
 
 // Delete removes the object under the rowID key. It expects the key to exists already
@@ -68,24 +241,72 @@ func (a AutoUInt64Table) Save(ctx HasKVStore, rowID uint64, newValue codec.Proto
 //
 // Delete iterates though the registered callbacks and removes secondary index keys by them.
 func (a AutoUInt64Table) Delete(ctx HasKVStore, rowID uint64) error {
-	return a.table.Delete(ctx, EncodeSequence(rowID))
+	rawRowID, err := a.rowID(rowID)
+	if err != nil {
+		return err
+	}
+	return a.table.Delete(ctx, rawRowID)
 }
 
-// Has checks if a rowID exists.
+// Has checks if a rowID exists. It is always false for a table with a `SequenceKeyFunc` that is not scoped.
 func (a AutoUInt64Table) Has(ctx HasKVStore, rowID uint64) bool {
-	return a.table.Has(ctx, EncodeSequence(rowID))
+	rawRowID, err := a.rowID(rowID)
+	if err != nil {
+		return false
+	}
+	return a.table.Has(ctx, rawRowID)
+}
+
+// Count returns the number of rows in the table. See `Table.Count`.
+func (a AutoUInt64Table) Count(ctx HasKVStore) (uint64, error) {
+	return a.table.Count(ctx)
+}
+
+// Stats returns the stats of the table. See `Table.Stats`.
+func (a AutoUInt64Table) Stats(ctx HasKVStore) (TableStats, error) {
+	return a.table.Stats(ctx)
+}
+
+// StatsWithOpts returns the stats of the table with the given options. See `Table.StatsWithOpts`.
+func (a AutoUInt64Table) StatsWithOpts(ctx HasKVStore, opts StatsOpts) (TableStats, error) {
+	return a.table.StatsWithOpts(ctx, opts)
 }
 
 // GetOne load the object persisted for the given RowID into the dest parameter.
 // If none exists `ErrNotFound` is returned instead. Parameters must not be nil.
 func (a AutoUInt64Table) GetOne(ctx HasKVStore, rowID uint64, dest codec.ProtoMarshaler) (RowID, error) {
-	rawRowID := EncodeSequence(rowID)
+	rawRowID, err := a.rowID(rowID)
+	if err != nil {
+		return nil, err
+	}
 	if err := a.table.GetOne(ctx, rawRowID, dest); err != nil {
 		return nil, err
 	}
 	return rawRowID, nil
 }
 
+// MultiRead loads the objects persisted for the given RowIDs into a new slice at the passed ModelSlicePtr.
+// RowIDs without an object are skipped and returned instead. See `Table.MultiRead`.
+func (a AutoUInt64Table) MultiRead(ctx HasKVStore, rowIDs []uint64, dest ModelSlicePtr) ([]uint64, error) {
+	rawRowIDs := make([]RowID, len(rowIDs))
+	for i, id := range rowIDs {
+		rawRowID, err := a.rowID(id)
+		if err != nil {
+			return nil, err
+		}
+		rawRowIDs[i] = rawRowID
+	}
+	rawNotFound, err := a.table.MultiRead(ctx, rawRowIDs, dest)
+	if err != nil {
+		return nil, err
+	}
+	var notFound []uint64
+	for _, id := range rawNotFound {
+		notFound = append(notFound, DecodeSequence(id[len(id)-EncodedSeqLength:]))
+	}
+	return notFound, nil
+}
+
 // PrefixScan returns an Iterator over a domain of keys in ascending order. End is exclusive.
 // Start is an MultiKeyIndex key or prefix. It must be less than end, or the Iterator is invalid and error is returned.
 // Iterator must be closed by caller.
@@ -103,7 +324,20 @@ func (a AutoUInt64Table) GetOne(ctx HasKVStore, rowID uint64, dest codec.ProtoMa
 //
 // CONTRACT: No writes may happen within a domain while an iterator exists over it.
 func (a AutoUInt64Table) PrefixScan(ctx HasKVStore, start, end uint64) (Iterator, error) {
-	return a.table.PrefixScan(ctx, EncodeSequence(start), EncodeSequence(end))
+	startRowID, endRowID, err := a.rowIDRange(start, end)
+	if err != nil {
+		return NewInvalidIterator(), err
+	}
+	return a.table.PrefixScan(ctx, startRowID, endRowID)
+}
+
+// PrefixScanWithOpts returns an Iterator like PrefixScan with the given options. See `Table.PrefixScanWithOpts`.
+func (a AutoUInt64Table) PrefixScanWithOpts(ctx HasKVStore, start, end uint64, opts ScanOpts) (Iterator, error) {
+	startRowID, endRowID, err := a.rowIDRange(start, end)
+	if err != nil {
+		return NewInvalidIterator(), err
+	}
+	return a.table.PrefixScanWithOpts(ctx, startRowID, endRowID, opts)
 }
 
 // ReversePrefixScan returns an Iterator over a domain of keys in descending order. End is exclusive.
@@ -116,7 +350,21 @@ func (a AutoUInt64Table) PrefixScan(ctx HasKVStore, start, end uint64) (Iterator
 //
 // CONTRACT: No writes may happen within a domain while an iterator exists over it.
 func (a AutoUInt64Table) ReversePrefixScan(ctx HasKVStore, start uint64, end uint64) (Iterator, error) {
-	return a.table.ReversePrefixScan(ctx, EncodeSequence(start), EncodeSequence(end))
+	startRowID, endRowID, err := a.rowIDRange(start, end)
+	if err != nil {
+		return NewInvalidIterator(), err
+	}
+	return a.table.ReversePrefixScan(ctx, startRowID, endRowID)
+}
+
+// ReversePrefixScanWithOpts returns an Iterator like ReversePrefixScan with the given options.
+// See `Table.PrefixScanWithOpts`.
+func (a AutoUInt64Table) ReversePrefixScanWithOpts(ctx HasKVStore, start, end uint64, opts ScanOpts) (Iterator, error) {
+	startRowID, endRowID, err := a.rowIDRange(start, end)
+	if err != nil {
+		return NewInvalidIterator(), err
+	}
+	return a.table.ReversePrefixScanWithOpts(ctx, startRowID, endRowID, opts)
 }
 
 // Sequence returns the sequence used by this table
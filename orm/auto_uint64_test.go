@@ -1,13 +1,16 @@
 package orm_test
 
 import (
+	"fmt"
 	"math"
+	"strings"
 	"testing"
 
 	"github.com/cosmos/cosmos-sdk/codec"
 	"github.com/cosmos/cosmos-sdk/codec/types"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/cosmos/cosmos-sdk/types/query"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -133,6 +136,12 @@ func TestAutoUInt64PrefixScan(t *testing.T) {
 			expResult: []testdata.GroupInfo{g3, g2},
 			expRowIDs: []orm.RowID{orm.EncodeSequence(3), orm.EncodeSequence(2)},
 		},
+		"reverse empty range": {
+			start:     10,
+			end:       20,
+			method:    tb.ReversePrefixScan,
+			expResult: []testdata.GroupInfo{},
+		},
 		"reverse start before end should fail": {
 			start:    2,
 			end:      1,
@@ -161,3 +170,344 @@ func TestAutoUInt64PrefixScan(t *testing.T) {
 		})
 	}
 }
+
+func TestAutoUInt64ReversePrefixScanComposition(t *testing.T) {
+	interfaceRegistry := types.NewInterfaceRegistry()
+	cdc := codec.NewProtoCodec(interfaceRegistry)
+
+	storeKey := sdk.NewKVStoreKey("test")
+	const (
+		testTablePrefix = iota
+		testTableSeqPrefix
+	)
+	tb := orm.NewAutoUInt64TableBuilder(testTablePrefix, testTableSeqPrefix, storeKey, &testdata.GroupInfo{}, cdc).Build()
+	ctx := orm.NewMockContext()
+
+	g1 := testdata.GroupInfo{Description: "my test 1"}
+	g2 := testdata.GroupInfo{Description: "my test 2"}
+	g3 := testdata.GroupInfo{Description: "my test 3"}
+	for _, g := range []testdata.GroupInfo{g1, g2, g3} {
+		_, err := tb.Create(ctx, &g)
+		require.NoError(t, err)
+	}
+
+	t.Run("with limit iterator", func(t *testing.T) {
+		it, err := tb.ReversePrefixScan(ctx, 1, math.MaxUint64)
+		require.NoError(t, err)
+		var loaded []testdata.GroupInfo
+		rowIDs, err := orm.ReadAll(orm.LimitIterator(it, 2), &loaded)
+		require.NoError(t, err)
+		assert.Equal(t, []testdata.GroupInfo{g3, g2}, loaded)
+		assert.Equal(t, []orm.RowID{orm.EncodeSequence(3), orm.EncodeSequence(2)}, rowIDs)
+	})
+	t.Run("with paginate", func(t *testing.T) {
+		it, err := tb.ReversePrefixScan(ctx, 1, math.MaxUint64)
+		require.NoError(t, err)
+		var loaded []testdata.GroupInfo
+		res, err := orm.Paginate(it, &query.PageRequest{Limit: 2}, &loaded)
+		require.NoError(t, err)
+		assert.Equal(t, []testdata.GroupInfo{g3, g2}, loaded)
+		assert.Equal(t, orm.EncodeSequence(1), res.NextKey)
+	})
+	t.Run("empty range", func(t *testing.T) {
+		it, err := tb.ReversePrefixScan(ctx, 10, 20)
+		require.NoError(t, err)
+		defer it.Close()
+		var loaded testdata.GroupInfo
+		_, err = it.LoadNext(&loaded)
+		assert.True(t, orm.ErrIteratorDone.Is(err), err)
+	})
+}
+
+func TestAutoUInt64MultiRead(t *testing.T) {
+	interfaceRegistry := types.NewInterfaceRegistry()
+	cdc := codec.NewProtoCodec(interfaceRegistry)
+
+	storeKey := sdk.NewKVStoreKey("test")
+	const (
+		testTablePrefix = iota
+		testTableSeqPrefix
+	)
+	tb := orm.NewAutoUInt64TableBuilder(testTablePrefix, testTableSeqPrefix, storeKey, &testdata.GroupInfo{}, cdc).Build()
+	ctx := orm.NewMockContext()
+
+	g1 := testdata.GroupInfo{Description: "my test 1"}
+	g2 := testdata.GroupInfo{Description: "my test 2"}
+	g3 := testdata.GroupInfo{Description: "my test 3"}
+	for _, g := range []testdata.GroupInfo{g1, g2, g3} {
+		_, err := tb.Create(ctx, &g)
+		require.NoError(t, err)
+	}
+
+	specs := map[string]struct {
+		rowIDs      []uint64
+		expResult   []testdata.GroupInfo
+		expNotFound []uint64
+	}{
+		"all found in given order": {
+			rowIDs:    []uint64{3, 1},
+			expResult: []testdata.GroupInfo{g3, g1},
+		},
+		"missing skipped": {
+			rowIDs:      []uint64{4, 2, 0},
+			expResult:   []testdata.GroupInfo{g2},
+			expNotFound: []uint64{4, 0},
+		},
+		"none found": {
+			rowIDs:      []uint64{5},
+			expResult:   []testdata.GroupInfo{},
+			expNotFound: []uint64{5},
+		},
+		"empty": {
+			expResult: []testdata.GroupInfo{},
+		},
+	}
+	for msg, spec := range specs {
+		t.Run(msg, func(t *testing.T) {
+			var loaded []testdata.GroupInfo
+			notFound, err := tb.MultiRead(ctx, spec.rowIDs, &loaded)
+			require.NoError(t, err)
+			assert.Equal(t, spec.expResult, loaded)
+			assert.Equal(t, spec.expNotFound, notFound)
+		})
+	}
+}
+
+func TestAutoUInt64SequenceKeyFunc(t *testing.T) {
+	interfaceRegistry := types.NewInterfaceRegistry()
+	cdc := codec.NewProtoCodec(interfaceRegistry)
+
+	storeKey := sdk.NewKVStoreKey("test")
+	const (
+		testTablePrefix = iota
+		testTableSeqPrefix
+	)
+	byAdmin := func(model codec.ProtoMarshaler) []byte {
+		return model.(*testdata.GroupInfo).Admin
+	}
+	tBuilder := orm.NewAutoUInt64TableBuilder(testTablePrefix, testTableSeqPrefix, storeKey, &testdata.GroupInfo{}, cdc)
+	tBuilder.WithSequenceKeyFunc(byAdmin)
+	idx := orm.NewIndex(tBuilder, GroupByAdminIndexPrefix, func(val interface{}) ([]orm.RowID, error) {
+		return []orm.RowID{[]byte(val.(*testdata.GroupInfo).Description)}, nil
+	})
+	tb := tBuilder.Build()
+	ctx := orm.NewMockContext()
+
+	// the JSON encoding of the addresses requires the default address length
+	admin1 := sdk.AccAddress([]byte("admin-address-1-----"))
+	admin2 := sdk.AccAddress([]byte("admin-address-2-----"))
+	g1 := testdata.GroupInfo{Description: "my test 1", Admin: admin1}
+	g2 := testdata.GroupInfo{Description: "my test 2", Admin: admin1}
+	g3 := testdata.GroupInfo{Description: "my test 3", Admin: admin2}
+	var ids []uint64
+	for _, g := range []testdata.GroupInfo{g1, g2, g3} {
+		id, err := tb.Create(ctx, &g)
+		require.NoError(t, err)
+		ids = append(ids, id)
+	}
+	// the IDs start at 1 for every admin
+	assert.Equal(t, []uint64{1, 2, 1}, ids)
+
+	var loaded testdata.GroupInfo
+	rowID, err := tb.Scope(admin1).GetOne(ctx, 2, &loaded)
+	require.NoError(t, err)
+	assert.Equal(t, g2, loaded)
+	assert.Equal(t, orm.RowID(append(append([]byte{byte(len(admin1))}, admin1...), orm.EncodeSequence(2)...)), rowID)
+	_, err = tb.Scope(admin2).GetOne(ctx, 1, &loaded)
+	require.NoError(t, err)
+	assert.Equal(t, g3, loaded)
+	assert.False(t, tb.Scope(admin2).Has(ctx, 2))
+	assert.False(t, tb.Has(ctx, 1))
+
+	it, err := tb.Scope(admin1).PrefixScan(ctx, 1, 100)
+	require.NoError(t, err)
+	var scanned []testdata.GroupInfo
+	_, err = orm.ReadAll(it, &scanned)
+	require.NoError(t, err)
+	assert.Equal(t, []testdata.GroupInfo{g1, g2}, scanned)
+
+	notFound, err := tb.Scope(admin2).MultiRead(ctx, []uint64{1, 2}, &scanned)
+	require.NoError(t, err)
+	assert.Equal(t, []uint64{2}, notFound)
+
+	it, err = idx.Get(ctx, []byte("my test 3"))
+	require.NoError(t, err)
+	var indexed testdata.GroupInfo
+	_, err = orm.First(it, &indexed)
+	require.NoError(t, err)
+	assert.Equal(t, g3, indexed)
+
+	t.Run("invalid sequence keys", func(t *testing.T) {
+		_, err := tb.Scope(admin1).Create(ctx, &testdata.GroupInfo{Description: "other admin", Admin: admin2})
+		assert.True(t, orm.ErrArgument.Is(err), err)
+		_, err = tb.Create(ctx, &testdata.GroupInfo{Description: "no admin"})
+		assert.True(t, orm.ErrArgument.Is(err), err)
+		err = tb.Scope(admin1).Save(ctx, 1, &testdata.GroupInfo{Description: "other admin", Admin: admin2})
+		assert.True(t, orm.ErrArgument.Is(err), err)
+	})
+	t.Run("not scoped", func(t *testing.T) {
+		err := tb.Save(ctx, 1, &g1)
+		assert.True(t, orm.ErrArgument.Is(err), err)
+		_, err = tb.GetOne(ctx, 1, &loaded)
+		assert.True(t, orm.ErrArgument.Is(err), err)
+		err = tb.Delete(ctx, 1)
+		assert.True(t, orm.ErrArgument.Is(err), err)
+		_, err = tb.MultiRead(ctx, []uint64{1}, &scanned)
+		assert.True(t, orm.ErrArgument.Is(err), err)
+		_, err = tb.PrefixScan(ctx, 1, 100)
+		assert.True(t, orm.ErrArgument.Is(err), err)
+		_, err = tb.ReversePrefixScan(ctx, 1, 100)
+		assert.True(t, orm.ErrArgument.Is(err), err)
+	})
+	t.Run("sequences restored on import", func(t *testing.T) {
+		bz, err := orm.JSONExporter(ctx, tb)
+		require.NoError(t, err)
+
+		newCtx := orm.NewMockContext()
+		admin3 := sdk.AccAddress([]byte("admin-address-3-----"))
+		_, err = tb.Create(newCtx, &testdata.GroupInfo{Description: "replaced", Admin: admin3})
+		require.NoError(t, err)
+		require.NoError(t, orm.JSONImporter(newCtx, tb, bz))
+		id, err := tb.Create(newCtx, &testdata.GroupInfo{Description: "my test 4", Admin: admin1})
+		require.NoError(t, err)
+		assert.Equal(t, uint64(3), id)
+		id, err = tb.Create(newCtx, &testdata.GroupInfo{Description: "my test 5", Admin: admin2})
+		require.NoError(t, err)
+		assert.Equal(t, uint64(2), id)
+		// the sequences of the replaced rows are reset
+		id, err = tb.Create(newCtx, &testdata.GroupInfo{Description: "my test 6", Admin: admin3})
+		require.NoError(t, err)
+		assert.Equal(t, uint64(1), id)
+	})
+	t.Run("invalid setup", func(t *testing.T) {
+		assert.Panics(t, func() { tBuilder.WithSequenceKeyFunc(byAdmin) })
+		assert.Panics(t, func() {
+			orm.NewAutoUInt64TableBuilder(testTablePrefix, testTableSeqPrefix, storeKey, &testdata.GroupInfo{}, cdc).Build().Scope(admin1)
+		})
+	})
+}
+
+func TestAutoUInt64SequenceKeyPrefix(t *testing.T) {
+	interfaceRegistry := types.NewInterfaceRegistry()
+	cdc := codec.NewProtoCodec(interfaceRegistry)
+
+	storeKey := sdk.NewKVStoreKey("test")
+	const (
+		testTablePrefix = iota
+		testTableSeqPrefix
+	)
+	tBuilder := orm.NewAutoUInt64TableBuilder(testTablePrefix, testTableSeqPrefix, storeKey, &testdata.GroupInfo{}, cdc)
+	// the sequence key is the description up to the separator for the test
+	tBuilder.WithSequenceKeyFunc(func(model codec.ProtoMarshaler) []byte {
+		desc := model.(*testdata.GroupInfo).Description
+		return []byte(desc[:strings.IndexByte(desc, '|')])
+	})
+	tb := tBuilder.Build()
+	ctx := orm.NewMockContext()
+
+	// the longer key is the shorter key followed by bytes that sort within the IDs of the shorter key
+	short := []byte("k")
+	long := append([]byte("k"), orm.EncodeSequence(2)...)
+	var shortGroups, longGroups []testdata.GroupInfo
+	for i := 1; i <= 3; i++ {
+		for _, seqKey := range [][]byte{short, long} {
+			g := testdata.GroupInfo{Description: fmt.Sprintf("%s|%d", seqKey, i)}
+			id, err := tb.Create(ctx, &g)
+			require.NoError(t, err)
+			require.Equal(t, uint64(i), id)
+			if len(seqKey) == len(short) {
+				shortGroups = append(shortGroups, g)
+			} else {
+				longGroups = append(longGroups, g)
+			}
+		}
+	}
+
+	specs := map[string]struct {
+		seqKey []byte
+		exp    []testdata.GroupInfo
+	}{
+		"short": {seqKey: short, exp: shortGroups[:2]},
+		"long":  {seqKey: long, exp: longGroups[:2]},
+	}
+	for msg, spec := range specs {
+		t.Run(msg, func(t *testing.T) {
+			it, err := tb.Scope(spec.seqKey).PrefixScan(ctx, 1, 3)
+			require.NoError(t, err)
+			var loaded []testdata.GroupInfo
+			_, err = orm.ReadAll(it, &loaded)
+			require.NoError(t, err)
+			assert.Equal(t, spec.exp, loaded)
+
+			it, err = tb.Scope(spec.seqKey).ReversePrefixScan(ctx, 1, 3)
+			require.NoError(t, err)
+			loaded = nil
+			_, err = orm.ReadAll(it, &loaded)
+			require.NoError(t, err)
+			assert.Equal(t, []testdata.GroupInfo{spec.exp[1], spec.exp[0]}, loaded)
+		})
+	}
+
+	t.Run("sequences restored on import", func(t *testing.T) {
+		bz, err := orm.JSONExporter(ctx, tb)
+		require.NoError(t, err)
+		newCtx := orm.NewMockContext()
+		require.NoError(t, orm.JSONImporter(newCtx, tb, bz))
+		id, err := tb.Create(newCtx, &testdata.GroupInfo{Description: "k|new"})
+		require.NoError(t, err)
+		assert.Equal(t, uint64(4), id)
+	})
+}
+
+func TestAutoUInt64BulkCreate(t *testing.T) {
+	interfaceRegistry := types.NewInterfaceRegistry()
+	cdc := codec.NewProtoCodec(interfaceRegistry)
+
+	storeKey := sdk.NewKVStoreKey("test")
+	const (
+		testTablePrefix = iota
+		testTableSeqPrefix
+		testTableCounterPrefix = 0x10
+	)
+	tBuilder := orm.NewAutoUInt64TableBuilder(testTablePrefix, testTableSeqPrefix, storeKey, &testdata.GroupInfo{}, cdc)
+	tBuilder.WithRowCounter(testTableCounterPrefix)
+	idx := orm.NewUniqueIndex(tBuilder, GroupByAdminIndexPrefix, func(val interface{}) (orm.RowID, error) {
+		return []byte(val.(*testdata.GroupInfo).Description), nil
+	})
+	tb := tBuilder.Build()
+	ctx := orm.NewMockContext()
+
+	_, err := tb.Create(ctx, &testdata.GroupInfo{Description: "my test 1", Admin: sdk.AccAddress("admin")})
+	require.NoError(t, err)
+
+	g2 := testdata.GroupInfo{Description: "my test 2", Admin: sdk.AccAddress("admin")}
+	g3 := testdata.GroupInfo{Description: "my test 3", Admin: sdk.AccAddress("admin")}
+	rowIDs, err := tb.BulkCreate(ctx, []codec.ProtoMarshaler{&g2, &g3})
+	require.NoError(t, err)
+	assert.Equal(t, []orm.RowID{orm.EncodeSequence(2), orm.EncodeSequence(3)}, rowIDs)
+	assert.Equal(t, uint64(3), tb.Sequence().CurVal(ctx))
+	n, err := tb.Count(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), n)
+
+	var loaded testdata.GroupInfo
+	_, err = tb.GetOne(ctx, 3, &loaded)
+	require.NoError(t, err)
+	assert.Equal(t, g3, loaded)
+	assert.True(t, idx.Has(ctx, []byte("my test 2")))
+
+	// the next Create continues with the sequence
+	id, err := tb.Create(ctx, &testdata.GroupInfo{Description: "my test 4", Admin: sdk.AccAddress("admin")})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(4), id)
+
+	// unique constraints are enforced against the stored rows and within the batch
+	_, err = tb.BulkCreate(orm.NewMockContext(), []codec.ProtoMarshaler{&g2, &g2})
+	assert.True(t, orm.ErrUniqueConstraint.Is(err))
+	_, err = tb.BulkCreate(ctx, []codec.ProtoMarshaler{&g3})
+	assert.True(t, orm.ErrUniqueConstraint.Is(err))
+	// all models are validated before any is persisted
+	_, err = tb.BulkCreate(ctx, []codec.ProtoMarshaler{&testdata.GroupInfo{Description: "my test 5", Admin: sdk.AccAddress("admin")}, &testdata.GroupMember{}})
+	assert.True(t, orm.ErrType.Is(err))
+	assert.False(t, idx.Has(ctx, []byte("my test 5")))
+}
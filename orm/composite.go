@@ -0,0 +1,62 @@
+package orm
+
+import (
+	"github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+// CompositeRowID encodes the RowIDs of tables with a primary key of multiple fields, like a credit batch
+// that is identified by the project and a batch sequence. Every part is prefixed with its length as
+// single byte by `LengthPrefixedKey`, so that different parts never result in the same RowID: without the
+// length prefix the parts ("ab", "c") and ("a", "bc") would share the RowID "abc". As all RowIDs of a
+// table have the same number of parts, no RowID is the prefix of another one, while the RowIDs with the
+// same leading parts share the prefix of these parts, see `Prefix`.
+// Parts must not be longer than 255 bytes.
+type CompositeRowID struct {
+	parts int
+}
+
+// NewCompositeRowID creates a CompositeRowID for the given number of parts.
+// parts must be a positive number
+func NewCompositeRowID(parts int) CompositeRowID {
+	if parts <= 0 {
+		panic("number of parts must be positive")
+	}
+	return CompositeRowID{parts: parts}
+}
+
+// Encode returns the RowID for all parts. An `ErrArgument` error is returned for another number of parts
+// and an `ErrIndexKeyMaxLength` error for a part that is longer than 255 bytes.
+func (c CompositeRowID) Encode(parts ...[]byte) (RowID, error) {
+	if len(parts) != c.parts {
+		return nil, errors.Wrapf(ErrArgument, "%d parts for a composite RowID of %d", len(parts), c.parts)
+	}
+	return LengthPrefixedKey(parts...)
+}
+
+// Prefix returns the common prefix of the RowIDs with the given leading parts, for example to scan all
+// batches of a project with `Table.PrefixScan` and the `PrefixRange` of the prefix.
+func (c CompositeRowID) Prefix(parts ...[]byte) (RowID, error) {
+	if len(parts) > c.parts {
+		return nil, errors.Wrapf(ErrArgument, "%d parts for a composite RowID of %d", len(parts), c.parts)
+	}
+	return LengthPrefixedKey(parts...)
+}
+
+// Decode returns the parts of the RowID. An `ErrArgument` error is returned when the RowID was not
+// encoded with the same number of parts.
+func (c CompositeRowID) Decode(rowID RowID) ([][]byte, error) {
+	parts := make([][]byte, 0, c.parts)
+	rest := rowID
+	for len(rest) != 0 && len(parts) < c.parts {
+		n := int(rest[0])
+		if len(rest) < n+1 {
+			return nil, errors.Wrapf(ErrArgument, "part %d of RowID %X exceeds the RowID", len(parts), rowID)
+		}
+		parts = append(parts, rest[1:n+1])
+		rest = rest[n+1:]
+	}
+	if len(parts) != c.parts || len(rest) != 0 {
+		return nil, errors.Wrapf(ErrArgument, "RowID %X is not a composite RowID of %d parts", rowID, c.parts)
+	}
+	return parts, nil
+}
@@ -0,0 +1,177 @@
+package orm
+
+import (
+	"encoding/binary"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/cosmos/cosmos-sdk/types/query"
+)
+
+// KeyField is an encoded field of the tuple key of a `CompositeIndex`. The encodings preserve the order of
+// the values, so that the byte order of the encoded tuples matches the order of the tuples: by the first
+// field, then by the second one and so on. Every encoding delimits itself, so that the key of the leading
+// fields of a tuple is a prefix of the full key and never the one of another tuple.
+// The field at a position of the tuple must have the same type for all objects of an index.
+type KeyField []byte
+
+// StringField encodes a string field of a tuple key. The bytes of the string are terminated with
+// `0x00 0x00` and any 0x00 byte within the string is escaped as `0x00 0xff`, so that shorter strings
+// sort before the longer ones they are a prefix of, as for Go strings. A length prefix, like the one of
+// `LengthPrefixedKey`, would sort the strings by length first.
+func StringField(s string) KeyField {
+	return BytesField([]byte(s))
+}
+
+// BytesField encodes a bytes field of a tuple key like a `StringField`.
+func BytesField(b []byte) KeyField {
+	res := make(KeyField, 0, len(b)+2)
+	for _, c := range b {
+		res = append(res, c)
+		if c == 0x00 {
+			res = append(res, 0xff)
+		}
+	}
+	return append(res, 0x00, 0x00)
+}
+
+// Uint64Field encodes an uint64 field of a tuple key with 8 bytes in big endian order.
+func Uint64Field(v uint64) KeyField {
+	res := make(KeyField, 8)
+	binary.BigEndian.PutUint64(res, v)
+	return res
+}
+
+// Int64Field encodes an int64 field of a tuple key with 8 bytes in big endian order and the sign bit
+// flipped, so that negative numbers sort before positive ones.
+func Int64Field(v int64) KeyField {
+	return Uint64Field(uint64(v) ^ 1<<63)
+}
+
+// TimeField encodes a timestamp field of a tuple key with a fixed width of 12 bytes: the seconds since the
+// unix epoch like an `Int64Field` followed by the nanoseconds within the second as 4 bytes in big endian
+// order. The location of the timestamp is not encoded.
+// The field is not encoded like the 8 bytes unix nanos of `EncodeUnixNanoTime`, that are the keys of a
+// `TimeIndex`, as it covers all years of time.Time.
+func TimeField(t time.Time) KeyField {
+	res := make(KeyField, 12)
+	binary.BigEndian.PutUint64(res, uint64(t.Unix())^1<<63)
+	binary.BigEndian.PutUint32(res[8:], uint32(t.Nanosecond()))
+	return res
+}
+
+// CompositeKey concatenates the encoded fields to the key of a `CompositeIndex`. Keys of KeyFields keep the
+// order of the fields, unlike the `LengthPrefixedKey` of raw fields of a `CompoundUniqueIndex`.
+func CompositeKey(fields ...KeyField) []byte {
+	var n int
+	for _, f := range fields {
+		n += len(f)
+	}
+	key := make([]byte, 0, n)
+	for _, f := range fields {
+		key = append(key, f...)
+	}
+	return key
+}
+
+// CompositeIndexerFunc returns the ordered tuple of fields for the index key of the source object in a
+// `CompositeIndex`. It must return the number of fields of the index, or nil when the object is not
+// indexed.
+type CompositeIndexerFunc func(value interface{}) ([]KeyField, error)
+
+// CompositeIndex is a MultiKeyIndex with a key that is a tuple of multiple fields, like the index of a SQL
+// table on `(a, b)`. The rows are sorted by the tuples, so that they can be scanned by all or just the
+// leading fields in the order of the remaining fields, for example all credit batches of a class by start
+// date. Rows with the same tuple are sorted by RowID.
+type CompositeIndex struct {
+	MultiKeyIndex
+	fields int
+}
+
+// NewCompositeIndex creates a new index with keys of the given number of fields that the indexer returns.
+func NewCompositeIndex(builder Indexable, prefix byte, fields int, indexer CompositeIndexerFunc) CompositeIndex {
+	if fields <= 0 {
+		panic("number of fields must be positive")
+	}
+	if indexer == nil {
+		panic("Indexer func must not be nil")
+	}
+	return CompositeIndex{
+		MultiKeyIndex: NewIndex(builder, prefix, func(value interface{}) ([]RowID, error) {
+			tuple, err := indexer(value)
+			switch {
+			case err != nil:
+				return nil, err
+			case tuple == nil:
+				return nil, nil
+			case len(tuple) != fields:
+				return nil, errors.Wrapf(ErrArgument, "expected %d fields but got %d", fields, len(tuple))
+			}
+			return []RowID{CompositeKey(tuple...)}, nil
+		}),
+		fields: fields,
+	}
+}
+
+// HasFields checks if an object with the given tuple of fields exists.
+func (i CompositeIndex) HasFields(ctx HasKVStore, fields ...KeyField) (bool, error) {
+	if len(fields) != i.fields {
+		return false, errors.Wrapf(ErrArgument, "expected %d fields but got %d", i.fields, len(fields))
+	}
+	return i.Has(ctx, CompositeKey(fields...)), nil
+}
+
+// GetFields returns an Iterator over all objects with the given tuple of fields.
+func (i CompositeIndex) GetFields(ctx HasKVStore, fields ...KeyField) (Iterator, error) {
+	if len(fields) != i.fields {
+		return NewInvalidIterator(), errors.Wrapf(ErrArgument, "expected %d fields but got %d", i.fields, len(fields))
+	}
+	return i.Get(ctx, CompositeKey(fields...))
+}
+
+// PrefixScanFields returns an Iterator over all objects whose leading fields are the given ones, in
+// ascending order of the remaining fields. At least one and at most the number of fields of the index
+// are required.
+//
+// WARNING: The use of a PrefixScanFields can be very expensive in terms of Gas. Please make sure you do not
+// expose this as an endpoint to the public without further limits. See `LimitIterator`
+//
+// CONTRACT: No writes may happen within a domain while an iterator exists over it.
+func (i CompositeIndex) PrefixScanFields(ctx HasKVStore, fields ...KeyField) (Iterator, error) {
+	key, err := i.leadingKey(fields)
+	if err != nil {
+		return NewInvalidIterator(), err
+	}
+	start, end := PrefixRange(key)
+	return i.PrefixScan(ctx, start, end)
+}
+
+// ReversePrefixScanFields returns an Iterator like PrefixScanFields in descending order.
+//
+// CONTRACT: No writes may happen within a domain while an iterator exists over it.
+func (i CompositeIndex) ReversePrefixScanFields(ctx HasKVStore, fields ...KeyField) (Iterator, error) {
+	key, err := i.leadingKey(fields)
+	if err != nil {
+		return NewInvalidIterator(), err
+	}
+	start, end := PrefixRange(key)
+	return i.ReversePrefixScan(ctx, start, end)
+}
+
+// GetPaginatedFields creates an iterator over all objects whose leading fields are the given ones
+// starting from pageRequest.Key if provided. See `MultiKeyIndex.GetPaginated` for the pageRequest.
+func (i CompositeIndex) GetPaginatedFields(ctx HasKVStore, pageRequest *query.PageRequest, fields ...KeyField) (Iterator, error) {
+	key, err := i.leadingKey(fields)
+	if err != nil {
+		return NewInvalidIterator(), err
+	}
+	return i.GetPaginated(ctx, key, pageRequest)
+}
+
+// leadingKey returns the key of the leading fields of a tuple.
+func (i CompositeIndex) leadingKey(fields []KeyField) ([]byte, error) {
+	if len(fields) == 0 || len(fields) > i.fields {
+		return nil, errors.Wrapf(ErrArgument, "expected 1 to %d fields but got %d", i.fields, len(fields))
+	}
+	return CompositeKey(fields...), nil
+}
@@ -0,0 +1,235 @@
+package orm_test
+
+import (
+	"bytes"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	"github.com/cosmos/cosmos-sdk/codec/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/query"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/regen-network/regen-ledger/orm"
+	"github.com/regen-network/regen-ledger/testutil/testdata"
+)
+
+func TestKeyFieldOrder(t *testing.T) {
+	specs := map[string][]orm.KeyField{
+		"string": {
+			orm.StringField(""),
+			orm.StringField("\x00"),
+			orm.StringField("\x00\x00"),
+			orm.StringField("\x00\x01"),
+			orm.StringField("\x01"),
+			orm.StringField("a"),
+			orm.StringField("a\x00"),
+			orm.StringField("a\x00b"),
+			orm.StringField("a\x01"),
+			orm.StringField("ab"),
+			orm.StringField("b"),
+			orm.StringField("\xff"),
+			orm.StringField("\xff\xff"),
+		},
+		"bytes": {
+			orm.BytesField(nil),
+			orm.BytesField([]byte{0}),
+			orm.BytesField([]byte{0, 0xff}),
+			orm.BytesField([]byte{1}),
+			orm.BytesField([]byte{0xff}),
+		},
+		"uint64": {
+			orm.Uint64Field(0),
+			orm.Uint64Field(1),
+			orm.Uint64Field(255),
+			orm.Uint64Field(256),
+			orm.Uint64Field(math.MaxUint32 + 1),
+			orm.Uint64Field(math.MaxUint64 - 1),
+			orm.Uint64Field(math.MaxUint64),
+		},
+		"int64": {
+			orm.Int64Field(math.MinInt64),
+			orm.Int64Field(math.MinInt64 + 1),
+			orm.Int64Field(-256),
+			orm.Int64Field(-1),
+			orm.Int64Field(0),
+			orm.Int64Field(1),
+			orm.Int64Field(256),
+			orm.Int64Field(math.MaxInt64),
+		},
+		"time": {
+			orm.TimeField(time.Date(1, 1, 1, 0, 0, 0, 0, time.UTC)),
+			orm.TimeField(time.Unix(-1, 0)),
+			orm.TimeField(time.Unix(-1, 999999999)),
+			orm.TimeField(time.Unix(0, 0)),
+			orm.TimeField(time.Unix(0, 1)),
+			orm.TimeField(time.Unix(1, 0)),
+			orm.TimeField(time.Date(2021, 4, 1, 10, 0, 0, 0, time.FixedZone("CEST", 2*60*60))),
+			orm.TimeField(time.Date(2021, 4, 1, 9, 0, 0, 1, time.UTC)),
+			orm.TimeField(time.Date(9999, 12, 31, 23, 59, 59, 999999999, time.UTC)),
+		},
+		"tuple of string and uint64": {
+			orm.CompositeKey(orm.StringField(""), orm.Uint64Field(math.MaxUint64)),
+			orm.CompositeKey(orm.StringField("a"), orm.Uint64Field(0)),
+			orm.CompositeKey(orm.StringField("a"), orm.Uint64Field(math.MaxUint64)),
+			orm.CompositeKey(orm.StringField("a\x00"), orm.Uint64Field(0)),
+			orm.CompositeKey(orm.StringField("ab"), orm.Uint64Field(0)),
+			orm.CompositeKey(orm.StringField("b"), orm.Uint64Field(0)),
+		},
+		"tuple of int64 and string": {
+			orm.CompositeKey(orm.Int64Field(-1), orm.StringField("b")),
+			orm.CompositeKey(orm.Int64Field(0), orm.StringField("")),
+			orm.CompositeKey(orm.Int64Field(0), orm.StringField("a")),
+			orm.CompositeKey(orm.Int64Field(0), orm.StringField("a\x00")),
+			orm.CompositeKey(orm.Int64Field(1), orm.StringField("")),
+		},
+	}
+	for msg, keys := range specs {
+		t.Run(msg, func(t *testing.T) {
+			for i := 1; i < len(keys); i++ {
+				assert.Equal(t, -1, bytes.Compare(keys[i-1], keys[i]), "key %d: %X >= %X", i, keys[i-1], keys[i])
+			}
+		})
+	}
+}
+
+func TestKeyFieldEncoding(t *testing.T) {
+	assert.Equal(t, orm.KeyField{'a', 0, 0xff, 'b', 0, 0}, orm.StringField("a\x00b"))
+	assert.Equal(t, orm.KeyField{0, 0}, orm.StringField(""))
+	assert.Equal(t, orm.KeyField{0, 0, 0, 0, 0, 0, 1, 0}, orm.Uint64Field(256))
+	assert.Equal(t, orm.KeyField{0x7f, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}, orm.Int64Field(-1))
+	assert.Equal(t, orm.KeyField{0x80, 0, 0, 0, 0, 0, 0, 1, 0, 0, 0, 2}, orm.TimeField(time.Unix(1, 2)))
+	// the location is not encoded
+	ts := time.Date(2021, 4, 1, 10, 0, 0, 0, time.UTC)
+	assert.Equal(t, orm.TimeField(ts), orm.TimeField(ts.In(time.FixedZone("CEST", 2*60*60))))
+}
+
+func TestCompositeIndex(t *testing.T) {
+	interfaceRegistry := types.NewInterfaceRegistry()
+	cdc := codec.NewProtoCodec(interfaceRegistry)
+
+	storeKey := sdk.NewKVStoreKey("test")
+
+	tableBuilder := orm.NewPrimaryKeyTableBuilder(GroupMemberTablePrefix, storeKey, &testdata.GroupMember{}, orm.Max255DynamicLengthIndexKeyCodec{}, cdc)
+	byGroupWeightIdx := orm.NewCompositeIndex(tableBuilder, 0x10, 2, func(val interface{}) ([]orm.KeyField, error) {
+		m := val.(*testdata.GroupMember)
+		if m.Weight == 0 {
+			return nil, nil
+		}
+		return []orm.KeyField{orm.BytesField(m.Group), orm.Uint64Field(m.Weight)}, nil
+	})
+	myTable := tableBuilder.Build()
+
+	ctx := orm.NewMockContext()
+
+	group1 := sdk.AccAddress(orm.EncodeSequence(1))
+	group2 := sdk.AccAddress(orm.EncodeSequence(2))
+	m1 := testdata.GroupMember{Group: group1, Member: sdk.AccAddress([]byte("member-address-1")), Weight: 256}
+	m2 := testdata.GroupMember{Group: group1, Member: sdk.AccAddress([]byte("member-address-2")), Weight: 1}
+	m3 := testdata.GroupMember{Group: group1, Member: sdk.AccAddress([]byte("member-address-3")), Weight: 2}
+	m4 := testdata.GroupMember{Group: group2, Member: sdk.AccAddress([]byte("member-address-1")), Weight: 1}
+	m5 := testdata.GroupMember{Group: group2, Member: sdk.AccAddress([]byte("member-address-2"))}
+	for _, m := range []testdata.GroupMember{m1, m2, m3, m4, m5} {
+		require.NoError(t, myTable.Create(ctx, &m))
+	}
+
+	// HasFields
+	exists, err := byGroupWeightIdx.HasFields(ctx, orm.BytesField(group1), orm.Uint64Field(256))
+	require.NoError(t, err)
+	assert.True(t, exists)
+	exists, err = byGroupWeightIdx.HasFields(ctx, orm.BytesField(group2), orm.Uint64Field(0))
+	require.NoError(t, err)
+	assert.False(t, exists, "not indexed")
+	_, err = byGroupWeightIdx.HasFields(ctx, orm.BytesField(group1))
+	assert.True(t, orm.ErrArgument.Is(err), err)
+
+	// GetFields
+	it, err := byGroupWeightIdx.GetFields(ctx, orm.BytesField(group2), orm.Uint64Field(1))
+	require.NoError(t, err)
+	var loaded testdata.GroupMember
+	rowID, err := orm.First(it, &loaded)
+	require.NoError(t, err)
+	assert.Equal(t, orm.RowID(m4.PrimaryKey()), rowID)
+	assert.Equal(t, m4, loaded)
+	_, err = byGroupWeightIdx.GetFields(ctx, orm.BytesField(group2))
+	assert.True(t, orm.ErrArgument.Is(err), err)
+
+	// PrefixScanFields by the leading field in the order of the weights
+	it, err = byGroupWeightIdx.PrefixScanFields(ctx, orm.BytesField(group1))
+	require.NoError(t, err)
+	var members []testdata.GroupMember
+	_, err = orm.ReadAll(it, &members)
+	require.NoError(t, err)
+	assert.Equal(t, []testdata.GroupMember{m2, m3, m1}, members)
+
+	it, err = byGroupWeightIdx.ReversePrefixScanFields(ctx, orm.BytesField(group1))
+	require.NoError(t, err)
+	members = nil
+	_, err = orm.ReadAll(it, &members)
+	require.NoError(t, err)
+	assert.Equal(t, []testdata.GroupMember{m1, m3, m2}, members)
+
+	// by all fields
+	it, err = byGroupWeightIdx.PrefixScanFields(ctx, orm.BytesField(group1), orm.Uint64Field(2))
+	require.NoError(t, err)
+	members = nil
+	_, err = orm.ReadAll(it, &members)
+	require.NoError(t, err)
+	assert.Equal(t, []testdata.GroupMember{m3}, members)
+
+	_, err = byGroupWeightIdx.PrefixScanFields(ctx)
+	assert.True(t, orm.ErrArgument.Is(err), err)
+	_, err = byGroupWeightIdx.PrefixScanFields(ctx, orm.BytesField(group1), orm.Uint64Field(2), orm.Uint64Field(3))
+	assert.True(t, orm.ErrArgument.Is(err), err)
+
+	// GetPaginatedFields
+	pageReq := &query.PageRequest{Limit: 2}
+	it, err = byGroupWeightIdx.GetPaginatedFields(ctx, pageReq, orm.BytesField(group1))
+	require.NoError(t, err)
+	members = nil
+	res, err := orm.Paginate(it, pageReq, &members)
+	require.NoError(t, err)
+	assert.Equal(t, []testdata.GroupMember{m2, m3}, members)
+	require.NotNil(t, res.NextKey)
+
+	pageReq = &query.PageRequest{Key: res.NextKey, Limit: 2}
+	it, err = byGroupWeightIdx.GetPaginatedFields(ctx, pageReq, orm.BytesField(group1))
+	require.NoError(t, err)
+	members = nil
+	res, err = orm.Paginate(it, pageReq, &members)
+	require.NoError(t, err)
+	assert.Equal(t, []testdata.GroupMember{m1}, members)
+	assert.Nil(t, res.NextKey)
+
+	// an update moves the row to the new tuple
+	m1.Weight = 0
+	require.NoError(t, myTable.Save(ctx, &m1))
+	exists, err = byGroupWeightIdx.HasFields(ctx, orm.BytesField(group1), orm.Uint64Field(256))
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func TestCompositeIndexFieldCount(t *testing.T) {
+	interfaceRegistry := types.NewInterfaceRegistry()
+	cdc := codec.NewProtoCodec(interfaceRegistry)
+
+	storeKey := sdk.NewKVStoreKey("test")
+
+	tableBuilder := orm.NewPrimaryKeyTableBuilder(GroupMemberTablePrefix, storeKey, &testdata.GroupMember{}, orm.Max255DynamicLengthIndexKeyCodec{}, cdc)
+	orm.NewCompositeIndex(tableBuilder, 0x10, 2, func(val interface{}) ([]orm.KeyField, error) {
+		return []orm.KeyField{orm.BytesField(val.(*testdata.GroupMember).Group)}, nil
+	})
+	myTable := tableBuilder.Build()
+
+	ctx := orm.NewMockContext()
+	err := myTable.Create(ctx, &testdata.GroupMember{Group: []byte("group"), Member: []byte("member"), Weight: 1})
+	assert.True(t, orm.ErrArgument.Is(err), err)
+
+	assert.Panics(t, func() {
+		orm.NewCompositeIndex(tableBuilder, 0x11, 0, func(val interface{}) ([]orm.KeyField, error) { return nil, nil })
+	})
+	assert.Panics(t, func() { orm.NewCompositeIndex(tableBuilder, 0x11, 1, nil) })
+}
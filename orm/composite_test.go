@@ -0,0 +1,127 @@
+package orm_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	"github.com/cosmos/cosmos-sdk/codec/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/regen-network/regen-ledger/orm"
+	"github.com/regen-network/regen-ledger/testutil/testdata"
+)
+
+func TestCompositeRowID(t *testing.T) {
+	c := orm.NewCompositeRowID(2)
+	specs := map[string]struct {
+		parts    [][]byte
+		expRowID orm.RowID
+		expErr   *errors.Error
+	}{
+		"two parts": {
+			parts:    [][]byte{[]byte("ab"), []byte("c")},
+			expRowID: []byte{2, 'a', 'b', 1, 'c'},
+		},
+		"same bytes in other parts": {
+			parts:    [][]byte{[]byte("a"), []byte("bc")},
+			expRowID: []byte{1, 'a', 2, 'b', 'c'},
+		},
+		"empty parts": {
+			parts:    [][]byte{{}, nil},
+			expRowID: []byte{0, 0},
+		},
+		"max length": {
+			parts:    [][]byte{bytes.Repeat([]byte{1}, 255), []byte("c")},
+			expRowID: append(append([]byte{255}, bytes.Repeat([]byte{1}, 255)...), 1, 'c'),
+		},
+		"part too long": {
+			parts:  [][]byte{bytes.Repeat([]byte{1}, 256), []byte("c")},
+			expErr: orm.ErrIndexKeyMaxLength,
+		},
+		"too few parts": {
+			parts:  [][]byte{[]byte("a")},
+			expErr: orm.ErrArgument,
+		},
+		"too many parts": {
+			parts:  [][]byte{[]byte("a"), []byte("b"), []byte("c")},
+			expErr: orm.ErrArgument,
+		},
+	}
+	for msg, spec := range specs {
+		t.Run(msg, func(t *testing.T) {
+			rowID, err := c.Encode(spec.parts...)
+			if spec.expErr != nil {
+				assert.True(t, spec.expErr.Is(err), err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, spec.expRowID, rowID)
+
+			parts, err := c.Decode(rowID)
+			require.NoError(t, err)
+			require.Len(t, parts, len(spec.parts))
+			for i := range parts {
+				assert.Equal(t, string(spec.parts[i]), string(parts[i]))
+			}
+		})
+	}
+
+	t.Run("prefix", func(t *testing.T) {
+		prefix, err := c.Prefix([]byte("ab"))
+		require.NoError(t, err)
+		rowID, err := c.Encode([]byte("ab"), []byte("c"))
+		require.NoError(t, err)
+		assert.True(t, bytes.HasPrefix(rowID, prefix))
+		other, err := c.Encode([]byte("abc"), []byte("d"))
+		require.NoError(t, err)
+		assert.False(t, bytes.HasPrefix(other, prefix))
+	})
+
+	for msg, rowID := range map[string]orm.RowID{
+		"empty":           {},
+		"too few parts":   {1, 'a'},
+		"too many parts":  {1, 'a', 1, 'b', 1, 'c'},
+		"part exceeds":    {1, 'a', 2, 'b'},
+		"trailing bytes":  {1, 'a', 1, 'b', 'c'},
+		"without lengths": []byte("ab"),
+	} {
+		t.Run("decode "+msg, func(t *testing.T) {
+			_, err := c.Decode(rowID)
+			assert.True(t, orm.ErrArgument.Is(err), err)
+		})
+	}
+
+	assert.Panics(t, func() { orm.NewCompositeRowID(0) })
+}
+
+func TestPrimaryKeyTableWithCompositeRowID(t *testing.T) {
+	interfaceRegistry := types.NewInterfaceRegistry()
+	cdc := codec.NewProtoCodec(interfaceRegistry)
+
+	storeKey := sdk.NewKVStoreKey("test")
+	const testTablePrefix = iota
+
+	builder := orm.NewPrimaryKeyTableBuilder(testTablePrefix, storeKey, &testdata.GroupMember{}, orm.Max255DynamicLengthIndexKeyCodec{}, cdc)
+	builder.WithCompositeRowID(orm.NewCompositeRowID(2))
+	tb := builder.Build()
+	ctx := orm.NewMockContext()
+
+	// the primary key of a GroupMember is the group followed by the member
+	m := testdata.GroupMember{Group: []byte{1, 'a'}, Member: []byte{1, 'b'}, Weight: 1}
+	require.NoError(t, tb.Create(ctx, &m))
+	assert.True(t, tb.Contains(ctx, &m))
+
+	err := tb.Create(ctx, &testdata.GroupMember{Group: []byte("group-a"), Member: []byte("member-one"), Weight: 1})
+	assert.True(t, orm.ErrArgument.Is(err), err)
+	_, err = tb.BulkCreate(ctx, []orm.PrimaryKeyed{&testdata.GroupMember{Group: []byte{1, 'a'}, Member: []byte{2, 'b'}, Weight: 1}})
+	assert.True(t, orm.ErrArgument.Is(err), err)
+
+	// the RowIDs of a composite can not be stripped from index keys of a fix length
+	fixBuilder := orm.NewPrimaryKeyTableBuilder(testTablePrefix, storeKey, &testdata.GroupMember{}, orm.FixLengthIndexKeys(4), cdc)
+	assert.Panics(t, func() { fixBuilder.WithCompositeRowID(orm.NewCompositeRowID(2)) })
+	assert.Panics(t, func() { builder.WithCompositeRowID(orm.CompositeRowID{}) })
+}
@@ -0,0 +1,72 @@
+package orm
+
+import (
+	"fmt"
+
+	"github.com/cosmos/cosmos-sdk/store/prefix"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// counterStorageKey is a fix key to read/ write data on the storage layer
+var counterStorageKey = []byte{0x1}
+
+// rowCounter is a persistent counter of the rows stored in a table.
+type rowCounter struct {
+	storeKey sdk.StoreKey
+	prefix   byte
+}
+
+func newRowCounter(storeKey sdk.StoreKey, prefix byte) *rowCounter {
+	return &rowCounter{
+		prefix:   prefix,
+		storeKey: storeKey,
+	}
+}
+
+// Get returns the current value. 0 if none.
+func (c rowCounter) Get(ctx HasKVStore) uint64 {
+	store := prefix.NewStore(ctx.KVStore(c.storeKey), []byte{c.prefix})
+	return DecodeSequence(store.Get(counterStorageKey))
+}
+
+// Set persists the given value.
+func (c rowCounter) Set(ctx HasKVStore, v uint64) {
+	store := prefix.NewStore(ctx.KVStore(c.storeKey), []byte{c.prefix})
+	store.Set(counterStorageKey, EncodeSequence(v))
+}
+
+// Inc increments and persists the counter by one.
+func (c rowCounter) Inc(ctx HasKVStore) {
+	c.Set(ctx, c.Get(ctx)+1)
+}
+
+// Dec decrements and persists the counter by one. The counter does not go below 0.
+func (c rowCounter) Dec(ctx HasKVStore) {
+	if v := c.Get(ctx); v > 0 {
+		c.Set(ctx, v-1)
+	}
+}
+
+// RowCounterInvariant returns an invariant that verifies the row counter of the table against the number
+// of rows found by a full table scan. The module name and route are used for the invariant message like
+// with `sdk.FormatInvariant`. It panics when the table has no row counter.
+func RowCounterInvariant(moduleName, route string, t TableExportable) sdk.Invariant {
+	table := t.Table()
+	if table.counter == nil {
+		panic("table has no row counter")
+	}
+	return func(ctx sdk.Context) (string, bool) {
+		persisted := table.counter.Get(ctx)
+		it, err := table.PrefixScan(ctx, nil, nil)
+		if err != nil {
+			return sdk.FormatInvariant(moduleName, route, err.Error()), true
+		}
+		rows, err := Count(it)
+		if err != nil {
+			return sdk.FormatInvariant(moduleName, route, err.Error()), true
+		}
+		broken := rows != persisted
+		msg := fmt.Sprintf("row counter of table 0x%x is %d for %d rows", table.prefix, persisted, rows)
+		return sdk.FormatInvariant(moduleName, route, msg), broken
+	}
+}
@@ -0,0 +1,70 @@
+package orm
+
+import (
+	"encoding/binary"
+
+	"github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+// cursorPrefix marks a page key as cursor and contains the version of the format.
+var cursorPrefix = []byte{0xff, 0x1}
+
+// EncodeCursor returns an opaque page key for an element of an index. It contains the index key and the RowID,
+// so that a page can be continued exactly at the element, even when the search key is only a prefix of the
+// index keys. The format is versioned and both values are length prefixed:
+// `concat(0xff, version, uvarint(len(indexKey)), indexKey, uvarint(len(rowID)), rowID)`.
+func EncodeCursor(indexKey []byte, rowID RowID) []byte {
+	res := make([]byte, 0, len(cursorPrefix)+2*binary.MaxVarintLen64+len(indexKey)+len(rowID))
+	res = append(res, cursorPrefix...)
+	res = appendLengthPrefixed(res, indexKey)
+	return appendLengthPrefixed(res, rowID)
+}
+
+// DecodeCursor returns the index key and RowID of a cursor created with EncodeCursor.
+// An `ErrArgument` error is returned for malformed cursors or an unsupported version.
+func DecodeCursor(cursor []byte) (indexKey []byte, rowID RowID, err error) {
+	if !isCursor(cursor) {
+		return nil, nil, errors.Wrap(ErrArgument, "not a cursor")
+	}
+	if len(cursor) < len(cursorPrefix) || cursor[1] != cursorPrefix[1] {
+		return nil, nil, errors.Wrap(ErrArgument, "unsupported cursor version")
+	}
+	rest := cursor[len(cursorPrefix):]
+	if indexKey, rest, err = readLengthPrefixed(rest); err != nil {
+		return nil, nil, errors.Wrap(err, "index key")
+	}
+	var bz []byte
+	if bz, rest, err = readLengthPrefixed(rest); err != nil {
+		return nil, nil, errors.Wrap(err, "row id")
+	}
+	if len(bz) == 0 {
+		return nil, nil, errors.Wrap(ErrArgument, "empty row id")
+	}
+	if len(rest) != 0 {
+		return nil, nil, errors.Wrapf(ErrArgument, "%d unexpected bytes after cursor", len(rest))
+	}
+	return indexKey, bz, nil
+}
+
+// isCursor returns true when the page key has the prefix of a cursor created with EncodeCursor.
+func isCursor(key []byte) bool {
+	return len(key) != 0 && key[0] == cursorPrefix[0]
+}
+
+func appendLengthPrefixed(dst []byte, bz []byte) []byte {
+	var n [binary.MaxVarintLen64]byte
+	dst = append(dst, n[:binary.PutUvarint(n[:], uint64(len(bz)))]...)
+	return append(dst, bz...)
+}
+
+func readLengthPrefixed(bz []byte) ([]byte, []byte, error) {
+	l, n := binary.Uvarint(bz)
+	if n <= 0 {
+		return nil, nil, errors.Wrap(ErrArgument, "invalid length prefix")
+	}
+	if l > uint64(len(bz)-n) {
+		return nil, nil, errors.Wrapf(ErrArgument, "length %d exceeds cursor", l)
+	}
+	end := n + int(l)
+	return bz[n:end], bz[end:], nil
+}
@@ -0,0 +1,207 @@
+package orm_test
+
+import (
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	"github.com/cosmos/cosmos-sdk/codec/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/query"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/regen-network/regen-ledger/orm"
+	"github.com/regen-network/regen-ledger/testutil/testdata"
+)
+
+func TestCursor(t *testing.T) {
+	specs := map[string]struct {
+		indexKey []byte
+		rowID    orm.RowID
+	}{
+		"index key and row id": {
+			indexKey: []byte("admin-address"),
+			rowID:    orm.EncodeSequence(1),
+		},
+		"empty index key": {
+			indexKey: []byte{},
+			rowID:    orm.EncodeSequence(1),
+		},
+		"long index key": {
+			indexKey: make([]byte, 300),
+			rowID:    []byte{0xff},
+		},
+	}
+	for msg, spec := range specs {
+		t.Run(msg, func(t *testing.T) {
+			cursor := orm.EncodeCursor(spec.indexKey, spec.rowID)
+			indexKey, rowID, err := orm.DecodeCursor(cursor)
+			require.NoError(t, err)
+			assert.Equal(t, spec.indexKey, indexKey)
+			assert.Equal(t, spec.rowID, rowID)
+		})
+	}
+}
+
+func TestDecodeMalformedCursor(t *testing.T) {
+	valid := orm.EncodeCursor([]byte("admin-address"), orm.EncodeSequence(1))
+	specs := map[string][]byte{
+		"nil":                 nil,
+		"empty":               {},
+		"row id":              orm.EncodeSequence(1),
+		"marker only":         {0xff},
+		"unsupported version": {0xff, 0x2, 0x0, 0x1, 0x1},
+		"invalid length":      {0xff, 0x1, 0xff, 0xff},
+		"index key too long":  {0xff, 0x1, 0x5, 0x1},
+		"row id too long":     {0xff, 0x1, 0x0, 0x5, 0x1},
+		"empty row id":        {0xff, 0x1, 0x1, 0x1, 0x0},
+		"missing row id":      {0xff, 0x1, 0x1, 0x1},
+		"trailing bytes":      append(valid, 0x1),
+		"truncated":           valid[:len(valid)-1],
+	}
+	for msg, cursor := range specs {
+		t.Run(msg, func(t *testing.T) {
+			_, _, err := orm.DecodeCursor(cursor)
+			assert.True(t, orm.ErrArgument.Is(err), err)
+		})
+	}
+}
+
+func TestGetPaginatedWithCursor(t *testing.T) {
+	interfaceRegistry := types.NewInterfaceRegistry()
+	cdc := codec.NewProtoCodec(interfaceRegistry)
+
+	storeKey := sdk.NewKVStoreKey("test")
+	const (
+		testTablePrefix = iota
+		testTableSeqPrefix
+	)
+	tBuilder := orm.NewAutoUInt64TableBuilder(testTablePrefix, testTableSeqPrefix, storeKey, &testdata.GroupInfo{}, cdc)
+	idx := orm.NewIndex(tBuilder, GroupByAdminIndexPrefix, func(val interface{}) ([]orm.RowID, error) {
+		return []orm.RowID{[]byte(val.(*testdata.GroupInfo).Admin)}, nil
+	})
+	tb := tBuilder.Build()
+	ctx := orm.NewMockContext()
+
+	admin := sdk.AccAddress([]byte("admin-address"))
+	g1 := testdata.GroupInfo{Description: "my test 1", Admin: admin}
+	g2 := testdata.GroupInfo{Description: "my test 2", Admin: admin}
+	g3 := testdata.GroupInfo{Description: "my test 3", Admin: admin}
+	for _, g := range []testdata.GroupInfo{g1, g2, g3} {
+		_, err := tb.Create(ctx, &g)
+		require.NoError(t, err)
+	}
+
+	specs := map[string]struct {
+		key     []byte
+		exp     []testdata.GroupInfo
+		expDesc []testdata.GroupInfo
+		expErr  bool
+	}{
+		"cursor": {
+			key:     orm.EncodeCursor(admin, orm.EncodeSequence(2)),
+			exp:     []testdata.GroupInfo{g2, g3},
+			expDesc: []testdata.GroupInfo{g2, g1},
+		},
+		"row id of previous versions": {
+			key:     orm.EncodeSequence(2),
+			exp:     []testdata.GroupInfo{g2, g3},
+			expDesc: []testdata.GroupInfo{g2, g1},
+		},
+		"malformed cursor": {
+			key:    []byte{0xff, 0x1, 0x5},
+			expErr: true,
+		},
+		"cursor of other search key": {
+			key:    orm.EncodeCursor([]byte("other-admin-address"), orm.EncodeSequence(2)),
+			expErr: true,
+		},
+		"cursor with row id exceeding the index key codec": {
+			key:    orm.EncodeCursor(admin, make([]byte, 9)),
+			expErr: true,
+		},
+		"cursor with row id shorter than the index key codec": {
+			key:    orm.EncodeCursor(admin, make([]byte, 7)),
+			expErr: true,
+		},
+		"row id of previous versions shorter than the index key codec": {
+			key:    []byte{0x1},
+			expErr: true,
+		},
+	}
+	for msg, spec := range specs {
+		t.Run(msg, func(t *testing.T) {
+			pageReq := &query.PageRequest{Key: spec.key}
+			for _, c := range []struct {
+				getPaginated func(orm.HasKVStore, []byte, *query.PageRequest) (orm.Iterator, error)
+				exp          []testdata.GroupInfo
+			}{
+				{getPaginated: idx.GetPaginated, exp: spec.exp},
+				{getPaginated: idx.ReverseGetPaginated, exp: spec.expDesc},
+			} {
+				it, err := c.getPaginated(ctx, admin, pageReq)
+				if spec.expErr {
+					assert.True(t, orm.ErrArgument.Is(err), err)
+					continue
+				}
+				require.NoError(t, err)
+				var loaded []testdata.GroupInfo
+				_, err = orm.Paginate(it, pageReq, &loaded)
+				require.NoError(t, err)
+				assert.Equal(t, c.exp, loaded)
+			}
+		})
+	}
+	t.Run("row id of previous versions with cursor marker", func(t *testing.T) {
+		storeKey := sdk.NewKVStoreKey("natural")
+		tBuilder := orm.NewTableBuilder(testTablePrefix, storeKey, &testdata.GroupInfo{}, orm.Max255DynamicLengthIndexKeyCodec{}, cdc)
+		idx := orm.NewIndex(tBuilder, GroupByAdminIndexPrefix, func(val interface{}) ([]orm.RowID, error) {
+			return []orm.RowID{[]byte(val.(*testdata.GroupInfo).Admin)}, nil
+		})
+		tb := tBuilder.Build()
+		ctx := orm.NewMockContext()
+		// natural keys like addresses or hashes can start with 0xff
+		rowIDs := []orm.RowID{{0x1}, {0xff, 'a'}, {0xff, 'b'}}
+		for i, rowID := range rowIDs {
+			require.NoError(t, tb.Create(ctx, rowID, &testdata.GroupInfo{GroupId: uint64(i + 1), Admin: admin}))
+		}
+
+		pageReq := &query.PageRequest{Key: []byte{0xff, 'a'}}
+		it, err := idx.GetPaginated(ctx, admin, pageReq)
+		require.NoError(t, err)
+		var loaded []testdata.GroupInfo
+		_, err = orm.Paginate(it, pageReq, &loaded)
+		require.NoError(t, err)
+		assert.Equal(t, []testdata.GroupInfo{{GroupId: 2, Admin: admin}, {GroupId: 3, Admin: admin}}, loaded)
+
+		it, err = idx.ReverseGetPaginated(ctx, admin, pageReq)
+		require.NoError(t, err)
+		loaded = nil
+		_, err = orm.Paginate(it, pageReq, &loaded)
+		require.NoError(t, err)
+		assert.Equal(t, []testdata.GroupInfo{{GroupId: 2, Admin: admin}, {GroupId: 1, Admin: admin}}, loaded)
+
+		// a malformed cursor that is no valid rowID either is rejected
+		_, err = idx.GetPaginated(ctx, admin, &query.PageRequest{Key: append([]byte{0xff}, make([]byte, 255)...)})
+		assert.True(t, orm.ErrArgument.Is(err), err)
+	})
+	t.Run("next key continues the page", func(t *testing.T) {
+		pageReq := &query.PageRequest{Limit: 1}
+		var loaded []testdata.GroupInfo
+		for {
+			it, err := idx.GetPaginated(ctx, admin, pageReq)
+			require.NoError(t, err)
+			var page []testdata.GroupInfo
+			res, err := orm.Paginate(it, pageReq, &page)
+			require.NoError(t, err)
+			loaded = append(loaded, page...)
+			if res.NextKey == nil {
+				break
+			}
+			_, _, err = orm.DecodeCursor(res.NextKey)
+			require.NoError(t, err)
+			pageReq = &query.PageRequest{Key: res.NextKey, Limit: 1}
+		}
+		assert.Equal(t, []testdata.GroupInfo{g1, g2, g3}, loaded)
+	})
+}
@@ -1,7 +1,11 @@
 package orm_test
 
 import (
+	"strconv"
+
+	"github.com/armon/go-metrics"
 	"github.com/cosmos/cosmos-sdk/codec"
+	"github.com/cosmos/cosmos-sdk/telemetry"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 
 	"github.com/regen-network/regen-ledger/orm"
@@ -51,3 +55,25 @@ func NewGroupKeeper(storeKey sdk.StoreKey, cdc codec.Marshaler) GroupKeeper {
 
 	return k
 }
+
+// ExampleSetScanHook records the number of rows that every table and index scan reads with the
+// cosmos-sdk telemetry. Wrapping a query result with InstrumentedIterator records the number of
+// returned rows the same way.
+func ExampleSetScanHook() {
+	orm.SetScanHook(func(scan orm.ScanInfo) (func(orm.RowID, error), func(int)) {
+		labels := []metrics.Label{
+			telemetry.NewLabel("store", scan.StoreKey),
+			telemetry.NewLabel("prefix", strconv.Itoa(int(scan.Prefix))),
+			telemetry.NewLabel("index", strconv.FormatBool(scan.Index)),
+		}
+		onNext := func(_ orm.RowID, err error) {
+			if err != nil && !orm.IsIteratorDone(err) {
+				telemetry.IncrCounterWithLabels([]string{"orm", "scan", "errors"}, 1, labels)
+			}
+		}
+		onClose := func(scanned int) {
+			telemetry.IncrCounterWithLabels([]string{"orm", "scan", "rows"}, float32(scanned), labels)
+		}
+		return onNext, onClose
+	})
+}
@@ -0,0 +1,214 @@
+package orm
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// maxExplainDepth limits the plan of `Explain` for iterators that reference themselves.
+const maxExplainDepth = 32
+
+// explainer is implemented by the iterators of this package to describe themselves in the plan of `Explain`.
+type explainer interface {
+	// explain returns the description of the iterator and the parent iterators that it reads from.
+	explain() (string, []Iterator)
+}
+
+// Explain returns a human readable plan of the iterator chain, comparable to a SQL EXPLAIN, for debugging
+// slow queries in tests. Every line describes an iterator and is followed by the iterators that it reads
+// from with a deeper indentation, down to the table and index scans with their store keys and prefixes:
+//
+//	Limit remaining=10
+//	  Filter
+//	    IndexScan store=group prefix=0x02
+//
+// Other implementations of the Iterator are described by their type and the iterators in their exported
+// fields. Explain does not read from the iterator and can be called on any iterator, also after Close.
+// The format is not stable and must not be parsed.
+func Explain(it Iterator) string {
+	var b strings.Builder
+	explainInto(&b, it, 0)
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+func explainInto(b *strings.Builder, it Iterator, depth int) {
+	indent := strings.Repeat("  ", depth)
+	if depth == maxExplainDepth {
+		b.WriteString(indent + "...\n")
+		return
+	}
+	if it == nil || isNilPointer(it) {
+		b.WriteString(indent + "nil\n")
+		return
+	}
+	description, parents := explainIterator(it)
+	b.WriteString(indent + description + "\n")
+	for _, p := range parents {
+		explainInto(b, p, depth+1)
+	}
+}
+
+// explainIterator describes an iterator of this package with its explain method and other iterators by
+// their type and the iterators in their exported fields.
+func explainIterator(it Iterator) (string, []Iterator) {
+	if e, ok := it.(explainer); ok {
+		return e.explain()
+	}
+	v := reflect.ValueOf(it)
+	for v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	var parents []Iterator
+	if v.Kind() == reflect.Struct {
+		for n := 0; n < v.NumField(); n++ {
+			// PkgPath is empty for exported fields
+			if v.Type().Field(n).PkgPath != "" {
+				continue
+			}
+			parents = append(parents, iteratorsOf(v.Field(n))...)
+		}
+	}
+	return fmt.Sprintf("%T", it), parents
+}
+
+var iteratorType = reflect.TypeOf((*Iterator)(nil)).Elem()
+
+// iteratorsOf returns the iterator of a field value or the iterators of a slice.
+func iteratorsOf(v reflect.Value) []Iterator {
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		if elem := v.Type().Elem(); elem.Kind() != reflect.Interface && !elem.Implements(iteratorType) {
+			return nil
+		}
+		var res []Iterator
+		for n := 0; n < v.Len(); n++ {
+			res = append(res, iteratorsOf(v.Index(n))...)
+		}
+		return res
+	case reflect.Interface, reflect.Ptr:
+		if v.IsNil() {
+			return nil
+		}
+	}
+	if it, ok := v.Interface().(Iterator); ok {
+		return []Iterator{it}
+	}
+	return nil
+}
+
+func isNilPointer(x interface{}) bool {
+	v := reflect.ValueOf(x)
+	return v.Kind() == reflect.Ptr && v.IsNil()
+}
+
+func explainScan(kind string, scan ScanInfo) string {
+	return fmt.Sprintf("%s store=%s prefix=0x%02x", kind, scan.StoreKey, scan.Prefix)
+}
+
+func (i *typeSafeIterator) explain() (string, []Iterator) {
+	return explainScan("TableScan", i.scan), nil
+}
+
+func (i *snapshotIterator) explain() (string, []Iterator) {
+	return fmt.Sprintf("%s rows=%d", explainScan("TableSnapshotScan", i.scan), len(i.all)), nil
+}
+
+func (i *indexIterator) explain() (string, []Iterator) {
+	description := explainScan("IndexScan", i.scan)
+	if i.cursors {
+		description += " paginated"
+	}
+	if i.total != nil {
+		description += " count-total"
+	}
+	return description, nil
+}
+
+func (i *sliceIterator) explain() (string, []Iterator) {
+	return fmt.Sprintf("Slice rows=%d", len(i.rowIDs)), nil
+}
+
+func (i IteratorFunc) explain() (string, []Iterator) {
+	return "Func", nil
+}
+
+func (i *closeGuardIterator) explain() (string, []Iterator) {
+	return "CloseGuard", []Iterator{i.parentIterator}
+}
+
+func (i *kvStoreIterator) explain() (string, []Iterator) {
+	return fmt.Sprintf("KVStoreIterator strip-prefix=%d", i.stripPrefix), nil
+}
+
+func (i *bufferedIterator) explain() (string, []Iterator) {
+	return fmt.Sprintf("Buffered batch-size=%d", i.batchSize), []Iterator{i.parentIterator}
+}
+
+func (i *LimitedIterator) explain() (string, []Iterator) {
+	var remaining int
+	if i.state != nil {
+		remaining = i.state.remainingCount
+	}
+	return fmt.Sprintf("Limit remaining=%d", remaining), []Iterator{i.parentIterator}
+}
+
+func (i *ctxIterator) explain() (string, []Iterator) {
+	return "Context", []Iterator{i.parentIterator}
+}
+
+func (i *PeekableIterator) explain() (string, []Iterator) {
+	return "Peek", []Iterator{i.parentIterator}
+}
+
+func (i *skippedIterator) explain() (string, []Iterator) {
+	return fmt.Sprintf("Skip remaining=%d", i.remainingSkip), []Iterator{i.parentIterator}
+}
+
+func (i *filteredIterator) explain() (string, []Iterator) {
+	return "Filter", []Iterator{i.parentIterator}
+}
+
+func (i *takeWhileIterator) explain() (string, []Iterator) {
+	return "TakeWhile", []Iterator{i.parentIterator}
+}
+
+func (i *dropWhileIterator) explain() (string, []Iterator) {
+	return "DropWhile", []Iterator{i.parentIterator}
+}
+
+func (i *mappedIterator) explain() (string, []Iterator) {
+	return "Map", []Iterator{i.parentIterator}
+}
+
+func (i *distinctIterator) explain() (string, []Iterator) {
+	return fmt.Sprintf("Distinct max-row-ids=%d", i.maxRowIDs), []Iterator{i.parentIterator}
+}
+
+func (i *chainedIterator) explain() (string, []Iterator) {
+	return "Chain", i.iterators
+}
+
+func (i *mergedIterator) explain() (string, []Iterator) {
+	return "Merge", i.iterators
+}
+
+func (i *intersectedIterator) explain() (string, []Iterator) {
+	return "Intersect", []Iterator{i.a, i.b}
+}
+
+func (i *decodingIterator) explain() (string, []Iterator) {
+	return "Decode", []Iterator{i.RawIterator}
+}
+
+func (i *joinIterator) explain() (string, []Iterator) {
+	return fmt.Sprintf("Join parents store=%s prefix=0x%02x", i.parents.storeKey.Name(), i.parents.prefix), []Iterator{i.parentIterator}
+}
+
+func (i *instrumentedIterator) explain() (string, []Iterator) {
+	return "Instrumented", []Iterator{i.parentIterator}
+}
+
+func (i *transactionalIterator) explain() (string, []Iterator) {
+	return fmt.Sprintf("Transactional snapshots=%d", len(i.snapshots)), []Iterator{i.parentIterator}
+}
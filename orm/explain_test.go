@@ -0,0 +1,146 @@
+package orm_test
+
+import (
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	"github.com/cosmos/cosmos-sdk/codec/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/query"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/regen-network/regen-ledger/orm"
+	"github.com/regen-network/regen-ledger/testutil/testdata"
+)
+
+// wrappingIterator is an Iterator of another package with a parent in an exported field.
+type wrappingIterator struct {
+	Parent  orm.Iterator
+	Others  []orm.Iterator
+	private orm.Iterator
+}
+
+func (w wrappingIterator) LoadNext(dest codec.ProtoMarshaler) (orm.RowID, error) {
+	return w.Parent.LoadNext(dest)
+}
+
+func (w wrappingIterator) Close() error {
+	return w.Parent.Close()
+}
+
+func TestExplain(t *testing.T) {
+	interfaceRegistry := types.NewInterfaceRegistry()
+	cdc := codec.NewProtoCodec(interfaceRegistry)
+
+	storeKey := sdk.NewKVStoreKey("test")
+	const (
+		testTablePrefix = iota
+		testTableSeqPrefix
+	)
+	tBuilder := orm.NewAutoUInt64TableBuilder(testTablePrefix, testTableSeqPrefix, storeKey, &testdata.GroupInfo{}, cdc)
+	idx := orm.NewIndex(tBuilder, GroupByAdminIndexPrefix, func(val interface{}) ([]orm.RowID, error) {
+		return []orm.RowID{[]byte(val.(*testdata.GroupInfo).Admin)}, nil
+	})
+	tb := tBuilder.Build()
+
+	ctx := orm.NewMockContext()
+	admin := sdk.AccAddress([]byte("admin-address"))
+	_, err := tb.Create(ctx, &testdata.GroupInfo{Description: "my test 1", Admin: admin})
+	require.NoError(t, err)
+
+	specs := map[string]struct {
+		src func() (orm.Iterator, error)
+		exp string
+	}{
+		"table scan": {
+			src: func() (orm.Iterator, error) { return tb.PrefixScan(ctx, 1, 100) },
+			exp: "TableScan store=test prefix=0x00",
+		},
+		"table scan with opts": {
+			src: func() (orm.Iterator, error) {
+				return tb.PrefixScanWithOpts(ctx, 1, 100, orm.ScanOpts{BatchSize: 10})
+			},
+			exp: "Buffered batch-size=10\n  TableScan store=test prefix=0x00",
+		},
+		"table snapshot scan": {
+			src: func() (orm.Iterator, error) {
+				return tb.PrefixScanWithOpts(ctx, 1, 100, orm.ScanOpts{Snapshot: true})
+			},
+			exp: "TableSnapshotScan store=test prefix=0x00 rows=1",
+		},
+		"filtered index scan with limit": {
+			src: func() (orm.Iterator, error) {
+				it, err := idx.Get(ctx, admin)
+				return orm.LimitIterator(orm.FilterIterator(it, func(codec.ProtoMarshaler) bool { return true }), 10), err
+			},
+			exp: "Limit remaining=10\n  Filter\n    IndexScan store=test prefix=0x02",
+		},
+		"paginated index scan": {
+			src: func() (orm.Iterator, error) {
+				return idx.GetPaginated(ctx, admin, &query.PageRequest{Key: orm.EncodeCursor(admin, orm.EncodeSequence(1)), CountTotal: true})
+			},
+			exp: "IndexScan store=test prefix=0x02 paginated count-total",
+		},
+		"intersection": {
+			src: func() (orm.Iterator, error) {
+				a, err := tb.PrefixScan(ctx, 1, 100)
+				if err != nil {
+					return nil, err
+				}
+				b, err := idx.Get(ctx, admin)
+				return orm.IntersectIterator(a, orm.DistinctIterator(b, 5)), err
+			},
+			exp: "Intersect\n  TableScan store=test prefix=0x00\n  Distinct max-row-ids=5\n    IndexScan store=test prefix=0x02",
+		},
+		"chain": {
+			src: func() (orm.Iterator, error) {
+				return orm.ChainIterator(orm.NewSliceIterator(nil, nil), orm.NewInvalidIterator()), nil
+			},
+			exp: "Chain\n  Slice rows=0\n  CloseGuard\n    Func",
+		},
+		"other implementation": {
+			src: func() (orm.Iterator, error) {
+				it, err := tb.PrefixScan(ctx, 1, 100)
+				return &wrappingIterator{
+					Parent:  orm.PeekIterator(it),
+					Others:  []orm.Iterator{orm.NewSliceIterator(nil, nil), nil},
+					private: orm.NewSliceIterator(nil, nil),
+				}, err
+			},
+			exp: "*orm_test.wrappingIterator\n  Peek\n    TableScan store=test prefix=0x00\n  Slice rows=0",
+		},
+		"function": {
+			src: func() (orm.Iterator, error) {
+				return orm.IteratorFunc(func(codec.ProtoMarshaler) (orm.RowID, error) { return nil, orm.ErrIteratorDone }), nil
+			},
+			exp: "Func",
+		},
+		"nil": {
+			src: func() (orm.Iterator, error) { return nil, nil },
+			exp: "nil",
+		},
+		"nil pointer": {
+			src: func() (orm.Iterator, error) { return (*orm.PeekableIterator)(nil), nil },
+			exp: "nil",
+		},
+	}
+	for msg, spec := range specs {
+		t.Run(msg, func(t *testing.T) {
+			it, err := spec.src()
+			require.NoError(t, err)
+			assert.Equal(t, spec.exp, orm.Explain(it))
+			if it != nil && spec.exp != "nil" {
+				// the iterator is not read by Explain and can be explained after close, too
+				require.NoError(t, it.Close())
+				assert.NotEmpty(t, orm.Explain(it))
+			}
+		})
+	}
+
+	t.Run("self reference", func(t *testing.T) {
+		w := &wrappingIterator{}
+		w.Parent = w
+		assert.Contains(t, orm.Explain(w), "...")
+	})
+}
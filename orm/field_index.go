@@ -0,0 +1,114 @@
+package orm
+
+import (
+	"encoding/binary"
+
+	"github.com/cosmos/cosmos-sdk/types/query"
+)
+
+// EncodeBool encodes a bool as index key of one byte: 0x00 for false and 0x01 for true.
+func EncodeBool(v bool) []byte {
+	if v {
+		return []byte{1}
+	}
+	return []byte{0}
+}
+
+// EncodeEnum encodes the value of a proto enum as index key with a signed varint, so that the small
+// values of an enum take one byte and negative values are supported.
+func EncodeEnum(v int32) []byte {
+	bz := make([]byte, binary.MaxVarintLen32)
+	return bz[:binary.PutVarint(bz, int64(v))]
+}
+
+// BoolIndexer returns an IndexerFunc with one key for the bool field of the source object that the
+// fieldFn returns, see `EncodeBool`.
+func BoolIndexer(fieldFn func(value interface{}) bool) IndexerFunc {
+	if fieldFn == nil {
+		panic("field func must not be nil")
+	}
+	return func(value interface{}) ([]RowID, error) {
+		return []RowID{EncodeBool(fieldFn(value))}, nil
+	}
+}
+
+// EnumIndexer returns an IndexerFunc with one key for the enum field of the source object that the
+// fieldFn returns, see `EncodeEnum`.
+func EnumIndexer(fieldFn func(value interface{}) int32) IndexerFunc {
+	if fieldFn == nil {
+		panic("field func must not be nil")
+	}
+	return func(value interface{}) ([]RowID, error) {
+		return []RowID{EncodeEnum(fieldFn(value))}, nil
+	}
+}
+
+// BoolIndex is a typed index of a bool field, like a disabled flag.
+type BoolIndex struct {
+	multiKeyIndex MultiKeyIndex
+}
+
+// NewBoolIndex creates a typed secondary index of the bool field that the fieldFn returns.
+func NewBoolIndex(builder Indexable, prefix byte, fieldFn func(value interface{}) bool) BoolIndex {
+	return BoolIndex{
+		multiKeyIndex: NewIndex(builder, prefix, BoolIndexer(fieldFn)),
+	}
+}
+
+// WithMaxCountTotal returns a copy of the index that counts at most max elements for the total of a
+// page requested by key. See `MultiKeyIndex.WithMaxCountTotal`.
+func (i BoolIndex) WithMaxCountTotal(max uint64) BoolIndex {
+	i.multiKeyIndex = i.multiKeyIndex.WithMaxCountTotal(max)
+	return i
+}
+
+// ScanEqual returns an Iterator over all objects with the given value of the field in ascending order
+// of their RowIDs.
+// Iterator must be closed by caller.
+//
+// WARNING: The use of a ScanEqual can be very expensive in terms of Gas. Please make sure you do not expose
+// this as an endpoint to the public without further limits. See `LimitIterator`
+func (i BoolIndex) ScanEqual(ctx HasKVStore, value bool) (Iterator, error) {
+	return i.multiKeyIndex.Get(ctx, EncodeBool(value))
+}
+
+// GetPaginated creates an iterator over all objects with the given value of the field
+// starting from pageRequest.Key if provided. See `MultiKeyIndex.GetPaginated` for the pageRequest.
+func (i BoolIndex) GetPaginated(ctx HasKVStore, value bool, pageRequest *query.PageRequest) (Iterator, error) {
+	return i.multiKeyIndex.GetPaginated(ctx, EncodeBool(value), pageRequest)
+}
+
+// EnumIndex is a typed index of a proto enum field, like a status.
+type EnumIndex struct {
+	multiKeyIndex MultiKeyIndex
+}
+
+// NewEnumIndex creates a typed secondary index of the enum field that the fieldFn returns.
+func NewEnumIndex(builder Indexable, prefix byte, fieldFn func(value interface{}) int32) EnumIndex {
+	return EnumIndex{
+		multiKeyIndex: NewIndex(builder, prefix, EnumIndexer(fieldFn)),
+	}
+}
+
+// WithMaxCountTotal returns a copy of the index that counts at most max elements for the total of a
+// page requested by key. See `MultiKeyIndex.WithMaxCountTotal`.
+func (i EnumIndex) WithMaxCountTotal(max uint64) EnumIndex {
+	i.multiKeyIndex = i.multiKeyIndex.WithMaxCountTotal(max)
+	return i
+}
+
+// ScanEqual returns an Iterator over all objects with the given value of the field in ascending order
+// of their RowIDs.
+// Iterator must be closed by caller.
+//
+// WARNING: The use of a ScanEqual can be very expensive in terms of Gas. Please make sure you do not expose
+// this as an endpoint to the public without further limits. See `LimitIterator`
+func (i EnumIndex) ScanEqual(ctx HasKVStore, value int32) (Iterator, error) {
+	return i.multiKeyIndex.Get(ctx, EncodeEnum(value))
+}
+
+// GetPaginated creates an iterator over all objects with the given value of the field
+// starting from pageRequest.Key if provided. See `MultiKeyIndex.GetPaginated` for the pageRequest.
+func (i EnumIndex) GetPaginated(ctx HasKVStore, value int32, pageRequest *query.PageRequest) (Iterator, error) {
+	return i.multiKeyIndex.GetPaginated(ctx, EncodeEnum(value), pageRequest)
+}
@@ -0,0 +1,173 @@
+package orm_test
+
+import (
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	"github.com/cosmos/cosmos-sdk/codec/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/query"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/regen-network/regen-ledger/orm"
+	"github.com/regen-network/regen-ledger/testutil/testdata"
+)
+
+func TestFieldEncoding(t *testing.T) {
+	assert.Equal(t, []byte{0}, orm.EncodeBool(false))
+	assert.Equal(t, []byte{1}, orm.EncodeBool(true))
+
+	specs := map[int32][]byte{
+		0:    {0x00},
+		1:    {0x02},
+		-1:   {0x01},
+		63:   {0x7e},
+		64:   {0x80, 0x01},
+		-128: {0xff, 0x01},
+	}
+	for v, exp := range specs {
+		assert.Equal(t, exp, orm.EncodeEnum(v), "value %d", v)
+	}
+}
+
+func TestBoolIndex(t *testing.T) {
+	interfaceRegistry := types.NewInterfaceRegistry()
+	cdc := codec.NewProtoCodec(interfaceRegistry)
+
+	storeKey := sdk.NewKVStoreKey("test")
+	const (
+		testTablePrefix = iota
+		testTableSeqPrefix
+	)
+	tBuilder := orm.NewAutoUInt64TableBuilder(testTablePrefix, testTableSeqPrefix, storeKey, &testdata.GroupInfo{}, cdc)
+	// a group is disabled by its description for the test
+	disabledIdx := orm.NewBoolIndex(tBuilder, GroupByAdminIndexPrefix, func(val interface{}) bool {
+		return val.(*testdata.GroupInfo).Description == "disabled"
+	})
+	tb := tBuilder.Build()
+
+	ctx := orm.NewMockContext()
+	groups := []*testdata.GroupInfo{
+		{GroupId: 1, Description: "enabled"},
+		{GroupId: 2, Description: "disabled"},
+		{GroupId: 3, Description: "enabled"},
+	}
+	for _, g := range groups {
+		_, err := tb.Create(ctx, g)
+		require.NoError(t, err)
+	}
+
+	scanEqual := func(t *testing.T, value bool) []testdata.GroupInfo {
+		it, err := disabledIdx.ScanEqual(ctx, value)
+		require.NoError(t, err)
+		var loaded []testdata.GroupInfo
+		_, err = orm.ReadAll(it, &loaded)
+		require.NoError(t, err)
+		return loaded
+	}
+	assert.Equal(t, []testdata.GroupInfo{*groups[0], *groups[2]}, scanEqual(t, false))
+	assert.Equal(t, []testdata.GroupInfo{*groups[1]}, scanEqual(t, true))
+
+	// flip the bool of the first group with an update
+	updated := testdata.GroupInfo{GroupId: 1, Description: "disabled"}
+	require.NoError(t, tb.Save(ctx, 1, &updated))
+	assert.Equal(t, []testdata.GroupInfo{*groups[2]}, scanEqual(t, false))
+	assert.Equal(t, []testdata.GroupInfo{updated, *groups[1]}, scanEqual(t, true))
+
+	// and back
+	require.NoError(t, tb.Save(ctx, 1, groups[0]))
+	assert.Equal(t, []testdata.GroupInfo{*groups[0], *groups[2]}, scanEqual(t, false))
+	assert.Equal(t, []testdata.GroupInfo{*groups[1]}, scanEqual(t, true))
+
+	// an update without a change of the bool keeps the entry
+	renamed := testdata.GroupInfo{GroupId: 3, Description: "enabled", Admin: []byte("admin")}
+	require.NoError(t, tb.Save(ctx, 3, &renamed))
+	assert.Equal(t, []testdata.GroupInfo{*groups[0], renamed}, scanEqual(t, false))
+
+	// delete removes the entry
+	require.NoError(t, tb.Delete(ctx, 2))
+	assert.Equal(t, []testdata.GroupInfo{}, scanEqual(t, true))
+
+	// paginated
+	pageReq := &query.PageRequest{Limit: 1}
+	it, err := disabledIdx.GetPaginated(ctx, false, pageReq)
+	require.NoError(t, err)
+	var loaded []testdata.GroupInfo
+	res, err := orm.Paginate(it, pageReq, &loaded)
+	require.NoError(t, err)
+	assert.Equal(t, []testdata.GroupInfo{*groups[0]}, loaded)
+	it, err = disabledIdx.GetPaginated(ctx, false, &query.PageRequest{Key: res.NextKey})
+	require.NoError(t, err)
+	loaded = nil
+	_, err = orm.ReadAll(it, &loaded)
+	require.NoError(t, err)
+	assert.Equal(t, []testdata.GroupInfo{renamed}, loaded)
+}
+
+func TestEnumIndex(t *testing.T) {
+	interfaceRegistry := types.NewInterfaceRegistry()
+	cdc := codec.NewProtoCodec(interfaceRegistry)
+
+	storeKey := sdk.NewKVStoreKey("test")
+	const (
+		testTablePrefix = iota
+		testTableSeqPrefix
+	)
+	// the description is the name of the status of a group for the test
+	statuses := map[string]int32{
+		"unspecified": 0,
+		"proposed":    1,
+		"closed":      200,
+		"aborted":     -1,
+	}
+	tBuilder := orm.NewAutoUInt64TableBuilder(testTablePrefix, testTableSeqPrefix, storeKey, &testdata.GroupInfo{}, cdc)
+	byStatusIdx := orm.NewEnumIndex(tBuilder, GroupByAdminIndexPrefix, func(val interface{}) int32 {
+		return statuses[val.(*testdata.GroupInfo).Description]
+	})
+	tb := tBuilder.Build()
+
+	ctx := orm.NewMockContext()
+	groups := []*testdata.GroupInfo{
+		{GroupId: 1, Description: "proposed"},
+		{GroupId: 2, Description: "closed"},
+		{GroupId: 3, Description: "aborted"},
+		{GroupId: 4, Description: "proposed"},
+		{GroupId: 5, Description: "unspecified"},
+	}
+	for _, g := range groups {
+		_, err := tb.Create(ctx, g)
+		require.NoError(t, err)
+	}
+
+	scanEqual := func(t *testing.T, value int32) []testdata.GroupInfo {
+		it, err := byStatusIdx.ScanEqual(ctx, value)
+		require.NoError(t, err)
+		var loaded []testdata.GroupInfo
+		_, err = orm.ReadAll(it, &loaded)
+		require.NoError(t, err)
+		return loaded
+	}
+	specs := map[string]struct {
+		value int32
+		exp   []testdata.GroupInfo
+	}{
+		"zero":            {value: 0, exp: []testdata.GroupInfo{*groups[4]}},
+		"multiple rows":   {value: 1, exp: []testdata.GroupInfo{*groups[0], *groups[3]}},
+		"multi byte key":  {value: 200, exp: []testdata.GroupInfo{*groups[1]}},
+		"negative":        {value: -1, exp: []testdata.GroupInfo{*groups[2]}},
+		"no match":        {value: 2, exp: []testdata.GroupInfo{}},
+		"no prefix match": {value: 72, exp: []testdata.GroupInfo{}},
+	}
+	for msg, spec := range specs {
+		t.Run(msg, func(t *testing.T) {
+			assert.Equal(t, spec.exp, scanEqual(t, spec.value))
+		})
+	}
+
+	// moving a group to another status updates both scans
+	closed := testdata.GroupInfo{GroupId: 1, Description: "closed"}
+	require.NoError(t, tb.Save(ctx, 1, &closed))
+	assert.Equal(t, []testdata.GroupInfo{*groups[3]}, scanEqual(t, 1))
+	assert.Equal(t, []testdata.GroupInfo{closed, *groups[1]}, scanEqual(t, 200))
+}
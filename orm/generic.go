@@ -0,0 +1,108 @@
+package orm
+
+import (
+	"context"
+	"math"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	"github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/cosmos/cosmos-sdk/types/query"
+)
+
+// Collect consumes all values for the iterator and returns them in a new slice together with their RowIDs.
+// The slice can be empty when the iterator does not return any values but not nil. The iterator
+// is closed afterwards.
+// T is the model type and must implement codec.ProtoMarshaler with a pointer receiver, which is
+// checked by the compiler.
+// Example:
+// 			loaded, rowIDs, err := Collect[testdata.GroupInfo](it)
+//			require.NoError(t, err)
+//
+func Collect[T any, PT interface {
+	*T
+	codec.ProtoMarshaler
+}](it Iterator) ([]T, []RowID, error) {
+	if it == nil {
+		return nil, nil, errors.Wrap(ErrArgument, "iterator must not be nil")
+	}
+	defer it.Close()
+
+	result := make([]T, 0)
+	var rowIDs []RowID
+	for {
+		var obj T
+		binKey, err := it.LoadNext(PT(&obj))
+		switch {
+		case err == nil:
+			result = append(result, obj)
+		case IsIteratorDone(err):
+			return result, rowIDs, nil
+		default:
+			return nil, nil, err
+		}
+		rowIDs = append(rowIDs, binKey)
+	}
+}
+
+// PaginateT does pagination like Paginate but returns the elements of the page in a new slice of type T.
+// The models are created with newT, so that no reflection is needed.
+// T is the model type, commonly a pointer to a proto message.
+// Example:
+//			members, res, err := PaginateT(it, pageRequest, func() *testdata.GroupMember { return &testdata.GroupMember{} })
+//
+// This function will call it.Close().
+func PaginateT[T codec.ProtoMarshaler](it Iterator, pageRequest *query.PageRequest, newT func() T) ([]T, *query.PageResponse, error) {
+	c := &typedCollector[T]{newT: newT}
+	_, res, err := paginateInto(context.Background(), it, pageRequest, c, PaginateOpts{}, false)
+	if err != nil {
+		return nil, nil, err
+	}
+	return c.result, res, nil
+}
+
+// ReadAllT consumes all values of the iterator like ReadAll and returns them in a new slice of type T
+// together with their RowIDs. The models are created with newT, so that no reflection is needed.
+// The slice can be empty when the iterator does not return any values but not nil. The iterator
+// is closed afterwards.
+// T is the model type, commonly a pointer to a proto message.
+func ReadAllT[T codec.ProtoMarshaler](it Iterator, newT func() T) ([]RowID, []T, error) {
+	c := &typedCollector[T]{newT: newT}
+	rowIDs, err := readAllInto(it, c, math.MaxUint64)
+	if err != nil {
+		return nil, nil, err
+	}
+	return rowIDs, c.result, nil
+}
+
+// typedCollector collects models of type T into a slice.
+type typedCollector[T codec.ProtoMarshaler] struct {
+	newT         func() T
+	result       []T
+	scratchModel codec.ProtoMarshaler
+}
+
+func (c *typedCollector[T]) init() error {
+	if c.newT == nil {
+		return errors.Wrap(ErrArgument, "model constructor must not be nil")
+	}
+	c.result = make([]T, 0)
+	return nil
+}
+
+func (c *typedCollector[T]) newModel() codec.ProtoMarshaler {
+	return c.newT()
+}
+
+func (c *typedCollector[T]) scratch() codec.ProtoMarshaler {
+	if c.scratchModel == nil {
+		c.scratchModel = c.newT()
+	}
+	c.scratchModel.Reset()
+	return c.scratchModel
+}
+
+func (c *typedCollector[T]) add(model codec.ProtoMarshaler) {
+	c.result = append(c.result, model.(T))
+}
+
+func (c *typedCollector[T]) finish() {}
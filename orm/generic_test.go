@@ -0,0 +1,186 @@
+package orm_test
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	"github.com/cosmos/cosmos-sdk/codec/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/cosmos/cosmos-sdk/types/query"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/regen-network/regen-ledger/orm"
+	"github.com/regen-network/regen-ledger/testutil/testdata"
+)
+
+func TestCollect(t *testing.T) {
+	specs := map[string]struct {
+		srcIT     orm.Iterator
+		expErr    *errors.Error
+		expIDs    []orm.RowID
+		expResult []testdata.GroupInfo
+	}{
+		"all good": {
+			srcIT:     mockIter(orm.EncodeSequence(1), &testdata.GroupInfo{Description: "test"}),
+			expIDs:    []orm.RowID{orm.EncodeSequence(1)},
+			expResult: []testdata.GroupInfo{{Description: "test"}},
+		},
+		"empty iterator": {
+			srcIT:     orm.NewSingleValueIterator(orm.EncodeSequence(1), nil),
+			expResult: []testdata.GroupInfo{},
+		},
+		"iterator is nil": {
+			srcIT:  nil,
+			expErr: orm.ErrArgument,
+		},
+		"error on loadNext is returned": {
+			srcIT:  orm.NewInvalidIterator(),
+			expErr: orm.ErrIteratorInvalid,
+		},
+	}
+	for msg, spec := range specs {
+		t.Run(msg, func(t *testing.T) {
+			loaded, ids, err := orm.Collect[testdata.GroupInfo](spec.srcIT)
+			require.True(t, spec.expErr.Is(err), "expected %s but got %s", spec.expErr, err)
+			assert.Equal(t, spec.expIDs, ids)
+			assert.Equal(t, spec.expResult, loaded)
+		})
+	}
+}
+
+func TestReadAllT(t *testing.T) {
+	newGroupInfo := func() *testdata.GroupInfo { return &testdata.GroupInfo{} }
+	specs := map[string]struct {
+		srcIT     orm.Iterator
+		newT      func() *testdata.GroupInfo
+		expErr    *errors.Error
+		expIDs    []orm.RowID
+		expResult []*testdata.GroupInfo
+	}{
+		"all good": {
+			srcIT:     mockIter(orm.EncodeSequence(1), &testdata.GroupInfo{Description: "test"}),
+			newT:      newGroupInfo,
+			expIDs:    []orm.RowID{orm.EncodeSequence(1)},
+			expResult: []*testdata.GroupInfo{{Description: "test"}},
+		},
+		"empty iterator": {
+			srcIT:     orm.NewSingleValueIterator(orm.EncodeSequence(1), nil),
+			newT:      newGroupInfo,
+			expResult: []*testdata.GroupInfo{},
+		},
+		"iterator is nil": {
+			srcIT:  nil,
+			newT:   newGroupInfo,
+			expErr: orm.ErrArgument,
+		},
+		"constructor is nil": {
+			srcIT:  mockIter(orm.EncodeSequence(1), &testdata.GroupInfo{Description: "test"}),
+			expErr: orm.ErrArgument,
+		},
+		"error on loadNext is returned": {
+			srcIT:  orm.NewInvalidIterator(),
+			newT:   newGroupInfo,
+			expErr: orm.ErrIteratorInvalid,
+		},
+	}
+	for msg, spec := range specs {
+		t.Run(msg, func(t *testing.T) {
+			ids, loaded, err := orm.ReadAllT(spec.srcIT, spec.newT)
+			require.True(t, spec.expErr.Is(err), "expected %s but got %s", spec.expErr, err)
+			assert.Equal(t, spec.expIDs, ids)
+			assert.Equal(t, spec.expResult, loaded)
+		})
+	}
+}
+
+func TestPaginateT(t *testing.T) {
+	tb, ctx := newGenericTestTable(t, 5)
+	newGroupInfo := func() *testdata.GroupInfo { return &testdata.GroupInfo{} }
+
+	it, err := tb.PrefixScan(ctx, 1, 100)
+	require.NoError(t, err)
+	loaded, res, err := orm.PaginateT(it, &query.PageRequest{Limit: 2, CountTotal: true}, newGroupInfo)
+	require.NoError(t, err)
+	require.Len(t, loaded, 2)
+	assert.Equal(t, "group 1", loaded[0].Description)
+	assert.Equal(t, "group 2", loaded[1].Description)
+	assert.Equal(t, orm.EncodeSequence(3), res.NextKey)
+	assert.Equal(t, uint64(5), res.Total)
+
+	// same page as the reflection based Paginate
+	it, err = tb.PrefixScan(ctx, 1, 100)
+	require.NoError(t, err)
+	var expLoaded []*testdata.GroupInfo
+	expRes, err := orm.Paginate(it, &query.PageRequest{Limit: 2, CountTotal: true}, &expLoaded)
+	require.NoError(t, err)
+	assert.Equal(t, expLoaded, loaded)
+	assert.Equal(t, expRes, res)
+
+	t.Run("with key", func(t *testing.T) {
+		it, err := tb.PrefixScan(ctx, 3, 100)
+		require.NoError(t, err)
+		loaded, res, err := orm.PaginateT(it, &query.PageRequest{Key: orm.EncodeSequence(3), Limit: 5}, newGroupInfo)
+		require.NoError(t, err)
+		require.Len(t, loaded, 3)
+		assert.Equal(t, "group 3", loaded[0].Description)
+		assert.Nil(t, res.NextKey)
+	})
+	t.Run("constructor is nil", func(t *testing.T) {
+		it, err := tb.PrefixScan(ctx, 1, 100)
+		require.NoError(t, err)
+		_, _, err = orm.PaginateT[*testdata.GroupInfo](it, nil, nil)
+		assert.True(t, orm.ErrArgument.Is(err), err)
+	})
+	t.Run("iterator is nil", func(t *testing.T) {
+		_, _, err := orm.PaginateT(nil, nil, newGroupInfo)
+		assert.True(t, orm.ErrArgument.Is(err), err)
+	})
+}
+
+func BenchmarkPaginate(b *testing.B) {
+	tb, ctx := newGenericTestTable(b, 1000)
+	pageRequest := &query.PageRequest{Limit: 100}
+
+	b.Run("reflection", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			it, err := tb.PrefixScan(ctx, 1, math.MaxUint64)
+			require.NoError(b, err)
+			var loaded []*testdata.GroupInfo
+			_, err = orm.Paginate(it, pageRequest, &loaded)
+			require.NoError(b, err)
+		}
+	})
+	b.Run("generic", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			it, err := tb.PrefixScan(ctx, 1, math.MaxUint64)
+			require.NoError(b, err)
+			_, _, err = orm.PaginateT(it, pageRequest, func() *testdata.GroupInfo { return &testdata.GroupInfo{} })
+			require.NoError(b, err)
+		}
+	})
+}
+
+// newGenericTestTable returns a table with n groups with the descriptions "group 1" to "group n".
+func newGenericTestTable(t require.TestingT, n int) (orm.AutoUInt64Table, orm.HasKVStore) {
+	interfaceRegistry := types.NewInterfaceRegistry()
+	cdc := codec.NewProtoCodec(interfaceRegistry)
+
+	storeKey := sdk.NewKVStoreKey("test")
+	const (
+		testTablePrefix = iota
+		testTableSeqPrefix
+	)
+	tb := orm.NewAutoUInt64TableBuilder(testTablePrefix, testTableSeqPrefix, storeKey, &testdata.GroupInfo{}, cdc).Build()
+	ctx := orm.NewMockContext()
+	for i := 1; i <= n; i++ {
+		_, err := tb.Create(ctx, &testdata.GroupInfo{Description: fmt.Sprintf("group %d", i)})
+		require.NoError(t, err)
+	}
+	return tb, ctx
+}
@@ -1,8 +1,10 @@
 package orm
 
 import (
+	"encoding/json"
 	"reflect"
 
+	"github.com/cosmos/cosmos-sdk/codec"
 	"github.com/cosmos/cosmos-sdk/store/prefix"
 	"github.com/cosmos/cosmos-sdk/types/errors"
 )
@@ -19,6 +21,13 @@ type SequenceExportable interface {
 	Sequence() Sequence
 }
 
+// scopedSequenceImportable is implemented by tables with sequences per key, like an `AutoUInt64Table`
+// with a `SequenceKeyFunc`. The sequences are not exported but restored from the RowIDs of the imported rows.
+type scopedSequenceImportable interface {
+	resetScopedSequences(ctx HasKVStore)
+	importScopedRowID(ctx HasKVStore, rowID RowID) error
+}
+
 // ExportTableData iterates over the given table entries and stores them at the passed ModelSlicePtr.
 // When the given table implements the `SequenceExportable` interface then it's current value
 // is returned as well or otherwise defaults to 0.
@@ -42,15 +51,9 @@ func ExportTableData(ctx HasKVStore, t TableExportable, dest ModelSlicePtr) (uin
 // data should be a slice of structs that implement PrimaryKeyed (eg []*GroupInfo).
 // The seqValue is optional and only used with tables that implement the `SequenceExportable` interface.
 func ImportTableData(ctx HasKVStore, t TableExportable, data interface{}, seqValue uint64) error {
-	table := t.Table()
-	if err := clearAllInTable(ctx, table); err != nil {
-		return errors.Wrap(err, "clear old entries")
-	}
-
-	if st, ok := t.(SequenceExportable); ok {
-		if err := st.Sequence().InitVal(ctx, seqValue); err != nil {
-			return errors.Wrap(err, "sequence")
-		}
+	table, err := resetTable(ctx, t, seqValue)
+	if err != nil {
+		return err
 	}
 
 	// Provided data must be a slice
@@ -60,17 +63,128 @@ func ImportTableData(ctx HasKVStore, t TableExportable, data interface{}, seqVal
 	}
 
 	// Create table entries
+	rowIDs := make([]RowID, modelSlice.Len())
+	objs := make([]codec.ProtoMarshaler, modelSlice.Len())
 	for i := 0; i < modelSlice.Len(); i++ {
 		obj, ok := modelSlice.Index(i).Interface().(PrimaryKeyed)
 		if !ok {
 			return errors.Wrapf(ErrArgument, "unsupported type :%s", reflect.TypeOf(data).Elem().Elem())
 		}
-		err := table.Create(ctx, obj.PrimaryKey(), obj)
+		rowIDs[i] = obj.PrimaryKey()
+		objs[i] = obj
+	}
+	return importRows(ctx, t, table, rowIDs, objs)
+}
+
+// JSONExporter iterates over the given table entries and returns them as JSON, with the values encoded
+// by the table codec. When the given table implements the `SequenceExportable` interface then it's
+// current value is included as well.
+func JSONExporter(ctx HasKVStore, t TableExportable) (json.RawMessage, error) {
+	table := t.Table()
+	it, err := table.PrefixScan(ctx, nil, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "table PrefixScan failure when exporting table data")
+	}
+	defer it.Close()
+
+	data := jsonTableData{Rows: []jsonTableRow{}}
+	if st, ok := t.(SequenceExportable); ok {
+		data.Sequence = st.Sequence().CurVal(ctx)
+	}
+	for {
+		obj := reflect.New(table.model).Interface().(codec.ProtoMarshaler)
+		rowID, err := it.LoadNext(obj)
+		if IsIteratorDone(err) {
+			break
+		}
 		if err != nil {
+			return nil, err
+		}
+		bz, err := table.cdc.MarshalJSON(obj)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to serialize %T", obj)
+		}
+		data.Rows = append(data.Rows, jsonTableRow{RowID: rowID, Value: bz})
+	}
+	return json.Marshal(data)
+}
+
+// JSONImporter initializes a table and attaches indexers from the given JSON as returned by `JSONExporter`.
+func JSONImporter(ctx HasKVStore, t TableExportable, bz json.RawMessage) error {
+	var data jsonTableData
+	if err := json.Unmarshal(bz, &data); err != nil {
+		return errors.Wrap(ErrArgument, err.Error())
+	}
+	table, err := resetTable(ctx, t, data.Sequence)
+	if err != nil {
+		return err
+	}
+	rowIDs := make([]RowID, len(data.Rows))
+	objs := make([]codec.ProtoMarshaler, len(data.Rows))
+	for i, row := range data.Rows {
+		obj := reflect.New(table.model).Interface().(codec.ProtoMarshaler)
+		if err := table.cdc.UnmarshalJSON(row.Value, obj); err != nil {
+			return errors.Wrapf(err, "row %d", i)
+		}
+		rowIDs[i] = row.RowID
+		objs[i] = obj
+	}
+	return importRows(ctx, t, table, rowIDs, objs)
+}
+
+// jsonTableData is the JSON representation of a table used by `JSONExporter` and `JSONImporter`.
+type jsonTableData struct {
+	Sequence uint64         `json:"sequence,omitempty"`
+	Rows     []jsonTableRow `json:"rows"`
+}
+
+type jsonTableRow struct {
+	RowID RowID           `json:"row_id"`
+	Value json.RawMessage `json:"value"`
+}
+
+// resetTable deletes all entries in the table and initializes the sequence with the seqValue when
+// the table implements the `SequenceExportable` interface.
+func resetTable(ctx HasKVStore, t TableExportable, seqValue uint64) (Table, error) {
+	table := t.Table()
+	if err := clearAllInTable(ctx, table); err != nil {
+		return Table{}, errors.Wrap(err, "clear old entries")
+	}
+	if table.counter != nil {
+		// the counter may not have been initialized for the old entries
+		table.counter.Set(ctx, 0)
+	}
+
+	if st, ok := t.(SequenceExportable); ok {
+		if err := st.Sequence().InitVal(ctx, seqValue); err != nil {
+			return Table{}, errors.Wrap(err, "sequence")
+		}
+	}
+	if st, ok := t.(scopedSequenceImportable); ok {
+		st.resetScopedSequences(ctx)
+	}
+	return table, nil
+}
+
+// importRows persists the rows of an import via `Table.BulkCreate` and restores the sequences per key
+// when the table has them.
+func importRows(ctx HasKVStore, t TableExportable, table Table, rowIDs []RowID, objs []codec.ProtoMarshaler) error {
+	if err := table.BulkCreate(ctx, rowIDs, objs); err != nil {
+		return err
+	}
+	for _, rowID := range rowIDs {
+		if err := importScopedRowID(ctx, t, rowID); err != nil {
 			return err
 		}
 	}
+	return nil
+}
 
+// importScopedRowID restores the sequence of the key of an imported row when the table has sequences per key.
+func importScopedRowID(ctx HasKVStore, t TableExportable, rowID RowID) error {
+	if st, ok := t.(scopedSequenceImportable); ok {
+		return st.importScopedRowID(ctx, rowID)
+	}
 	return nil
 }
 
@@ -52,3 +52,100 @@ func TestImportExportTableData(t *testing.T) {
 		require.Equal(t, g, groups[i])
 	}
 }
+
+func TestImportTableDataRowCounter(t *testing.T) {
+	interfaceRegistry := types.NewInterfaceRegistry()
+	cdc := codec.NewProtoCodec(interfaceRegistry)
+
+	storeKey := sdk.NewKVStoreKey("test")
+	const (
+		prefix = iota
+		seqPrefix
+		counterPrefix
+	)
+	builder := orm.NewAutoUInt64TableBuilder(prefix, seqPrefix, storeKey, &testdata.GroupInfo{}, cdc)
+	builder.WithRowCounter(counterPrefix)
+	table := builder.Build()
+
+	ctx := orm.NewMockContext()
+	// old rows with a counter that is out of sync
+	counterBuilder := orm.NewTableBuilder(prefix, storeKey, &testdata.GroupInfo{}, orm.Max255DynamicLengthIndexKeyCodec{}, cdc)
+	counterBuilder.WithRowCounter(counterPrefix)
+	counterTable := counterBuilder.Build()
+	for i := uint64(10); i < 13; i++ {
+		require.NoError(t, counterTable.Create(ctx, orm.EncodeSequence(i), &testdata.GroupInfo{GroupId: i}))
+	}
+	legacyTable := orm.NewTableBuilder(prefix, storeKey, &testdata.GroupInfo{}, orm.Max255DynamicLengthIndexKeyCodec{}, cdc).Build()
+	require.NoError(t, legacyTable.Delete(ctx, orm.EncodeSequence(10)))
+
+	groups := []*testdata.GroupInfo{
+		{GroupId: 1, Admin: sdk.AccAddress([]byte("admin1-address"))},
+		{GroupId: 2, Admin: sdk.AccAddress([]byte("admin2-address"))},
+	}
+	err := orm.ImportTableData(ctx, table, groups, 2)
+	require.NoError(t, err)
+
+	n, err := table.Count(ctx)
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), n)
+}
+
+func TestJSONExportImport(t *testing.T) {
+	interfaceRegistry := types.NewInterfaceRegistry()
+	cdc := codec.NewProtoCodec(interfaceRegistry)
+
+	storeKey := sdk.NewKVStoreKey("test")
+	const prefix = iota
+	tBuilder := orm.NewAutoUInt64TableBuilder(prefix, 0x1, storeKey, &testdata.GroupInfo{}, cdc)
+	idx := orm.NewIndex(tBuilder, GroupByAdminIndexPrefix, func(val interface{}) ([]orm.RowID, error) {
+		return []orm.RowID{[]byte(val.(*testdata.GroupInfo).Admin)}, nil
+	})
+	table := tBuilder.Build()
+
+	ctx := orm.NewMockContext()
+
+	// the JSON encoding of the addresses requires the default address length
+	groups := []*testdata.GroupInfo{
+		{
+			GroupId: 1,
+			Admin:   sdk.AccAddress([]byte("admin1-address-00000")),
+		},
+		{
+			GroupId: 2,
+			Admin:   sdk.AccAddress([]byte("admin2-address-00000")),
+		},
+	}
+	for _, g := range groups {
+		_, err := table.Create(ctx, g)
+		require.NoError(t, err)
+	}
+
+	bz, err := orm.JSONExporter(ctx, table)
+	require.NoError(t, err)
+
+	newCtx := orm.NewMockContext()
+	err = orm.JSONImporter(newCtx, table, bz)
+	require.NoError(t, err)
+
+	require.Equal(t, uint64(2), table.Sequence().CurVal(newCtx))
+	for _, g := range groups {
+		var loaded testdata.GroupInfo
+		_, err := table.GetOne(newCtx, g.GroupId, &loaded)
+		require.NoError(t, err)
+		require.Equal(t, g, &loaded)
+
+		it, err := idx.Get(newCtx, g.Admin)
+		require.NoError(t, err)
+		var byAdmin []*testdata.GroupInfo
+		_, err = orm.ReadAll(it, &byAdmin)
+		require.NoError(t, err)
+		require.Equal(t, []*testdata.GroupInfo{g}, byAdmin)
+	}
+
+	exported, err := orm.JSONExporter(newCtx, table)
+	require.NoError(t, err)
+	require.JSONEq(t, string(bz), string(exported))
+
+	err = orm.JSONImporter(orm.NewMockContext(), table, []byte("not json"))
+	require.True(t, orm.ErrArgument.Is(err), err)
+}
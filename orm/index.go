@@ -24,8 +24,10 @@ type MultiKeyIndex struct {
 	storeKey      sdk.StoreKey
 	prefix        byte
 	rowGetter     RowGetter
+	rawRowGetter  RawRowGetter
 	indexer       indexer
 	indexKeyCodec IndexKeyCodec
+	maxCountTotal uint64
 }
 
 // NewIndex builds a MultiKeyIndex
@@ -46,19 +48,39 @@ func newIndex(builder Indexable, prefix byte, indexer *Indexer) MultiKeyIndex {
 	if rowGetter == nil {
 		panic("RowGetter must not be nil")
 	}
+	rawRowGetter := builder.RawRowGetter()
+	if rawRowGetter == nil {
+		panic("RawRowGetter must not be nil")
+	}
 
 	idx := MultiKeyIndex{
 		storeKey:      storeKey,
 		prefix:        prefix,
 		rowGetter:     rowGetter,
+		rawRowGetter:  rawRowGetter,
 		indexer:       indexer,
 		indexKeyCodec: codec,
 	}
 	builder.AddAfterSaveInterceptor(idx.onSave)
 	builder.AddAfterDeleteInterceptor(idx.onDelete)
+	if r, ok := builder.(indexRegistry); ok {
+		r.addIndexPrefix(prefix)
+	}
 	return idx
 }
 
+// indexRegistry is implemented by builders that keep track of the prefixes of their indexes.
+type indexRegistry interface {
+	addIndexPrefix(prefix byte)
+}
+
+// WithMaxCountTotal returns a copy of the index that counts at most max elements for the total of a
+// page requested by key. See `GetPaginated`. The default 0 means no limit.
+func (i MultiKeyIndex) WithMaxCountTotal(max uint64) MultiKeyIndex {
+	i.maxCountTotal = max
+	return i
+}
+
 // Has checks if a key exists. Panics on nil key.
 func (i MultiKeyIndex) Has(ctx HasKVStore, key []byte) bool {
 	store := prefix.NewStore(ctx.KVStore(i.storeKey), []byte{i.prefix})
@@ -70,22 +92,119 @@ func (i MultiKeyIndex) Has(ctx HasKVStore, key []byte) bool {
 // Get returns a result iterator for the searchKey. Parameters must not be nil.
 func (i MultiKeyIndex) Get(ctx HasKVStore, searchKey []byte) (Iterator, error) {
 	store := prefix.NewStore(ctx.KVStore(i.storeKey), []byte{i.prefix})
-	it := store.Iterator(PrefixRange(searchKey))
-	return indexIterator{ctx: ctx, it: it, rowGetter: i.rowGetter, keyCodec: i.indexKeyCodec}, nil
+	return i.instrument(i.newIterator(ctx, func() types.Iterator { return store.Iterator(PrefixRange(searchKey)) })), nil
+}
+
+// ReverseGet returns a result iterator for the searchKey in descending order. Parameters must not be nil.
+func (i MultiKeyIndex) ReverseGet(ctx HasKVStore, searchKey []byte) (Iterator, error) {
+	store := prefix.NewStore(ctx.KVStore(i.storeKey), []byte{i.prefix})
+	return i.instrument(i.newIterator(ctx, func() types.Iterator { return store.ReverseIterator(PrefixRange(searchKey)) })), nil
 }
 
 // GetPaginated creates an iterator for the searchKey
 // starting from pageRequest.Key if provided.
-// The pageRequest.Key is the rowID while searchKey is a MultiKeyIndex key.
+// The pageRequest.Key is a cursor, see `EncodeCursor`, while searchKey is a MultiKeyIndex key. `Paginate`
+// returns a cursor as NextKey for the iterator. Malformed cursors or cursors with an index key that does
+// not start with the searchKey result in an `ErrArgument` error.
+// For compatibility with previous versions, a pageRequest.Key that does not decode as cursor is used as
+// rowID when it is a valid RowID for the index key codec, so that the rowIDs of natural keys that start
+// with the cursor marker byte 0xff continue a page as well. Only a rowID that is also a well formed cursor
+// is read as cursor. This will be removed with the next release.
+// An empty pageRequest.Key is treated like a nil one, as by `Paginate`.
+//
+// When pageRequest.Key and pageRequest.CountTotal are set, `Paginate` returns the total number of
+// elements for the searchKey. They are counted with an extra scan over the index keys from the
+// beginning, without reading the table rows, that costs Gas for every element. The scan stops after
+// the max elements set with `WithMaxCountTotal` and the total is capped at that value then.
 func (i MultiKeyIndex) GetPaginated(ctx HasKVStore, searchKey []byte, pageRequest *query.PageRequest) (Iterator, error) {
 	store := prefix.NewStore(ctx.KVStore(i.storeKey), []byte{i.prefix})
 	start, end := PrefixRange(searchKey)
 
 	if pageRequest != nil && len(pageRequest.Key) != 0 {
-		start = i.indexKeyCodec.BuildIndexKey(searchKey, RowID(pageRequest.Key))
+		var err error
+		if start, err = i.pageIndexKey(searchKey, pageRequest.Key); err != nil {
+			return nil, err
+		}
+	}
+	it := i.newIterator(ctx, func() types.Iterator { return store.Iterator(start, end) })
+	it.total = i.totalCounter(ctx, searchKey, pageRequest)
+	it.cursors = true
+	return i.instrument(it), nil
+}
+
+// ReverseGetPaginated creates an iterator for the searchKey in descending order
+// starting from pageRequest.Key if provided. The element for pageRequest.Key is included.
+// The pageRequest.Key is a cursor like for `GetPaginated` while searchKey is a MultiKeyIndex key.
+func (i MultiKeyIndex) ReverseGetPaginated(ctx HasKVStore, searchKey []byte, pageRequest *query.PageRequest) (Iterator, error) {
+	store := prefix.NewStore(ctx.KVStore(i.storeKey), []byte{i.prefix})
+	start, end := PrefixRange(searchKey)
+
+	if pageRequest != nil && len(pageRequest.Key) != 0 {
+		pageKey, err := i.pageIndexKey(searchKey, pageRequest.Key)
+		if err != nil {
+			return nil, err
+		}
+		// end is exclusive, so we use the smallest key after the index key for the page key
+		end = append(pageKey, 0)
+	}
+	it := i.newIterator(ctx, func() types.Iterator { return store.ReverseIterator(start, end) })
+	it.total = i.totalCounter(ctx, searchKey, pageRequest)
+	it.cursors = true
+	return i.instrument(it), nil
+}
+
+// pageIndexKey returns the persisted index key for the page key of a paginated request.
+func (i MultiKeyIndex) pageIndexKey(searchKey []byte, pageKey []byte) ([]byte, error) {
+	if !isCursor(pageKey) {
+		// page key of a previous version
+		return buildIndexKey(i.indexKeyCodec, searchKey, RowID(pageKey))
+	}
+	indexKey, rowID, err := DecodeCursor(pageKey)
+	if err != nil {
+		// page key of a previous version with a rowID that starts with the cursor marker
+		key, legacyErr := buildIndexKey(i.indexKeyCodec, searchKey, RowID(pageKey))
+		if legacyErr != nil {
+			return nil, err
+		}
+		return key, nil
+	}
+	if !bytes.HasPrefix(indexKey, searchKey) {
+		return nil, errors.Wrap(ErrArgument, "cursor does not match search key")
+	}
+	return buildIndexKey(i.indexKeyCodec, indexKey, rowID)
+}
+
+// buildIndexKey builds the index key with the codec and returns an `ErrArgument` error for a RowID with a
+// length that the codec does not support, instead of the panic of the codec.
+func buildIndexKey(codec IndexKeyCodec, searchableKey []byte, rowID RowID) ([]byte, error) {
+	if err := checkRowIDLength(codec, rowID); err != nil {
+		return nil, err
 	}
-	it := store.Iterator(start, end)
-	return indexIterator{ctx: ctx, it: it, rowGetter: i.rowGetter, keyCodec: i.indexKeyCodec}, nil
+	return codec.BuildIndexKey(searchableKey, rowID), nil
+}
+
+// checkRowIDLength returns an `ErrArgument` error when the RowID is empty, is not of the length of a
+// FixLengthIndexKeyCodec or is longer than 255 bytes for a Max255DynamicLengthIndexKeyCodec.
+func checkRowIDLength(codec IndexKeyCodec, rowID RowID) error {
+	maxLength, exact := 255, false
+	switch c := codec.(type) {
+	case FixLengthIndexKeyCodec:
+		maxLength, exact = c.rowIDLength, true
+	case *FixLengthIndexKeyCodec:
+		maxLength, exact = c.rowIDLength, true
+	case Max255DynamicLengthIndexKeyCodec, *Max255DynamicLengthIndexKeyCodec:
+	default:
+		return nil
+	}
+	switch n := len(rowID); {
+	case n == 0:
+		return errors.Wrap(ErrArgument, "empty RowID")
+	case exact && n != maxLength:
+		return errors.Wrapf(ErrArgument, "RowID of %d bytes but expected %d", n, maxLength)
+	case n > maxLength:
+		return errors.Wrapf(ErrArgument, "RowID of %d bytes exceeds %d", n, maxLength)
+	}
+	return nil
 }
 
 // PrefixScan returns an Iterator over a domain of keys in ascending order. End is exclusive.
@@ -109,8 +228,49 @@ func (i MultiKeyIndex) PrefixScan(ctx HasKVStore, start []byte, end []byte) (Ite
 		return NewInvalidIterator(), errors.Wrap(ErrArgument, "start must be less than end")
 	}
 	store := prefix.NewStore(ctx.KVStore(i.storeKey), []byte{i.prefix})
-	it := store.Iterator(start, end)
-	return indexIterator{ctx: ctx, it: it, rowGetter: i.rowGetter, keyCodec: i.indexKeyCodec}, nil
+	return i.instrument(i.newIterator(ctx, func() types.Iterator { return store.Iterator(start, end) })), nil
+}
+
+// PrefixScanFrom returns an Iterator over all index keys in ascending order that starts strictly after the
+// resumeKey, which is the persisted index key returned as `Positioner` position by a previous index scan,
+// so that a long running process can continue where it stopped. An empty resumeKey starts at the first key.
+// Iterator must be closed by caller.
+//
+// CONTRACT: No writes may happen within a domain while an iterator exists over it.
+func (i MultiKeyIndex) PrefixScanFrom(ctx HasKVStore, resumeKey []byte) (Iterator, error) {
+	return i.PrefixScan(ctx, keyAfter(resumeKey), nil)
+}
+
+// KeysPrefixScan returns an IndexKeyIterator over a domain of keys in ascending order. End is exclusive.
+// It returns the persisted index keys and RowIDs in the same order as PrefixScan without reading the
+// table rows. Start is an MultiKeyIndex key or prefix. It must be less than end, or the Iterator is
+// invalid and error is returned.
+// Iterator must be closed by caller.
+// To iterate over entire domain, use KeysPrefixScan(nil, nil)
+//
+// CONTRACT: No writes may happen within a domain while an iterator exists over it.
+func (i MultiKeyIndex) KeysPrefixScan(ctx HasKVStore, start []byte, end []byte) (IndexKeyIterator, error) {
+	if start != nil && end != nil && bytes.Compare(start, end) >= 0 {
+		return nil, errors.Wrap(ErrArgument, "start must be less than end")
+	}
+	store := prefix.NewStore(ctx.KVStore(i.storeKey), []byte{i.prefix})
+	return &indexKeyIterator{it: store.Iterator(start, end), keyCodec: i.indexKeyCodec}, nil
+}
+
+// DistinctPrefixScan returns an Iterator like PrefixScan that returns every RowID only once, even when the
+// object is indexed with multiple keys in the domain. See `DistinctIterator` for the maxRowIDs parameter.
+// Iterator must be closed by caller.
+//
+// WARNING: The use of a DistinctPrefixScan can be very expensive in terms of Gas and memory. Please make sure
+// you do not expose this as an endpoint to the public without further limits.
+//
+// CONTRACT: No writes may happen within a domain while an iterator exists over it.
+func (i MultiKeyIndex) DistinctPrefixScan(ctx HasKVStore, start []byte, end []byte, maxRowIDs int) (Iterator, error) {
+	it, err := i.PrefixScan(ctx, start, end)
+	if err != nil {
+		return it, err
+	}
+	return DistinctIterator(it, maxRowIDs), nil
 }
 
 // ReversePrefixScan returns an Iterator over a domain of keys in descending order. End is exclusive.
@@ -127,8 +287,44 @@ func (i MultiKeyIndex) ReversePrefixScan(ctx HasKVStore, start []byte, end []byt
 		return NewInvalidIterator(), errors.Wrap(ErrArgument, "start must be less than end")
 	}
 	store := prefix.NewStore(ctx.KVStore(i.storeKey), []byte{i.prefix})
-	it := store.ReverseIterator(start, end)
-	return indexIterator{ctx: ctx, it: it, rowGetter: i.rowGetter, keyCodec: i.indexKeyCodec}, nil
+	return i.instrument(i.newIterator(ctx, func() types.Iterator { return store.ReverseIterator(start, end) })), nil
+}
+
+// newIterator returns the iterator of a scan over the store iterator that open returns. open is called
+// again when the iterator is rewound.
+func (i MultiKeyIndex) newIterator(ctx HasKVStore, open func() types.Iterator) *indexIterator {
+	return &indexIterator{
+		ctx:          ctx,
+		it:           open(),
+		open:         open,
+		rowGetter:    i.rowGetter,
+		rawRowGetter: i.rawRowGetter,
+		keyCodec:     i.indexKeyCodec,
+		scan:         ScanInfo{StoreKey: i.storeKey.Name(), Prefix: i.prefix, Index: true},
+	}
+}
+
+// instrument wraps the iterator of a scan when a scan hook is set, see `SetScanHook`.
+func (i MultiKeyIndex) instrument(it *indexIterator) Iterator {
+	return instrumentScan(i.storeKey, i.prefix, true, it)
+}
+
+// totalCounter returns a function that counts all index keys for the searchKey when the total is requested
+// for a page by key, or nil otherwise.
+func (i MultiKeyIndex) totalCounter(ctx HasKVStore, searchKey []byte, pageRequest *query.PageRequest) func() (uint64, error) {
+	if pageRequest == nil || len(pageRequest.Key) == 0 || !(pageRequest.CountTotal || pageRequest.Limit == 0) {
+		return nil
+	}
+	return func() (uint64, error) {
+		store := prefix.NewStore(ctx.KVStore(i.storeKey), []byte{i.prefix})
+		it := store.Iterator(PrefixRange(searchKey))
+		defer it.Close()
+		var count uint64
+		for ; it.Valid() && (i.maxCountTotal == 0 || count < i.maxCountTotal); it.Next() {
+			count++
+		}
+		return count, nil
+	}
 }
 
 func (i MultiKeyIndex) onSave(ctx HasKVStore, rowID RowID, newValue, oldValue codec.ProtoMarshaler) error {
@@ -144,6 +340,53 @@ func (i MultiKeyIndex) onDelete(ctx HasKVStore, rowID RowID, oldValue codec.Prot
 	return i.indexer.OnDelete(store, rowID, oldValue)
 }
 
+// rebuildBatchSize is the number of table rows that `Rebuild` reads at once.
+const rebuildBatchSize = 100
+
+// Rebuild removes all entries of the index and creates them again for all rows of the table that the index
+// was built for, for example in a store migration after the encoding of the index keys was changed. The
+// table must be in the same store as the index.
+//
+// WARNING: Rebuild reads all rows of the table and all entries of the index and can be very expensive in
+// terms of Gas.
+func (i MultiKeyIndex) Rebuild(ctx HasKVStore, t TableExportable) error {
+	table := t.Table()
+	if table.storeKey != i.storeKey {
+		return errors.Wrap(ErrArgument, "table not in the store of the index")
+	}
+	store := prefix.NewStore(ctx.KVStore(i.storeKey), []byte{i.prefix})
+	// the keys are collected before the delete, as no writes may happen within the domain of an iterator
+	var keys [][]byte
+	it := store.Iterator(nil, nil)
+	for ; it.Valid(); it.Next() {
+		keys = append(keys, it.Key())
+	}
+	if err := it.Close(); err != nil {
+		return err
+	}
+	for _, k := range keys {
+		store.Delete(k)
+	}
+
+	var resumeKey RowID
+	for {
+		next, done, err := MigrateInBatches(ctx, t, resumeKey, rebuildBatchSize, func(_ HasKVStore, rowID RowID, model codec.ProtoMarshaler) error {
+			return i.indexer.OnCreate(store, rowID, model)
+		})
+		if err != nil {
+			return err
+		}
+		if done {
+			return nil
+		}
+		resumeKey = next
+	}
+}
+
+// UniqueIndex is a MultiKeyIndex where every index key belongs to one object at most, like "one row per
+// denom". Creating or updating an object with the key of another one fails with an `ErrUniqueConstraint`
+// error that identifies the key and the RowID of the other object. An update that keeps the key succeeds
+// and a delete frees the key for other objects.
 type UniqueIndex struct {
 	MultiKeyIndex
 }
@@ -155,33 +398,274 @@ func NewUniqueIndex(builder Indexable, prefix byte, uniqueIndexerFunc UniqueInde
 	}
 }
 
+// GetOne loads the object with exactly the given index key into the dest parameter and returns its RowID.
+// Objects with longer keys that start with the key are not returned, unlike with Get.
+// If none exists `ErrNotFound` is returned instead. Parameters must not be nil.
+func (i UniqueIndex) GetOne(ctx HasKVStore, key []byte, dest codec.ProtoMarshaler) (RowID, error) {
+	if len(key) == 0 {
+		return nil, errors.Wrap(ErrArgument, "empty index key")
+	}
+	store := prefix.NewStore(ctx.KVStore(i.storeKey), []byte{i.prefix})
+	rowID, ok := findIndexKey(store, i.indexKeyCodec, key)
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return rowID, i.rowGetter(ctx, rowID, dest)
+}
+
+// KeyFunc returns the fields of the compound key of the source object for a `CompoundUniqueIndex`.
+// It must return the same number of fields for all objects.
+type KeyFunc func(value interface{}) ([][]byte, error)
+
+// CompoundUniqueIndex is a UniqueIndex with a key that combines multiple fields, like the SQL constraint
+// `UNIQUE(a, b)`. Saving an object with the same combination of fields as an existing one fails with
+// `ErrUniqueConstraint`. The index keys are encoded by `LengthPrefixedKey`, so that the rows can be
+// scanned by the leading fields, too.
+type CompoundUniqueIndex struct {
+	UniqueIndex
+}
+
+// NewCompoundUniqueIndex creates a new index where duplicate combinations of the fields returned by the
+// keyFunc are prohibited.
+func NewCompoundUniqueIndex(builder Indexable, prefix byte, keyFunc KeyFunc) CompoundUniqueIndex {
+	if keyFunc == nil {
+		panic("KeyFunc must not be nil")
+	}
+	return CompoundUniqueIndex{
+		UniqueIndex: NewUniqueIndex(builder, prefix, func(value interface{}) (RowID, error) {
+			fields, err := keyFunc(value)
+			if err != nil {
+				return nil, err
+			}
+			return LengthPrefixedKey(fields...)
+		}),
+	}
+}
+
+// HasFields checks if an object with the given combination of fields exists.
+func (i CompoundUniqueIndex) HasFields(ctx HasKVStore, fields ...[]byte) (bool, error) {
+	key, err := LengthPrefixedKey(fields...)
+	if err != nil {
+		return false, err
+	}
+	return i.Has(ctx, key), nil
+}
+
+// GetFields returns an Iterator over the object with the given combination of fields.
+func (i CompoundUniqueIndex) GetFields(ctx HasKVStore, fields ...[]byte) (Iterator, error) {
+	key, err := LengthPrefixedKey(fields...)
+	if err != nil {
+		return NewInvalidIterator(), err
+	}
+	return i.Get(ctx, key)
+}
+
+// PrefixScanFields returns an Iterator over all objects whose leading fields are the given ones, for example
+// all objects with the field `a` of `UNIQUE(a, b)`. They are returned in the order of their
+// `LengthPrefixedKey`, that sorts the remaining fields by length first and then by their bytes, so
+// ("a", "c") comes before ("a", "bb"). A `CompositeIndex` keeps the byte order of the fields instead.
+// At least one field is required.
+func (i CompoundUniqueIndex) PrefixScanFields(ctx HasKVStore, fields ...[]byte) (Iterator, error) {
+	if len(fields) == 0 {
+		return NewInvalidIterator(), errors.Wrap(ErrArgument, "fields must not be empty")
+	}
+	key, err := LengthPrefixedKey(fields...)
+	if err != nil {
+		return NewInvalidIterator(), err
+	}
+	start, end := PrefixRange(key)
+	return i.PrefixScan(ctx, start, end)
+}
+
+// LengthPrefixedKey encodes the fields as index key of a `CompoundUniqueIndex`. Every field is prefixed
+// with its length, so that different combinations of the same number of fields never share a prefix, and
+// the key of the leading fields is a prefix of the full key. The keys do not preserve the byte order of
+// fields of variable length, as shorter fields sort first, unlike the `CompositeKey` of a `CompositeIndex`.
+// Fields must not be longer than 255 bytes.
+func LengthPrefixedKey(fields ...[]byte) (RowID, error) {
+	var key RowID
+	for i, f := range fields {
+		if len(f) > 255 {
+			return nil, errors.Wrapf(ErrIndexKeyMaxLength, "field %d exceeds 255 bytes", i)
+		}
+		key = append(key, byte(len(f)))
+		key = append(key, f...)
+	}
+	return key, nil
+}
+
+var (
+	_ RawIterator = &indexIterator{}
+	_ Rewinder    = &indexIterator{}
+	_ Positioner  = &indexIterator{}
+	_ RowIDPeeker = &indexIterator{}
+)
+
 // indexIterator uses rowGetter to lazy load new model values on request.
 type indexIterator struct {
-	ctx       HasKVStore
-	rowGetter RowGetter
-	it        types.Iterator
-	keyCodec  IndexKeyCodec
+	ctx          HasKVStore
+	rowGetter    RowGetter
+	rawRowGetter RawRowGetter
+	it           types.Iterator
+	// open returns a new store iterator over the domain of the scan
+	open     func() types.Iterator
+	keyCodec IndexKeyCodec
+	total    func() (uint64, error)
+	// last is the index key of the last element returned
+	last []byte
+	// cursors makes the iterator return cursors instead of RowIDs as page keys
+	cursors bool
+	// scan describes the index for `Explain`
+	scan   ScanInfo
+	closed bool
 }
 
 // LoadNext loads the next value in the sequence into the pointer passed as dest and returns the key. If there
 // are no more items the ErrIteratorDone error is returned
 // The key is the rowID and not any MultiKeyIndex key.
-func (i indexIterator) LoadNext(dest codec.ProtoMarshaler) (RowID, error) {
+func (i *indexIterator) LoadNext(dest codec.ProtoMarshaler) (RowID, error) {
+	if i.closed {
+		return nil, ErrIteratorClosed
+	}
 	if !i.it.Valid() {
 		return nil, ErrIteratorDone
 	}
 	indexPrefixKey := i.it.Key()
 	rowID := i.keyCodec.StripRowID(indexPrefixKey)
 	i.it.Next()
+	i.last = indexPrefixKey
 	return rowID, i.rowGetter(i.ctx, rowID, dest)
 }
 
-// Close releases the iterator and should be called at the end of iteration
-func (i indexIterator) Close() error {
-	i.it.Close()
+// RawNext returns the rowID and the persisted bytes of the next element without unmarshaling them.
+func (i *indexIterator) RawNext() (RowID, []byte, error) {
+	if i.closed {
+		return nil, nil, ErrIteratorClosed
+	}
+	if !i.it.Valid() {
+		return nil, nil, ErrIteratorDone
+	}
+	i.last = i.it.Key()
+	rowID := i.keyCodec.StripRowID(i.last)
+	i.it.Next()
+	value, err := i.rawRowGetter(i.ctx, rowID)
+	return rowID, value, err
+}
+
+// nextRowID returns the rowID of the next element without reading it from the table.
+func (i *indexIterator) nextRowID() (RowID, error) {
+	if i.closed {
+		return nil, ErrIteratorClosed
+	}
+	if !i.it.Valid() {
+		return nil, ErrIteratorDone
+	}
+	i.last = i.it.Key()
+	rowID := i.keyCodec.StripRowID(i.last)
+	i.it.Next()
+	return rowID, nil
+}
+
+// nextPageKey returns the key to continue a page with at the next element and advances without reading it
+// from the table. It is a cursor when the iterator was created for pagination and the rowID otherwise.
+func (i *indexIterator) nextPageKey(_ codec.ProtoMarshaler) ([]byte, error) {
+	if i.closed {
+		return nil, ErrIteratorClosed
+	}
+	if !i.it.Valid() {
+		return nil, ErrIteratorDone
+	}
+	indexPrefixKey := i.it.Key()
+	rowID := i.keyCodec.StripRowID(indexPrefixKey)
+	i.it.Next()
+	i.last = indexPrefixKey
+	if !i.cursors {
+		return rowID, nil
+	}
+	// the index key without the encoded rowID
+	searchableKey := indexPrefixKey[:len(indexPrefixKey)-len(i.keyCodec.BuildIndexKey(nil, rowID))]
+	return EncodeCursor(searchableKey, rowID), nil
+}
+
+// PeekRowID returns the rowID of the next element without consuming it or reading it from the table.
+func (i *indexIterator) PeekRowID() (RowID, error) {
+	if i.closed {
+		return nil, ErrIteratorClosed
+	}
+	if !i.it.Valid() {
+		return nil, ErrIteratorDone
+	}
+	return i.keyCodec.StripRowID(i.it.Key()), nil
+}
+
+// countTotal returns the number of all elements of a paginated domain when it was requested.
+func (i *indexIterator) countTotal() (uint64, bool, error) {
+	if i.total == nil {
+		return 0, false, nil
+	}
+	n, err := i.total()
+	return n, true, err
+}
+
+// Rewind replaces the store iterator with a new one from the start of the scanned domain. For a
+// paginated iterator that is the page key.
+func (i *indexIterator) Rewind() error {
+	if i.closed {
+		return ErrIteratorClosed
+	}
+	if err := i.it.Close(); err != nil {
+		return err
+	}
+	i.it = i.open()
+	i.last = nil
 	return nil
 }
 
+// Position returns the persisted index key of the last element returned, that `MultiKeyIndex.PrefixScanFrom`
+// can continue after.
+func (i *indexIterator) Position() []byte {
+	return i.last
+}
+
+// Close releases the iterator and should be called at the end of iteration
+func (i *indexIterator) Close() error {
+	if i.closed {
+		return nil
+	}
+	i.closed = true
+	return i.it.Close()
+}
+
+// indexKeyIterator uses the keyCodec to return the RowIDs of the index keys.
+type indexKeyIterator struct {
+	it       types.Iterator
+	keyCodec IndexKeyCodec
+	closed   bool
+}
+
+// LoadNextKey returns the persisted index key and the RowID of the next element. If there are no more
+// items the ErrIteratorDone error is returned
+func (i *indexKeyIterator) LoadNextKey() ([]byte, RowID, error) {
+	if i.closed {
+		return nil, nil, ErrIteratorClosed
+	}
+	if !i.it.Valid() {
+		return nil, nil, ErrIteratorDone
+	}
+	indexKey := i.it.Key()
+	i.it.Next()
+	return indexKey, i.keyCodec.StripRowID(indexKey), nil
+}
+
+// Close releases the iterator and should be called at the end of iteration
+func (i *indexKeyIterator) Close() error {
+	if i.closed {
+		return nil
+	}
+	i.closed = true
+	return i.it.Close()
+}
+
 // PrefixRange turns a prefix into a (start, end) range. The start is the given prefix value and
 // the end is calculated by adding 1 bit to the start value. Nil is not allowed as prefix.
 // 		Example: []byte{1, 3, 4} becomes []byte{1, 3, 5}
@@ -1,6 +1,8 @@
 package orm_test
 
 import (
+	"bytes"
+	"fmt"
 	"testing"
 
 	"github.com/cosmos/cosmos-sdk/codec"
@@ -206,6 +208,294 @@ func TestIndexPrefixScan(t *testing.T) {
 	}
 }
 
+func TestIndexReverseGet(t *testing.T) {
+	interfaceRegistry := types.NewInterfaceRegistry()
+	cdc := codec.NewProtoCodec(interfaceRegistry)
+	storeKey := sdk.NewKVStoreKey("test")
+	const (
+		testTablePrefix = iota
+		testTableSeqPrefix
+	)
+	tBuilder := orm.NewAutoUInt64TableBuilder(testTablePrefix, testTableSeqPrefix, storeKey, &testdata.GroupInfo{}, cdc)
+	idx := orm.NewIndex(tBuilder, GroupByAdminIndexPrefix, func(val interface{}) ([]orm.RowID, error) {
+		return []orm.RowID{[]byte(val.(*testdata.GroupInfo).Admin)}, nil
+	})
+	tb := tBuilder.Build()
+	ctx := orm.NewMockContext()
+
+	admin := sdk.AccAddress([]byte("admin-address"))
+	g1 := testdata.GroupInfo{Description: "my test 1", Admin: admin}
+	g2 := testdata.GroupInfo{Description: "my test 2", Admin: sdk.AccAddress([]byte("other-admin-address"))}
+	g3 := testdata.GroupInfo{Description: "my test 3", Admin: admin}
+	g4 := testdata.GroupInfo{Description: "my test 4", Admin: admin}
+	for _, g := range []testdata.GroupInfo{g1, g2, g3, g4} {
+		_, err := tb.Create(ctx, &g)
+		require.NoError(t, err)
+	}
+
+	specs := map[string]struct {
+		key       []byte
+		limit     int
+		expResult []testdata.GroupInfo
+		expRowIDs []orm.RowID
+	}{
+		"all in descending order": {
+			key:       admin,
+			limit:     10,
+			expResult: []testdata.GroupInfo{g4, g3, g1},
+			expRowIDs: []orm.RowID{orm.EncodeSequence(4), orm.EncodeSequence(3), orm.EncodeSequence(1)},
+		},
+		"most recent with limit": {
+			key:       admin,
+			limit:     2,
+			expResult: []testdata.GroupInfo{g4, g3},
+			expRowIDs: []orm.RowID{orm.EncodeSequence(4), orm.EncodeSequence(3)},
+		},
+		"no match": {
+			key:       []byte("nobody"),
+			limit:     10,
+			expResult: []testdata.GroupInfo{},
+		},
+	}
+	for msg, spec := range specs {
+		t.Run(msg, func(t *testing.T) {
+			it, err := idx.ReverseGet(ctx, spec.key)
+			require.NoError(t, err)
+			var loaded []testdata.GroupInfo
+			rowIDs, err := orm.ReadAll(orm.LimitIterator(it, spec.limit), &loaded)
+			require.NoError(t, err)
+			assert.Equal(t, spec.expResult, loaded)
+			assert.Equal(t, spec.expRowIDs, rowIDs)
+		})
+	}
+}
+
+func TestIndexDistinctPrefixScan(t *testing.T) {
+	interfaceRegistry := types.NewInterfaceRegistry()
+	cdc := codec.NewProtoCodec(interfaceRegistry)
+	storeKey := sdk.NewKVStoreKey("test")
+	const (
+		testTablePrefix = iota
+		testTableSeqPrefix
+	)
+	tBuilder := orm.NewAutoUInt64TableBuilder(testTablePrefix, testTableSeqPrefix, storeKey, &testdata.GroupInfo{}, cdc)
+	// every model is indexed twice within the scanned domain
+	idx := orm.NewIndex(tBuilder, GroupByAdminIndexPrefix, func(val interface{}) ([]orm.RowID, error) {
+		admin := val.(*testdata.GroupInfo).Admin
+		return []orm.RowID{append([]byte("primary-"), admin...), append([]byte("secondary-"), admin...)}, nil
+	})
+	tb := tBuilder.Build()
+	ctx := orm.NewMockContext()
+
+	g1 := testdata.GroupInfo{Description: "my test 1", Admin: sdk.AccAddress([]byte("admin-address-a"))}
+	g2 := testdata.GroupInfo{Description: "my test 2", Admin: sdk.AccAddress([]byte("admin-address-b"))}
+	for _, g := range []testdata.GroupInfo{g1, g2} {
+		_, err := tb.Create(ctx, &g)
+		require.NoError(t, err)
+	}
+
+	specs := map[string]struct {
+		limit     int
+		maxRowIDs int
+		expResult []testdata.GroupInfo
+		expRowIDs []orm.RowID
+		expError  *errors.Error
+	}{
+		"all distinct": {
+			limit:     10,
+			expResult: []testdata.GroupInfo{g1, g2},
+			expRowIDs: []orm.RowID{orm.EncodeSequence(1), orm.EncodeSequence(2)},
+		},
+		"limit applies to distinct rows": {
+			limit:     2,
+			expResult: []testdata.GroupInfo{g1, g2},
+			expRowIDs: []orm.RowID{orm.EncodeSequence(1), orm.EncodeSequence(2)},
+		},
+		"within max row IDs": {
+			limit:     10,
+			maxRowIDs: 2,
+			expResult: []testdata.GroupInfo{g1, g2},
+			expRowIDs: []orm.RowID{orm.EncodeSequence(1), orm.EncodeSequence(2)},
+		},
+		"max row IDs exceeded": {
+			limit:     10,
+			maxRowIDs: 1,
+			expError:  orm.ErrArgument,
+		},
+	}
+	for msg, spec := range specs {
+		t.Run(msg, func(t *testing.T) {
+			it, err := idx.DistinctPrefixScan(ctx, []byte("primary-"), []byte("tertiary-"), spec.maxRowIDs)
+			require.NoError(t, err)
+			var loaded []testdata.GroupInfo
+			rowIDs, err := orm.ReadAll(orm.LimitIterator(it, spec.limit), &loaded)
+			require.True(t, spec.expError.Is(err), "expected #+v but got #+v", spec.expError, err)
+			if spec.expError != nil {
+				return
+			}
+			assert.Equal(t, spec.expResult, loaded)
+			assert.Equal(t, spec.expRowIDs, rowIDs)
+		})
+	}
+
+	t.Run("without deduplication", func(t *testing.T) {
+		it, err := idx.PrefixScan(ctx, []byte("primary-"), []byte("tertiary-"))
+		require.NoError(t, err)
+		var loaded []testdata.GroupInfo
+		_, err = orm.ReadAll(it, &loaded)
+		require.NoError(t, err)
+		assert.Equal(t, []testdata.GroupInfo{g1, g2, g1, g2}, loaded)
+	})
+}
+
+func TestIndexKeysPrefixScan(t *testing.T) {
+	interfaceRegistry := types.NewInterfaceRegistry()
+	cdc := codec.NewProtoCodec(interfaceRegistry)
+	storeKey := sdk.NewKVStoreKey("test")
+	const (
+		testTablePrefix = iota
+		testTableSeqPrefix
+	)
+	tBuilder := orm.NewAutoUInt64TableBuilder(testTablePrefix, testTableSeqPrefix, storeKey, &testdata.GroupInfo{}, cdc)
+	idx := orm.NewIndex(tBuilder, GroupByAdminIndexPrefix, func(val interface{}) ([]orm.RowID, error) {
+		return []orm.RowID{[]byte(val.(*testdata.GroupInfo).Admin)}, nil
+	})
+	tb := tBuilder.Build()
+	ctx := orm.NewMockContext()
+
+	adminA := sdk.AccAddress([]byte("admin-address-a"))
+	adminB := sdk.AccAddress([]byte("admin-address-b"))
+	for _, g := range []testdata.GroupInfo{
+		{Description: "my test 1", Admin: adminB},
+		{Description: "my test 2", Admin: adminA},
+		{Description: "my test 3", Admin: adminB},
+	} {
+		_, err := tb.Create(ctx, &g)
+		require.NoError(t, err)
+	}
+
+	specs := map[string]struct {
+		start, end []byte
+		expRowIDs  []orm.RowID
+	}{
+		"all": {
+			expRowIDs: []orm.RowID{orm.EncodeSequence(2), orm.EncodeSequence(1), orm.EncodeSequence(3)},
+		},
+		"by prefix": {
+			start:     adminB,
+			end:       []byte("admin-address-c"),
+			expRowIDs: []orm.RowID{orm.EncodeSequence(1), orm.EncodeSequence(3)},
+		},
+		"none": {
+			start: []byte("admin-address-c"),
+			end:   []byte("admin-address-d"),
+		},
+	}
+	for msg, spec := range specs {
+		t.Run(msg, func(t *testing.T) {
+			keysIt, err := idx.KeysPrefixScan(ctx, spec.start, spec.end)
+			require.NoError(t, err)
+			defer keysIt.Close()
+			var keyRowIDs []orm.RowID
+			for {
+				indexKey, rowID, err := keysIt.LoadNextKey()
+				if orm.ErrIteratorDone.Is(err) {
+					break
+				}
+				require.NoError(t, err)
+				assert.Equal(t, rowID, orm.FixLengthIndexKeys(orm.EncodedSeqLength).StripRowID(indexKey))
+				keyRowIDs = append(keyRowIDs, rowID)
+			}
+			assert.Equal(t, spec.expRowIDs, keyRowIDs)
+
+			// same order as the full scan
+			it, err := idx.PrefixScan(ctx, spec.start, spec.end)
+			require.NoError(t, err)
+			var loaded []testdata.GroupInfo
+			rowIDs, err := orm.ReadAll(it, &loaded)
+			require.NoError(t, err)
+			assert.Equal(t, rowIDs, keyRowIDs)
+		})
+	}
+	t.Run("start after end", func(t *testing.T) {
+		_, err := idx.KeysPrefixScan(ctx, adminB, adminA)
+		require.True(t, orm.ErrArgument.Is(err), err)
+	})
+}
+
+func TestIndexPaginateSharedIndexKey(t *testing.T) {
+	interfaceRegistry := types.NewInterfaceRegistry()
+	cdc := codec.NewProtoCodec(interfaceRegistry)
+	storeKey := sdk.NewKVStoreKey("test")
+	const (
+		testTablePrefix = iota
+		testTableSeqPrefix
+	)
+	tBuilder := orm.NewAutoUInt64TableBuilder(testTablePrefix, testTableSeqPrefix, storeKey, &testdata.GroupInfo{}, cdc)
+	idx := orm.NewIndex(tBuilder, GroupByAdminIndexPrefix, func(val interface{}) ([]orm.RowID, error) {
+		return []orm.RowID{[]byte(val.(*testdata.GroupInfo).Admin)}, nil
+	})
+	tb := tBuilder.Build()
+	ctx := orm.NewMockContext()
+
+	// the index key of the second admin starts with the index key of the first one
+	admin := sdk.AccAddress([]byte("admin-address"))
+	otherAdmin := sdk.AccAddress([]byte("admin-address-2"))
+	g1 := testdata.GroupInfo{Description: "my test 1", Admin: admin}
+	g2 := testdata.GroupInfo{Description: "my test 2", Admin: otherAdmin}
+	g3 := testdata.GroupInfo{Description: "my test 3", Admin: admin}
+	g4 := testdata.GroupInfo{Description: "my test 4", Admin: otherAdmin}
+	g5 := testdata.GroupInfo{Description: "my test 5", Admin: admin}
+	for _, g := range []testdata.GroupInfo{g1, g2, g3, g4, g5} {
+		_, err := tb.Create(ctx, &g)
+		require.NoError(t, err)
+	}
+
+	specs := map[string]struct {
+		getPaginated func(orm.HasKVStore, []byte, *query.PageRequest) (orm.Iterator, error)
+		wrap         func(orm.Iterator) orm.Iterator
+		exp          []testdata.GroupInfo
+	}{
+		"ascending": {
+			getPaginated: idx.GetPaginated,
+			exp:          []testdata.GroupInfo{g1, g3, g5, g2, g4},
+		},
+		"descending": {
+			getPaginated: idx.ReverseGetPaginated,
+			exp:          []testdata.GroupInfo{g4, g2, g5, g3, g1},
+		},
+		"with limit iterator": {
+			getPaginated: idx.GetPaginated,
+			wrap: func(it orm.Iterator) orm.Iterator {
+				return orm.LimitIterator(it, 3)
+			},
+			exp: []testdata.GroupInfo{g1, g3, g5, g2, g4},
+		},
+	}
+	for msg, spec := range specs {
+		t.Run(msg, func(t *testing.T) {
+			pageReq := &query.PageRequest{Limit: 2}
+			var loaded []testdata.GroupInfo
+			for n := 0; n < len(spec.exp); n++ {
+				it, err := spec.getPaginated(ctx, admin, pageReq)
+				require.NoError(t, err)
+				if spec.wrap != nil {
+					it = spec.wrap(it)
+				}
+				var page []testdata.GroupInfo
+				res, err := orm.Paginate(it, pageReq, &page)
+				require.NoError(t, err)
+				loaded = append(loaded, page...)
+				if res.NextKey == nil {
+					break
+				}
+				pageReq = &query.PageRequest{Key: res.NextKey, Limit: 2}
+			}
+			assert.Equal(t, spec.exp, loaded)
+		})
+	}
+}
+
 func TestUniqueIndex(t *testing.T) {
 	interfaceRegistry := types.NewInterfaceRegistry()
 	cdc := codec.NewProtoCodec(interfaceRegistry)
@@ -316,6 +606,184 @@ func TestUniqueIndex(t *testing.T) {
 	assert.False(t, uniqueIdx.Has(ctx, indexedKey))
 }
 
+func TestUniqueIndexConstraint(t *testing.T) {
+	interfaceRegistry := types.NewInterfaceRegistry()
+	cdc := codec.NewProtoCodec(interfaceRegistry)
+
+	storeKey := sdk.NewKVStoreKey("test")
+
+	tableBuilder := orm.NewAutoUInt64TableBuilder(GroupTablePrefix, GroupTableSeqPrefix, storeKey, &testdata.GroupInfo{}, cdc)
+	// an admin can have one group only
+	uniqueIdx := orm.NewUniqueIndex(tableBuilder, GroupByAdminIndexPrefix, func(val interface{}) (orm.RowID, error) {
+		return orm.RowID(val.(*testdata.GroupInfo).Admin), nil
+	})
+	myTable := tableBuilder.Build()
+
+	ctx := orm.NewMockContext()
+
+	g1 := testdata.GroupInfo{Description: "my test 1", Admin: sdk.AccAddress([]byte("admin"))}
+	g2 := testdata.GroupInfo{Description: "my test 2", Admin: sdk.AccAddress([]byte("other-admin"))}
+	// a key that starts with the key of g1 is not a conflict
+	g3 := testdata.GroupInfo{Description: "my test 3", Admin: sdk.AccAddress([]byte("admin-2"))}
+	for _, g := range []testdata.GroupInfo{g1, g2, g3} {
+		_, err := myTable.Create(ctx, &g)
+		require.NoError(t, err)
+	}
+	rowID1, rowID2 := orm.RowID(orm.EncodeSequence(1)), orm.RowID(orm.EncodeSequence(2))
+
+	// create with the same key fails
+	_, err := myTable.Create(ctx, &testdata.GroupInfo{Description: "my test 4", Admin: g1.Admin})
+	require.True(t, orm.ErrUniqueConstraint.Is(err), err)
+	// and identifies the key and the row with it
+	assert.Contains(t, err.Error(), fmt.Sprintf("index key %X used by row %X", []byte(g1.Admin), rowID1))
+
+	// an update that keeps the key is not a conflict
+	g2.Description = "my updated test 2"
+	require.NoError(t, myTable.Save(ctx, 2, &g2))
+	// but an update to the key of another row is. The writes of the failed update are not reverted by the
+	// mock context, so row 3 is not used afterwards.
+	err = myTable.Save(ctx, 3, &testdata.GroupInfo{Description: g3.Description, Admin: g1.Admin})
+	require.True(t, orm.ErrUniqueConstraint.Is(err), err)
+
+	// GetOne loads the row with exactly the key
+	var loaded testdata.GroupInfo
+	rowID, err := uniqueIdx.GetOne(ctx, g1.Admin, &loaded)
+	require.NoError(t, err)
+	assert.Equal(t, rowID1, rowID)
+	assert.Equal(t, g1, loaded)
+	_, err = uniqueIdx.GetOne(ctx, []byte("adm"), &loaded)
+	assert.True(t, orm.ErrNotFound.Is(err), err)
+	_, err = uniqueIdx.GetOne(ctx, nil, &loaded)
+	assert.True(t, orm.ErrArgument.Is(err), err)
+
+	// delete frees the key
+	require.NoError(t, myTable.Delete(ctx, 1))
+	_, err = uniqueIdx.GetOne(ctx, g1.Admin, &loaded)
+	assert.True(t, orm.ErrNotFound.Is(err), err)
+	g2.Admin = g1.Admin
+	require.NoError(t, myTable.Save(ctx, 2, &g2))
+	rowID, err = uniqueIdx.GetOne(ctx, g1.Admin, &loaded)
+	require.NoError(t, err)
+	assert.Equal(t, rowID2, rowID)
+	assert.Equal(t, g2, loaded)
+}
+
+func TestCompoundUniqueIndex(t *testing.T) {
+	interfaceRegistry := types.NewInterfaceRegistry()
+	cdc := codec.NewProtoCodec(interfaceRegistry)
+
+	storeKey := sdk.NewKVStoreKey("test")
+
+	tableBuilder := orm.NewPrimaryKeyTableBuilder(GroupMemberTablePrefix, storeKey, &testdata.GroupMember{}, orm.Max255DynamicLengthIndexKeyCodec{}, cdc)
+	// a weight must be unique within a group
+	compoundIdx := orm.NewCompoundUniqueIndex(tableBuilder, 0x10, func(val interface{}) ([][]byte, error) {
+		m := val.(*testdata.GroupMember)
+		return [][]byte{m.Group, orm.EncodeSequence(m.Weight)}, nil
+	})
+	myTable := tableBuilder.Build()
+
+	ctx := orm.NewMockContext()
+
+	group1 := sdk.AccAddress(orm.EncodeSequence(1))
+	group2 := sdk.AccAddress(orm.EncodeSequence(2))
+	m1 := testdata.GroupMember{Group: group1, Member: sdk.AccAddress([]byte("member-address-1")), Weight: 1}
+	m2 := testdata.GroupMember{Group: group1, Member: sdk.AccAddress([]byte("member-address-2")), Weight: 2}
+	m3 := testdata.GroupMember{Group: group2, Member: sdk.AccAddress([]byte("member-address-1")), Weight: 1}
+	for _, m := range []testdata.GroupMember{m1, m2, m3} {
+		require.NoError(t, myTable.Create(ctx, &m))
+	}
+
+	// create with the same combination of fields should fail
+	err := myTable.Create(ctx, &testdata.GroupMember{Group: group1, Member: sdk.AccAddress([]byte("member-address-3")), Weight: 1})
+	assert.True(t, orm.ErrUniqueConstraint.Is(err), err)
+	// and update, too
+	err = myTable.Save(ctx, &testdata.GroupMember{Group: group1, Member: m2.Member, Weight: 1})
+	assert.True(t, orm.ErrUniqueConstraint.Is(err), err)
+	// an update without a change of the fields succeeds
+	require.NoError(t, myTable.Save(ctx, &m2))
+
+	// HasFields
+	exists, err := compoundIdx.HasFields(ctx, group2, orm.EncodeSequence(1))
+	require.NoError(t, err)
+	assert.True(t, exists)
+	exists, err = compoundIdx.HasFields(ctx, group2, orm.EncodeSequence(2))
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	// GetFields
+	it, err := compoundIdx.GetFields(ctx, group1, orm.EncodeSequence(2))
+	require.NoError(t, err)
+	var loaded testdata.GroupMember
+	rowID, err := orm.First(it, &loaded)
+	require.NoError(t, err)
+	assert.Equal(t, orm.RowID(m2.PrimaryKey()), rowID)
+	assert.Equal(t, m2, loaded)
+
+	// PrefixScanFields by the leading field
+	it, err = compoundIdx.PrefixScanFields(ctx, group1)
+	require.NoError(t, err)
+	var members []testdata.GroupMember
+	_, err = orm.ReadAll(it, &members)
+	require.NoError(t, err)
+	assert.Equal(t, []testdata.GroupMember{m1, m2}, members)
+
+	_, err = compoundIdx.PrefixScanFields(ctx)
+	assert.True(t, orm.ErrArgument.Is(err), err)
+
+	// delete removes the fields
+	require.NoError(t, myTable.Delete(ctx, &m3))
+	exists, err = compoundIdx.HasFields(ctx, group2, orm.EncodeSequence(1))
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func TestLengthPrefixedKey(t *testing.T) {
+	specs := map[string]struct {
+		fields [][]byte
+		exp    orm.RowID
+		expErr *errors.Error
+	}{
+		"single field": {
+			fields: [][]byte{[]byte("ab")},
+			exp:    []byte{2, 'a', 'b'},
+		},
+		"multiple fields": {
+			fields: [][]byte{[]byte("ab"), []byte("c")},
+			exp:    []byte{2, 'a', 'b', 1, 'c'},
+		},
+		"other split of the same bytes": {
+			fields: [][]byte{[]byte("a"), []byte("bc")},
+			exp:    []byte{1, 'a', 2, 'b', 'c'},
+		},
+		"empty field": {
+			fields: [][]byte{{}, []byte("c")},
+			exp:    []byte{0, 1, 'c'},
+		},
+		"field too long": {
+			fields: [][]byte{make([]byte, 256)},
+			expErr: orm.ErrIndexKeyMaxLength,
+		},
+	}
+	for msg, spec := range specs {
+		t.Run(msg, func(t *testing.T) {
+			key, err := orm.LengthPrefixedKey(spec.fields...)
+			if spec.expErr != nil {
+				assert.True(t, spec.expErr.Is(err), err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, spec.exp, key)
+		})
+	}
+	t.Run("sorted by length first", func(t *testing.T) {
+		short, err := orm.LengthPrefixedKey([]byte("a"), []byte("c"))
+		require.NoError(t, err)
+		long, err := orm.LengthPrefixedKey([]byte("a"), []byte("bb"))
+		require.NoError(t, err)
+		assert.Equal(t, -1, bytes.Compare(short, long))
+	})
+}
+
 func TestPrefixRange(t *testing.T) {
 	cases := map[string]struct {
 		src      []byte
@@ -104,16 +104,30 @@ func uniqueKeysAddFunc(store sdk.KVStore, codec IndexKeyCodec, secondaryIndexKey
 	if len(secondaryIndexKey) == 0 {
 		return errors.Wrap(ErrArgument, "empty index key")
 	}
-	it := store.Iterator(PrefixRange(secondaryIndexKey))
-	defer it.Close()
-	if it.Valid() {
-		return ErrUniqueConstraint
+	if existing, ok := findIndexKey(store, codec, secondaryIndexKey); ok {
+		return errors.Wrapf(ErrUniqueConstraint, "index key %X used by row %X", secondaryIndexKey, existing)
 	}
 	indexKey := codec.BuildIndexKey(secondaryIndexKey, rowID)
 	store.Set(indexKey, []byte{})
 	return nil
 }
 
+// findIndexKey returns the RowID of the first entry for exactly the secondaryIndexKey. Entries of longer keys
+// that start with the secondaryIndexKey are skipped, which costs gas for each of them as the persisted
+// keys are not delimited.
+func findIndexKey(store sdk.KVStore, codec IndexKeyCodec, secondaryIndexKey []byte) (RowID, bool) {
+	it := store.Iterator(PrefixRange(secondaryIndexKey))
+	defer it.Close()
+	for ; it.Valid(); it.Next() {
+		indexKey := it.Key()
+		rowID := codec.StripRowID(indexKey)
+		if len(indexKey)-len(codec.BuildIndexKey(nil, rowID)) == len(secondaryIndexKey) {
+			return rowID, true
+		}
+	}
+	return nil, false
+}
+
 // multiKeyAddFunc allows multiple entries for a key
 func multiKeyAddFunc(store sdk.KVStore, codec IndexKeyCodec, secondaryIndexKey []byte, rowID RowID) error {
 	if len(secondaryIndexKey) == 0 {
@@ -285,6 +285,10 @@ func TestUniqueKeyAddFunc(t *testing.T) {
 			srcKey: []byte("my-preset-key"),
 			expErr: ErrUniqueConstraint,
 		},
+		"create when a longer key exists": {
+			srcKey:           []byte("my-preset"),
+			expExistingEntry: append([]byte("my-preset"), myRowID...),
+		},
 		"nil key not allowed": {
 			srcKey: nil,
 			expErr: ErrArgument,
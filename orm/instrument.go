@@ -0,0 +1,191 @@
+package orm
+
+import (
+	"github.com/cosmos/cosmos-sdk/codec"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// InstrumentedIterator returns a new iterator that reports the elements of the parent iterator to the
+// given callbacks, for example to record metrics on how many rows a query scans. onNext is called with
+// the result of every element that is read from the parent, including the final `ErrIteratorDone`.
+// onClose is called on the first Close with the number of elements that were read successfully.
+// Both callbacks are optional and do not get access to the destination model. The RowID passed to
+// onNext must not be modified.
+//
+// The iterator passes the page keys and totals of the parent through, so that it can be used with
+// Paginate like the parent. When the parent is a RawIterator, the returned iterator is one, too.
+// The parent iterator must not be nil.
+func InstrumentedIterator(parent Iterator, onNext func(rowID RowID, err error), onClose func(scanned int)) Iterator {
+	if parent == nil {
+		panic("parent iterator must not be nil")
+	}
+	it := &instrumentedIterator{parentIterator: parent, onNext: onNext, onClose: onClose}
+	if raw, ok := parent.(RawIterator); ok {
+		return &instrumentedRawIterator{instrumentedIterator: it, raw: raw}
+	}
+	return it
+}
+
+// ScanInfo describes a table or index scan for a `ScanHook`.
+type ScanInfo struct {
+	// StoreKey is the name of the store key of the table.
+	StoreKey string
+	// Prefix is the prefix of the table or index in the store.
+	Prefix byte
+	// Index is true for scans of an index and false for scans of a table.
+	Index bool
+}
+
+// ScanHook returns the callbacks of an `InstrumentedIterator` for a new table or index scan.
+// Both callbacks may be nil.
+type ScanHook func(scan ScanInfo) (onNext func(rowID RowID, err error), onClose func(scanned int))
+
+// scanHook is consulted by the table and index scans when set.
+var scanHook ScanHook
+
+// SetScanHook sets the hook that instruments the iterators of all table and index scans, like
+// `Table.PrefixScan` or `MultiKeyIndex.Get`, so that operators can record the scan lengths by table.
+// A nil hook removes it and the scans are not instrumented then.
+// The hook is not synchronized and must be set before any store is read, for example on
+// app creation.
+func SetScanHook(hook ScanHook) {
+	scanHook = hook
+}
+
+// instrumentScan wraps the iterator of a scan when a scan hook is set.
+func instrumentScan(storeKey sdk.StoreKey, prefix byte, index bool, it Iterator) Iterator {
+	if scanHook == nil {
+		return it
+	}
+	onNext, onClose := scanHook(ScanInfo{StoreKey: storeKey.Name(), Prefix: prefix, Index: index})
+	if onNext == nil && onClose == nil {
+		return it
+	}
+	return InstrumentedIterator(it, onNext, onClose)
+}
+
+var (
+	_ pageKeyIterator = &instrumentedIterator{}
+	_ totalCounter    = &instrumentedIterator{}
+	_ Rewinder        = &instrumentedIterator{}
+	_ Positioner      = &instrumentedIterator{}
+	_ RowIDPeeker     = &instrumentedIterator{}
+	_ RawIterator     = &instrumentedRawIterator{}
+	_ rowIDIterator   = &instrumentedRawIterator{}
+)
+
+// instrumentedIterator reports the elements of the parent iterator to the callbacks.
+type instrumentedIterator struct {
+	parentIterator Iterator
+	onNext         func(rowID RowID, err error)
+	onClose        func(scanned int)
+	scanned        int
+	closed         bool
+}
+
+// LoadNext loads the next value of the parent iterator into the pointer passed as dest and returns the key.
+// After Close the `ErrIteratorClosed` error is returned
+// The key is the rowID and not any MultiKeyIndex key.
+func (i *instrumentedIterator) LoadNext(dest codec.ProtoMarshaler) (RowID, error) {
+	if i.closed {
+		return nil, ErrIteratorClosed
+	}
+	rowID, err := i.parentIterator.LoadNext(dest)
+	i.report(rowID, err)
+	return rowID, err
+}
+
+// nextPageKey returns the page key of the next element of the parent.
+func (i *instrumentedIterator) nextPageKey(dest codec.ProtoMarshaler) ([]byte, error) {
+	if i.closed {
+		return nil, ErrIteratorClosed
+	}
+	key, err := nextPageKey(i.parentIterator, dest)
+	rowID := RowID(key)
+	if err == nil && isCursor(key) {
+		_, rowID, _ = DecodeCursor(key)
+	}
+	i.report(rowID, err)
+	return key, err
+}
+
+// Rewind rewinds the parent iterator, see `Rewind`. The elements that are read again are reported again.
+func (i *instrumentedIterator) Rewind() error {
+	if i.closed {
+		return ErrIteratorClosed
+	}
+	return Rewind(i.parentIterator)
+}
+
+// PeekRowID returns the rowID of the next element of the parent, see `PeekRowID`. The element is reported
+// when it is consumed.
+func (i *instrumentedIterator) PeekRowID() (RowID, error) {
+	if i.closed {
+		return nil, ErrIteratorClosed
+	}
+	return PeekRowID(i.parentIterator)
+}
+
+// Position returns the position of the parent, or nil when the parent is not a Positioner.
+func (i *instrumentedIterator) Position() []byte {
+	if p, ok := i.parentIterator.(Positioner); ok {
+		return p.Position()
+	}
+	return nil
+}
+
+// countTotal returns the total of the parent when it can count one.
+func (i *instrumentedIterator) countTotal() (uint64, bool, error) {
+	if tc, ok := i.parentIterator.(totalCounter); ok {
+		return tc.countTotal()
+	}
+	return 0, false, nil
+}
+
+func (i *instrumentedIterator) report(rowID RowID, err error) {
+	if err == nil {
+		i.scanned++
+	}
+	if i.onNext != nil {
+		i.onNext(rowID, err)
+	}
+}
+
+// Close releases the iterator and should be called at the end of iteration.
+// Only the first call closes the parent iterator and calls onClose.
+func (i *instrumentedIterator) Close() error {
+	if i.closed {
+		return nil
+	}
+	i.closed = true
+	if i.onClose != nil {
+		i.onClose(i.scanned)
+	}
+	return i.parentIterator.Close()
+}
+
+// instrumentedRawIterator is an instrumentedIterator over a RawIterator.
+type instrumentedRawIterator struct {
+	*instrumentedIterator
+	raw RawIterator
+}
+
+// RawNext returns the RowID and persisted bytes of the next element of the parent iterator.
+func (i *instrumentedRawIterator) RawNext() (RowID, []byte, error) {
+	if i.closed {
+		return nil, nil, ErrIteratorClosed
+	}
+	rowID, value, err := i.raw.RawNext()
+	i.report(rowID, err)
+	return rowID, value, err
+}
+
+// nextRowID advances the parent by one element without unmarshaling it.
+func (i *instrumentedRawIterator) nextRowID() (RowID, error) {
+	if i.closed {
+		return nil, ErrIteratorClosed
+	}
+	rowID, err := nextRowID(i.raw, nil)
+	i.report(rowID, err)
+	return rowID, err
+}
@@ -0,0 +1,225 @@
+package orm_test
+
+import (
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	"github.com/cosmos/cosmos-sdk/codec/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/query"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/regen-network/regen-ledger/orm"
+	"github.com/regen-network/regen-ledger/testutil/testdata"
+)
+
+func TestInstrumentedIterator(t *testing.T) {
+	interfaceRegistry := types.NewInterfaceRegistry()
+	cdc := codec.NewProtoCodec(interfaceRegistry)
+
+	storeKey := sdk.NewKVStoreKey("test")
+	const (
+		testTablePrefix = iota
+		testTableSeqPrefix
+	)
+	tBuilder := orm.NewAutoUInt64TableBuilder(testTablePrefix, testTableSeqPrefix, storeKey, &testdata.GroupInfo{}, cdc)
+	idx := orm.NewIndex(tBuilder, GroupByAdminIndexPrefix, func(val interface{}) ([]orm.RowID, error) {
+		return []orm.RowID{[]byte(val.(*testdata.GroupInfo).Admin)}, nil
+	})
+	tb := tBuilder.Build()
+	ctx := orm.NewMockContext()
+
+	admin := sdk.AccAddress([]byte("admin-address"))
+	for i := 1; i <= 3; i++ {
+		_, err := tb.Create(ctx, &testdata.GroupInfo{Description: "my test", Admin: admin})
+		require.NoError(t, err)
+	}
+	readAll := func(it orm.Iterator) error {
+		var loaded []testdata.GroupInfo
+		_, err := orm.ReadAll(it, &loaded)
+		return err
+	}
+	paginate := func(pageReq *query.PageRequest) func(orm.Iterator) error {
+		return func(it orm.Iterator) error {
+			var loaded []testdata.GroupInfo
+			_, err := orm.Paginate(it, pageReq, &loaded)
+			return err
+		}
+	}
+
+	specs := map[string]struct {
+		src        func() (orm.Iterator, error)
+		read       func(orm.Iterator) error
+		expRowIDs  []orm.RowID
+		expErrs    int
+		expScanned int
+	}{
+		"table read all": {
+			src:        func() (orm.Iterator, error) { return tb.PrefixScan(ctx, 1, 100) },
+			read:       readAll,
+			expRowIDs:  []orm.RowID{orm.EncodeSequence(1), orm.EncodeSequence(2), orm.EncodeSequence(3)},
+			expErrs:    1,
+			expScanned: 3,
+		},
+		"table page with offset": {
+			src:        func() (orm.Iterator, error) { return tb.PrefixScan(ctx, 1, 100) },
+			read:       paginate(&query.PageRequest{Offset: 1, Limit: 1}),
+			expRowIDs:  []orm.RowID{orm.EncodeSequence(1), orm.EncodeSequence(2), orm.EncodeSequence(3)},
+			expScanned: 3,
+		},
+		"index page with next key": {
+			src:        func() (orm.Iterator, error) { return idx.GetPaginated(ctx, admin, nil) },
+			read:       paginate(&query.PageRequest{Limit: 2}),
+			expRowIDs:  []orm.RowID{orm.EncodeSequence(1), orm.EncodeSequence(2), orm.EncodeSequence(3)},
+			expScanned: 3,
+		},
+		"empty": {
+			src:     func() (orm.Iterator, error) { return tb.PrefixScan(ctx, 100, 200) },
+			read:    readAll,
+			expErrs: 1,
+		},
+		"invalid parent": {
+			src:     func() (orm.Iterator, error) { return orm.NewInvalidIterator(), nil },
+			read:    readAll,
+			expErrs: 1,
+		},
+	}
+	for msg, spec := range specs {
+		t.Run(msg, func(t *testing.T) {
+			parent, err := spec.src()
+			require.NoError(t, err)
+			var rowIDs []orm.RowID
+			var errs, closed int
+			scanned := -1
+			it := orm.InstrumentedIterator(parent, func(rowID orm.RowID, err error) {
+				if err != nil {
+					errs++
+					return
+				}
+				rowIDs = append(rowIDs, rowID)
+			}, func(n int) {
+				closed++
+				scanned = n
+			})
+			_, isRaw := parent.(orm.RawIterator)
+			_, ok := it.(orm.RawIterator)
+			assert.Equal(t, isRaw, ok)
+
+			_ = spec.read(it)
+			require.NoError(t, it.Close())
+			require.NoError(t, it.Close())
+			assert.Equal(t, spec.expRowIDs, rowIDs)
+			assert.Equal(t, spec.expErrs, errs)
+			assert.Equal(t, 1, closed)
+			assert.Equal(t, spec.expScanned, scanned)
+		})
+	}
+	t.Run("page is not changed", func(t *testing.T) {
+		for _, pageReq := range []*query.PageRequest{
+			{Limit: 1, CountTotal: true},
+			{Key: orm.EncodeCursor(admin, orm.EncodeSequence(2)), Limit: 1, CountTotal: true},
+			{Offset: 1, Limit: 1, CountTotal: true},
+		} {
+			var exp, loaded []testdata.GroupInfo
+			it, err := idx.GetPaginated(ctx, admin, pageReq)
+			require.NoError(t, err)
+			expRes, err := orm.Paginate(it, pageReq, &exp)
+			require.NoError(t, err)
+
+			it, err = idx.GetPaginated(ctx, admin, pageReq)
+			require.NoError(t, err)
+			res, err := orm.Paginate(orm.InstrumentedIterator(it, nil, nil), pageReq, &loaded)
+			require.NoError(t, err)
+			assert.Equal(t, expRes, res)
+			assert.Equal(t, exp, loaded)
+		}
+	})
+}
+
+func TestSetScanHook(t *testing.T) {
+	interfaceRegistry := types.NewInterfaceRegistry()
+	cdc := codec.NewProtoCodec(interfaceRegistry)
+
+	storeKey := sdk.NewKVStoreKey("test")
+	const (
+		testTablePrefix = iota
+		testTableSeqPrefix
+	)
+	tBuilder := orm.NewAutoUInt64TableBuilder(testTablePrefix, testTableSeqPrefix, storeKey, &testdata.GroupInfo{}, cdc)
+	idx := orm.NewIndex(tBuilder, GroupByAdminIndexPrefix, func(val interface{}) ([]orm.RowID, error) {
+		return []orm.RowID{[]byte(val.(*testdata.GroupInfo).Admin)}, nil
+	})
+	tb := tBuilder.Build()
+	ctx := orm.NewMockContext()
+
+	admin := sdk.AccAddress([]byte("admin-address"))
+	for i := 1; i <= 2; i++ {
+		_, err := tb.Create(ctx, &testdata.GroupInfo{Description: "my test", Admin: admin})
+		require.NoError(t, err)
+	}
+
+	var scans []orm.ScanInfo
+	var scanned []int
+	orm.SetScanHook(func(scan orm.ScanInfo) (func(orm.RowID, error), func(int)) {
+		scans = append(scans, scan)
+		return nil, func(n int) { scanned = append(scanned, n) }
+	})
+	defer orm.SetScanHook(nil)
+
+	specs := map[string]struct {
+		src     func() (orm.Iterator, error)
+		expScan orm.ScanInfo
+	}{
+		"table prefix scan": {
+			src:     func() (orm.Iterator, error) { return tb.PrefixScan(ctx, 1, 100) },
+			expScan: orm.ScanInfo{StoreKey: "test", Prefix: testTablePrefix},
+		},
+		"table scan with batch size": {
+			src: func() (orm.Iterator, error) {
+				return tb.ReversePrefixScanWithOpts(ctx, 1, 100, orm.ScanOpts{BatchSize: 1})
+			},
+			expScan: orm.ScanInfo{StoreKey: "test", Prefix: testTablePrefix},
+		},
+		"index get": {
+			src:     func() (orm.Iterator, error) { return idx.Get(ctx, admin) },
+			expScan: orm.ScanInfo{StoreKey: "test", Prefix: GroupByAdminIndexPrefix, Index: true},
+		},
+		"index paginated": {
+			src:     func() (orm.Iterator, error) { return idx.GetPaginated(ctx, admin, nil) },
+			expScan: orm.ScanInfo{StoreKey: "test", Prefix: GroupByAdminIndexPrefix, Index: true},
+		},
+	}
+	for msg, spec := range specs {
+		t.Run(msg, func(t *testing.T) {
+			scans, scanned = nil, nil
+			it, err := spec.src()
+			require.NoError(t, err)
+			var loaded []testdata.GroupInfo
+			_, err = orm.ReadAll(it, &loaded)
+			require.NoError(t, err)
+			require.Len(t, loaded, 2)
+			assert.Equal(t, []orm.ScanInfo{spec.expScan}, scans)
+			assert.Equal(t, []int{2}, scanned)
+		})
+	}
+	t.Run("without callbacks", func(t *testing.T) {
+		orm.SetScanHook(func(scan orm.ScanInfo) (func(orm.RowID, error), func(int)) {
+			return nil, nil
+		})
+		it, err := idx.Get(ctx, admin)
+		require.NoError(t, err)
+		var loaded []testdata.GroupInfo
+		_, err = orm.ReadAll(it, &loaded)
+		require.NoError(t, err)
+		assert.Len(t, loaded, 2)
+	})
+	t.Run("removed", func(t *testing.T) {
+		scans = nil
+		orm.SetScanHook(nil)
+		it, err := tb.PrefixScan(ctx, 1, 100)
+		require.NoError(t, err)
+		require.NoError(t, it.Close())
+		assert.Empty(t, scans)
+	})
+}
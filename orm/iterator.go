@@ -1,16 +1,24 @@
 package orm
 
 import (
+	"bytes"
+	"context"
 	"fmt"
+	"io"
+	"math"
 	"reflect"
+	"sort"
 
 	"github.com/cosmos/cosmos-sdk/codec"
+	"github.com/cosmos/cosmos-sdk/store/types"
 	"github.com/cosmos/cosmos-sdk/types/errors"
 	"github.com/cosmos/cosmos-sdk/types/query"
 )
 
 // IteratorFunc is a function type that satisfies the Iterator interface
 // The passed function is called on LoadNext operations.
+// An IteratorFunc has no state, so that it can not follow the Close contract of the Iterator by itself.
+// The function is still called after Close.
 type IteratorFunc func(dest codec.ProtoMarshaler) (RowID, error)
 
 // LoadNext loads the next value in the sequence into the pointer passed as dest and returns the key. If there
@@ -25,100 +33,1563 @@ func (i IteratorFunc) Close() error {
 	return nil
 }
 
+// NewSingleValueIterator returns an iterator over the value only, which is unmarshaled into dest on the first
+// successful call. A nil val results in an empty iterator.
 func NewSingleValueIterator(rowID RowID, val []byte) Iterator {
-	var closed bool
-	return IteratorFunc(func(dest codec.ProtoMarshaler) (RowID, error) {
+	var done bool
+	return &closeGuardIterator{parentIterator: IteratorFunc(func(dest codec.ProtoMarshaler) (RowID, error) {
+		if done || val == nil {
+			return nil, ErrIteratorDone
+		}
 		if dest == nil {
 			return nil, errors.Wrap(ErrArgument, "destination object must not be nil")
 		}
-		if closed || val == nil {
-			return nil, ErrIteratorDone
+		if err := dest.Unmarshal(val); err != nil {
+			return nil, err
 		}
-		closed = true
-		return rowID, dest.Unmarshal(val)
-	})
+		done = true
+		return rowID, nil
+	})}
+}
+
+// NewSliceIterator returns an iterator over pre-loaded elements, like the results of a cache, that returns
+// the rowIDs with the values at the same positions in order and the `ErrIteratorDone` error afterwards.
+// The values are unmarshaled into the destination with its Unmarshal method like for `FromKVStoreIterator`.
+// The iterator supports `Rewind` and does not copy the slices, so they must not be modified while it is used.
+// Empty slices result in an empty iterator.
+// When the slices differ in length or a value is nil, the iterator returns an `ErrArgument` error only.
+func NewSliceIterator(rowIDs []RowID, values [][]byte) Iterator {
+	if len(rowIDs) != len(values) {
+		return newErrorIterator(errors.Wrapf(ErrArgument, "%d rowIDs for %d values", len(rowIDs), len(values)), nil)
+	}
+	for i, v := range values {
+		if v == nil {
+			return newErrorIterator(errors.Wrapf(ErrArgument, "value %d of row %X must not be nil", i, rowIDs[i]), nil)
+		}
+	}
+	return &sliceIterator{rowIDs: rowIDs, values: values}
+}
+
+var (
+	_ RawIterator = &sliceIterator{}
+	_ Rewinder    = &sliceIterator{}
+	_ RowIDPeeker = &sliceIterator{}
+)
+
+// sliceIterator returns the elements of a slice.
+type sliceIterator struct {
+	rowIDs []RowID
+	values [][]byte
+	pos    int
+	closed bool
+}
+
+// LoadNext loads the next value in the sequence into the pointer passed as dest and returns the key. If there
+// are no more items the `ErrIteratorDone` error is returned
+func (i *sliceIterator) LoadNext(dest codec.ProtoMarshaler) (RowID, error) {
+	if dest == nil {
+		return nil, errors.Wrap(ErrArgument, "destination object must not be nil")
+	}
+	rowID, value, err := i.RawNext()
+	if err != nil {
+		return nil, err
+	}
+	dest.Reset()
+	if err := dest.Unmarshal(value); err != nil {
+		return nil, errors.Wrapf(err, "unmarshal row %X", rowID)
+	}
+	return rowID, nil
+}
+
+// RawNext returns the rowID and the bytes of the next element without unmarshaling them.
+func (i *sliceIterator) RawNext() (RowID, []byte, error) {
+	if i.closed {
+		return nil, nil, ErrIteratorClosed
+	}
+	if i.pos == len(i.rowIDs) {
+		return nil, nil, ErrIteratorDone
+	}
+	rowID, value := i.rowIDs[i.pos], i.values[i.pos]
+	i.pos++
+	return rowID, value, nil
+}
+
+// PeekRowID returns the rowID of the next element without consuming it.
+func (i *sliceIterator) PeekRowID() (RowID, error) {
+	if i.closed {
+		return nil, ErrIteratorClosed
+	}
+	if i.pos == len(i.rowIDs) {
+		return nil, ErrIteratorDone
+	}
+	return i.rowIDs[i.pos], nil
+}
+
+// Rewind restarts the iteration at the first element.
+func (i *sliceIterator) Rewind() error {
+	if i.closed {
+		return ErrIteratorClosed
+	}
+	i.pos = 0
+	return nil
+}
+
+// Close releases the slices and should be called at the end of iteration.
+func (i *sliceIterator) Close() error {
+	i.closed = true
+	i.rowIDs, i.values = nil, nil
+	return nil
 }
 
 // Iterator that return ErrIteratorInvalid only.
 func NewInvalidIterator() Iterator {
-	return IteratorFunc(func(dest codec.ProtoMarshaler) (RowID, error) {
+	return &closeGuardIterator{parentIterator: IteratorFunc(func(dest codec.ProtoMarshaler) (RowID, error) {
 		return nil, ErrIteratorInvalid
-	})
+	})}
+}
+
+// closeGuardIterator adds the Close contract to a parent iterator that does not track its state itself.
+type closeGuardIterator struct {
+	parentIterator Iterator
+	closed         bool
+}
+
+// LoadNext loads the next value of the parent iterator into the pointer passed as dest and returns the key.
+// After Close the `ErrIteratorClosed` error is returned
+// The key is the rowID and not any MultiKeyIndex key.
+func (i *closeGuardIterator) LoadNext(dest codec.ProtoMarshaler) (RowID, error) {
+	if i.closed {
+		return nil, ErrIteratorClosed
+	}
+	return i.parentIterator.LoadNext(dest)
+}
+
+// Close releases the iterator and should be called at the end of iteration.
+// Only the first call closes the parent iterator.
+func (i *closeGuardIterator) Close() error {
+	if i.closed {
+		return nil
+	}
+	i.closed = true
+	return i.parentIterator.Close()
+}
+
+var _ RawIterator = &errorIterator{}
+
+// errorIterator is returned by the constructors of iterators for invalid arguments, so that the error is
+// returned on the first read instead of a panic. An iterator or store iterator that was passed to the
+// constructor is closed with it.
+type errorIterator struct {
+	err    error
+	closer io.Closer
+	closed bool
+}
+
+func newErrorIterator(err error, closer io.Closer) *errorIterator {
+	return &errorIterator{err: err, closer: closer}
+}
+
+// LoadNext returns the error of the iterator.
+func (i *errorIterator) LoadNext(codec.ProtoMarshaler) (RowID, error) {
+	_, _, err := i.RawNext()
+	return nil, err
+}
+
+// RawNext returns the error of the iterator.
+func (i *errorIterator) RawNext() (RowID, []byte, error) {
+	if i.closed {
+		return nil, nil, ErrIteratorClosed
+	}
+	return nil, nil, i.err
+}
+
+// Close closes the iterator that was passed to the constructor, if any.
+func (i *errorIterator) Close() error {
+	if i.closed {
+		return nil
+	}
+	i.closed = true
+	if i.closer == nil {
+		return nil
+	}
+	return i.closer.Close()
+}
+
+// FromKVStoreIterator returns a new iterator over the elements of a store iterator, like one returned by
+// `KVStore.Iterator`, so that it can be used with Paginate, ReadAll and the other iterator functions.
+// The store key without the first stripPrefix bytes is used as RowID. The value is unmarshaled into the
+// destination with its Unmarshal method, so that interfaces are not unpacked. Close closes the store
+// iterator and the error of the store iterator is returned when it becomes invalid.
+// The store iterator must not be nil.
+// stripPrefix can be 0 or any positive number
+// For invalid arguments the iterator returns an `ErrArgument` error only.
+func FromKVStoreIterator(it types.Iterator, stripPrefix int) Iterator {
+	if it == nil {
+		return newErrorIterator(errors.Wrap(ErrArgument, "store iterator must not be nil"), nil)
+	}
+	if stripPrefix < 0 {
+		return newErrorIterator(errors.Wrap(ErrArgument, "prefix length must not be negative"), it)
+	}
+	return &kvStoreIterator{it: it, stripPrefix: stripPrefix}
+}
+
+var _ RawIterator = &kvStoreIterator{}
+
+// kvStoreIterator adapts a store iterator.
+type kvStoreIterator struct {
+	it          types.Iterator
+	stripPrefix int
+	closed      bool
+}
+
+// LoadNext loads the next value in the sequence into the pointer passed as dest and returns the key. If there
+// are no more items the `ErrIteratorDone` error is returned
+// The key is the store key without the stripped prefix.
+func (i *kvStoreIterator) LoadNext(dest codec.ProtoMarshaler) (RowID, error) {
+	if dest == nil {
+		return nil, errors.Wrap(ErrArgument, "destination object must not be nil")
+	}
+	rowID, value, err := i.RawNext()
+	if err != nil {
+		return nil, err
+	}
+	dest.Reset()
+	if err := dest.Unmarshal(value); err != nil {
+		return nil, errors.Wrapf(err, "unmarshal row %X", rowID)
+	}
+	return rowID, nil
+}
+
+// RawNext returns the key without the stripped prefix and the bytes of the next element without
+// unmarshaling them.
+func (i *kvStoreIterator) RawNext() (RowID, []byte, error) {
+	if i.closed {
+		return nil, nil, ErrIteratorClosed
+	}
+	if !i.it.Valid() {
+		if err := i.it.Error(); err != nil {
+			return nil, nil, err
+		}
+		return nil, nil, ErrIteratorDone
+	}
+	key, value := i.it.Key(), i.it.Value()
+	if len(key) <= i.stripPrefix {
+		return nil, nil, errors.Wrapf(ErrArgument, "key %X not longer than prefix length %d", key, i.stripPrefix)
+	}
+	if value == nil {
+		value = []byte{}
+	}
+	i.it.Next()
+	return key[i.stripPrefix:], value, nil
+}
+
+// Close releases the iterator and should be called at the end of iteration.
+// Only the first call closes the store iterator.
+func (i *kvStoreIterator) Close() error {
+	if i.closed {
+		return nil
+	}
+	i.closed = true
+	return i.it.Close()
+}
+
+// BufferedIterator returns a new iterator that reads up to batchSize elements of the parent ahead into memory
+// and serves them from the buffer, refilling it when all were returned. This separates the store reads from
+// unmarshaling the values, which is done on LoadNext with the Unmarshal method of the destination like for
+// `FromKVStoreIterator`. An error of the parent is returned after the buffered elements.
+// The parent iterator must not be nil
+// batchSize must be a positive number
+// For invalid arguments the iterator returns an `ErrArgument` error only.
+func BufferedIterator(parent RawIterator, batchSize int) Iterator {
+	if parent == nil {
+		return newErrorIterator(errors.Wrap(ErrArgument, "parent iterator must not be nil"), nil)
+	}
+	if batchSize <= 0 {
+		return newErrorIterator(errors.Wrap(ErrArgument, "batch size must be positive"), parent)
+	}
+	return newBufferedIterator(parent, batchSize, unmarshalModel)
+}
+
+func newBufferedIterator(parent RawIterator, batchSize int, unmarshal func([]byte, codec.ProtoMarshaler) error) *bufferedIterator {
+	return &bufferedIterator{parentIterator: parent, batchSize: batchSize, unmarshal: unmarshal}
+}
+
+func unmarshalModel(value []byte, dest codec.ProtoMarshaler) error {
+	dest.Reset()
+	return dest.Unmarshal(value)
+}
+
+var (
+	_ RawIterator = &bufferedIterator{}
+	_ Rewinder    = &bufferedIterator{}
+	_ RowIDPeeker = &bufferedIterator{}
+)
+
+// bufferedIterator reads the elements of the parent in batches.
+type bufferedIterator struct {
+	parentIterator RawIterator
+	batchSize      int
+	unmarshal      func([]byte, codec.ProtoMarshaler) error
+	rowIDs         []RowID
+	values         [][]byte
+	pos            int
+	// err is the error of the parent that ended the last batch
+	err    error
+	closed bool
+}
+
+// LoadNext loads the next value in the sequence into the pointer passed as dest and returns the key. If there
+// are no more items the `ErrIteratorDone` error is returned
+// The key is the rowID and not any MultiKeyIndex key.
+func (i *bufferedIterator) LoadNext(dest codec.ProtoMarshaler) (RowID, error) {
+	if dest == nil {
+		return nil, errors.Wrap(ErrArgument, "destination object must not be nil")
+	}
+	rowID, value, err := i.RawNext()
+	if err != nil {
+		return nil, err
+	}
+	if err := i.unmarshal(value, dest); err != nil {
+		return nil, errors.Wrapf(err, "unmarshal row %X", rowID)
+	}
+	return rowID, nil
+}
+
+// RawNext returns the rowID and the persisted bytes of the next element from the buffer without
+// unmarshaling them. The next batch is read from the parent when the buffer is empty.
+func (i *bufferedIterator) RawNext() (RowID, []byte, error) {
+	if i.closed {
+		return nil, nil, ErrIteratorClosed
+	}
+	if i.pos == len(i.rowIDs) {
+		if i.err != nil {
+			return nil, nil, i.err
+		}
+		i.fill()
+		if i.pos == len(i.rowIDs) {
+			return nil, nil, i.err
+		}
+	}
+	rowID, value := i.rowIDs[i.pos], i.values[i.pos]
+	// release the element for the garbage collector, the buffer is reused for the next batch
+	i.rowIDs[i.pos], i.values[i.pos] = nil, nil
+	i.pos++
+	return rowID, value, nil
+}
+
+// PeekRowID returns the rowID of the next element from the buffer without consuming it. The next batch is
+// read from the parent when the buffer is empty.
+func (i *bufferedIterator) PeekRowID() (RowID, error) {
+	if i.closed {
+		return nil, ErrIteratorClosed
+	}
+	if i.pos == len(i.rowIDs) {
+		if i.err != nil {
+			return nil, i.err
+		}
+		i.fill()
+		if i.pos == len(i.rowIDs) {
+			return nil, i.err
+		}
+	}
+	return i.rowIDs[i.pos], nil
+}
+
+// fill reads the next batch from the parent. It stops at the first error of the parent, which is kept
+// to be returned when the buffer is empty again.
+func (i *bufferedIterator) fill() {
+	i.rowIDs, i.values, i.pos = i.rowIDs[:0], i.values[:0], 0
+	for len(i.rowIDs) < i.batchSize {
+		rowID, value, err := i.parentIterator.RawNext()
+		if err != nil {
+			i.err = err
+			return
+		}
+		i.rowIDs = append(i.rowIDs, rowID)
+		i.values = append(i.values, value)
+	}
+}
+
+// Rewind rewinds the parent iterator, see `Rewind`, and drops the buffered elements.
+func (i *bufferedIterator) Rewind() error {
+	if i.closed {
+		return ErrIteratorClosed
+	}
+	if err := Rewind(i.parentIterator); err != nil {
+		return err
+	}
+	i.rowIDs, i.values, i.pos, i.err = i.rowIDs[:0], i.values[:0], 0, nil
+	return nil
+}
+
+// Close releases the buffer and the parent iterator and should be called at the end of iteration.
+// Only the first call closes the parent iterator.
+func (i *bufferedIterator) Close() error {
+	if i.closed {
+		return nil
+	}
+	i.closed = true
+	i.rowIDs, i.values = nil, nil
+	return i.parentIterator.Close()
 }
 
 // LimitedIterator returns up to defined maximum number of elements.
+// Copies of a LimitedIterator share their state, so that the limit applies to all of them together.
+// The zero value is an exhausted iterator.
 type LimitedIterator struct {
-	remainingCount int
+	state          *limitedIteratorState
 	parentIterator Iterator
 }
 
+type limitedIteratorState struct {
+	remainingCount int
+	closed         bool
+}
+
 // LimitIterator returns a new iterator that returns max number of elements.
+// It panics on invalid arguments, see `NewLimitIterator` for a variant that returns an error instead.
 // The parent iterator must not be nil
 // max can be 0 or any positive number
 func LimitIterator(parent Iterator, max int) *LimitedIterator {
+	it, err := NewLimitIterator(parent, max)
+	if err != nil {
+		panic(err)
+	}
+	return it
+}
+
+// NewLimitIterator returns a new iterator that returns max number of elements.
+// An `ErrArgument` error is returned when the parent iterator is nil or max is negative.
+func NewLimitIterator(parent Iterator, max int) (*LimitedIterator, error) {
 	if max < 0 {
-		panic("quantity must not be negative")
+		return nil, errors.Wrap(ErrArgument, "quantity must not be negative")
 	}
 	if parent == nil {
-		panic("parent iterator must not be nil")
+		return nil, errors.Wrap(ErrArgument, "parent iterator must not be nil")
 	}
-	return &LimitedIterator{remainingCount: max, parentIterator: parent}
+	return &LimitedIterator{state: &limitedIteratorState{remainingCount: max}, parentIterator: parent}, nil
 }
 
 // LoadNext loads the next value in the sequence into the pointer passed as dest and returns the key. If there
-// are no more items or the defined max number of elements was returned the `ErrIteratorDone` error is returned
+// are no more items or the defined max number of elements was returned the `ErrIteratorDone` error is
+// returned. After Close the `ErrIteratorClosed` error is returned
 // The key is the rowID and not any MultiKeyIndex key.
 func (i *LimitedIterator) LoadNext(dest codec.ProtoMarshaler) (RowID, error) {
-	if i.remainingCount == 0 {
-		return nil, ErrIteratorDone
+	if err := i.take(); err != nil {
+		return nil, err
 	}
-	i.remainingCount--
 	return i.parentIterator.LoadNext(dest)
 }
 
-// Close releases the iterator and should be called at the end of iteration
-func (i LimitedIterator) Close() error {
+// nextPageKey returns the page key of the next element of the parent within the limit.
+func (i *LimitedIterator) nextPageKey(dest codec.ProtoMarshaler) ([]byte, error) {
+	if err := i.take(); err != nil {
+		return nil, err
+	}
+	return nextPageKey(i.parentIterator, dest)
+}
+
+// take counts the next element against the limit. A LimitedIterator without state, like the zero value, is
+// exhausted.
+func (i *LimitedIterator) take() error {
+	switch {
+	case i.state == nil:
+		return ErrIteratorDone
+	case i.state.closed:
+		return ErrIteratorClosed
+	case i.state.remainingCount == 0:
+		return ErrIteratorDone
+	}
+	i.state.remainingCount--
+	return nil
+}
+
+// Close releases the iterator and should be called at the end of iteration.
+// Only the first call closes the parent iterator.
+func (i *LimitedIterator) Close() error {
+	if i.state == nil {
+		if i.parentIterator == nil {
+			return nil
+		}
+		return i.parentIterator.Close()
+	}
+	if i.state.closed {
+		return nil
+	}
+	i.state.closed = true
 	return i.parentIterator.Close()
 }
 
-// First loads the first element into the given destination type and closes the iterator.
-// When the iterator is closed or has no elements the according error is passed as return value.
-func First(it Iterator, dest codec.ProtoMarshaler) (RowID, error) {
-	if it == nil {
-		return nil, errors.Wrap(ErrArgument, "iterator must not be nil")
+// CtxIterator returns a new iterator that checks the context before every element is loaded from the parent
+// iterator, so that long scans can be aborted when a client disconnects. When the context is done the
+// parent iterator is closed and the context error, `context.Canceled` or `context.DeadlineExceeded`, is
+// returned wrapped.
+// The context and parent iterator must not be nil
+func CtxIterator(ctx context.Context, parent Iterator) Iterator {
+	if ctx == nil {
+		panic("context must not be nil")
 	}
-	defer it.Close()
-	binKey, err := it.LoadNext(dest)
-	if err != nil {
+	if parent == nil {
+		panic("parent iterator must not be nil")
+	}
+	return &ctxIterator{ctx: ctx, parentIterator: parent}
+}
+
+// ctxIterator stops on a done context.
+type ctxIterator struct {
+	ctx            context.Context
+	parentIterator Iterator
+	parentClosed   bool
+	closeErr       error
+	closed         bool
+}
+
+// LoadNext loads the next value in the sequence into the pointer passed as dest and returns the key. If there
+// are no more items the `ErrIteratorDone` error is returned. When the context is done the wrapped context
+// error is returned
+// The key is the rowID and not any MultiKeyIndex key.
+func (i *ctxIterator) LoadNext(dest codec.ProtoMarshaler) (RowID, error) {
+	if i.closed {
+		return nil, ErrIteratorClosed
+	}
+	if err := ctxErr(i.ctx); err != nil {
+		i.closeParent()
+		return nil, err
+	}
+	return i.parentIterator.LoadNext(dest)
+}
+
+// Close releases the iterator and should be called at the end of iteration.
+// Only the first call closes the parent iterator, unless it was closed because the context is done.
+// The error of closing the parent iterator is returned in both cases.
+func (i *ctxIterator) Close() error {
+	if i.closed {
+		return nil
+	}
+	i.closed = true
+	i.closeParent()
+	return i.closeErr
+}
+
+func (i *ctxIterator) closeParent() {
+	if i.parentClosed {
+		return
+	}
+	i.parentClosed = true
+	i.closeErr = i.parentIterator.Close()
+}
+
+// ctxErr returns the wrapped context error when the context is done.
+func ctxErr(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return errors.Wrap(err, "iteration stopped")
+	}
+	return nil
+}
+
+// PeekableIterator can return the next element of the parent iterator without consuming it.
+type PeekableIterator struct {
+	parentIterator Iterator
+	// next is the element loaded by Peek that was not consumed, yet
+	next   codec.ProtoMarshaler
+	rowID  RowID
+	err    error
+	done   bool
+	closed bool
+}
+
+// PeekIterator returns a new iterator that allows to look at the next element of the parent iterator
+// before it is consumed with LoadNext. Exactly one element is buffered.
+// The parent iterator must not be nil, or the iterator returns an `ErrArgument` error only.
+func PeekIterator(parent Iterator) *PeekableIterator {
+	if parent == nil {
+		return &PeekableIterator{parentIterator: newErrorIterator(errors.Wrap(ErrArgument, "parent iterator must not be nil"), nil)}
+	}
+	return &PeekableIterator{parentIterator: parent}
+}
+
+// Peek loads the next value in the sequence into the pointer passed as dest and returns the key without
+// consuming it, so that the same element is returned by the next Peek or LoadNext call. The value is
+// unmarshaled only once. If there are no more items the `ErrIteratorDone` error is returned
+// The key is the rowID and not any MultiKeyIndex key.
+func (i *PeekableIterator) Peek(dest codec.ProtoMarshaler) (RowID, error) {
+	if i.closed {
+		return nil, ErrIteratorClosed
+	}
+	if dest == nil {
+		return nil, errors.Wrap(ErrArgument, "destination object must not be nil")
+	}
+	if i.done {
+		return nil, ErrIteratorDone
+	}
+	if i.next == nil && i.err == nil {
+		next := reflect.New(reflect.TypeOf(dest).Elem()).Interface().(codec.ProtoMarshaler)
+		rowID, err := i.parentIterator.LoadNext(next)
+		if err != nil {
+			if IsIteratorDone(err) {
+				i.done = true
+			}
+			i.err = err
+			return nil, err
+		}
+		i.next, i.rowID = next, rowID
+	}
+	if i.err != nil {
+		return nil, i.err
+	}
+	if err := copyModel(i.next, dest); err != nil {
+		return nil, err
+	}
+	return i.rowID, nil
+}
+
+// PeekRowID returns the rowID of the next element without consuming it. It is the rowID of an element
+// loaded by Peek or otherwise the one of the parent iterator, see `PeekRowID`.
+func (i *PeekableIterator) PeekRowID() (RowID, error) {
+	if i.closed {
+		return nil, ErrIteratorClosed
+	}
+	if i.done {
+		return nil, ErrIteratorDone
+	}
+	if i.err != nil {
+		return nil, i.err
+	}
+	if i.next != nil {
+		return i.rowID, nil
+	}
+	return PeekRowID(i.parentIterator)
+}
+
+// LoadNext loads the next value in the sequence into the pointer passed as dest and returns the key. An
+// element returned by Peek before is returned first. If there are no more items the `ErrIteratorDone`
+// error is returned
+// The key is the rowID and not any MultiKeyIndex key.
+func (i *PeekableIterator) LoadNext(dest codec.ProtoMarshaler) (RowID, error) {
+	if i.closed {
+		return nil, ErrIteratorClosed
+	}
+	if dest == nil {
+		return nil, errors.Wrap(ErrArgument, "destination object must not be nil")
+	}
+	if i.done {
+		return nil, ErrIteratorDone
+	}
+	if i.err != nil {
+		// an error of Peek is returned once
+		err := i.err
+		i.err = nil
 		return nil, err
 	}
-	return binKey, nil
+	if i.next != nil {
+		next, rowID := i.next, i.rowID
+		i.next, i.rowID = nil, nil
+		if err := copyModel(next, dest); err != nil {
+			return nil, err
+		}
+		return rowID, nil
+	}
+	rowID, err := i.parentIterator.LoadNext(dest)
+	if IsIteratorDone(err) {
+		i.done = true
+	}
+	return rowID, err
+}
+
+// Close releases the iterator and should be called at the end of iteration.
+// Only the first call closes the parent iterator.
+func (i *PeekableIterator) Close() error {
+	if i.closed {
+		return nil
+	}
+	i.closed = true
+	return i.parentIterator.Close()
+}
+
+// copyModel copies the src model into dest. When both are of different types src is marshaled and
+// unmarshaled into dest.
+func copyModel(src, dest codec.ProtoMarshaler) error {
+	if reflect.TypeOf(src) == reflect.TypeOf(dest) {
+		reflect.ValueOf(dest).Elem().Set(reflect.ValueOf(src).Elem())
+		return nil
+	}
+	bz, err := src.Marshal()
+	if err != nil {
+		return err
+	}
+	dest.Reset()
+	return dest.Unmarshal(bz)
+}
+
+// SkipIterator returns a new iterator that discards the first skip elements of the parent
+// iterator. Skipped elements are not unmarshaled when the parent is a RawIterator. Otherwise
+// they are loaded into a throwaway object of the destination type.
+// The parent iterator must not be nil.
+// skip can be 0 or any positive number
+func SkipIterator(parent Iterator, skip uint64) Iterator {
+	if parent == nil {
+		panic("parent iterator must not be nil")
+	}
+	if skip == 0 {
+		return parent
+	}
+	return &skippedIterator{remainingSkip: skip, parentIterator: parent}
+}
+
+// skippedIterator discards a defined number of elements before returning any from the parent.
+type skippedIterator struct {
+	remainingSkip  uint64
+	parentIterator Iterator
+	closed         bool
+}
+
+// LoadNext loads the next value in the sequence into the pointer passed as dest and returns the key. If there
+// are no more items the `ErrIteratorDone` error is returned
+// The key is the rowID and not any MultiKeyIndex key.
+func (i *skippedIterator) LoadNext(dest codec.ProtoMarshaler) (RowID, error) {
+	if i.closed {
+		return nil, ErrIteratorClosed
+	}
+	if err := i.skip(dest); err != nil {
+		return nil, err
+	}
+	return i.parentIterator.LoadNext(dest)
+}
+
+// nextPageKey returns the page key of the next element of the parent after the skipped ones.
+func (i *skippedIterator) nextPageKey(dest codec.ProtoMarshaler) ([]byte, error) {
+	if i.closed {
+		return nil, ErrIteratorClosed
+	}
+	if err := i.skip(dest); err != nil {
+		return nil, err
+	}
+	return nextPageKey(i.parentIterator, dest)
+}
+
+func (i *skippedIterator) skip(dest codec.ProtoMarshaler) error {
+	for i.remainingSkip > 0 {
+		if err := skipNext(i.parentIterator, dest); err != nil {
+			return err
+		}
+		i.remainingSkip--
+	}
+	return nil
+}
+
+// Close releases the iterator and should be called at the end of iteration.
+// Only the first call closes the parent iterator.
+func (i *skippedIterator) Close() error {
+	if i.closed {
+		return nil
+	}
+	i.closed = true
+	return i.parentIterator.Close()
+}
+
+// skipNext advances the iterator by one element. When the iterator is a RawIterator the element is not
+// unmarshaled. Otherwise it is loaded into a throwaway object of the dest type.
+func skipNext(it Iterator, dest codec.ProtoMarshaler) error {
+	if raw, ok := it.(RawIterator); ok {
+		_, _, err := raw.RawNext()
+		return err
+	}
+	if dest == nil {
+		return errors.Wrap(ErrArgument, "destination object must not be nil")
+	}
+	tmp := reflect.New(reflect.TypeOf(dest).Elem()).Interface().(codec.ProtoMarshaler)
+	_, err := it.LoadNext(tmp)
+	return err
+}
+
+// FilterIterator returns a new iterator that returns only the elements of the parent iterator
+// for which the predicate returns true. Elements are loaded into dest before the predicate
+// is called with it.
+// The parent iterator and predicate must not be nil
+func FilterIterator(parent Iterator, predicate func(codec.ProtoMarshaler) bool) Iterator {
+	if parent == nil {
+		panic("parent iterator must not be nil")
+	}
+	if predicate == nil {
+		panic("predicate must not be nil")
+	}
+	return &filteredIterator{predicate: predicate, parentIterator: parent}
+}
+
+// filteredIterator skips all elements that don't match a predicate.
+type filteredIterator struct {
+	predicate      func(codec.ProtoMarshaler) bool
+	parentIterator Iterator
+	closed         bool
+}
+
+// LoadNext loads the next matching value in the sequence into the pointer passed as dest and returns the key.
+// If there are no more matching items the `ErrIteratorDone` error is returned
+// The key is the rowID and not any MultiKeyIndex key.
+func (i *filteredIterator) LoadNext(dest codec.ProtoMarshaler) (RowID, error) {
+	if i.closed {
+		return nil, ErrIteratorClosed
+	}
+	if dest == nil {
+		return nil, errors.Wrap(ErrArgument, "destination object must not be nil")
+	}
+	for {
+		// reset dest so that no data of a skipped element is merged into the next one
+		dest.Reset()
+		rowID, err := i.parentIterator.LoadNext(dest)
+		if err != nil {
+			return nil, err
+		}
+		if i.predicate(dest) {
+			return rowID, nil
+		}
+	}
+}
+
+// Close releases the iterator and should be called at the end of iteration.
+// Only the first call closes the parent iterator.
+func (i *filteredIterator) Close() error {
+	if i.closed {
+		return nil
+	}
+	i.closed = true
+	return i.parentIterator.Close()
+}
+
+// TakeWhileIterator returns a new iterator that returns the elements of the parent iterator as long as the
+// predicate returns true for them. At the first element that does not match, the parent iterator is closed and
+// `ErrIteratorDone` is returned. This allows to end a scan at a boundary that can not be expressed as key range.
+// Elements are loaded into dest before the predicate is called with them. Errors of the parent, like
+// unmarshal errors, are returned without calling the predicate.
+// The parent iterator and predicate must not be nil
+func TakeWhileIterator(parent Iterator, predicate func(codec.ProtoMarshaler, RowID) bool) Iterator {
+	if parent == nil {
+		panic("parent iterator must not be nil")
+	}
+	if predicate == nil {
+		panic("predicate must not be nil")
+	}
+	return &takeWhileIterator{predicate: predicate, parentIterator: parent}
+}
+
+// takeWhileIterator ends at the first element that doesn't match a predicate.
+type takeWhileIterator struct {
+	predicate      func(codec.ProtoMarshaler, RowID) bool
+	parentIterator Iterator
+	done           bool
+	closed         bool
+}
+
+// LoadNext loads the next value in the sequence into the pointer passed as dest and returns the key. If there
+// are no more items or the value does not match the predicate the `ErrIteratorDone` error is returned
+// The key is the rowID and not any MultiKeyIndex key.
+func (i *takeWhileIterator) LoadNext(dest codec.ProtoMarshaler) (RowID, error) {
+	if i.closed {
+		return nil, ErrIteratorClosed
+	}
+	if dest == nil {
+		return nil, errors.Wrap(ErrArgument, "destination object must not be nil")
+	}
+	if i.done {
+		return nil, ErrIteratorDone
+	}
+	rowID, err := i.parentIterator.LoadNext(dest)
+	if err != nil {
+		return nil, err
+	}
+	if i.predicate(dest, rowID) {
+		return rowID, nil
+	}
+	// the element that does not match is not returned
+	dest.Reset()
+	i.done = true
+	if err := i.parentIterator.Close(); err != nil {
+		return nil, err
+	}
+	return nil, ErrIteratorDone
+}
+
+// Close releases the iterator and should be called at the end of iteration.
+// Only the first call closes the parent iterator, unless it was closed at the end already.
+func (i *takeWhileIterator) Close() error {
+	if i.closed {
+		return nil
+	}
+	i.closed = true
+	if i.done {
+		return nil
+	}
+	return i.parentIterator.Close()
+}
+
+// DropWhileIterator returns a new iterator that discards the leading elements of the parent iterator as
+// long as the predicate returns true for them. All elements from the first one that does not match are
+// returned, without calling the predicate again. With Paginate the leading elements are dropped again from
+// the page key of a following page, so that the predicate should match a leading run of the whole domain only,
+// like "all rows before timestamp T" in a scan ordered by time.
+// Elements are loaded into dest before the predicate is called with them. Errors of the parent, like
+// unmarshal errors, are returned without calling the predicate.
+// The parent iterator and predicate must not be nil
+func DropWhileIterator(parent Iterator, predicate func(codec.ProtoMarshaler, RowID) bool) Iterator {
+	if parent == nil {
+		panic("parent iterator must not be nil")
+	}
+	if predicate == nil {
+		panic("predicate must not be nil")
+	}
+	return &dropWhileIterator{predicate: predicate, parentIterator: parent}
+}
+
+// dropWhileIterator skips the leading elements that match a predicate.
+type dropWhileIterator struct {
+	predicate      func(codec.ProtoMarshaler, RowID) bool
+	parentIterator Iterator
+	dropped        bool
+	closed         bool
+}
+
+// LoadNext loads the next value in the sequence into the pointer passed as dest and returns the key. The
+// leading matching values are skipped on the first call. If there are no more items the `ErrIteratorDone`
+// error is returned
+// The key is the rowID and not any MultiKeyIndex key.
+func (i *dropWhileIterator) LoadNext(dest codec.ProtoMarshaler) (RowID, error) {
+	if i.closed {
+		return nil, ErrIteratorClosed
+	}
+	if dest == nil {
+		return nil, errors.Wrap(ErrArgument, "destination object must not be nil")
+	}
+	if i.dropped {
+		return i.parentIterator.LoadNext(dest)
+	}
+	for {
+		// reset dest so that no data of a skipped element is merged into the next one
+		dest.Reset()
+		rowID, err := i.parentIterator.LoadNext(dest)
+		if err != nil {
+			return nil, err
+		}
+		if !i.predicate(dest, rowID) {
+			i.dropped = true
+			return rowID, nil
+		}
+	}
+}
+
+// Close releases the iterator and should be called at the end of iteration.
+// Only the first call closes the parent iterator.
+func (i *dropWhileIterator) Close() error {
+	if i.closed {
+		return nil
+	}
+	i.closed = true
+	return i.parentIterator.Close()
+}
+
+// MapIterator returns a new iterator that loads the elements of the parent iterator into a source model
+// created by newSrc and returns the result of the transform function for them. The result is copied into the
+// destination when both are of the same type. Otherwise it is marshaled and unmarshaled into the destination.
+// A nil result is returned as `ErrArgument` error.
+// The parent iterator and functions must not be nil
+func MapIterator(parent Iterator, transform func(src codec.ProtoMarshaler, rowID RowID) (codec.ProtoMarshaler, error), newSrc func() codec.ProtoMarshaler) Iterator {
+	if parent == nil {
+		panic("parent iterator must not be nil")
+	}
+	if transform == nil {
+		panic("transform must not be nil")
+	}
+	if newSrc == nil {
+		panic("source model constructor must not be nil")
+	}
+	return &mappedIterator{transform: transform, newSrc: newSrc, parentIterator: parent}
+}
+
+// mappedIterator projects the elements of the parent iterator into another type.
+type mappedIterator struct {
+	transform      func(src codec.ProtoMarshaler, rowID RowID) (codec.ProtoMarshaler, error)
+	newSrc         func() codec.ProtoMarshaler
+	parentIterator Iterator
+	closed         bool
+}
+
+// LoadNext loads the transformed next value in the sequence into the pointer passed as dest and returns the key.
+// If there are no more items the `ErrIteratorDone` error is returned
+// The key is the rowID and not any MultiKeyIndex key.
+func (i *mappedIterator) LoadNext(dest codec.ProtoMarshaler) (RowID, error) {
+	if i.closed {
+		return nil, ErrIteratorClosed
+	}
+	if dest == nil {
+		return nil, errors.Wrap(ErrArgument, "destination object must not be nil")
+	}
+	src := i.newSrc()
+	rowID, err := i.parentIterator.LoadNext(src)
+	if err != nil {
+		return nil, err
+	}
+	result, err := i.transform(src, rowID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "transform row %X", rowID)
+	}
+	if result == nil || isNilPointer(result) {
+		return nil, errors.Wrapf(ErrArgument, "transform returned nil for row %X", rowID)
+	}
+	if reflect.TypeOf(result) == reflect.TypeOf(dest) {
+		reflect.ValueOf(dest).Elem().Set(reflect.ValueOf(result).Elem())
+		return rowID, nil
+	}
+	bz, err := result.Marshal()
+	if err != nil {
+		return nil, errors.Wrapf(err, "marshal transformed row %X", rowID)
+	}
+	dest.Reset()
+	if err := dest.Unmarshal(bz); err != nil {
+		return nil, errors.Wrapf(err, "unmarshal transformed row %X", rowID)
+	}
+	return rowID, nil
+}
+
+// Close releases the iterator and should be called at the end of iteration.
+// Only the first call closes the parent iterator.
+func (i *mappedIterator) Close() error {
+	if i.closed {
+		return nil
+	}
+	i.closed = true
+	return i.parentIterator.Close()
+}
+
+// DistinctIterator returns a new iterator that returns every RowID of the parent iterator only once.
+// The RowIDs already returned are kept in memory. When maxRowIDs is not 0 and the parent iterator
+// returns more distinct RowIDs, an `ErrArgument` error is returned instead.
+// The parent iterator must not be nil
+// maxRowIDs can be 0 or any positive number
+func DistinctIterator(parent Iterator, maxRowIDs int) Iterator {
+	if parent == nil {
+		panic("parent iterator must not be nil")
+	}
+	if maxRowIDs < 0 {
+		panic("quantity must not be negative")
+	}
+	return &distinctIterator{maxRowIDs: maxRowIDs, seen: make(map[string]struct{}), parentIterator: parent}
+}
+
+// distinctIterator skips all elements with a RowID that was returned before.
+type distinctIterator struct {
+	maxRowIDs      int
+	seen           map[string]struct{}
+	parentIterator Iterator
+	closed         bool
+}
+
+// LoadNext loads the next value with an unseen RowID into the pointer passed as dest and returns the key.
+// If there are no more items the `ErrIteratorDone` error is returned
+// The key is the rowID and not any MultiKeyIndex key.
+func (i *distinctIterator) LoadNext(dest codec.ProtoMarshaler) (RowID, error) {
+	if i.closed {
+		return nil, ErrIteratorClosed
+	}
+	if dest == nil {
+		return nil, errors.Wrap(ErrArgument, "destination object must not be nil")
+	}
+	for {
+		dest.Reset()
+		rowID, err := i.parentIterator.LoadNext(dest)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := i.seen[string(rowID)]; ok {
+			continue
+		}
+		if i.maxRowIDs != 0 && len(i.seen) == i.maxRowIDs {
+			return nil, errors.Wrapf(ErrArgument, "more than %d distinct row IDs", i.maxRowIDs)
+		}
+		i.seen[string(rowID)] = struct{}{}
+		return rowID, nil
+	}
+}
+
+// Close releases the iterator and should be called at the end of iteration.
+// Only the first call closes the parent iterator.
+func (i *distinctIterator) Close() error {
+	if i.closed {
+		return nil
+	}
+	i.closed = true
+	return i.parentIterator.Close()
+}
+
+// ChainIterator returns a new iterator that returns all elements of the given iterators
+// in the order they were passed. An iterator is only read after the previous one is done.
+// None of the iterators must be nil
+func ChainIterator(iters ...Iterator) Iterator {
+	for _, it := range iters {
+		if it == nil {
+			panic("iterator must not be nil")
+		}
+	}
+	return &chainedIterator{iterators: iters}
+}
+
+// chainedIterator concatenates multiple iterators.
+type chainedIterator struct {
+	iterators []Iterator
+	pos       int
+	closed    bool
+}
+
+// LoadNext loads the next value in the sequence into the pointer passed as dest and returns the key. If there
+// are no more items in the last iterator the `ErrIteratorDone` error is returned
+// The key is the rowID and not any MultiKeyIndex key.
+func (i *chainedIterator) LoadNext(dest codec.ProtoMarshaler) (RowID, error) {
+	if i.closed {
+		return nil, ErrIteratorClosed
+	}
+	for i.pos < len(i.iterators) {
+		rowID, err := i.iterators[i.pos].LoadNext(dest)
+		if err == nil {
+			return rowID, nil
+		}
+		if !IsIteratorDone(err) {
+			return nil, err
+		}
+		i.pos++
+	}
+	return nil, ErrIteratorDone
+}
+
+// Close releases all iterators and should be called at the end of iteration
+func (i *chainedIterator) Close() error {
+	if i.closed {
+		return nil
+	}
+	i.closed = true
+	return closeAll(i.iterators)
+}
+
+// MergeIterator returns a new iterator that performs a k-way merge of the given iterators which are all sorted
+// by RowID in the order defined by less. Elements with the same RowID are returned only once.
+// The next element of every iterator is loaded ahead into an object of the destination type.
+// Close releases all iterators, even when one of them fails.
+// less and the iterators must not be nil
+func MergeIterator(less func(a, b RowID) bool, iters ...Iterator) Iterator {
+	if less == nil {
+		panic("less must not be nil")
+	}
+	for _, it := range iters {
+		if it == nil {
+			panic("iterator must not be nil")
+		}
+	}
+	return &mergedIterator{less: less, iterators: iters, heads: make([]mergeHead, len(iters))}
+}
+
+// UnionIterator returns a new iterator that returns the elements of both iterators, which must be sorted by
+// RowID in ascending byte order like index and table prefix scans. Elements with the same RowID are returned
+// only once so that pagination keys are stable. Errors other than `ErrIteratorDone` of either iterator are
+// returned immediately. See `MergeIterator` for custom orders or more iterators.
+// The iterators must not be nil
+func UnionIterator(a, b Iterator) Iterator {
+	return MergeIterator(func(x, y RowID) bool { return bytes.Compare(x, y) < 0 }, a, b)
+}
+
+// mergedIterator merges multiple sorted iterators.
+type mergedIterator struct {
+	less      func(RowID, RowID) bool
+	iterators []Iterator
+	heads     []mergeHead
+	last      RowID
+	closed    bool
+}
+
+// mergeHead is the next element of an iterator that was loaded ahead.
+type mergeHead struct {
+	rowID RowID
+	value codec.ProtoMarshaler
+	done  bool
+}
+
+// LoadNext loads the next value in the sequence into the pointer passed as dest and returns the key. If there
+// are no more items in all iterators the `ErrIteratorDone` error is returned
+// The key is the rowID and not any MultiKeyIndex key.
+func (i *mergedIterator) LoadNext(dest codec.ProtoMarshaler) (RowID, error) {
+	if i.closed {
+		return nil, ErrIteratorClosed
+	}
+	if dest == nil {
+		return nil, errors.Wrap(ErrArgument, "destination object must not be nil")
+	}
+	destType := reflect.TypeOf(dest)
+	for {
+		next := -1
+		for n := range i.heads {
+			head := &i.heads[n]
+			if head.done {
+				continue
+			}
+			if head.value == nil {
+				value := reflect.New(destType.Elem()).Interface().(codec.ProtoMarshaler)
+				rowID, err := i.iterators[n].LoadNext(value)
+				switch {
+				case err == nil:
+					head.rowID, head.value = rowID, value
+				case IsIteratorDone(err):
+					head.done = true
+					continue
+				default:
+					return nil, err
+				}
+			}
+			if reflect.TypeOf(head.value) != destType {
+				return nil, errors.Wrapf(ErrType, "can not use %T after %T", dest, head.value)
+			}
+			if next == -1 || i.less(head.rowID, i.heads[next].rowID) {
+				next = n
+			}
+		}
+		if next == -1 {
+			return nil, ErrIteratorDone
+		}
+		head := &i.heads[next]
+		rowID, value := head.rowID, head.value
+		head.rowID, head.value = nil, nil
+		// drop elements that were already returned
+		if i.last != nil && bytes.Equal(rowID, i.last) {
+			continue
+		}
+		i.last = rowID
+		reflect.ValueOf(dest).Elem().Set(reflect.ValueOf(value).Elem())
+		return rowID, nil
+	}
+}
+
+// Close releases all iterators and should be called at the end of iteration
+func (i *mergedIterator) Close() error {
+	if i.closed {
+		return nil
+	}
+	i.closed = true
+	return closeAll(i.iterators)
+}
+
+// IntersectIterator returns a new iterator that returns only the elements with a RowID that both iterators
+// contain. Both iterators must be sorted by RowID in ascending byte order, like index and table prefix scans.
+// The values are loaded from iterator a while iterator b is only used to advance by RowID, so a should be
+// the more selective one. ErrIteratorDone is returned as soon as either iterator is exhausted.
+// The iterators must not be nil
+func IntersectIterator(a, b Iterator) Iterator {
+	if a == nil || b == nil {
+		panic("iterator must not be nil")
+	}
+	return &intersectedIterator{a: a, b: b}
+}
+
+// intersectedIterator returns the elements of a with a RowID in b.
+type intersectedIterator struct {
+	a, b   Iterator
+	rowID  RowID
+	closed bool
+}
+
+// LoadNext loads the next value with a RowID in both iterators into the pointer passed as dest and returns the
+// key. If there are no more items the `ErrIteratorDone` error is returned
+// The key is the rowID and not any MultiKeyIndex key.
+func (i *intersectedIterator) LoadNext(dest codec.ProtoMarshaler) (RowID, error) {
+	if i.closed {
+		return nil, ErrIteratorClosed
+	}
+	if dest == nil {
+		return nil, errors.Wrap(ErrArgument, "destination object must not be nil")
+	}
+	for {
+		dest.Reset()
+		rowIDA, err := i.a.LoadNext(dest)
+		if err != nil {
+			return nil, err
+		}
+		// advance b to the first RowID that is not before the one of a
+		for i.rowID == nil || bytes.Compare(i.rowID, rowIDA) < 0 {
+			if i.rowID, err = nextRowID(i.b, dest); err != nil {
+				return nil, err
+			}
+		}
+		if bytes.Equal(i.rowID, rowIDA) {
+			return rowIDA, nil
+		}
+	}
+}
+
+// Close releases both iterators and should be called at the end of iteration
+func (i *intersectedIterator) Close() error {
+	if i.closed {
+		return nil
+	}
+	i.closed = true
+	return closeAll([]Iterator{i.a, i.b})
+}
+
+// totalCounter is implemented by iterators that can count all elements of a paginated domain, including the
+// elements before the page key. The bool is false when the total was not requested on creation.
+type totalCounter interface {
+	countTotal() (uint64, bool, error)
+}
+
+// rowIDIterator is implemented by iterators that can advance by RowID only, without reading the value.
+type rowIDIterator interface {
+	nextRowID() (RowID, error)
+}
+
+// pageKeyIterator is implemented by iterators that continue a page at an element with another key than the
+// RowID, like a cursor, or that pass the page keys of their parent through. dest is used like for `nextRowID`.
+type pageKeyIterator interface {
+	nextPageKey(dest codec.ProtoMarshaler) ([]byte, error)
+}
+
+// nextPageKey advances the iterator by one element and returns the key to continue a page with at the element.
+// That is the RowID unless the iterator implements pageKeyIterator.
+func nextPageKey(it Iterator, dest codec.ProtoMarshaler) ([]byte, error) {
+	if it, ok := it.(pageKeyIterator); ok {
+		return it.nextPageKey(dest)
+	}
+	return nextRowID(it, dest)
+}
+
+// nextRowID advances the iterator by one element and returns the RowID. The value is read only when the
+// iterator can not advance otherwise and dest is used to determine the type then.
+func nextRowID(it Iterator, dest codec.ProtoMarshaler) (RowID, error) {
+	switch it := it.(type) {
+	case rowIDIterator:
+		return it.nextRowID()
+	case RawIterator:
+		rowID, _, err := it.RawNext()
+		return rowID, err
+	}
+	tmp := reflect.New(reflect.TypeOf(dest).Elem()).Interface().(codec.ProtoMarshaler)
+	return it.LoadNext(tmp)
+}
+
+// closeAll closes all iterators, even when one fails. The first error is returned with the
+// messages of any further errors added.
+func closeAll(iters []Iterator) error {
+	var result error
+	for n, it := range iters {
+		err := it.Close()
+		switch {
+		case err == nil:
+		case result == nil:
+			result = errors.Wrapf(err, "close iterator %d", n)
+		default:
+			result = errors.Wrapf(result, "close iterator %d: %s", n, err)
+		}
+	}
+	return result
+}
+
+// First loads the first element into the given destination type and closes the iterator.
+// When the iterator is closed or has no elements the according error is passed as return value.
+// A nil iterator is treated like an iterator without elements and `ErrIteratorDone` is returned.
+func First(it Iterator, dest codec.ProtoMarshaler) (RowID, error) {
+	if it == nil {
+		return nil, ErrIteratorDone
+	}
+	defer it.Close()
+	binKey, err := it.LoadNext(dest)
+	if err != nil {
+		return nil, err
+	}
+	return binKey, nil
+}
+
+// FirstOrNotFound loads the first element into the given destination type and closes the iterator like First.
+// When the iterator is nil or has no elements `ErrNotFound` is returned instead of `ErrIteratorDone`.
+func FirstOrNotFound(it Iterator, dest codec.ProtoMarshaler) (RowID, error) {
+	binKey, err := First(it, dest)
+	if IsIteratorDone(err) {
+		return nil, ErrNotFound
+	}
+	return binKey, err
+}
+
+// Last loads the last element into the given destination type and closes the iterator. All elements
+// are read to get to the last one, so a reverse iterator should be used with First instead when available.
+// When the iterator is closed or has no elements the according error is passed as return value.
+// A nil iterator is treated like an iterator without elements and `ErrIteratorDone` is returned.
+func Last(it Iterator, dest codec.ProtoMarshaler) (RowID, error) {
+	if it == nil {
+		return nil, ErrIteratorDone
+	}
+	defer it.Close()
+	if dest == nil {
+		return nil, errors.Wrap(ErrArgument, "destination object must not be nil")
+	}
+
+	var lastKey RowID
+	var last codec.ProtoMarshaler
+	for {
+		// gogo unmarshal merges, so that every element is loaded into a new object
+		model := reflect.New(reflect.TypeOf(dest).Elem()).Interface().(codec.ProtoMarshaler)
+		binKey, err := it.LoadNext(model)
+		switch {
+		case err == nil:
+			lastKey, last = binKey, model
+		case IsIteratorDone(err):
+			if last == nil {
+				return nil, err
+			}
+			reflect.ValueOf(dest).Elem().Set(reflect.ValueOf(last).Elem())
+			return lastKey, nil
+		default:
+			return nil, err
+		}
+	}
+}
+
+// Paginate does pagination with a given Iterator based on the provided
+// PageRequest and unmarshals the results into the dest interface that must be
+// an non-nil pointer to a slice.
+//
+// If pageRequest is nil, then we will use these default values:
+//  - Offset: 0
+//  - Key: nil
+//  - Limit: 100
+//  - CountTotal: true
+//
+// If pageRequest.Key was provided, it got used beforehand to instantiate the Iterator,
+// using for instance UInt64Index.GetPaginated method. Only one of pageRequest.Offset or
+// pageRequest.Key should be set. Using pageRequest.Key is more efficient for querying
+// the next page. An empty pageRequest.Key is treated like a nil one, so that the first page is
+// returned for it.
+//
+// The order of the results is defined by the Iterator. For descending order, the Iterator
+// should be created with a reverse method, for instance UInt64Index.ReverseGetPaginated.
+// The returned NextKey can then be used with the same method to continue backwards.
+//
+// The NextKey is the RowID of the next element, except for iterators created by MultiKeyIndex.GetPaginated
+// and MultiKeyIndex.ReverseGetPaginated, which return a cursor with the index key, see `EncodeCursor`.
+//
+// When the Iterator is a RawIterator, the elements before pageRequest.Offset are skipped
+// without unmarshaling them.
+//
+// If pageRequest.CountTotal is set, we'll visit all iterators elements. The elements after the
+// page are counted without unmarshaling them when the Iterator is a RawIterator. Index iterators
+// count them by the index keys only, without reading the table rows.
+// When pageRequest.Key is set, the total is only returned for iterators that were created with the
+// total requested, like by MultiKeyIndex.GetPaginated. They count it with an extra scan.
+//
+// This function will call it.Close().
+func Paginate(
+	it Iterator,
+	pageRequest *query.PageRequest,
+	dest ModelSlicePtr,
+) (*query.PageResponse, error) {
+	_, res, err := paginate(it, pageRequest, dest, PaginateOpts{}, false)
+	return res, err
+}
+
+// defaultMaxLimit is the maximum page limit of all paginations without a MaxLimit, see `SetDefaultMaxLimit`.
+var defaultMaxLimit uint64
+
+// SetDefaultMaxLimit sets the maximum number of elements of a page for all pagination functions, like
+// Paginate, so that clients can not make a node iterate a whole table in one request with a huge
+// pageRequest.Limit. The limit of a request above the maximum is clamped to it, or fails with an
+// `ErrArgument` error with the RejectAboveMaxLimit option of PaginateWithOpts. Query servers can set other
+// limits per endpoint with the options of PaginateWithOpts. The default 0 means no maximum.
+// It is not synchronized and must be set before any query is served, for example on app creation.
+func SetDefaultMaxLimit(max uint64) {
+	defaultMaxLimit = max
+}
+
+// PaginateOpts are the options for PaginateWithOpts.
+type PaginateOpts struct {
+	// MaxLimit is the maximum number of elements of a page. The default 0 means the maximum set by
+	// `SetDefaultMaxLimit`, if any.
+	MaxLimit uint64
+	// RejectAboveMaxLimit makes pagination fail with an `ErrArgument` error for a pageRequest.Limit
+	// above MaxLimit. Otherwise the limit is clamped to MaxLimit.
+	RejectAboveMaxLimit bool
+	// Decoder decodes the persisted values of the elements into the destination slice instead of the
+	// proto unmarshaling into models, see `ValueDecoder`. The Iterator must be a RawIterator then.
+	Decoder ValueDecoder
+	// total is the known number of elements of the domain, see `PaginateWithTotal`.
+	total *uint64
+}
+
+// PaginateWithOpts does pagination like Paginate with a maximum page limit, that query servers can use
+// to protect against clients requesting a very large number of elements at once. The default limit, that
+// is used when pageRequest.Limit is not set, is clamped to opts.MaxLimit but never rejected.
+//
+// This function will call it.Close().
+func PaginateWithOpts(
+	it Iterator,
+	pageRequest *query.PageRequest,
+	dest ModelSlicePtr,
+	opts PaginateOpts,
+) (*query.PageResponse, error) {
+	_, res, err := paginate(it, pageRequest, dest, opts, false)
+	return res, err
+}
+
+// PaginateWithTotal does pagination like Paginate with a known total number of elements, like the value
+// of a table row counter returned by `Table.Count`. When the total is requested, it is returned as is
+// instead of counting the remaining elements after the page, so that the first page of a large table is
+// not the most expensive one. The total must be the number of elements of the whole domain of the
+// iterator, also for pages requested by key. It can be counted with the same iterator in a first pass
+// when the iterator supports `Rewind`.
+//
+// This function will call it.Close().
+func PaginateWithTotal(
+	it Iterator,
+	pageRequest *query.PageRequest,
+	dest ModelSlicePtr,
+	total uint64,
+) (*query.PageResponse, error) {
+	_, res, err := paginate(it, pageRequest, dest, PaginateOpts{total: &total}, false)
+	return res, err
+}
+
+// PaginateWithRowIDs does pagination like Paginate and returns the RowIDs of the loaded elements as well.
+// The RowIDs have the same order as the elements in the destination slice.
+//
+// This function will call it.Close().
+func PaginateWithRowIDs(
+	it Iterator,
+	pageRequest *query.PageRequest,
+	dest ModelSlicePtr,
+) ([]RowID, *query.PageResponse, error) {
+	return paginate(it, pageRequest, dest, PaginateOpts{}, true)
+}
+
+// paginate implements Paginate and collects the RowIDs of the loaded elements when withRowIDs is set.
+func paginate(it Iterator, pageRequest *query.PageRequest, dest ModelSlicePtr, opts PaginateOpts, withRowIDs bool) ([]RowID, *query.PageResponse, error) {
+	if opts.Decoder != nil && it != nil {
+		decoding, err := newDecodingIterator(it, opts.Decoder)
+		if err != nil {
+			it.Close()
+			return nil, nil, err
+		}
+		return paginateInto(context.Background(), decoding, pageRequest, &decodedCollector{dest: dest}, opts, withRowIDs)
+	}
+	return paginateInto(context.Background(), it, pageRequest, &sliceCollector{dest: dest}, opts, withRowIDs)
 }
 
-// Paginate does pagination with a given Iterator based on the provided
-// PageRequest and unmarshals the results into the dest interface that must be
-// an non-nil pointer to a slice.
-//
-// If pageRequest is nil, then we will use these default values:
-//  - Offset: 0
-//  - Key: nil
-//  - Limit: 100
-//  - CountTotal: true
-//
-// If pageRequest.Key was provided, it got used beforehand to instantiate the Iterator,
-// using for instance UInt64Index.GetPaginated method. Only one of pageRequest.Offset or
-// pageRequest.Key should be set. Using pageRequest.Key is more efficient for querying
-// the next page.
-//
-// If pageRequest.CountTotal is set, we'll visit all iterators elements.
-// pageRequest.CountTotal is only respected when offset is used.
+// PaginateCtx does pagination like Paginate but stops with a wrapped context error as soon as the context
+// is done, for example when the deadline of a gRPC query is exceeded. The context is checked before every
+// element that is read, including the elements that are skipped or counted only.
 //
 // This function will call it.Close().
-func Paginate(
+func PaginateCtx(
+	ctx context.Context,
 	it Iterator,
 	pageRequest *query.PageRequest,
 	dest ModelSlicePtr,
 ) (*query.PageResponse, error) {
+	_, res, err := paginateInto(ctx, it, pageRequest, &sliceCollector{dest: dest}, PaginateOpts{}, false)
+	return res, err
+}
+
+// paginateInto implements the pagination for all destination types. The elements of the page are loaded
+// into the models of the collector and added to it.
+func paginateInto(ctx context.Context, it Iterator, pageRequest *query.PageRequest, c modelCollector, opts PaginateOpts, withRowIDs bool) ([]RowID, *query.PageResponse, error) {
 	// if the PageRequest is nil, use default PageRequest
 	if pageRequest == nil {
 		pageRequest = &query.PageRequest{}
@@ -129,8 +1600,15 @@ func Paginate(
 	limit := pageRequest.Limit
 	countTotal := pageRequest.CountTotal
 
+	// an empty key is no key, like for the GetPaginated methods of the indexes
+	if len(key) == 0 {
+		key = nil
+	}
 	if offset > 0 && key != nil {
-		return nil, fmt.Errorf("invalid request, either offset or key is expected, got both")
+		return nil, nil, fmt.Errorf("invalid request, either offset or key is expected, got both")
+	}
+	if opts.MaxLimit == 0 {
+		opts.MaxLimit = defaultMaxLimit
 	}
 
 	if limit == 0 {
@@ -138,47 +1616,64 @@ func Paginate(
 
 		// count total results when the limit is zero/not supplied
 		countTotal = true
+	} else if opts.MaxLimit != 0 && limit > opts.MaxLimit && opts.RejectAboveMaxLimit {
+		return nil, nil, errors.Wrapf(ErrArgument, "limit %d exceeds max limit %d", limit, opts.MaxLimit)
+	}
+	if opts.MaxLimit != 0 && limit > opts.MaxLimit {
+		// the page ends at the clamped limit, so that the next key and the counting of the
+		// remaining elements for the total start after it
+		limit = opts.MaxLimit
+	}
+
+	if limit > math.MaxUint64-offset {
+		return nil, nil, errors.Wrap(ErrArgument, "offset + limit overflows uint64")
 	}
 
 	if it == nil {
-		return nil, errors.Wrap(ErrArgument, "iterator must not be nil")
+		return nil, nil, errors.Wrap(ErrArgument, "iterator must not be nil")
 	}
 	defer it.Close()
 
-	var destRef, tmpSlice reflect.Value
-	elemType, err := assertDest(dest, &destRef, &tmpSlice)
-	if err != nil {
-		return nil, err
+	if err := c.init(); err != nil {
+		return nil, nil, err
 	}
 
 	var end = offset + limit
 	var count uint64
 	var nextKey []byte
-	for {
-		obj := reflect.New(elemType)
-		val := obj.Elem()
-		model := obj
-		if elemType.Kind() == reflect.Ptr {
-			val.Set(reflect.New(elemType.Elem()))
-			// if elemType is already a pointer (e.g. dest being some pointer to a slice of pointers,
-			// like []*GroupMember), then obj is a pointer to a pointer which might cause issues
-			// if we try to do obj.Interface().(codec.ProtoMarshaler).
-			// For that reason, we copy obj into model if we have a simple pointer
-			// but in case elemType.Kind() == reflect.Ptr, we overwrite it with model = val
-			// so we can safely call model.Interface().(codec.ProtoMarshaler) afterwards.
-			model = val
-		}
-
-		modelProto, ok := model.Interface().(codec.ProtoMarshaler)
-		if !ok {
-			return nil, errors.Wrapf(ErrArgument, "%s should implement codec.ProtoMarshaler", elemType)
+	var rowIDs []RowID
+	if raw, ok := it.(RawIterator); ok {
+		// skip the offset without unmarshaling the values
+		for count < offset {
+			if err := ctxErr(ctx); err != nil {
+				return nil, nil, err
+			}
+			if _, _, err := raw.RawNext(); err != nil {
+				if IsIteratorDone(err) {
+					break
+				}
+				return nil, nil, err
+			}
+			count++
+		}
+	}
+	var done bool
+	for count < end {
+		if err := ctxErr(ctx); err != nil {
+			return nil, nil, err
+		}
+		// elements of the offset are loaded into the scratch model as they are not collected
+		modelProto := c.scratch()
+		if count >= offset {
+			modelProto = c.newModel()
 		}
 		binKey, err := it.LoadNext(modelProto)
 		if err != nil {
-			if ErrIteratorDone.Is(err) {
+			if IsIteratorDone(err) {
+				done = true
 				break
 			}
-			return nil, err
+			return nil, nil, err
 		}
 
 		count++
@@ -186,33 +1681,202 @@ func Paginate(
 		// During the first loop, count value at this point will be 1,
 		// so if offset is >= 1, it will continue to load the next value until count > offset
 		// else (offset = 0, key might be set or not),
-		// it will start to append values to tmpSlice.
+		// it will start to add values to the collector.
 		if count <= offset {
 			continue
 		}
 
-		if count <= end {
-			tmpSlice = reflect.Append(tmpSlice, val)
-		} else if count == end+1 {
+		c.add(modelProto)
+		if withRowIDs {
+			rowIDs = append(rowIDs, binKey)
+		}
+	}
+	if !done {
+		// the next key is the RowID of the element after the page, which is not unmarshaled
+		// when the iterator can advance by RowID
+		if err := ctxErr(ctx); err != nil {
+			return nil, nil, err
+		}
+		binKey, err := nextPageKey(it, c.scratch())
+		switch {
+		case err == nil:
 			nextKey = binKey
+			count++
+		case !IsIteratorDone(err):
+			return nil, nil, err
+		}
 
-			// countTotal is set to true to indicate that the result set should include
-			// a count of the total number of items available for pagination in UIs.
-			// countTotal is only respected when offset is used. It is ignored when key
-			// is set.
-			if !countTotal || len(key) != 0 {
-				break
+		// countTotal is set to true to indicate that the result set should include
+		// a count of the total number of items available for pagination in UIs.
+		// When key is set, the elements before the key are not visited by the iterator,
+		// so the total is counted by the iterator itself, if supported.
+		if nextKey != nil && countTotal && len(key) == 0 && opts.total == nil {
+			n, err := countRemaining(ctx, it, c)
+			if err != nil {
+				return nil, nil, err
 			}
+			count += n
 		}
 	}
-	destRef.Set(tmpSlice)
+	c.finish()
 
 	res := &query.PageResponse{NextKey: nextKey}
-	if countTotal && len(key) == 0 {
+	switch {
+	case !countTotal:
+	case opts.total != nil:
+		res.Total = *opts.total
+	case len(key) == 0:
 		res.Total = count
 	}
+	if tc, ok := it.(totalCounter); ok && countTotal && len(key) != 0 && opts.total == nil {
+		total, ok, err := tc.countTotal()
+		if err != nil {
+			return nil, nil, err
+		}
+		if ok {
+			res.Total = total
+		}
+	}
 
-	return res, nil
+	return rowIDs, res, nil
+}
+
+// countRemaining consumes all remaining values of the iterator and returns their number. The values are
+// only unmarshaled, into models of the collector, when the iterator can not advance otherwise.
+func countRemaining(ctx context.Context, it Iterator, c modelCollector) (uint64, error) {
+	if raw, ok := it.(RawIterator); ok {
+		return countRaw(ctx, raw)
+	}
+	var count uint64
+	for {
+		if err := ctxErr(ctx); err != nil {
+			return 0, err
+		}
+		_, err := nextRowID(it, c.scratch())
+		switch {
+		case err == nil:
+			count++
+		case IsIteratorDone(err):
+			return count, nil
+		default:
+			return 0, err
+		}
+	}
+}
+
+// Rewind resets the iterator to the start of its domain, so that it can be read again, for example to
+// emit the elements after a first pass that validated them or counted the total for `PaginateWithTotal`.
+// The table and index iterators implement the `Rewinder` interface, as well as the iterators of
+// `BufferedIterator` and `InstrumentedIterator` over them. An `ErrArgument` error is returned for other
+// iterators and the ErrIteratorClosed error after Close.
+func Rewind(it Iterator) error {
+	r, ok := it.(Rewinder)
+	if !ok {
+		return errors.Wrapf(ErrArgument, "%T does not implement Rewinder", it)
+	}
+	return r.Rewind()
+}
+
+// PeekRowID returns the RowID of the next element of the iterator without consuming the element or
+// unmarshaling its value, so that the element is returned by the next LoadNext call. The table and index
+// iterators implement the `RowIDPeeker` interface, as well as the iterators of `NewSliceIterator`,
+// `BufferedIterator`, `PeekIterator` and `InstrumentedIterator` over them. An `ErrArgument` error is returned for other
+// iterators.
+func PeekRowID(it Iterator) (RowID, error) {
+	p, ok := it.(RowIDPeeker)
+	if !ok {
+		return nil, errors.Wrapf(ErrArgument, "%T does not implement RowIDPeeker", it)
+	}
+	return p.PeekRowID()
+}
+
+// Count consumes all values of the iterator without unmarshaling them and returns their number.
+// The iterator must be a RawIterator, like the table and index iterators, and is closed afterwards.
+func Count(it Iterator) (uint64, error) {
+	if it == nil {
+		return 0, errors.Wrap(ErrArgument, "iterator must not be nil")
+	}
+	defer it.Close()
+
+	raw, ok := it.(RawIterator)
+	if !ok {
+		return 0, errors.Wrapf(ErrArgument, "%T does not implement RawIterator", it)
+	}
+	return countRaw(context.Background(), raw)
+}
+
+// countRaw consumes all remaining values of the iterator and returns their number.
+// Index iterators are advanced without reading the table rows.
+func countRaw(ctx context.Context, it RawIterator) (uint64, error) {
+	var count uint64
+	for {
+		if err := ctxErr(ctx); err != nil {
+			return 0, err
+		}
+		var err error
+		if r, ok := it.(rowIDIterator); ok {
+			_, err = r.nextRowID()
+		} else {
+			_, _, err = it.RawNext()
+		}
+		switch {
+		case err == nil:
+			count++
+		case IsIteratorDone(err):
+			return count, nil
+		default:
+			return 0, err
+		}
+	}
+}
+
+// ForEach loads all values of the iterator one by one and calls fn with them. A new model is created with
+// newModel for every element. The iteration stops when fn returns stop or an error, which is passed as
+// return value then. The iterator is closed afterwards.
+// Example:
+//			err := ForEach(it, func() codec.ProtoMarshaler { return &testdata.GroupMember{} },
+//				func(rowID RowID, m codec.ProtoMarshaler) (bool, error) {
+//					totalWeight += m.(*testdata.GroupMember).Weight
+//					return false, nil
+//				})
+//
+func ForEach(it Iterator, newModel func() codec.ProtoMarshaler, fn func(RowID, codec.ProtoMarshaler) (stop bool, err error)) error {
+	if it == nil {
+		return errors.Wrap(ErrArgument, "iterator must not be nil")
+	}
+	defer it.Close()
+	if newModel == nil {
+		return errors.Wrap(ErrArgument, "model constructor must not be nil")
+	}
+	if fn == nil {
+		return errors.Wrap(ErrArgument, "callback must not be nil")
+	}
+
+	for {
+		model := newModel()
+		rowID, err := it.LoadNext(model)
+		switch {
+		case err == nil:
+		case IsIteratorDone(err):
+			return nil
+		default:
+			return err
+		}
+		stop, err := fn(rowID, model)
+		if err != nil || stop {
+			return err
+		}
+	}
+}
+
+// ForEachCtx loads all values of the iterator one by one and calls fn with them like ForEach. The iteration
+// stops with a wrapped context error as soon as the context is done, see `CtxIterator`.
+// The iterator is closed afterwards.
+func ForEachCtx(ctx context.Context, it Iterator, newModel func() codec.ProtoMarshaler, fn func(RowID, codec.ProtoMarshaler) (stop bool, err error)) error {
+	if it == nil {
+		return errors.Wrap(ErrArgument, "iterator must not be nil")
+	}
+	return ForEach(CtxIterator(ctx, it), newModel, fn)
 }
 
 // ModelSlicePtr represents a pointer to a slice of models. Think of it as
@@ -230,33 +1894,43 @@ type ModelSlicePtr interface{}
 //			require.NoError(t, err)
 //
 func ReadAll(it Iterator, dest ModelSlicePtr) ([]RowID, error) {
+	return ReadAllLimited(it, dest, math.MaxUint64)
+}
+
+// ReadAllLimited consumes the values of the iterator like ReadAll but stops after max elements. When the
+// iterator has more elements, the first max are stored in the slice at the passed ModelSlicePtr and
+// returned with their RowIDs together with an `ErrLimit` error, so that a truncated result can be told
+// apart from a complete one. The iterator is closed afterwards.
+// max can be 0 or any positive number
+func ReadAllLimited(it Iterator, dest ModelSlicePtr, max uint64) ([]RowID, error) {
+	return readAllInto(it, &sliceCollector{dest: dest}, max)
+}
+
+// readAllInto implements ReadAllLimited for all destination types. The elements are loaded into the models
+// of the collector and added to it.
+func readAllInto(it Iterator, c modelCollector, max uint64) ([]RowID, error) {
 	if it == nil {
 		return nil, errors.Wrap(ErrArgument, "iterator must not be nil")
 	}
 	defer it.Close()
 
-	var destRef, tmpSlice reflect.Value
-	elemType, err := assertDest(dest, &destRef, &tmpSlice)
-	if err != nil {
+	if err := c.init(); err != nil {
 		return nil, err
 	}
 
 	var rowIDs []RowID
 	for {
-		obj := reflect.New(elemType)
-		val := obj.Elem()
-		model := obj
-		if elemType.Kind() == reflect.Ptr {
-			val.Set(reflect.New(elemType.Elem()))
-			model = val
-		}
-
-		binKey, err := it.LoadNext(model.Interface().(codec.ProtoMarshaler))
+		model := c.newModel()
+		binKey, err := it.LoadNext(model)
 		switch {
+		case err == nil && uint64(len(rowIDs)) == max:
+			// one more element than allowed exists
+			c.finish()
+			return rowIDs, errors.Wrapf(ErrLimit, "more than %d elements", max)
 		case err == nil:
-			tmpSlice = reflect.Append(tmpSlice, val)
-		case ErrIteratorDone.Is(err):
-			destRef.Set(tmpSlice)
+			c.add(model)
+		case IsIteratorDone(err):
+			c.finish()
 			return rowIDs, nil
 		default:
 			return nil, err
@@ -265,19 +1939,387 @@ func ReadAll(it Iterator, dest ModelSlicePtr) ([]RowID, error) {
 	}
 }
 
+// modelCollector creates the models that the elements of an iterator are loaded into and collects the
+// loaded ones.
+type modelCollector interface {
+	// init validates the collector before any model is created.
+	init() error
+	// newModel returns a new model to load the next element into.
+	newModel() codec.ProtoMarshaler
+	// scratch returns a reset model to load an element into that is not collected. The same model is
+	// returned on every call.
+	scratch() codec.ProtoMarshaler
+	// add collects the last model returned by newModel after the element was loaded into it.
+	add(model codec.ProtoMarshaler)
+	// finish is called when all elements were added.
+	finish()
+}
+
+// sliceCollector collects models into the slice at a ModelSlicePtr using reflection.
+type sliceCollector struct {
+	dest         ModelSlicePtr
+	destRef      reflect.Value
+	tmpSlice     reflect.Value
+	plan         destPlan
+	val          reflect.Value
+	scratchModel codec.ProtoMarshaler
+}
+
+func (c *sliceCollector) init() error {
+	plan, err := assertDest(c.dest, &c.destRef, &c.tmpSlice)
+	c.plan = plan
+	return err
+}
+
+func (c *sliceCollector) newModel() codec.ProtoMarshaler {
+	val, model := c.plan.newElem()
+	c.val = val
+	return model
+}
+
+func (c *sliceCollector) scratch() codec.ProtoMarshaler {
+	if c.scratchModel == nil {
+		_, c.scratchModel = c.plan.newElem()
+	}
+	c.scratchModel.Reset()
+	return c.scratchModel
+}
+
+func (c *sliceCollector) add(codec.ProtoMarshaler) {
+	c.tmpSlice = reflect.Append(c.tmpSlice, c.val)
+}
+
+func (c *sliceCollector) finish() {
+	c.destRef.Set(c.tmpSlice)
+}
+
+// ValueDecoder decodes the persisted bytes of an element into a value of a destination slice, for
+// destinations whose elements do not implement codec.ProtoMarshaler, like view structs or interfaces
+// of `Any` wrapped messages. The value must be assignable to the slice elements.
+type ValueDecoder func(value []byte, rowID RowID) (interface{}, error)
+
+// ReadAllDecoded consumes all values of the iterator like ReadAll, but decodes them with the decoder
+// into a new slice at the passed pointer. The slice elements can be of any type that the values
+// returned by the decoder are assignable to. The iterator must be a RawIterator, like the table and
+// index iterators, and is closed afterwards.
+func ReadAllDecoded(it Iterator, dest interface{}, decoder ValueDecoder) ([]RowID, error) {
+	if decoder == nil {
+		return nil, errors.Wrap(ErrArgument, "decoder must not be nil")
+	}
+	if it == nil {
+		return nil, errors.Wrap(ErrArgument, "iterator must not be nil")
+	}
+	decoding, err := newDecodingIterator(it, decoder)
+	if err != nil {
+		it.Close()
+		return nil, err
+	}
+	return readAllInto(decoding, &decodedCollector{dest: dest}, math.MaxUint64)
+}
+
+// decodingIterator decodes the raw values of the parent iterator with a ValueDecoder into the
+// `decodedValue` passed to LoadNext. All other methods are passed through to the parent.
+type decodingIterator struct {
+	RawIterator
+	decoder ValueDecoder
+}
+
+func newDecodingIterator(it Iterator, decoder ValueDecoder) (*decodingIterator, error) {
+	raw, ok := it.(RawIterator)
+	if !ok {
+		return nil, errors.Wrapf(ErrArgument, "%T does not implement RawIterator", it)
+	}
+	return &decodingIterator{RawIterator: raw, decoder: decoder}, nil
+}
+
+// LoadNext decodes the next value into the `decodedValue` passed as dest and returns the key.
+func (i *decodingIterator) LoadNext(dest codec.ProtoMarshaler) (RowID, error) {
+	d, ok := dest.(*decodedValue)
+	if !ok {
+		return nil, errors.Wrapf(ErrType, "%T is not a decoded value", dest)
+	}
+	rowID, bz, err := i.RawNext()
+	if err != nil {
+		return nil, err
+	}
+	v, err := i.decoder(bz, rowID)
+	if err != nil {
+		return nil, err
+	}
+	if err := d.set(v); err != nil {
+		return nil, err
+	}
+	return rowID, nil
+}
+
+func (i *decodingIterator) nextRowID() (RowID, error) {
+	return nextRowID(i.RawIterator, nil)
+}
+
+func (i *decodingIterator) nextPageKey(_ codec.ProtoMarshaler) ([]byte, error) {
+	return nextPageKey(i.RawIterator, nil)
+}
+
+func (i *decodingIterator) countTotal() (uint64, bool, error) {
+	if tc, ok := i.RawIterator.(totalCounter); ok {
+		return tc.countTotal()
+	}
+	return 0, false, nil
+}
+
+// decodedCollector collects the values of a decodingIterator into the slice at dest using reflection.
+type decodedCollector struct {
+	dest         interface{}
+	destRef      reflect.Value
+	tmpSlice     reflect.Value
+	elemType     reflect.Type
+	scratchValue *decodedValue
+}
+
+func (c *decodedCollector) init() error {
+	if c.dest == nil {
+		return errors.Wrap(ErrArgument, "destination must not be nil")
+	}
+	tp := reflect.ValueOf(c.dest)
+	if tp.Kind() != reflect.Ptr || tp.Elem().Kind() != reflect.Slice {
+		return errors.Wrap(ErrArgument, "destination must be a pointer to a slice")
+	}
+	c.destRef = tp.Elem()
+	if !c.destRef.CanSet() {
+		return errors.Wrap(ErrArgument, "destination not assignable")
+	}
+	c.elemType = c.destRef.Type().Elem()
+	c.tmpSlice = reflect.MakeSlice(reflect.SliceOf(c.elemType), 0, 0)
+	return nil
+}
+
+func (c *decodedCollector) newModel() codec.ProtoMarshaler {
+	return &decodedValue{elemType: c.elemType}
+}
+
+func (c *decodedCollector) scratch() codec.ProtoMarshaler {
+	if c.scratchValue == nil {
+		c.scratchValue = &decodedValue{elemType: c.elemType}
+	}
+	return c.scratchValue
+}
+
+func (c *decodedCollector) add(model codec.ProtoMarshaler) {
+	c.tmpSlice = reflect.Append(c.tmpSlice, model.(*decodedValue).value)
+}
+
+func (c *decodedCollector) finish() {
+	c.destRef.Set(c.tmpSlice)
+}
+
+// decodedValue holds the result of a ValueDecoder in place of a model. It implements codec.ProtoMarshaler
+// only to be passed through the iterators and can not be marshaled.
+type decodedValue struct {
+	elemType reflect.Type
+	value    reflect.Value
+}
+
+// set stores v when it is assignable to the element type. A nil value is stored as zero value of
+// element types that can be nil, like interfaces.
+func (d *decodedValue) set(v interface{}) error {
+	if v == nil {
+		switch d.elemType.Kind() {
+		case reflect.Interface, reflect.Ptr, reflect.Map, reflect.Slice:
+			d.value = reflect.Zero(d.elemType)
+			return nil
+		}
+		return errors.Wrapf(ErrType, "nil is not assignable to %s", d.elemType)
+	}
+	val := reflect.ValueOf(v)
+	if !val.Type().AssignableTo(d.elemType) {
+		return errors.Wrapf(ErrType, "%s is not assignable to %s", val.Type(), d.elemType)
+	}
+	d.value = val
+	return nil
+}
+
+func (d *decodedValue) Reset()         { d.value = reflect.Value{} }
+func (d *decodedValue) String() string { return fmt.Sprintf("%v", d.value) }
+func (d *decodedValue) ProtoMessage()  {}
+
+func (d *decodedValue) Marshal() ([]byte, error) {
+	return nil, errors.Wrap(ErrType, "decoded values can not be marshaled")
+}
+
+func (d *decodedValue) MarshalTo([]byte) (int, error) {
+	return 0, errors.Wrap(ErrType, "decoded values can not be marshaled")
+}
+
+func (d *decodedValue) MarshalToSizedBuffer([]byte) (int, error) {
+	return 0, errors.Wrap(ErrType, "decoded values can not be marshaled")
+}
+
+func (d *decodedValue) Size() int { return 0 }
+
+func (d *decodedValue) Unmarshal([]byte) error {
+	return errors.Wrap(ErrType, "decoded values can not be unmarshaled")
+}
+
+// StreamAll loads all values of the iterator one by one and passes them to send, which is commonly the
+// `Send` method of a gRPC server stream. This allows streaming large result sets without loading them all
+// into memory. A new model is created with newModel for every element. The iteration stops with the
+// context error when the context is done, for example when the stream was cancelled by the client, or
+// with the error returned by send. The iterator is closed afterwards.
+// Example:
+//			err := StreamAll(stream.Context(), it, func() codec.ProtoMarshaler { return &testdata.GroupMember{} },
+//				func(m codec.ProtoMarshaler) error {
+//					return stream.Send(m.(*testdata.GroupMember))
+//				})
+//
+func StreamAll(ctx context.Context, it Iterator, newModel func() codec.ProtoMarshaler, send func(codec.ProtoMarshaler) error) error {
+	if send == nil {
+		if it != nil {
+			it.Close()
+		}
+		return errors.Wrap(ErrArgument, "send must not be nil")
+	}
+	return ForEach(it, newModel, func(_ RowID, m codec.ProtoMarshaler) (bool, error) {
+		if err := ctx.Err(); err != nil {
+			return true, err
+		}
+		return false, send(m)
+	})
+}
+
+// GroupBy loads all values of the iterator one by one and aggregates them by the key that keyFn returns.
+// For every element reduceFn is called with the aggregate of its group, which is nil for the first
+// element of a group, and returns the new aggregate. Only the aggregates are kept, so that the memory
+// needed does not grow with the number of elements per group. A new model is created with newModel for
+// every element. The iterator is closed afterwards.
+// The order of the returned map is random. Use `GroupBySorted` when the result must be deterministic.
+// Example:
+//			weights, err := GroupBy(it, func() codec.ProtoMarshaler { return &testdata.GroupMember{} },
+//				func(m codec.ProtoMarshaler) string {
+//					return m.(*testdata.GroupMember).Group.String()
+//				},
+//				func(acc interface{}, m codec.ProtoMarshaler) interface{} {
+//					sum, _ := acc.(uint64)
+//					return sum + m.(*testdata.GroupMember).Weight
+//				})
+//
+func GroupBy(it Iterator, newModel func() codec.ProtoMarshaler, keyFn func(codec.ProtoMarshaler) string, reduceFn func(acc interface{}, m codec.ProtoMarshaler) interface{}) (map[string]interface{}, error) {
+	if keyFn == nil || reduceFn == nil {
+		if it != nil {
+			it.Close()
+		}
+		return nil, errors.Wrap(ErrArgument, "key and reduce functions must not be nil")
+	}
+	res := make(map[string]interface{})
+	err := ForEach(it, newModel, func(_ RowID, m codec.ProtoMarshaler) (bool, error) {
+		key := keyFn(m)
+		res[key] = reduceFn(res[key], m)
+		return false, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// Aggregate is the aggregate of a group returned by `GroupBySorted`.
+type Aggregate struct {
+	Key   string
+	Value interface{}
+}
+
+// GroupBySorted aggregates all values of the iterator by key like GroupBy and returns the aggregates
+// sorted by key in ascending byte order.
+func GroupBySorted(it Iterator, newModel func() codec.ProtoMarshaler, keyFn func(codec.ProtoMarshaler) string, reduceFn func(acc interface{}, m codec.ProtoMarshaler) interface{}) ([]Aggregate, error) {
+	groups, err := GroupBy(it, newModel, keyFn, reduceFn)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]Aggregate, 0, len(groups))
+	for k, v := range groups {
+		res = append(res, Aggregate{Key: k, Value: v})
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Key < res[j].Key })
+	return res, nil
+}
+
+// ReadAllRaw consumes all values of the iterator without unmarshaling them and returns the RowIDs and
+// the persisted bytes in the same order. The iterator is closed afterwards.
+// Values persisted with empty bytes are returned as empty slices, not nil.
+func ReadAllRaw(it RawIterator) ([]RowID, [][]byte, error) {
+	if it == nil {
+		return nil, nil, errors.Wrap(ErrArgument, "iterator must not be nil")
+	}
+	defer it.Close()
+
+	var rowIDs []RowID
+	var values [][]byte
+	for {
+		rowID, value, err := it.RawNext()
+		switch {
+		case err == nil:
+			rowIDs = append(rowIDs, rowID)
+			values = append(values, value)
+		case IsIteratorDone(err):
+			return rowIDs, values, nil
+		default:
+			return nil, nil, err
+		}
+	}
+}
+
+// ReadAllWithLimit consumes up to max values from the iterator and stores them in a new slice at the
+// passed ModelSlicePtr. The returned truncated flag is true when the iterator had more than max values, like
+// the `ErrLimit` error of ReadAllLimited.
+// The slice can be empty when the iterator does not return any values but not nil. The iterator
+// is closed afterwards.
+// max can be 0 or any positive number
+func ReadAllWithLimit(it Iterator, dest ModelSlicePtr, max int) ([]RowID, bool, error) {
+	if max < 0 {
+		return nil, false, errors.Wrap(ErrArgument, "max must not be negative")
+	}
+	rowIDs, err := readAllInto(it, &sliceCollector{dest: dest}, uint64(max))
+	switch {
+	case ErrLimit.Is(err):
+		return rowIDs, true, nil
+	case err != nil:
+		return nil, false, err
+	}
+	return rowIDs, false, nil
+}
+
+// destPlan describes how the elements of a destination slice verified by assertDest are created, so that
+// the type is inspected only once per call and not for every element.
+type destPlan struct {
+	elemType reflect.Type
+	// ptrElem is true for a slice of pointers, like []*GroupMember, where the new element itself is the
+	// model. Otherwise a pointer to the new element is the model, like for []GroupMember.
+	ptrElem bool
+}
+
+// newElem returns a new value of the element type, that can be appended to the destination slice, and
+// the model that shares its memory to load the data into.
+func (p destPlan) newElem() (reflect.Value, codec.ProtoMarshaler) {
+	if p.ptrElem {
+		val := reflect.New(p.elemType.Elem())
+		return val, val.Interface().(codec.ProtoMarshaler)
+	}
+	obj := reflect.New(p.elemType)
+	return obj.Elem(), obj.Interface().(codec.ProtoMarshaler)
+}
+
 // assertDest checks that the provided dest is not nil and a pointer to a slice.
 // It also verifies that the slice elements implement *codec.ProtoMarshaler.
 // It overwrites destRef and tmpSlice using reflection.
-func assertDest(dest ModelSlicePtr, destRef *reflect.Value, tmpSlice *reflect.Value) (reflect.Type, error) {
+func assertDest(dest ModelSlicePtr, destRef *reflect.Value, tmpSlice *reflect.Value) (destPlan, error) {
 	if dest == nil {
-		return nil, errors.Wrap(ErrArgument, "destination must not be nil")
+		return destPlan{}, errors.Wrap(ErrArgument, "destination must not be nil")
 	}
 	tp := reflect.ValueOf(dest)
 	if tp.Kind() != reflect.Ptr {
-		return nil, errors.Wrap(ErrArgument, "destination must be a pointer to a slice")
+		return destPlan{}, errors.Wrap(ErrArgument, "destination must be a pointer to a slice")
 	}
 	if tp.Elem().Kind() != reflect.Slice {
-		return nil, errors.Wrap(ErrArgument, "destination must point to a slice")
+		return destPlan{}, errors.Wrap(ErrArgument, "destination must point to a slice")
 	}
 
 	// Since dest is just an interface{}, we overwrite destRef using reflection
@@ -285,20 +2327,31 @@ func assertDest(dest ModelSlicePtr, destRef *reflect.Value, tmpSlice *reflect.Va
 	*destRef = tp.Elem()
 	// We need to verify that we can call Set() on destRef.
 	if !destRef.CanSet() {
-		return nil, errors.Wrap(ErrArgument, "destination not assignable")
+		return destPlan{}, errors.Wrap(ErrArgument, "destination not assignable")
 	}
 
 	elemType := reflect.TypeOf(dest).Elem().Elem()
 
+	// The check must match the model used by newElem: the element itself when it is a
+	// pointer (e.g. []*GroupMember) or a pointer to the element otherwise (e.g. []GroupMember or
+	// a slice of structs embedding a proto message).
 	protoMarshaler := reflect.TypeOf((*codec.ProtoMarshaler)(nil)).Elem()
-	if !elemType.Implements(protoMarshaler) &&
-		!reflect.PtrTo(elemType).Implements(protoMarshaler) {
-		return nil, errors.Wrapf(ErrArgument, "unsupported type :%s", elemType)
+	switch elemType.Kind() {
+	case reflect.Ptr:
+		if !elemType.Implements(protoMarshaler) {
+			return destPlan{}, errors.Wrapf(ErrArgument, "unsupported type :%s", elemType)
+		}
+	case reflect.Interface:
+		return destPlan{}, errors.Wrapf(ErrArgument, "unsupported interface type :%s", elemType)
+	default:
+		if !reflect.PtrTo(elemType).Implements(protoMarshaler) {
+			return destPlan{}, errors.Wrapf(ErrArgument, "unsupported type :%s", elemType)
+		}
 	}
 
 	// tmpSlice is a slice value for the specified type
 	// that we'll use for appending new elements.
 	*tmpSlice = reflect.MakeSlice(reflect.SliceOf(elemType), 0, 0)
 
-	return elemType, nil
+	return destPlan{elemType: elemType, ptrElem: elemType.Kind() == reflect.Ptr}, nil
 }
@@ -1,7 +1,13 @@
 package orm_test
 
 import (
+	"bytes"
+	"context"
+	stdErrors "errors"
+	"fmt"
+	"math"
 	"testing"
+	"time"
 
 	"github.com/cosmos/cosmos-sdk/codec"
 	"github.com/cosmos/cosmos-sdk/codec/types"
@@ -41,6 +47,21 @@ func TestReadAll(t *testing.T) {
 			expIDs:    []orm.RowID{orm.EncodeSequence(1)},
 			expResult: &[]*testdata.GroupInfo{{Description: "test"}},
 		},
+		"all good with wrapper struct slice": {
+			srcIT: mockIter(orm.EncodeSequence(1), &testdata.GroupInfo{Description: "test"}),
+			destSlice: func() orm.ModelSlicePtr {
+				return new([]groupInfoWrapper)
+			},
+			expIDs:    []orm.RowID{orm.EncodeSequence(1)},
+			expResult: &[]groupInfoWrapper{{GroupInfo: testdata.GroupInfo{Description: "test"}}},
+		},
+		"interface slice": {
+			srcIT: mockIter(orm.EncodeSequence(1), &testdata.GroupInfo{Description: "test"}),
+			destSlice: func() orm.ModelSlicePtr {
+				return new([]codec.ProtoMarshaler)
+			},
+			expErr: orm.ErrArgument,
+		},
 		"dest slice empty": {
 			srcIT: mockIter(orm.EncodeSequence(1), &testdata.GroupInfo{}),
 			destSlice: func() orm.ModelSlicePtr {
@@ -94,157 +115,3109 @@ func TestReadAll(t *testing.T) {
 	}
 }
 
-func TestLimitedIterator(t *testing.T) {
+func TestReadAllWithLimit(t *testing.T) {
+	groups := testGroups(2, nil)
+	g1, g2 := groups[0], groups[1]
+	tb, _, ctx := newTestTableWithRows(t, groups...)
+
 	specs := map[string]struct {
-		src orm.Iterator
-		exp []testdata.GroupInfo
+		max          int
+		exp          []testdata.GroupInfo
+		expRowIDs    []orm.RowID
+		expTruncated bool
+		expErr       *errors.Error
 	}{
-		"all from range with max > length": {
-			src: orm.LimitIterator(mockIter(orm.EncodeSequence(1), &testdata.GroupInfo{Description: "test"}), 2),
-			exp: []testdata.GroupInfo{testdata.GroupInfo{Description: "test"}},
+		"max > length": {
+			max:       3,
+			exp:       []testdata.GroupInfo{g1, g2},
+			expRowIDs: []orm.RowID{orm.EncodeSequence(1), orm.EncodeSequence(2)},
 		},
-		"up to max": {
-			src: orm.LimitIterator(mockIter(orm.EncodeSequence(1), &testdata.GroupInfo{Description: "test"}), 1),
-			exp: []testdata.GroupInfo{testdata.GroupInfo{Description: "test"}},
+		"max = length": {
+			max:       2,
+			exp:       []testdata.GroupInfo{g1, g2},
+			expRowIDs: []orm.RowID{orm.EncodeSequence(1), orm.EncodeSequence(2)},
 		},
-		"none when max = 0": {
-			src: orm.LimitIterator(mockIter(orm.EncodeSequence(1), &testdata.GroupInfo{Description: "test"}), 0),
-			exp: []testdata.GroupInfo{},
+		"max < length": {
+			max:          1,
+			exp:          []testdata.GroupInfo{g1},
+			expRowIDs:    []orm.RowID{orm.EncodeSequence(1)},
+			expTruncated: true,
+		},
+		"max = 0": {
+			max:          0,
+			exp:          []testdata.GroupInfo{},
+			expTruncated: true,
+		},
+		"negative max": {
+			max:    -1,
+			expErr: orm.ErrArgument,
 		},
 	}
 	for msg, spec := range specs {
 		t.Run(msg, func(t *testing.T) {
+			it, err := tb.PrefixScan(ctx, 1, 100)
+			require.NoError(t, err)
+
 			var loaded []testdata.GroupInfo
-			_, err := orm.ReadAll(spec.src, &loaded)
+			rowIDs, truncated, err := orm.ReadAllWithLimit(it, &loaded, spec.max)
+			require.True(t, spec.expErr.Is(err), "expected %s but got %s", spec.expErr, err)
+			if spec.expErr != nil {
+				return
+			}
+			assert.EqualValues(t, spec.exp, loaded)
+			assert.EqualValues(t, spec.expRowIDs, rowIDs)
+			assert.Equal(t, spec.expTruncated, truncated)
+		})
+	}
+	t.Run("empty iterator", func(t *testing.T) {
+		it, err := tb.PrefixScan(ctx, 10, 100)
+		require.NoError(t, err)
+
+		var loaded []testdata.GroupInfo
+		rowIDs, truncated, err := orm.ReadAllWithLimit(it, &loaded, 0)
+		require.NoError(t, err)
+		assert.Empty(t, loaded)
+		assert.Empty(t, rowIDs)
+		assert.False(t, truncated)
+	})
+}
+
+func TestReadAllLimited(t *testing.T) {
+	groups := testGroups(2, nil)
+	g1, g2 := groups[0], groups[1]
+	tb, _, ctx := newTestTableWithRows(t, groups...)
+
+	specs := map[string]struct {
+		max       uint64
+		exp       []testdata.GroupInfo
+		expRowIDs []orm.RowID
+		expErr    *errors.Error
+	}{
+		"max > length": {
+			max:       3,
+			exp:       []testdata.GroupInfo{g1, g2},
+			expRowIDs: []orm.RowID{orm.EncodeSequence(1), orm.EncodeSequence(2)},
+		},
+		"max = length": {
+			max:       2,
+			exp:       []testdata.GroupInfo{g1, g2},
+			expRowIDs: []orm.RowID{orm.EncodeSequence(1), orm.EncodeSequence(2)},
+		},
+		"max < length": {
+			max:       1,
+			exp:       []testdata.GroupInfo{g1},
+			expRowIDs: []orm.RowID{orm.EncodeSequence(1)},
+			expErr:    orm.ErrLimit,
+		},
+		"max = 0": {
+			max:    0,
+			exp:    []testdata.GroupInfo{},
+			expErr: orm.ErrLimit,
+		},
+	}
+	for msg, spec := range specs {
+		t.Run(msg, func(t *testing.T) {
+			it, err := tb.PrefixScan(ctx, 1, 100)
 			require.NoError(t, err)
+			var closed int
+			it = closingIter{Iterator: it, close: func() error {
+				closed++
+				return nil
+			}}
+
+			var loaded []testdata.GroupInfo
+			rowIDs, err := orm.ReadAllLimited(it, &loaded, spec.max)
+			require.True(t, spec.expErr.Is(err), "expected %s but got %s", spec.expErr, err)
 			assert.EqualValues(t, spec.exp, loaded)
+			assert.EqualValues(t, spec.expRowIDs, rowIDs)
+			assert.Equal(t, 1, closed)
 		})
 	}
+	t.Run("empty iterator with max = 0", func(t *testing.T) {
+		it, err := tb.PrefixScan(ctx, 10, 100)
+		require.NoError(t, err)
+
+		var loaded []testdata.GroupInfo
+		rowIDs, err := orm.ReadAllLimited(it, &loaded, 0)
+		require.NoError(t, err)
+		assert.NotNil(t, loaded)
+		assert.Empty(t, loaded)
+		assert.Empty(t, rowIDs)
+	})
+	t.Run("iterator is closed on errors", func(t *testing.T) {
+		var closed int
+		it := closingIter{Iterator: orm.NewInvalidIterator(), close: func() error {
+			closed++
+			return nil
+		}}
+		var loaded []testdata.GroupInfo
+		_, err := orm.ReadAllLimited(it, &loaded, 1)
+		require.True(t, orm.ErrIteratorInvalid.Is(err), err)
+
+		_, err = orm.ReadAllLimited(it, nil, 1)
+		require.True(t, orm.ErrArgument.Is(err), err)
+		assert.Equal(t, 2, closed)
+	})
 }
 
-func TestPaginate(t *testing.T) {
-	interfaceRegistry := types.NewInterfaceRegistry()
-	cdc := codec.NewProtoCodec(interfaceRegistry)
+func TestIsIteratorDone(t *testing.T) {
+	specs := map[string]struct {
+		err error
+		exp bool
+	}{
+		"done":              {err: orm.ErrIteratorDone, exp: true},
+		"wrapped":           {err: errors.Wrap(orm.ErrIteratorDone, "context"), exp: true},
+		"wrapped with fmt":  {err: fmt.Errorf("context: %w", orm.ErrIteratorDone), exp: true},
+		"wrapped twice":     {err: errors.Wrap(fmt.Errorf("context: %w", orm.ErrIteratorDone), "more"), exp: true},
+		"other orm error":   {err: orm.ErrIteratorInvalid},
+		"formatted message": {err: fmt.Errorf("context: %s", orm.ErrIteratorDone)},
+		"nil":               {},
+	}
+	for msg, spec := range specs {
+		t.Run(msg, func(t *testing.T) {
+			assert.Equal(t, spec.exp, orm.IsIteratorDone(spec.err))
+			assert.Equal(t, spec.exp, stdErrors.Is(spec.err, orm.ErrIteratorDone))
+		})
+	}
+	t.Run("errors as", func(t *testing.T) {
+		var ormErr *errors.Error
+		require.True(t, stdErrors.As(fmt.Errorf("context: %w", errors.Wrap(orm.ErrArgument, "more")), &ormErr))
+		assert.Equal(t, orm.ErrArgument.ABCICode(), ormErr.ABCICode())
+	})
 
-	storeKey := sdk.NewKVStoreKey("test")
-	const (
-		testTablePrefix = iota
-		testTableSeqPrefix
-	)
-	tBuilder := orm.NewAutoUInt64TableBuilder(testTablePrefix, testTableSeqPrefix, storeKey, &testdata.GroupInfo{}, cdc)
-	idx := orm.NewIndex(tBuilder, GroupByAdminIndexPrefix, func(val interface{}) ([]orm.RowID, error) {
-		return []orm.RowID{[]byte(val.(*testdata.GroupInfo).Admin)}, nil
+	// doneAfter returns an iterator with n elements that ends with a wrapped ErrIteratorDone
+	doneAfter := func(n int) orm.Iterator {
+		var calls int
+		return orm.IteratorFunc(func(dest codec.ProtoMarshaler) (orm.RowID, error) {
+			calls++
+			if calls > n {
+				return nil, fmt.Errorf("source exhausted: %w", orm.ErrIteratorDone)
+			}
+			dest.(*testdata.GroupInfo).Description = fmt.Sprintf("my test %d", calls)
+			return orm.EncodeSequence(uint64(calls)), nil
+		})
+	}
+	exp := []testdata.GroupInfo{{Description: "my test 1"}, {Description: "my test 2"}}
+	t.Run("read all", func(t *testing.T) {
+		var loaded []testdata.GroupInfo
+		rowIDs, err := orm.ReadAll(doneAfter(2), &loaded)
+		require.NoError(t, err)
+		assert.Equal(t, exp, loaded)
+		assert.Equal(t, []orm.RowID{orm.EncodeSequence(1), orm.EncodeSequence(2)}, rowIDs)
 	})
-	tb := tBuilder.Build()
-	ctx := orm.NewMockContext()
+	t.Run("paginate", func(t *testing.T) {
+		var loaded []testdata.GroupInfo
+		res, err := orm.Paginate(doneAfter(2), &query.PageRequest{Limit: 5, CountTotal: true}, &loaded)
+		require.NoError(t, err)
+		assert.Equal(t, exp, loaded)
+		assert.Nil(t, res.NextKey)
+		assert.Equal(t, uint64(2), res.Total)
+	})
+	t.Run("paginate with next key", func(t *testing.T) {
+		var loaded []testdata.GroupInfo
+		res, err := orm.Paginate(doneAfter(3), &query.PageRequest{Limit: 2, CountTotal: true}, &loaded)
+		require.NoError(t, err)
+		assert.Equal(t, exp, loaded)
+		assert.Equal(t, orm.EncodeSequence(3), res.NextKey)
+		assert.Equal(t, uint64(3), res.Total)
+	})
+}
 
+func TestCount(t *testing.T) {
 	admin := sdk.AccAddress([]byte("admin-address"))
-	g1 := testdata.GroupInfo{
-		Description: "my test 1",
-		Admin:       admin,
-	}
-	g2 := testdata.GroupInfo{
-		Description: "my test 2",
-		Admin:       admin,
+	tb, idx, ctx := newTestTableWithRows(t, []testdata.GroupInfo{
+		{Description: "my test 1", Admin: admin},
+		{Description: "my test 2", Admin: sdk.AccAddress([]byte("other-admin-address"))},
+		{Description: "my test 3", Admin: admin},
+	}...)
+
+	specs := map[string]struct {
+		srcIT    func() (orm.Iterator, error)
+		expCount uint64
+		expErr   *errors.Error
+	}{
+		"table scan": {
+			srcIT:    func() (orm.Iterator, error) { return tb.PrefixScan(ctx, 1, 100) },
+			expCount: 3,
+		},
+		"index lookup": {
+			srcIT:    func() (orm.Iterator, error) { return idx.Get(ctx, admin) },
+			expCount: 2,
+		},
+		"empty": {
+			srcIT: func() (orm.Iterator, error) { return idx.Get(ctx, []byte("nobody")) },
+		},
+		"iterator is nil": {
+			srcIT:  func() (orm.Iterator, error) { return nil, nil },
+			expErr: orm.ErrArgument,
+		},
+		"not a raw iterator": {
+			srcIT:  func() (orm.Iterator, error) { return noopIter(), nil },
+			expErr: orm.ErrArgument,
+		},
 	}
-	g3 := testdata.GroupInfo{
-		Description: "my test 3",
-		Admin:       sdk.AccAddress([]byte("other-admin-address")),
+	for msg, spec := range specs {
+		t.Run(msg, func(t *testing.T) {
+			it, err := spec.srcIT()
+			require.NoError(t, err)
+			count, err := orm.Count(it)
+			require.True(t, spec.expErr.Is(err), "expected %s but got %s", spec.expErr, err)
+			assert.Equal(t, spec.expCount, count)
+		})
 	}
-	g4 := testdata.GroupInfo{
-		Description: "my test 4",
-		Admin:       admin,
+}
+
+func TestFirst(t *testing.T) {
+	specs := map[string]struct {
+		srcIT     orm.Iterator
+		expErr    *errors.Error
+		expRowID  orm.RowID
+		expResult testdata.GroupInfo
+	}{
+		"first element": {
+			srcIT:     mockIter(orm.EncodeSequence(1), &testdata.GroupInfo{Description: "test"}),
+			expRowID:  orm.EncodeSequence(1),
+			expResult: testdata.GroupInfo{Description: "test"},
+		},
+		"no elements": {
+			srcIT:  orm.NewSingleValueIterator(orm.EncodeSequence(1), nil),
+			expErr: orm.ErrIteratorDone,
+		},
+		"iterator is nil": {
+			srcIT:  nil,
+			expErr: orm.ErrIteratorDone,
+		},
+		"error on loadNext is returned": {
+			srcIT:  orm.NewInvalidIterator(),
+			expErr: orm.ErrIteratorInvalid,
+		},
 	}
-	g5 := testdata.GroupInfo{
-		Description: "my test 5",
-		Admin:       sdk.AccAddress([]byte("other-admin-address")),
+	for msg, spec := range specs {
+		t.Run(msg, func(t *testing.T) {
+			var loaded testdata.GroupInfo
+			rowID, err := orm.First(spec.srcIT, &loaded)
+			require.True(t, spec.expErr.Is(err), "expected %s but got %s", spec.expErr, err)
+			assert.Equal(t, spec.expRowID, rowID)
+			assert.Equal(t, spec.expResult, loaded)
+		})
 	}
+}
 
-	for _, g := range []testdata.GroupInfo{g1, g2, g3, g4, g5} {
-		_, err := tb.Create(ctx, &g)
-		require.NoError(t, err)
+func TestFirstOrNotFound(t *testing.T) {
+	specs := map[string]struct {
+		srcIT     orm.Iterator
+		expErr    *errors.Error
+		expRowID  orm.RowID
+		expResult testdata.GroupInfo
+	}{
+		"single element": {
+			srcIT:     mockIter(orm.EncodeSequence(1), &testdata.GroupInfo{Description: "test"}),
+			expRowID:  orm.EncodeSequence(1),
+			expResult: testdata.GroupInfo{Description: "test"},
+		},
+		"multiple elements": {
+			srcIT: orm.ChainIterator(
+				mockIter(orm.EncodeSequence(1), &testdata.GroupInfo{Description: "first"}),
+				mockIter(orm.EncodeSequence(2), &testdata.GroupInfo{Description: "second"}),
+			),
+			expRowID:  orm.EncodeSequence(1),
+			expResult: testdata.GroupInfo{Description: "first"},
+		},
+		"no elements": {
+			srcIT:  orm.NewSingleValueIterator(orm.EncodeSequence(1), nil),
+			expErr: orm.ErrNotFound,
+		},
+		"iterator is nil": {
+			srcIT:  nil,
+			expErr: orm.ErrNotFound,
+		},
+		"error on loadNext is returned": {
+			srcIT:  orm.NewInvalidIterator(),
+			expErr: orm.ErrIteratorInvalid,
+		},
+	}
+	for msg, spec := range specs {
+		t.Run(msg, func(t *testing.T) {
+			var loaded testdata.GroupInfo
+			rowID, err := orm.FirstOrNotFound(spec.srcIT, &loaded)
+			require.True(t, spec.expErr.Is(err), "expected %s but got %s", spec.expErr, err)
+			assert.Equal(t, spec.expRowID, rowID)
+			assert.Equal(t, spec.expResult, loaded)
+		})
 	}
+}
 
+func TestLast(t *testing.T) {
 	specs := map[string]struct {
-		pageReq    *query.PageRequest
-		expPageRes *query.PageResponse
-		exp        []testdata.GroupInfo
-		key        []byte
-		expErr     bool
+		srcIT     orm.Iterator
+		expErr    *errors.Error
+		expRowID  orm.RowID
+		expResult testdata.GroupInfo
 	}{
-		"one item": {
-			pageReq:    &query.PageRequest{Key: nil, Limit: 1},
-			exp:        []testdata.GroupInfo{g1},
-			expPageRes: &query.PageResponse{Total: 0, NextKey: orm.EncodeSequence(2)},
-			key:        admin,
+		"single element": {
+			srcIT:     mockIter(orm.EncodeSequence(1), &testdata.GroupInfo{Description: "test"}),
+			expRowID:  orm.EncodeSequence(1),
+			expResult: testdata.GroupInfo{Description: "test"},
 		},
-		"with both key and offset": {
-			pageReq: &query.PageRequest{Key: orm.EncodeSequence(2), Offset: 1},
-			expErr:  true,
-			key:     admin,
+		"multiple elements": {
+			srcIT: orm.ChainIterator(
+				mockIter(orm.EncodeSequence(1), &testdata.GroupInfo{Description: "first", Admin: sdk.AccAddress("first-admin-address0")}),
+				mockIter(orm.EncodeSequence(2), &testdata.GroupInfo{Description: "second"}),
+			),
+			expRowID:  orm.EncodeSequence(2),
+			expResult: testdata.GroupInfo{Description: "second"},
 		},
-		"up to max": {
-			pageReq:    &query.PageRequest{Key: nil, Limit: 3, CountTotal: true},
-			exp:        []testdata.GroupInfo{g1, g2, g4},
-			expPageRes: &query.PageResponse{Total: 3, NextKey: nil},
-			key:        admin,
+		"no elements": {
+			srcIT:  orm.NewSingleValueIterator(orm.EncodeSequence(1), nil),
+			expErr: orm.ErrIteratorDone,
 		},
-		"no results": {
-			pageReq:    &query.PageRequest{Key: nil, Limit: 2, CountTotal: true},
-			exp:        []testdata.GroupInfo{},
-			expPageRes: &query.PageResponse{Total: 0, NextKey: nil},
-			key:        sdk.AccAddress([]byte("no-group-address")),
+		"iterator is nil": {
+			srcIT:  nil,
+			expErr: orm.ErrIteratorDone,
 		},
-		"with offset and count total": {
-			pageReq:    &query.PageRequest{Key: nil, Offset: 1, Limit: 2, CountTotal: true},
-			exp:        []testdata.GroupInfo{g2, g4},
-			expPageRes: &query.PageResponse{Total: 3, NextKey: nil},
-			key:        admin,
+		"error on loadNext is returned": {
+			srcIT:  orm.NewInvalidIterator(),
+			expErr: orm.ErrIteratorInvalid,
 		},
-		"nil/default page req (limit = 100 > number of items)": {
-			pageReq:    nil,
-			exp:        []testdata.GroupInfo{g1, g2, g4},
-			expPageRes: &query.PageResponse{Total: 3, NextKey: nil},
-			key:        admin,
+	}
+	for msg, spec := range specs {
+		t.Run(msg, func(t *testing.T) {
+			var loaded testdata.GroupInfo
+			rowID, err := orm.Last(spec.srcIT, &loaded)
+			require.True(t, spec.expErr.Is(err), "expected %s but got %s", spec.expErr, err)
+			assert.Equal(t, spec.expRowID, rowID)
+			assert.Equal(t, spec.expResult, loaded)
+		})
+	}
+	t.Run("iterator is closed", func(t *testing.T) {
+		var closed bool
+		it := closingIter{Iterator: mockIter(orm.EncodeSequence(1), &testdata.GroupInfo{}), close: func() error {
+			closed = true
+			return nil
+		}}
+		_, err := orm.Last(it, &testdata.GroupInfo{})
+		require.NoError(t, err)
+		assert.True(t, closed)
+	})
+}
+
+func TestForEach(t *testing.T) {
+	groups := testGroups(3, nil)
+	g1, g2, g3 := groups[0], groups[1], groups[2]
+	tb, _, ctx := newTestTableWithRows(t, groups...)
+	newModel := func() codec.ProtoMarshaler { return &testdata.GroupInfo{} }
+	myErr := errors.Register("test", 1, "my error")
+
+	specs := map[string]struct {
+		start, end uint64
+		stopAfter  int
+		failAfter  int
+		exp        []testdata.GroupInfo
+		expRowIDs  []orm.RowID
+		expErr     *errors.Error
+	}{
+		"all elements": {
+			start:     1,
+			end:       100,
+			exp:       []testdata.GroupInfo{g1, g2, g3},
+			expRowIDs: []orm.RowID{orm.EncodeSequence(1), orm.EncodeSequence(2), orm.EncodeSequence(3)},
 		},
-		"with key and limit < number of elem (count total is ignored in this case)": {
-			pageReq:    &query.PageRequest{Key: orm.EncodeSequence(2), Limit: 1, CountTotal: true},
-			exp:        []testdata.GroupInfo{g2},
-			expPageRes: &query.PageResponse{Total: 0, NextKey: orm.EncodeSequence(4)},
-			key:        admin,
+		"early stop": {
+			start:     1,
+			end:       100,
+			stopAfter: 2,
+			exp:       []testdata.GroupInfo{g1, g2},
+			expRowIDs: []orm.RowID{orm.EncodeSequence(1), orm.EncodeSequence(2)},
 		},
-		"with key and limit >= number of elem": {
-			pageReq:    &query.PageRequest{Key: orm.EncodeSequence(2), Limit: 2},
-			exp:        []testdata.GroupInfo{g2, g4},
-			expPageRes: &query.PageResponse{Total: 0, NextKey: nil},
-			key:        admin,
+		"callback error is returned": {
+			start:     1,
+			end:       100,
+			failAfter: 1,
+			exp:       []testdata.GroupInfo{g1},
+			expRowIDs: []orm.RowID{orm.EncodeSequence(1)},
+			expErr:    myErr,
 		},
-		"with nothing left to iterate from key": {
-			pageReq:    &query.PageRequest{Key: orm.EncodeSequence(5)},
-			exp:        []testdata.GroupInfo{},
-			expPageRes: &query.PageResponse{Total: 0, NextKey: nil},
-			key:        admin,
+		"empty iterator": {
+			start: 10,
+			end:   100,
 		},
 	}
 	for msg, spec := range specs {
 		t.Run(msg, func(t *testing.T) {
+			it, err := tb.PrefixScan(ctx, spec.start, spec.end)
+			require.NoError(t, err)
+
 			var loaded []testdata.GroupInfo
+			var rowIDs []orm.RowID
+			err = orm.ForEach(it, newModel, func(rowID orm.RowID, m codec.ProtoMarshaler) (bool, error) {
+				loaded = append(loaded, *m.(*testdata.GroupInfo))
+				rowIDs = append(rowIDs, rowID)
+				if len(loaded) == spec.failAfter {
+					return false, myErr
+				}
+				return len(loaded) == spec.stopAfter, nil
+			})
+			require.True(t, spec.expErr.Is(err), "expected %s but got %s", spec.expErr, err)
+			assert.Equal(t, spec.exp, loaded)
+			assert.Equal(t, spec.expRowIDs, rowIDs)
+		})
+	}
+	t.Run("error on loadNext is returned", func(t *testing.T) {
+		err := orm.ForEach(orm.NewInvalidIterator(), newModel, func(orm.RowID, codec.ProtoMarshaler) (bool, error) {
+			return false, nil
+		})
+		require.True(t, orm.ErrIteratorInvalid.Is(err), err)
+	})
+	t.Run("iterator is closed", func(t *testing.T) {
+		var closed bool
+		it := closingIter{Iterator: orm.NewInvalidIterator(), close: func() error {
+			closed = true
+			return nil
+		}}
+		_ = orm.ForEach(it, newModel, func(orm.RowID, codec.ProtoMarshaler) (bool, error) {
+			return false, nil
+		})
+		assert.True(t, closed)
+	})
+}
 
-			it, err := idx.GetPaginated(ctx, spec.key, spec.pageReq)
-			require.NoError(t, err)
+func TestStreamAll(t *testing.T) {
+	groups := testGroups(3, nil)
+	g1, g2, g3 := groups[0], groups[1], groups[2]
+	tb, _, ctx := newTestTableWithRows(t, groups...)
+	newModel := func() codec.ProtoMarshaler { return &testdata.GroupInfo{} }
+	myErr := errors.Register("test", 2, "my error")
 
-			res, err := orm.Paginate(it, spec.pageReq, &loaded)
-			if spec.expErr {
-				require.Error(t, err)
-			} else {
-				require.NoError(t, err)
-				assert.EqualValues(t, spec.exp, loaded)
-				assert.EqualValues(t, spec.expPageRes.Total, res.Total)
-				assert.EqualValues(t, spec.expPageRes.NextKey, res.NextKey)
-			}
+	specs := map[string]struct {
+		cancelAfter int
+		failAfter   int
+		exp         []testdata.GroupInfo
+		expErr      error
+	}{
+		"all elements": {
+			exp: []testdata.GroupInfo{g1, g2, g3},
+		},
+		"cancelled stream": {
+			cancelAfter: 2,
+			exp:         []testdata.GroupInfo{g1, g2},
+			expErr:      context.Canceled,
+		},
+		"send error is returned": {
+			failAfter: 1,
+			exp:       []testdata.GroupInfo{g1},
+			expErr:    myErr,
+		},
+	}
+	for msg, spec := range specs {
+		t.Run(msg, func(t *testing.T) {
+			it, err := tb.PrefixScan(ctx, 1, 100)
+			require.NoError(t, err)
 
+			streamCtx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			var sent []testdata.GroupInfo
+			err = orm.StreamAll(streamCtx, it, newModel, func(m codec.ProtoMarshaler) error {
+				sent = append(sent, *m.(*testdata.GroupInfo))
+				if len(sent) == spec.cancelAfter {
+					cancel()
+				}
+				if len(sent) == spec.failAfter {
+					return myErr
+				}
+				return nil
+			})
+			assert.Equal(t, spec.expErr, err)
+			assert.Equal(t, spec.exp, sent)
 		})
 	}
+	t.Run("iterator is closed on invalid send", func(t *testing.T) {
+		var closed bool
+		it := closingIter{Iterator: orm.NewInvalidIterator(), close: func() error {
+			closed = true
+			return nil
+		}}
+		err := orm.StreamAll(context.Background(), it, newModel, nil)
+		require.True(t, orm.ErrArgument.Is(err), err)
+		assert.True(t, closed)
+	})
+}
+
+func TestGroupBy(t *testing.T) {
+	admin1 := sdk.AccAddress([]byte("admin-address-1"))
+	admin2 := sdk.AccAddress([]byte("admin-address-2"))
+	_, idx, ctx := newTestTableWithRows(t, []testdata.GroupInfo{
+		{Description: "a", Admin: admin2},
+		{Description: "b", Admin: admin1},
+		{Description: "c", Admin: admin2},
+		{Description: "d", Admin: admin2},
+	}...)
+	newModel := func() codec.ProtoMarshaler { return &testdata.GroupInfo{} }
+	byAdmin := func(m codec.ProtoMarshaler) string { return m.(*testdata.GroupInfo).Admin.String() }
+	count := func(acc interface{}, _ codec.ProtoMarshaler) interface{} {
+		n, _ := acc.(int)
+		return n + 1
+	}
+	descriptions := func(acc interface{}, m codec.ProtoMarshaler) interface{} {
+		s, _ := acc.(string)
+		return s + m.(*testdata.GroupInfo).Description
+	}
+
+	specs := map[string]struct {
+		src      func() (orm.Iterator, error)
+		keyFn    func(codec.ProtoMarshaler) string
+		reduceFn func(interface{}, codec.ProtoMarshaler) interface{}
+		exp      []orm.Aggregate
+		expErr   *errors.Error
+	}{
+		"count by admin": {
+			src:      func() (orm.Iterator, error) { return idx.PrefixScan(ctx, nil, nil) },
+			keyFn:    byAdmin,
+			reduceFn: count,
+			exp:      []orm.Aggregate{{Key: admin1.String(), Value: 1}, {Key: admin2.String(), Value: 3}},
+		},
+		"reduce in index order": {
+			src:      func() (orm.Iterator, error) { return idx.ReversePrefixScan(ctx, nil, nil) },
+			keyFn:    byAdmin,
+			reduceFn: descriptions,
+			exp:      []orm.Aggregate{{Key: admin1.String(), Value: "b"}, {Key: admin2.String(), Value: "dca"}},
+		},
+		"single group": {
+			src:      func() (orm.Iterator, error) { return idx.Get(ctx, admin2) },
+			keyFn:    func(codec.ProtoMarshaler) string { return "all" },
+			reduceFn: count,
+			exp:      []orm.Aggregate{{Key: "all", Value: 3}},
+		},
+		"empty": {
+			src:      func() (orm.Iterator, error) { return idx.Get(ctx, []byte("other-admin")) },
+			keyFn:    byAdmin,
+			reduceFn: count,
+			exp:      []orm.Aggregate{},
+		},
+		"iterator error": {
+			src:      func() (orm.Iterator, error) { return orm.NewInvalidIterator(), nil },
+			keyFn:    byAdmin,
+			reduceFn: count,
+			expErr:   orm.ErrIteratorInvalid,
+		},
+		"nil key function": {
+			src:      func() (orm.Iterator, error) { return idx.PrefixScan(ctx, nil, nil) },
+			reduceFn: count,
+			expErr:   orm.ErrArgument,
+		},
+		"nil reduce function": {
+			src:    func() (orm.Iterator, error) { return idx.PrefixScan(ctx, nil, nil) },
+			keyFn:  byAdmin,
+			expErr: orm.ErrArgument,
+		},
+	}
+	for msg, spec := range specs {
+		t.Run(msg, func(t *testing.T) {
+			it, err := spec.src()
+			require.NoError(t, err)
+			groups, err := orm.GroupBy(it, newModel, spec.keyFn, spec.reduceFn)
+			if spec.expErr != nil {
+				assert.True(t, spec.expErr.Is(err), err)
+			} else {
+				require.NoError(t, err)
+				exp := make(map[string]interface{}, len(spec.exp))
+				for _, a := range spec.exp {
+					exp[a.Key] = a.Value
+				}
+				assert.Equal(t, exp, groups)
+			}
+			_, err = it.LoadNext(&testdata.GroupInfo{})
+			assert.True(t, orm.ErrIteratorClosed.Is(err), err)
+
+			it, err = spec.src()
+			require.NoError(t, err)
+			sorted, err := orm.GroupBySorted(it, newModel, spec.keyFn, spec.reduceFn)
+			if spec.expErr != nil {
+				assert.True(t, spec.expErr.Is(err), err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, spec.exp, sorted)
+		})
+	}
+}
+
+func TestCtxIterator(t *testing.T) {
+	g1 := testdata.GroupInfo{Description: "my test 1"}
+	g2 := testdata.GroupInfo{Description: "my test 2"}
+	g3 := testdata.GroupInfo{Description: "my test 3"}
+	newIter := func(closed *int) orm.Iterator {
+		return closingIter{
+			Iterator: orm.ChainIterator(
+				mockIter(orm.EncodeSequence(1), &g1),
+				mockIter(orm.EncodeSequence(2), &g2),
+				mockIter(orm.EncodeSequence(3), &g3),
+			),
+			close: func() error {
+				*closed++
+				return nil
+			},
+		}
+	}
+	newModel := func() codec.ProtoMarshaler { return &testdata.GroupInfo{} }
+
+	t.Run("cancel mid iteration", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		var closed int
+		var loaded []testdata.GroupInfo
+		err := orm.ForEachCtx(ctx, newIter(&closed), newModel, func(_ orm.RowID, m codec.ProtoMarshaler) (bool, error) {
+			loaded = append(loaded, *m.(*testdata.GroupInfo))
+			if len(loaded) == 2 {
+				cancel()
+			}
+			return false, nil
+		})
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, []testdata.GroupInfo{g1, g2}, loaded)
+		assert.Equal(t, 1, closed)
+	})
+	t.Run("deadline exceeded", func(t *testing.T) {
+		ctx, cancel := context.WithDeadline(context.Background(), time.Unix(0, 0))
+		defer cancel()
+		var closed int
+		it := orm.CtxIterator(ctx, newIter(&closed))
+		_, err := it.LoadNext(&testdata.GroupInfo{})
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		// the parent is closed when the context is done
+		assert.Equal(t, 1, closed)
+
+		_, err = it.LoadNext(&testdata.GroupInfo{})
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		require.NoError(t, it.Close())
+		assert.Equal(t, 1, closed)
+	})
+	t.Run("all elements", func(t *testing.T) {
+		var closed int
+		var loaded int
+		err := orm.ForEachCtx(context.Background(), newIter(&closed), newModel, func(orm.RowID, codec.ProtoMarshaler) (bool, error) {
+			loaded++
+			return false, nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 3, loaded)
+		assert.Equal(t, 1, closed)
+	})
+	t.Run("nil iterator", func(t *testing.T) {
+		err := orm.ForEachCtx(context.Background(), nil, newModel, func(orm.RowID, codec.ProtoMarshaler) (bool, error) {
+			return false, nil
+		})
+		assert.True(t, orm.ErrArgument.Is(err), err)
+		assert.Panics(t, func() { orm.CtxIterator(context.Background(), nil) })
+	})
+}
+
+func TestPaginateCtx(t *testing.T) {
+	tb, _, ctx := newTestTableWithRows(t, testGroups(3, nil)...)
+
+	t.Run("all good", func(t *testing.T) {
+		it, err := tb.PrefixScan(ctx, 1, 100)
+		require.NoError(t, err)
+		var loaded []testdata.GroupInfo
+		res, err := orm.PaginateCtx(context.Background(), it, &query.PageRequest{Limit: 2, CountTotal: true}, &loaded)
+		require.NoError(t, err)
+		assert.Len(t, loaded, 2)
+		assert.Equal(t, orm.EncodeSequence(3), res.NextKey)
+		assert.Equal(t, uint64(3), res.Total)
+	})
+	t.Run("cancel mid iteration", func(t *testing.T) {
+		queryCtx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		tableIt, err := tb.PrefixScan(ctx, 1, 100)
+		require.NoError(t, err)
+		var loaded, closed int
+		it := closingIter{
+			Iterator: orm.IteratorFunc(func(dest codec.ProtoMarshaler) (orm.RowID, error) {
+				loaded++
+				if loaded == 2 {
+					cancel()
+				}
+				return tableIt.LoadNext(dest)
+			}),
+			close: func() error {
+				closed++
+				return tableIt.Close()
+			},
+		}
+		var dest []testdata.GroupInfo
+		_, err = orm.PaginateCtx(queryCtx, it, &query.PageRequest{Limit: 3}, &dest)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, 2, loaded)
+		assert.Equal(t, 1, closed)
+	})
+	t.Run("already cancelled", func(t *testing.T) {
+		queryCtx, cancel := context.WithCancel(context.Background())
+		cancel()
+		it, err := tb.PrefixScan(ctx, 1, 100)
+		require.NoError(t, err)
+		var loaded []testdata.GroupInfo
+		_, err = orm.PaginateCtx(queryCtx, it, &query.PageRequest{Offset: 1, Limit: 1}, &loaded)
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
+
+func TestSingleValueIterator(t *testing.T) {
+	bz, err := (&testdata.GroupInfo{Description: "test"}).Marshal()
+	require.NoError(t, err)
+
+	t.Run("value loaded once", func(t *testing.T) {
+		it := orm.NewSingleValueIterator(orm.EncodeSequence(1), bz)
+		var loaded testdata.GroupInfo
+		rowID, err := it.LoadNext(&loaded)
+		require.NoError(t, err)
+		assert.Equal(t, orm.RowID(orm.EncodeSequence(1)), rowID)
+		assert.Equal(t, testdata.GroupInfo{Description: "test"}, loaded)
+
+		_, err = it.LoadNext(&loaded)
+		require.True(t, orm.ErrIteratorDone.Is(err), err)
+	})
+	t.Run("nil value with nil destination", func(t *testing.T) {
+		_, err := orm.NewSingleValueIterator(orm.EncodeSequence(1), nil).LoadNext(nil)
+		require.True(t, orm.ErrIteratorDone.Is(err), err)
+	})
+	t.Run("nil destination", func(t *testing.T) {
+		_, err := orm.NewSingleValueIterator(orm.EncodeSequence(1), bz).LoadNext(nil)
+		require.True(t, orm.ErrArgument.Is(err), err)
+	})
+	t.Run("unmarshal error is returned on retry", func(t *testing.T) {
+		it := orm.NewSingleValueIterator(orm.EncodeSequence(1), []byte{0xff})
+		var loaded testdata.GroupInfo
+		_, err := it.LoadNext(&loaded)
+		require.Error(t, err)
+		require.False(t, orm.ErrIteratorDone.Is(err), err)
+
+		_, err = it.LoadNext(&loaded)
+		require.Error(t, err)
+		require.False(t, orm.ErrIteratorDone.Is(err), err)
+	})
+}
+
+func TestSliceIterator(t *testing.T) {
+	bz1, err := (&testdata.GroupInfo{Description: "test 1"}).Marshal()
+	require.NoError(t, err)
+	bz2, err := (&testdata.GroupInfo{Description: "test 2"}).Marshal()
+	require.NoError(t, err)
+
+	t.Run("values in order", func(t *testing.T) {
+		it := orm.NewSliceIterator([]orm.RowID{orm.EncodeSequence(1), orm.EncodeSequence(2)}, [][]byte{bz1, bz2})
+		var loaded []testdata.GroupInfo
+		rowIDs, err := orm.ReadAll(it, &loaded)
+		require.NoError(t, err)
+		assert.Equal(t, []orm.RowID{orm.EncodeSequence(1), orm.EncodeSequence(2)}, rowIDs)
+		assert.Equal(t, []testdata.GroupInfo{{Description: "test 1"}, {Description: "test 2"}}, loaded)
+	})
+	t.Run("empty", func(t *testing.T) {
+		_, err := orm.NewSliceIterator(nil, nil).LoadNext(&testdata.GroupInfo{})
+		require.True(t, orm.ErrIteratorDone.Is(err), err)
+	})
+	t.Run("empty value", func(t *testing.T) {
+		it := orm.NewSliceIterator([]orm.RowID{orm.EncodeSequence(1)}, [][]byte{{}})
+		var loaded testdata.GroupInfo
+		_, err := it.LoadNext(&loaded)
+		require.NoError(t, err)
+		assert.Equal(t, testdata.GroupInfo{}, loaded)
+	})
+	t.Run("rewind", func(t *testing.T) {
+		it := orm.NewSliceIterator([]orm.RowID{orm.EncodeSequence(1)}, [][]byte{bz1})
+		_, err := it.LoadNext(&testdata.GroupInfo{})
+		require.NoError(t, err)
+		require.NoError(t, orm.Rewind(it))
+		var loaded testdata.GroupInfo
+		rowID, err := it.LoadNext(&loaded)
+		require.NoError(t, err)
+		assert.Equal(t, orm.RowID(orm.EncodeSequence(1)), rowID)
+		assert.Equal(t, testdata.GroupInfo{Description: "test 1"}, loaded)
+	})
+	t.Run("nil destination", func(t *testing.T) {
+		_, err := orm.NewSliceIterator([]orm.RowID{orm.EncodeSequence(1)}, [][]byte{bz1}).LoadNext(nil)
+		require.True(t, orm.ErrArgument.Is(err), err)
+	})
+	t.Run("invalid arguments", func(t *testing.T) {
+		for msg, it := range map[string]orm.Iterator{
+			"different lengths": orm.NewSliceIterator([]orm.RowID{orm.EncodeSequence(1)}, nil),
+			"nil value":         orm.NewSliceIterator([]orm.RowID{orm.EncodeSequence(1)}, [][]byte{nil}),
+		} {
+			_, err := it.LoadNext(&testdata.GroupInfo{})
+			assert.True(t, orm.ErrArgument.Is(err), "%s: %v", msg, err)
+			require.NoError(t, it.Close())
+			_, err = it.LoadNext(&testdata.GroupInfo{})
+			assert.True(t, orm.ErrIteratorClosed.Is(err), "%s: %v", msg, err)
+		}
+	})
+}
+
+func TestLimitedIterator(t *testing.T) {
+	specs := map[string]struct {
+		src orm.Iterator
+		exp []testdata.GroupInfo
+	}{
+		"all from range with max > length": {
+			src: orm.LimitIterator(mockIter(orm.EncodeSequence(1), &testdata.GroupInfo{Description: "test"}), 2),
+			exp: []testdata.GroupInfo{testdata.GroupInfo{Description: "test"}},
+		},
+		"up to max": {
+			src: orm.LimitIterator(mockIter(orm.EncodeSequence(1), &testdata.GroupInfo{Description: "test"}), 1),
+			exp: []testdata.GroupInfo{testdata.GroupInfo{Description: "test"}},
+		},
+		"none when max = 0": {
+			src: orm.LimitIterator(mockIter(orm.EncodeSequence(1), &testdata.GroupInfo{Description: "test"}), 0),
+			exp: []testdata.GroupInfo{},
+		},
+	}
+	for msg, spec := range specs {
+		t.Run(msg, func(t *testing.T) {
+			var loaded []testdata.GroupInfo
+			_, err := orm.ReadAll(spec.src, &loaded)
+			require.NoError(t, err)
+			assert.EqualValues(t, spec.exp, loaded)
+		})
+	}
+	t.Run("errors on invalid arguments", func(t *testing.T) {
+		_, err := orm.NewLimitIterator(noopIter(), -1)
+		require.True(t, orm.ErrArgument.Is(err), err)
+		_, err = orm.NewLimitIterator(nil, 1)
+		require.True(t, orm.ErrArgument.Is(err), err)
+
+		assert.Panics(t, func() { orm.LimitIterator(noopIter(), -1) })
+		assert.Panics(t, func() { orm.LimitIterator(nil, 1) })
+	})
+	t.Run("copies share the limit", func(t *testing.T) {
+		it := orm.LimitIterator(noopIter(), 3)
+		cp := *it
+		var loaded int
+		for _, i := range []orm.Iterator{it, &cp, it, &cp} {
+			if _, err := i.LoadNext(&testdata.GroupInfo{}); err == nil {
+				loaded++
+			}
+		}
+		assert.Equal(t, 3, loaded)
+	})
+	t.Run("close is idempotent", func(t *testing.T) {
+		var closed int
+		it := orm.LimitIterator(closingIter{Iterator: noopIter(), close: func() error {
+			closed++
+			return nil
+		}}, 2)
+		require.NoError(t, it.Close())
+		require.NoError(t, it.Close())
+		assert.Equal(t, 1, closed)
+
+		_, err := it.LoadNext(&testdata.GroupInfo{})
+		require.True(t, orm.ErrIteratorClosed.Is(err), err)
+	})
+	t.Run("zero value is done", func(t *testing.T) {
+		var it orm.LimitedIterator
+		_, err := it.LoadNext(&testdata.GroupInfo{})
+		require.True(t, orm.ErrIteratorDone.Is(err), err)
+		var loaded []testdata.GroupInfo
+		_, err = orm.Paginate(&it, &query.PageRequest{Limit: 1}, &loaded)
+		require.NoError(t, err)
+		assert.Empty(t, loaded)
+		require.NoError(t, it.Close())
+		assert.Equal(t, "Limit remaining=0\n  nil", orm.Explain(&it))
+	})
+}
+
+func TestPeekableIterator(t *testing.T) {
+	g1 := testdata.GroupInfo{Description: "my test 1"}
+	g2 := testdata.GroupInfo{Description: "my test 2"}
+	newIter := func(calls *int) orm.Iterator {
+		parent := orm.ChainIterator(mockIter(orm.EncodeSequence(1), &g1), mockIter(orm.EncodeSequence(2), &g2))
+		return orm.IteratorFunc(func(dest codec.ProtoMarshaler) (orm.RowID, error) {
+			*calls++
+			return parent.LoadNext(dest)
+		})
+	}
+
+	t.Run("peek does not consume", func(t *testing.T) {
+		var calls int
+		it := orm.PeekIterator(newIter(&calls))
+		for i := 0; i < 2; i++ {
+			var peeked testdata.GroupInfo
+			rowID, err := it.Peek(&peeked)
+			require.NoError(t, err)
+			assert.Equal(t, orm.RowID(orm.EncodeSequence(1)), rowID)
+			assert.Equal(t, g1, peeked)
+		}
+		var loaded testdata.GroupInfo
+		rowID, err := it.LoadNext(&loaded)
+		require.NoError(t, err)
+		assert.Equal(t, orm.RowID(orm.EncodeSequence(1)), rowID)
+		assert.Equal(t, g1, loaded)
+		// the peeked element was loaded from the parent only once
+		assert.Equal(t, 1, calls)
+
+		rowID, err = it.LoadNext(&loaded)
+		require.NoError(t, err)
+		assert.Equal(t, orm.RowID(orm.EncodeSequence(2)), rowID)
+		assert.Equal(t, g2, loaded)
+		assert.Equal(t, 2, calls)
+	})
+	t.Run("done is replayed", func(t *testing.T) {
+		var calls int
+		it := orm.PeekIterator(newIter(&calls))
+		for i := 0; i < 2; i++ {
+			_, err := it.LoadNext(&testdata.GroupInfo{})
+			require.NoError(t, err)
+		}
+		_, err := it.Peek(&testdata.GroupInfo{})
+		require.True(t, orm.ErrIteratorDone.Is(err), err)
+		_, err = it.Peek(&testdata.GroupInfo{})
+		require.True(t, orm.ErrIteratorDone.Is(err), err)
+		_, err = it.LoadNext(&testdata.GroupInfo{})
+		require.True(t, orm.ErrIteratorDone.Is(err), err)
+		assert.Equal(t, 3, calls)
+	})
+	t.Run("error of peek is returned by load next", func(t *testing.T) {
+		it := orm.PeekIterator(orm.NewInvalidIterator())
+		_, err := it.Peek(&testdata.GroupInfo{})
+		require.True(t, orm.ErrIteratorInvalid.Is(err), err)
+		_, err = it.Peek(&testdata.GroupInfo{})
+		require.True(t, orm.ErrIteratorInvalid.Is(err), err)
+		_, err = it.LoadNext(&testdata.GroupInfo{})
+		require.True(t, orm.ErrIteratorInvalid.Is(err), err)
+	})
+	t.Run("nil destination", func(t *testing.T) {
+		it := orm.PeekIterator(noopIter())
+		_, err := it.Peek(nil)
+		require.True(t, orm.ErrArgument.Is(err), err)
+		_, err = it.LoadNext(nil)
+		require.True(t, orm.ErrArgument.Is(err), err)
+	})
+	t.Run("nil parent", func(t *testing.T) {
+		it := orm.PeekIterator(nil)
+		_, err := it.Peek(&testdata.GroupInfo{})
+		assert.True(t, orm.ErrArgument.Is(err), err)
+		_, err = it.LoadNext(&testdata.GroupInfo{})
+		assert.True(t, orm.ErrArgument.Is(err), err)
+		require.NoError(t, it.Close())
+	})
+}
+
+func TestSkipIterator(t *testing.T) {
+	groups := testGroups(3, nil)
+	g1, g2, g3 := groups[0], groups[1], groups[2]
+	tb, _, ctx := newTestTableWithRows(t, groups...)
+
+	specs := map[string]struct {
+		skip      uint64
+		exp       []testdata.GroupInfo
+		expRowIDs []orm.RowID
+	}{
+		"none skipped with skip = 0": {
+			skip:      0,
+			exp:       []testdata.GroupInfo{g1, g2, g3},
+			expRowIDs: []orm.RowID{orm.EncodeSequence(1), orm.EncodeSequence(2), orm.EncodeSequence(3)},
+		},
+		"skip some": {
+			skip:      2,
+			exp:       []testdata.GroupInfo{g3},
+			expRowIDs: []orm.RowID{orm.EncodeSequence(3)},
+		},
+		"skip all": {
+			skip: 3,
+			exp:  []testdata.GroupInfo{},
+		},
+		"skip > length": {
+			skip: 4,
+			exp:  []testdata.GroupInfo{},
+		},
+	}
+	for msg, spec := range specs {
+		t.Run(msg, func(t *testing.T) {
+			it, err := tb.PrefixScan(ctx, 1, 100)
+			require.NoError(t, err)
+
+			var loaded []testdata.GroupInfo
+			rowIDs, err := orm.ReadAll(orm.SkipIterator(it, spec.skip), &loaded)
+			require.NoError(t, err)
+			assert.EqualValues(t, spec.exp, loaded)
+			assert.EqualValues(t, spec.expRowIDs, rowIDs)
+		})
+	}
+	t.Run("error on loadNext is returned", func(t *testing.T) {
+		var loaded testdata.GroupInfo
+		_, err := orm.SkipIterator(orm.NewInvalidIterator(), 1).LoadNext(&loaded)
+		require.True(t, orm.ErrIteratorInvalid.Is(err), err)
+	})
+}
+
+func TestFilterIterator(t *testing.T) {
+	admin := sdk.AccAddress([]byte("admin-address"))
+	g1 := testdata.GroupInfo{Description: "my test 1", Admin: admin}
+	g2 := testdata.GroupInfo{Description: "my test 2", Admin: sdk.AccAddress([]byte("other-admin-address"))}
+	g3 := testdata.GroupInfo{Description: "my test 3", Admin: admin}
+	g4 := testdata.GroupInfo{Description: "my test 4", Admin: admin}
+	tb, _, ctx := newTestTableWithRows(t, g1, g2, g3, g4)
+	byAdmin := func(m codec.ProtoMarshaler) bool {
+		return m.(*testdata.GroupInfo).Admin.Equals(admin)
+	}
+
+	t.Run("with read all", func(t *testing.T) {
+		it, err := tb.PrefixScan(ctx, 1, 100)
+		require.NoError(t, err)
+		var loaded []testdata.GroupInfo
+		rowIDs, err := orm.ReadAll(orm.FilterIterator(it, byAdmin), &loaded)
+		require.NoError(t, err)
+		assert.Equal(t, []testdata.GroupInfo{g1, g3, g4}, loaded)
+		assert.Equal(t, []orm.RowID{orm.EncodeSequence(1), orm.EncodeSequence(3), orm.EncodeSequence(4)}, rowIDs)
+	})
+	t.Run("limit applies to matching elements", func(t *testing.T) {
+		it, err := tb.PrefixScan(ctx, 1, 100)
+		require.NoError(t, err)
+		var loaded []testdata.GroupInfo
+		_, err = orm.ReadAll(orm.LimitIterator(orm.FilterIterator(it, byAdmin), 2), &loaded)
+		require.NoError(t, err)
+		assert.Equal(t, []testdata.GroupInfo{g1, g3}, loaded)
+	})
+	t.Run("with paginate", func(t *testing.T) {
+		it, err := tb.PrefixScan(ctx, 1, 100)
+		require.NoError(t, err)
+		var loaded []testdata.GroupInfo
+		res, err := orm.Paginate(orm.FilterIterator(it, byAdmin), &query.PageRequest{Limit: 2, CountTotal: true}, &loaded)
+		require.NoError(t, err)
+		assert.Equal(t, []testdata.GroupInfo{g1, g3}, loaded)
+		assert.EqualValues(t, orm.EncodeSequence(4), res.NextKey)
+		assert.EqualValues(t, 3, res.Total)
+	})
+	t.Run("no data of rejected elements is returned", func(t *testing.T) {
+		g5 := testdata.GroupInfo{Admin: admin}
+		_, err := tb.Create(ctx, &g5)
+		require.NoError(t, err)
+		it, err := tb.PrefixScan(ctx, 4, 100)
+		require.NoError(t, err)
+
+		var loaded []testdata.GroupInfo
+		_, err = orm.ReadAll(orm.FilterIterator(it, func(m codec.ProtoMarshaler) bool {
+			return m.(*testdata.GroupInfo).Description == ""
+		}), &loaded)
+		require.NoError(t, err)
+		assert.Equal(t, []testdata.GroupInfo{g5}, loaded)
+	})
+	t.Run("error on loadNext is returned", func(t *testing.T) {
+		var loaded testdata.GroupInfo
+		_, err := orm.FilterIterator(orm.NewInvalidIterator(), byAdmin).LoadNext(&loaded)
+		require.True(t, orm.ErrIteratorInvalid.Is(err), err)
+	})
+}
+
+func TestTakeWhileIterator(t *testing.T) {
+	admin := sdk.AccAddress([]byte("admin-address"))
+	g1 := testdata.GroupInfo{Description: "my test 1", Admin: admin}
+	g2 := testdata.GroupInfo{Description: "my test 2", Admin: admin}
+	g3 := testdata.GroupInfo{Description: "my test 3", Admin: sdk.AccAddress([]byte("other-admin-address"))}
+	g4 := testdata.GroupInfo{Description: "my test 4", Admin: admin}
+	tb, _, ctx := newTestTableWithRows(t, g1, g2, g3, g4)
+	byAdmin := func(m codec.ProtoMarshaler, _ orm.RowID) bool {
+		return m.(*testdata.GroupInfo).Admin.Equals(admin)
+	}
+
+	t.Run("with read all", func(t *testing.T) {
+		it, err := tb.PrefixScan(ctx, 1, 100)
+		require.NoError(t, err)
+		var loaded []testdata.GroupInfo
+		rowIDs, err := orm.ReadAll(orm.TakeWhileIterator(it, byAdmin), &loaded)
+		require.NoError(t, err)
+		assert.Equal(t, []testdata.GroupInfo{g1, g2}, loaded)
+		assert.Equal(t, []orm.RowID{orm.EncodeSequence(1), orm.EncodeSequence(2)}, rowIDs)
+	})
+	t.Run("parent is closed at the end", func(t *testing.T) {
+		parent, err := tb.PrefixScan(ctx, 2, 100)
+		require.NoError(t, err)
+		it := orm.TakeWhileIterator(parent, byAdmin)
+		var loaded testdata.GroupInfo
+		_, err = it.LoadNext(&loaded)
+		require.NoError(t, err)
+		_, err = it.LoadNext(&loaded)
+		assert.True(t, orm.ErrIteratorDone.Is(err), err)
+		assert.Equal(t, testdata.GroupInfo{}, loaded)
+		_, err = parent.LoadNext(&loaded)
+		assert.True(t, orm.ErrIteratorClosed.Is(err), err)
+
+		_, err = it.LoadNext(&loaded)
+		assert.True(t, orm.ErrIteratorDone.Is(err), err)
+		require.NoError(t, it.Close())
+	})
+	t.Run("row id is passed to the predicate", func(t *testing.T) {
+		it, err := tb.PrefixScan(ctx, 1, 100)
+		require.NoError(t, err)
+		var loaded []testdata.GroupInfo
+		_, err = orm.ReadAll(orm.TakeWhileIterator(it, func(_ codec.ProtoMarshaler, rowID orm.RowID) bool {
+			return orm.DecodeSequence(rowID) < 2
+		}), &loaded)
+		require.NoError(t, err)
+		assert.Equal(t, []testdata.GroupInfo{g1}, loaded)
+	})
+	t.Run("with paginate", func(t *testing.T) {
+		it, err := tb.PrefixScan(ctx, 1, 100)
+		require.NoError(t, err)
+		var loaded []testdata.GroupInfo
+		res, err := orm.Paginate(orm.TakeWhileIterator(it, byAdmin), &query.PageRequest{Limit: 1, CountTotal: true}, &loaded)
+		require.NoError(t, err)
+		assert.Equal(t, []testdata.GroupInfo{g1}, loaded)
+		assert.EqualValues(t, orm.EncodeSequence(2), res.NextKey)
+		assert.EqualValues(t, 2, res.Total)
+
+		it, err = tb.PrefixScan(ctx, orm.DecodeSequence(res.NextKey), 100)
+		require.NoError(t, err)
+		loaded = nil
+		res, err = orm.Paginate(orm.TakeWhileIterator(it, byAdmin), &query.PageRequest{Limit: 1}, &loaded)
+		require.NoError(t, err)
+		assert.Equal(t, []testdata.GroupInfo{g2}, loaded)
+		assert.Nil(t, res.NextKey)
+	})
+	t.Run("decode error is returned without the predicate", func(t *testing.T) {
+		it, err := tb.PrefixScan(ctx, 1, 100)
+		require.NoError(t, err)
+		var loaded testdata.GroupMember
+		_, err = orm.TakeWhileIterator(it, func(codec.ProtoMarshaler, orm.RowID) bool {
+			t.Fatal("predicate must not be called")
+			return false
+		}).LoadNext(&loaded)
+		require.True(t, orm.ErrType.Is(err), err)
+	})
+	t.Run("error on loadNext is returned", func(t *testing.T) {
+		var loaded testdata.GroupInfo
+		_, err := orm.TakeWhileIterator(orm.NewInvalidIterator(), byAdmin).LoadNext(&loaded)
+		require.True(t, orm.ErrIteratorInvalid.Is(err), err)
+	})
+}
+
+func TestDropWhileIterator(t *testing.T) {
+	admin := sdk.AccAddress([]byte("admin-address"))
+	g1 := testdata.GroupInfo{Description: "my test 1", Admin: admin}
+	g2 := testdata.GroupInfo{Description: "my test 2", Admin: admin}
+	g3 := testdata.GroupInfo{Admin: sdk.AccAddress([]byte("other-admin-address"))}
+	g4 := testdata.GroupInfo{Description: "my test 4", Admin: admin}
+	tb, _, ctx := newTestTableWithRows(t, g1, g2, g3, g4)
+	byAdmin := func(m codec.ProtoMarshaler, _ orm.RowID) bool {
+		return m.(*testdata.GroupInfo).Admin.Equals(admin)
+	}
+
+	t.Run("with read all", func(t *testing.T) {
+		it, err := tb.PrefixScan(ctx, 1, 100)
+		require.NoError(t, err)
+		var loaded []testdata.GroupInfo
+		rowIDs, err := orm.ReadAll(orm.DropWhileIterator(it, byAdmin), &loaded)
+		require.NoError(t, err)
+		// no data of the dropped elements is merged into g3
+		assert.Equal(t, []testdata.GroupInfo{g3, g4}, loaded)
+		assert.Equal(t, []orm.RowID{orm.EncodeSequence(3), orm.EncodeSequence(4)}, rowIDs)
+	})
+	t.Run("all dropped", func(t *testing.T) {
+		it, err := tb.PrefixScan(ctx, 1, 3)
+		require.NoError(t, err)
+		var loaded []testdata.GroupInfo
+		_, err = orm.ReadAll(orm.DropWhileIterator(it, byAdmin), &loaded)
+		require.NoError(t, err)
+		assert.Empty(t, loaded)
+	})
+	t.Run("with paginate", func(t *testing.T) {
+		it, err := tb.PrefixScan(ctx, 1, 100)
+		require.NoError(t, err)
+		var loaded []testdata.GroupInfo
+		res, err := orm.Paginate(orm.DropWhileIterator(it, byAdmin), &query.PageRequest{Limit: 1, CountTotal: true}, &loaded)
+		require.NoError(t, err)
+		assert.Equal(t, []testdata.GroupInfo{g3}, loaded)
+		assert.EqualValues(t, orm.EncodeSequence(4), res.NextKey)
+		assert.EqualValues(t, 2, res.Total)
+	})
+	t.Run("decode error is returned without the predicate", func(t *testing.T) {
+		it, err := tb.PrefixScan(ctx, 1, 100)
+		require.NoError(t, err)
+		var loaded testdata.GroupMember
+		_, err = orm.DropWhileIterator(it, func(codec.ProtoMarshaler, orm.RowID) bool {
+			t.Fatal("predicate must not be called")
+			return false
+		}).LoadNext(&loaded)
+		require.True(t, orm.ErrType.Is(err), err)
+	})
+	t.Run("error on loadNext is returned", func(t *testing.T) {
+		var loaded testdata.GroupInfo
+		_, err := orm.DropWhileIterator(orm.NewInvalidIterator(), byAdmin).LoadNext(&loaded)
+		require.True(t, orm.ErrIteratorInvalid.Is(err), err)
+	})
+}
+
+func TestMapIterator(t *testing.T) {
+	adminA := sdk.AccAddress([]byte("admin-address-a"))
+	adminB := sdk.AccAddress([]byte("admin-address-b"))
+	tb, _, ctx := newTestTableWithRows(t, []testdata.GroupInfo{
+		{GroupId: 1, Description: "my test 1", Admin: adminA},
+		{GroupId: 2, Description: "my test 2", Admin: adminB},
+		{GroupId: 3, Description: "my test 3", Admin: adminA},
+	}...)
+	newSrc := func() codec.ProtoMarshaler { return &testdata.GroupInfo{} }
+	toMember := func(src codec.ProtoMarshaler, _ orm.RowID) (codec.ProtoMarshaler, error) {
+		g := src.(*testdata.GroupInfo)
+		return &testdata.GroupMember{Member: g.Admin, Weight: g.GroupId}, nil
+	}
+
+	t.Run("with read all", func(t *testing.T) {
+		it, err := tb.PrefixScan(ctx, 1, 100)
+		require.NoError(t, err)
+		var loaded []testdata.GroupMember
+		rowIDs, err := orm.ReadAll(orm.MapIterator(it, toMember, newSrc), &loaded)
+		require.NoError(t, err)
+		assert.Equal(t, []testdata.GroupMember{
+			{Member: adminA, Weight: 1},
+			{Member: adminB, Weight: 2},
+			{Member: adminA, Weight: 3},
+		}, loaded)
+		assert.Equal(t, []orm.RowID{orm.EncodeSequence(1), orm.EncodeSequence(2), orm.EncodeSequence(3)}, rowIDs)
+	})
+	t.Run("with paginate", func(t *testing.T) {
+		it, err := tb.PrefixScan(ctx, 1, 100)
+		require.NoError(t, err)
+		var loaded []*testdata.GroupMember
+		res, err := orm.Paginate(orm.MapIterator(it, toMember, newSrc), &query.PageRequest{Offset: 1, Limit: 1}, &loaded)
+		require.NoError(t, err)
+		assert.Equal(t, []*testdata.GroupMember{{Member: adminB, Weight: 2}}, loaded)
+		assert.EqualValues(t, orm.EncodeSequence(3), res.NextKey)
+	})
+	t.Run("with other destination type", func(t *testing.T) {
+		it, err := tb.PrefixScan(ctx, 1, 100)
+		require.NoError(t, err)
+		var loaded []testdata.GroupInfo
+		// field 2 is a bytes field in GroupMember and a string field in GroupInfo
+		_, err = orm.ReadAll(orm.MapIterator(it, func(src codec.ProtoMarshaler, _ orm.RowID) (codec.ProtoMarshaler, error) {
+			return &testdata.GroupMember{Member: []byte(src.(*testdata.GroupInfo).Description)}, nil
+		}, newSrc), &loaded)
+		require.NoError(t, err)
+		assert.Equal(t, []testdata.GroupInfo{{Description: "my test 1"}, {Description: "my test 2"}, {Description: "my test 3"}}, loaded)
+	})
+	t.Run("transform error is returned", func(t *testing.T) {
+		it, err := tb.PrefixScan(ctx, 1, 100)
+		require.NoError(t, err)
+		var loaded []testdata.GroupMember
+		_, err = orm.ReadAll(orm.MapIterator(it, func(codec.ProtoMarshaler, orm.RowID) (codec.ProtoMarshaler, error) {
+			return nil, orm.ErrArgument
+		}, newSrc), &loaded)
+		require.True(t, orm.ErrArgument.Is(err), err)
+		assert.Contains(t, err.Error(), fmt.Sprintf("%X", orm.EncodeSequence(1)))
+	})
+	t.Run("nil result is rejected", func(t *testing.T) {
+		for msg, result := range map[string]codec.ProtoMarshaler{
+			"nil":       nil,
+			"typed nil": (*testdata.GroupMember)(nil),
+		} {
+			it, err := tb.PrefixScan(ctx, 1, 100)
+			require.NoError(t, err)
+			var loaded testdata.GroupMember
+			_, err = orm.MapIterator(it, func(codec.ProtoMarshaler, orm.RowID) (codec.ProtoMarshaler, error) {
+				return result, nil
+			}, newSrc).LoadNext(&loaded)
+			require.True(t, orm.ErrArgument.Is(err), "%s: %v", msg, err)
+			assert.Contains(t, err.Error(), fmt.Sprintf("%X", orm.EncodeSequence(1)), msg)
+		}
+	})
+	t.Run("error on loadNext is returned", func(t *testing.T) {
+		var loaded testdata.GroupMember
+		_, err := orm.MapIterator(orm.NewInvalidIterator(), toMember, newSrc).LoadNext(&loaded)
+		require.True(t, orm.ErrIteratorInvalid.Is(err), err)
+	})
+}
+
+func TestChainIterator(t *testing.T) {
+	adminA := sdk.AccAddress([]byte("admin-address-a"))
+	adminB := sdk.AccAddress([]byte("admin-address-b"))
+	g1 := testdata.GroupInfo{Description: "my test 1", Admin: adminA}
+	g2 := testdata.GroupInfo{Description: "my test 2", Admin: adminB}
+	g3 := testdata.GroupInfo{Description: "my test 3", Admin: adminA}
+	_, idx, ctx := newTestTableWithRows(t, g1, g2, g3)
+	chained := func(t *testing.T) orm.Iterator {
+		itA, err := idx.Get(ctx, adminA)
+		require.NoError(t, err)
+		itB, err := idx.Get(ctx, adminB)
+		require.NoError(t, err)
+		return orm.ChainIterator(itA, itB)
+	}
+
+	t.Run("all elements in order", func(t *testing.T) {
+		var loaded []testdata.GroupInfo
+		rowIDs, err := orm.ReadAll(chained(t), &loaded)
+		require.NoError(t, err)
+		assert.Equal(t, []testdata.GroupInfo{g1, g3, g2}, loaded)
+		assert.Equal(t, []orm.RowID{orm.EncodeSequence(1), orm.EncodeSequence(3), orm.EncodeSequence(2)}, rowIDs)
+	})
+	t.Run("paginate with limit straddling iterators", func(t *testing.T) {
+		var loaded []testdata.GroupInfo
+		res, err := orm.Paginate(chained(t), &query.PageRequest{Offset: 1, Limit: 2, CountTotal: true}, &loaded)
+		require.NoError(t, err)
+		assert.Equal(t, []testdata.GroupInfo{g3, g2}, loaded)
+		assert.Nil(t, res.NextKey)
+		assert.EqualValues(t, 3, res.Total)
+	})
+	t.Run("paginate with next key in second iterator", func(t *testing.T) {
+		var loaded []testdata.GroupInfo
+		res, err := orm.Paginate(chained(t), &query.PageRequest{Limit: 2}, &loaded)
+		require.NoError(t, err)
+		assert.Equal(t, []testdata.GroupInfo{g1, g3}, loaded)
+		assert.EqualValues(t, orm.EncodeSequence(2), res.NextKey)
+	})
+	t.Run("no iterators", func(t *testing.T) {
+		var loaded testdata.GroupInfo
+		_, err := orm.ChainIterator().LoadNext(&loaded)
+		assert.True(t, orm.ErrIteratorDone.Is(err), err)
+	})
+	t.Run("error on loadNext is returned", func(t *testing.T) {
+		var loaded []testdata.GroupInfo
+		_, err := orm.ReadAll(orm.ChainIterator(mockIter(orm.EncodeSequence(1), &g1), orm.NewInvalidIterator()), &loaded)
+		require.True(t, orm.ErrIteratorInvalid.Is(err), err)
+	})
+	t.Run("close closes all and aggregates errors", func(t *testing.T) {
+		var closed []int
+		closer := func(n int, err error) orm.Iterator {
+			return closingIter{Iterator: orm.NewInvalidIterator(), close: func() error {
+				closed = append(closed, n)
+				return err
+			}}
+		}
+		err := orm.ChainIterator(closer(0, nil), closer(1, orm.ErrIteratorInvalid), closer(2, orm.ErrArgument)).Close()
+		require.True(t, orm.ErrIteratorInvalid.Is(err), err)
+		assert.Contains(t, err.Error(), orm.ErrArgument.Error())
+		assert.Equal(t, []int{0, 1, 2}, closed)
+	})
+}
+
+func TestMergeIterator(t *testing.T) {
+	adminA := sdk.AccAddress([]byte("admin-address-a"))
+	adminB := sdk.AccAddress([]byte("admin-address-b"))
+	g1 := testdata.GroupInfo{Description: "foo", Admin: adminA}
+	g2 := testdata.GroupInfo{Description: "bar", Admin: adminB}
+	g3 := testdata.GroupInfo{Description: "bar", Admin: adminA}
+	g4 := testdata.GroupInfo{Description: "foo", Admin: adminB}
+	g5 := testdata.GroupInfo{Description: "bar", Admin: adminB}
+	_, byAdmin, byDescription, ctx := newTestTableWithIndexes(t, g1, g2, g3, g4, g5)
+	ascending := func(a, b orm.RowID) bool { return bytes.Compare(a, b) < 0 }
+	descending := func(a, b orm.RowID) bool { return bytes.Compare(a, b) > 0 }
+
+	t.Run("admin a or description foo", func(t *testing.T) {
+		itA, err := byAdmin.Get(ctx, adminA)
+		require.NoError(t, err)
+		itB, err := byDescription.Get(ctx, []byte("foo"))
+		require.NoError(t, err)
+
+		var loaded []testdata.GroupInfo
+		rowIDs, err := orm.ReadAll(orm.MergeIterator(ascending, itA, itB), &loaded)
+		require.NoError(t, err)
+		assert.Equal(t, []testdata.GroupInfo{g1, g3, g4}, loaded)
+		assert.Equal(t, []orm.RowID{orm.EncodeSequence(1), orm.EncodeSequence(3), orm.EncodeSequence(4)}, rowIDs)
+	})
+	t.Run("descending", func(t *testing.T) {
+		itA, err := byAdmin.ReverseGet(ctx, adminB)
+		require.NoError(t, err)
+		itB, err := byDescription.ReverseGet(ctx, []byte("foo"))
+		require.NoError(t, err)
+
+		var loaded []testdata.GroupInfo
+		rowIDs, err := orm.ReadAll(orm.MergeIterator(descending, itA, itB), &loaded)
+		require.NoError(t, err)
+		assert.Equal(t, []testdata.GroupInfo{g5, g4, g2, g1}, loaded)
+		assert.Equal(t, []orm.RowID{orm.EncodeSequence(5), orm.EncodeSequence(4), orm.EncodeSequence(2), orm.EncodeSequence(1)}, rowIDs)
+	})
+	t.Run("paginate", func(t *testing.T) {
+		itA, err := byAdmin.Get(ctx, adminB)
+		require.NoError(t, err)
+		itB, err := byDescription.Get(ctx, []byte("bar"))
+		require.NoError(t, err)
+
+		var loaded []testdata.GroupInfo
+		res, err := orm.Paginate(orm.MergeIterator(ascending, itA, itB), &query.PageRequest{Limit: 2, CountTotal: true}, &loaded)
+		require.NoError(t, err)
+		assert.Equal(t, []testdata.GroupInfo{g2, g3}, loaded)
+		assert.EqualValues(t, orm.EncodeSequence(4), res.NextKey)
+		assert.EqualValues(t, 4, res.Total)
+	})
+	t.Run("one empty", func(t *testing.T) {
+		itA, err := byAdmin.Get(ctx, []byte("nobody"))
+		require.NoError(t, err)
+		var loaded []testdata.GroupInfo
+		_, err = orm.ReadAll(orm.MergeIterator(ascending, itA, mockIter(orm.EncodeSequence(1), &g1)), &loaded)
+		require.NoError(t, err)
+		assert.Equal(t, []testdata.GroupInfo{g1}, loaded)
+	})
+	t.Run("k-way", func(t *testing.T) {
+		itA, err := byAdmin.Get(ctx, adminA)
+		require.NoError(t, err)
+		itB, err := byDescription.Get(ctx, []byte("foo"))
+		require.NoError(t, err)
+		itC, err := byDescription.Get(ctx, []byte("bar"))
+		require.NoError(t, err)
+
+		var loaded []testdata.GroupInfo
+		rowIDs, err := orm.ReadAll(orm.MergeIterator(ascending, itA, itB, itC), &loaded)
+		require.NoError(t, err)
+		assert.Equal(t, []testdata.GroupInfo{g1, g2, g3, g4, g5}, loaded)
+		assert.Equal(t, []orm.RowID{orm.EncodeSequence(1), orm.EncodeSequence(2), orm.EncodeSequence(3), orm.EncodeSequence(4), orm.EncodeSequence(5)}, rowIDs)
+	})
+	t.Run("no iterators", func(t *testing.T) {
+		var loaded []testdata.GroupInfo
+		rowIDs, err := orm.ReadAll(orm.MergeIterator(ascending), &loaded)
+		require.NoError(t, err)
+		assert.Empty(t, loaded)
+		assert.Empty(t, rowIDs)
+	})
+	t.Run("close all on failure", func(t *testing.T) {
+		var closed []int
+		iters := make([]orm.Iterator, 3)
+		for i := range iters {
+			i := i
+			iters[i] = closingIter{Iterator: mockIter(orm.EncodeSequence(uint64(i+1)), &g1), close: func() error {
+				closed = append(closed, i)
+				if i == 0 {
+					return orm.ErrArgument
+				}
+				return nil
+			}}
+		}
+		err := orm.MergeIterator(ascending, iters...).Close()
+		require.True(t, orm.ErrArgument.Is(err), err)
+		assert.Equal(t, []int{0, 1, 2}, closed)
+	})
+	t.Run("error on loadNext is returned", func(t *testing.T) {
+		var loaded []testdata.GroupInfo
+		_, err := orm.ReadAll(orm.MergeIterator(ascending, mockIter(orm.EncodeSequence(1), &g1), orm.NewInvalidIterator()), &loaded)
+		require.True(t, orm.ErrIteratorInvalid.Is(err), err)
+	})
+}
+
+func TestUnionIterator(t *testing.T) {
+	adminA := sdk.AccAddress([]byte("admin-address-a"))
+	adminB := sdk.AccAddress([]byte("admin-address-b"))
+	g1 := testdata.GroupInfo{Description: "foo", Admin: adminA}
+	g2 := testdata.GroupInfo{Description: "bar", Admin: adminB}
+	g3 := testdata.GroupInfo{Description: "foo", Admin: adminB}
+	_, byAdmin, byDescription, ctx := newTestTableWithIndexes(t, g1, g2, g3)
+
+	t.Run("stable pages", func(t *testing.T) {
+		var allLoaded []testdata.GroupInfo
+		pageReq := &query.PageRequest{Limit: 2}
+		for {
+			itA, err := byAdmin.GetPaginated(ctx, adminB, pageReq)
+			require.NoError(t, err)
+			itB, err := byDescription.GetPaginated(ctx, []byte("foo"), pageReq)
+			require.NoError(t, err)
+
+			var loaded []testdata.GroupInfo
+			res, err := orm.Paginate(orm.UnionIterator(itA, itB), pageReq, &loaded)
+			require.NoError(t, err)
+			allLoaded = append(allLoaded, loaded...)
+			if res.NextKey == nil {
+				break
+			}
+			pageReq = &query.PageRequest{Key: res.NextKey, Limit: 2}
+		}
+		assert.Equal(t, []testdata.GroupInfo{g1, g2, g3}, allLoaded)
+	})
+	t.Run("error on loadNext is returned", func(t *testing.T) {
+		itA, err := byAdmin.Get(ctx, adminB)
+		require.NoError(t, err)
+		var loaded []testdata.GroupInfo
+		_, err = orm.ReadAll(orm.UnionIterator(itA, orm.NewInvalidIterator()), &loaded)
+		require.True(t, orm.ErrIteratorInvalid.Is(err), err)
+		assert.Empty(t, loaded)
+	})
+}
+
+func TestIntersectIterator(t *testing.T) {
+	adminA := sdk.AccAddress([]byte("admin-address-a"))
+	adminB := sdk.AccAddress([]byte("admin-address-b"))
+	g1 := testdata.GroupInfo{Description: "foo", Admin: adminA}
+	g2 := testdata.GroupInfo{Description: "bar", Admin: adminB}
+	g3 := testdata.GroupInfo{Description: "bar", Admin: adminA}
+	g4 := testdata.GroupInfo{Description: "foo", Admin: adminB}
+	g5 := testdata.GroupInfo{Description: "bar", Admin: adminA}
+	_, byAdmin, byDescription, ctx := newTestTableWithIndexes(t, g1, g2, g3, g4, g5)
+
+	specs := map[string]struct {
+		admin       sdk.AccAddress
+		description string
+		expResult   []testdata.GroupInfo
+		expRowIDs   []orm.RowID
+	}{
+		"admin a and description bar": {
+			admin:       adminA,
+			description: "bar",
+			expResult:   []testdata.GroupInfo{g3, g5},
+			expRowIDs:   []orm.RowID{orm.EncodeSequence(3), orm.EncodeSequence(5)},
+		},
+		"admin b and description foo": {
+			admin:       adminB,
+			description: "foo",
+			expResult:   []testdata.GroupInfo{g4},
+			expRowIDs:   []orm.RowID{orm.EncodeSequence(4)},
+		},
+		"one empty": {
+			admin:       adminA,
+			description: "baz",
+			expResult:   []testdata.GroupInfo{},
+		},
+	}
+	for msg, spec := range specs {
+		t.Run(msg, func(t *testing.T) {
+			for _, valuesFromA := range []bool{true, false} {
+				itA, err := byAdmin.Get(ctx, spec.admin)
+				require.NoError(t, err)
+				itB, err := byDescription.Get(ctx, []byte(spec.description))
+				require.NoError(t, err)
+				if !valuesFromA {
+					itA, itB = itB, itA
+				}
+
+				var loaded []testdata.GroupInfo
+				rowIDs, err := orm.ReadAll(orm.IntersectIterator(itA, itB), &loaded)
+				require.NoError(t, err)
+				assert.Equal(t, spec.expResult, loaded)
+				assert.Equal(t, spec.expRowIDs, rowIDs)
+			}
+		})
+	}
+	t.Run("paginate", func(t *testing.T) {
+		itA, err := byDescription.Get(ctx, []byte("bar"))
+		require.NoError(t, err)
+		itB, err := byAdmin.Get(ctx, adminA)
+		require.NoError(t, err)
+
+		var loaded []testdata.GroupInfo
+		res, err := orm.Paginate(orm.IntersectIterator(itA, itB), &query.PageRequest{Limit: 1, CountTotal: true}, &loaded)
+		require.NoError(t, err)
+		assert.Equal(t, []testdata.GroupInfo{g3}, loaded)
+		assert.EqualValues(t, orm.EncodeSequence(5), res.NextKey)
+		assert.EqualValues(t, 2, res.Total)
+	})
+	t.Run("values from non index iterator", func(t *testing.T) {
+		itB, err := byAdmin.Get(ctx, adminB)
+		require.NoError(t, err)
+		var loaded []testdata.GroupInfo
+		_, err = orm.ReadAll(orm.IntersectIterator(mockIter(orm.EncodeSequence(2), &g2), itB), &loaded)
+		require.NoError(t, err)
+		assert.Equal(t, []testdata.GroupInfo{g2}, loaded)
+	})
+	t.Run("error on loadNext is returned", func(t *testing.T) {
+		var loaded []testdata.GroupInfo
+		_, err := orm.ReadAll(orm.IntersectIterator(mockIter(orm.EncodeSequence(1), &g1), orm.NewInvalidIterator()), &loaded)
+		require.True(t, orm.ErrIteratorInvalid.Is(err), err)
+	})
+}
+
+func TestTransactionalIterator(t *testing.T) {
+	groups := testGroups(3, nil)
+	g1, g2, g3 := groups[0], groups[1], groups[2]
+	tb, _, idx, ctx := newTestTableWithIndexes(t, groups...)
+
+	// the snapshot scan allows writes while iterating
+	parent, err := tb.PrefixScanWithOpts(ctx, 1, 100, orm.ScanOpts{Snapshot: true})
+	require.NoError(t, err)
+	it, rollback := orm.TransactionalIterator(ctx, tb.Table(), parent)
+
+	process := func(rowID orm.RowID, g *testdata.GroupInfo) error {
+		switch orm.DecodeSequence(rowID) {
+		case 1:
+			g.Description = "updated"
+			return tb.Save(ctx, 1, g)
+		case 2:
+			if _, err := tb.Create(ctx, &testdata.GroupInfo{Description: "my test 4"}); err != nil {
+				return err
+			}
+			return tb.Delete(ctx, 2)
+		default:
+			return testdata.ErrTest
+		}
+	}
+	for {
+		var g testdata.GroupInfo
+		rowID, err := it.LoadNext(&g)
+		require.NoError(t, err)
+		if err := process(rowID, &g); err != nil {
+			assert.True(t, testdata.ErrTest.Is(err), err)
+			break
+		}
+	}
+	require.NoError(t, rollback())
+
+	// the returned rows and their index keys are restored
+	_, err = it.LoadNext(&testdata.GroupInfo{})
+	assert.True(t, orm.ErrIteratorClosed.Is(err), err)
+	scan, err := tb.PrefixScan(ctx, 1, 100)
+	require.NoError(t, err)
+	var loaded []testdata.GroupInfo
+	_, err = orm.ReadAll(scan, &loaded)
+	require.NoError(t, err)
+	// the row created while processing is kept
+	assert.Equal(t, []testdata.GroupInfo{g1, g2, g3, {Description: "my test 4"}}, loaded)
+	assert.True(t, idx.Has(ctx, []byte("my test 2")))
+	assert.False(t, idx.Has(ctx, []byte("updated")))
+	n, err := tb.Count(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(4), n)
+
+	// the snapshots are released
+	require.NoError(t, tb.Delete(ctx, 1))
+	require.NoError(t, rollback())
+	assert.False(t, tb.Has(ctx, 1))
+
+	t.Run("create hooks do not run for restored rows", func(t *testing.T) {
+		cdc := codec.NewProtoCodec(types.NewInterfaceRegistry())
+		tBuilder := orm.NewAutoUInt64TableBuilder(GroupTablePrefix, GroupTableSeqPrefix, testTableStoreKey, &testdata.GroupInfo{}, cdc)
+		tBuilder.WithRowCounter(testTableCounterPrefix)
+		var created int
+		tBuilder.OnCreate(func(sdk.Context, codec.ProtoMarshaler, orm.RowID) error {
+			created++
+			return nil
+		})
+		idx := orm.NewIndex(tBuilder, GroupByAdminIndexPrefix, func(val interface{}) ([]orm.RowID, error) {
+			return []orm.RowID{[]byte(val.(*testdata.GroupInfo).Description)}, nil
+		})
+		tb := tBuilder.Build()
+		ctx := orm.NewMockContext()
+		_, err := tb.Create(ctx, &g1)
+		require.NoError(t, err)
+
+		parent, err := tb.PrefixScanWithOpts(ctx, 1, 100, orm.ScanOpts{Snapshot: true})
+		require.NoError(t, err)
+		it, rollback := orm.TransactionalIterator(ctx, tb.Table(), parent)
+		rowID, err := it.LoadNext(&testdata.GroupInfo{})
+		require.NoError(t, err)
+		require.NoError(t, tb.Delete(ctx, orm.DecodeSequence(rowID)))
+		require.NoError(t, rollback())
+
+		var loaded testdata.GroupInfo
+		_, err = tb.GetOne(ctx, 1, &loaded)
+		require.NoError(t, err)
+		assert.Equal(t, g1, loaded)
+		assert.True(t, idx.Has(ctx, []byte(g1.Description)))
+		n, err := tb.Count(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(1), n)
+		assert.Equal(t, 1, created)
+	})
+}
+
+func TestJoinIterator(t *testing.T) {
+	interfaceRegistry := types.NewInterfaceRegistry()
+	cdc := codec.NewProtoCodec(interfaceRegistry)
+
+	storeKey := sdk.NewKVStoreKey("test")
+	const (
+		groupTablePrefix = iota
+		groupTableSeqPrefix
+		memberTablePrefix
+	)
+	groupTable := orm.NewAutoUInt64TableBuilder(groupTablePrefix, groupTableSeqPrefix, storeKey, &testdata.GroupInfo{}, cdc).Build()
+	memberTable := orm.NewPrimaryKeyTableBuilder(memberTablePrefix, storeKey, &testdata.GroupMember{}, orm.Max255DynamicLengthIndexKeyCodec{}, cdc).Build()
+	ctx := orm.NewMockContext()
+
+	g1 := testdata.GroupInfo{GroupId: 1, Description: "my group 1"}
+	g2 := testdata.GroupInfo{GroupId: 2, Description: "my group 2"}
+	for _, g := range []testdata.GroupInfo{g1, g2} {
+		_, err := groupTable.Create(ctx, &g)
+		require.NoError(t, err)
+	}
+	// the members reference the groups by RowID
+	group := func(id uint64) sdk.AccAddress { return sdk.AccAddress(orm.EncodeSequence(id)) }
+	m1 := testdata.GroupMember{Group: group(1), Member: []byte("member-1")}
+	m2 := testdata.GroupMember{Group: group(1), Member: []byte("member-2")}
+	m3 := testdata.GroupMember{Group: group(2), Member: []byte("member-3")}
+	dangling := testdata.GroupMember{Group: group(3), Member: []byte("member-4")}
+	for _, m := range []testdata.GroupMember{m1, m2, m3, dangling} {
+		require.NoError(t, memberTable.Create(ctx, &m))
+	}
+	foreignKey := func(child codec.ProtoMarshaler) orm.RowID {
+		return orm.RowID(child.(*testdata.GroupMember).Group)
+	}
+	join := func(opts orm.JoinOpts) orm.Iterator {
+		it, err := memberTable.PrefixScan(ctx, nil, nil)
+		require.NoError(t, err)
+		return orm.JoinIterator(ctx, it, groupTable.Table(), foreignKey, opts)
+	}
+
+	t.Run("skip dangling references", func(t *testing.T) {
+		var loaded []testdata.GroupMemberWithGroup
+		rowIDs, err := orm.ReadAll(join(orm.JoinOpts{SkipDangling: true}), &loaded)
+		require.NoError(t, err)
+		assert.Equal(t, []testdata.GroupMemberWithGroup{
+			{Member: &m1, Group: &g1},
+			{Member: &m2, Group: &g1},
+			{Member: &m3, Group: &g2},
+		}, loaded)
+		assert.Equal(t, []orm.RowID{m1.PrimaryKey(), m2.PrimaryKey(), m3.PrimaryKey()}, rowIDs)
+	})
+	t.Run("error on dangling references", func(t *testing.T) {
+		var loaded []testdata.GroupMemberWithGroup
+		_, err := orm.ReadAll(join(orm.JoinOpts{}), &loaded)
+		require.Error(t, err)
+		assert.True(t, orm.ErrReferenceNotFound.Is(err), err)
+		assert.Contains(t, err.Error(), fmt.Sprintf("child %x references parent %x", dangling.PrimaryKey(), orm.EncodeSequence(3)))
+	})
+	t.Run("paginate", func(t *testing.T) {
+		var page []testdata.GroupMemberWithGroup
+		res, err := orm.Paginate(join(orm.JoinOpts{SkipDangling: true}), &query.PageRequest{Limit: 2}, &page)
+		require.NoError(t, err)
+		assert.Equal(t, []testdata.GroupMemberWithGroup{{Member: &m1, Group: &g1}, {Member: &m2, Group: &g1}}, page)
+		assert.Equal(t, m3.PrimaryKey(), res.NextKey)
+
+		it, err := memberTable.PrefixScan(ctx, res.NextKey, nil)
+		require.NoError(t, err)
+		page = nil
+		res, err = orm.Paginate(orm.JoinIterator(ctx, it, groupTable.Table(), foreignKey, orm.JoinOpts{SkipDangling: true}), &query.PageRequest{Limit: 2}, &page)
+		require.NoError(t, err)
+		assert.Equal(t, []testdata.GroupMemberWithGroup{{Member: &m3, Group: &g2}}, page)
+		assert.Nil(t, res.NextKey)
+	})
+	t.Run("wrong destination type", func(t *testing.T) {
+		it := join(orm.JoinOpts{})
+		defer it.Close()
+		_, err := it.LoadNext(&testdata.GroupMember{})
+		assert.True(t, orm.ErrType.Is(err), err)
+	})
+}
+
+func TestPaginate(t *testing.T) {
+	admin := sdk.AccAddress([]byte("admin-address"))
+	g1 := testdata.GroupInfo{
+		Description: "my test 1",
+		Admin:       admin,
+	}
+	g2 := testdata.GroupInfo{
+		Description: "my test 2",
+		Admin:       admin,
+	}
+	g3 := testdata.GroupInfo{
+		Description: "my test 3",
+		Admin:       sdk.AccAddress([]byte("other-admin-address")),
+	}
+	g4 := testdata.GroupInfo{
+		Description: "my test 4",
+		Admin:       admin,
+	}
+	g5 := testdata.GroupInfo{
+		Description: "my test 5",
+		Admin:       sdk.AccAddress([]byte("other-admin-address")),
+	}
+
+	_, idx, ctx := newTestTableWithRows(t, g1, g2, g3, g4, g5)
+
+	specs := map[string]struct {
+		pageReq    *query.PageRequest
+		expPageRes *query.PageResponse
+		exp        []testdata.GroupInfo
+		key        []byte
+		expErr     bool
+	}{
+		"one item": {
+			pageReq:    &query.PageRequest{Key: nil, Limit: 1},
+			exp:        []testdata.GroupInfo{g1},
+			expPageRes: &query.PageResponse{Total: 0, NextKey: orm.EncodeCursor(admin, orm.EncodeSequence(2))},
+			key:        admin,
+		},
+		"with both key and offset": {
+			pageReq: &query.PageRequest{Key: orm.EncodeSequence(2), Offset: 1},
+			expErr:  true,
+			key:     admin,
+		},
+		"with offset + limit overflow": {
+			pageReq: &query.PageRequest{Offset: math.MaxUint64, Limit: 1},
+			expErr:  true,
+			key:     admin,
+		},
+		"with max offset and default limit": {
+			pageReq: &query.PageRequest{Offset: math.MaxUint64},
+			expErr:  true,
+			key:     admin,
+		},
+		"up to max": {
+			pageReq:    &query.PageRequest{Key: nil, Limit: 3, CountTotal: true},
+			exp:        []testdata.GroupInfo{g1, g2, g4},
+			expPageRes: &query.PageResponse{Total: 3, NextKey: nil},
+			key:        admin,
+		},
+		"no results": {
+			pageReq:    &query.PageRequest{Key: nil, Limit: 2, CountTotal: true},
+			exp:        []testdata.GroupInfo{},
+			expPageRes: &query.PageResponse{Total: 0, NextKey: nil},
+			key:        sdk.AccAddress([]byte("no-group-address")),
+		},
+		"with offset and count total": {
+			pageReq:    &query.PageRequest{Key: nil, Offset: 1, Limit: 2, CountTotal: true},
+			exp:        []testdata.GroupInfo{g2, g4},
+			expPageRes: &query.PageResponse{Total: 3, NextKey: nil},
+			key:        admin,
+		},
+		"nil/default page req (limit = 100 > number of items)": {
+			pageReq:    nil,
+			exp:        []testdata.GroupInfo{g1, g2, g4},
+			expPageRes: &query.PageResponse{Total: 3, NextKey: nil},
+			key:        admin,
+		},
+		"with key and limit < number of elem": {
+			pageReq:    &query.PageRequest{Key: orm.EncodeCursor(admin, orm.EncodeSequence(2)), Limit: 1, CountTotal: true},
+			exp:        []testdata.GroupInfo{g2},
+			expPageRes: &query.PageResponse{Total: 3, NextKey: orm.EncodeCursor(admin, orm.EncodeSequence(4))},
+			key:        admin,
+		},
+		"with key for the last page and count total": {
+			pageReq:    &query.PageRequest{Key: orm.EncodeSequence(4), Limit: 1, CountTotal: true},
+			exp:        []testdata.GroupInfo{g4},
+			expPageRes: &query.PageResponse{Total: 3, NextKey: nil},
+			key:        admin,
+		},
+		"with key and limit >= number of elem": {
+			pageReq:    &query.PageRequest{Key: orm.EncodeSequence(2), Limit: 2},
+			exp:        []testdata.GroupInfo{g2, g4},
+			expPageRes: &query.PageResponse{Total: 0, NextKey: nil},
+			key:        admin,
+		},
+		"with nothing left to iterate from key": {
+			pageReq:    &query.PageRequest{Key: orm.EncodeSequence(5)},
+			exp:        []testdata.GroupInfo{},
+			expPageRes: &query.PageResponse{Total: 3, NextKey: nil},
+			key:        admin,
+		},
+	}
+	for msg, spec := range specs {
+		t.Run(msg, func(t *testing.T) {
+			var loaded []testdata.GroupInfo
+
+			it, err := idx.GetPaginated(ctx, spec.key, spec.pageReq)
+			require.NoError(t, err)
+
+			res, err := orm.Paginate(it, spec.pageReq, &loaded)
+			if spec.expErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				assert.EqualValues(t, spec.exp, loaded)
+				assert.EqualValues(t, spec.expPageRes.Total, res.Total)
+				assert.EqualValues(t, spec.expPageRes.NextKey, res.NextKey)
+			}
+
+		})
+	}
+	t.Run("with key and capped count total", func(t *testing.T) {
+		pageReq := &query.PageRequest{Key: orm.EncodeSequence(2), Limit: 1, CountTotal: true}
+		it, err := idx.WithMaxCountTotal(2).GetPaginated(ctx, admin, pageReq)
+		require.NoError(t, err)
+
+		var loaded []testdata.GroupInfo
+		res, err := orm.Paginate(it, pageReq, &loaded)
+		require.NoError(t, err)
+		assert.Equal(t, []testdata.GroupInfo{g2}, loaded)
+		assert.EqualValues(t, 2, res.Total)
+	})
+}
+
+func TestPaginateEmptyKey(t *testing.T) {
+	interfaceRegistry := types.NewInterfaceRegistry()
+	cdc := codec.NewProtoCodec(interfaceRegistry)
+
+	storeKey := sdk.NewKVStoreKey("test")
+	const (
+		testTablePrefix = iota
+		testTableSeqPrefix
+	)
+	tBuilder := orm.NewAutoUInt64TableBuilder(testTablePrefix, testTableSeqPrefix, storeKey, &testdata.GroupInfo{}, cdc)
+	idx := orm.NewIndex(tBuilder, GroupByAdminIndexPrefix, func(val interface{}) ([]orm.RowID, error) {
+		return []orm.RowID{[]byte(val.(*testdata.GroupInfo).Admin)}, nil
+	})
+	uint64Idx := orm.NewUInt64Index(tBuilder, GroupMemberByMemberIndexPrefix, func(val interface{}) ([]uint64, error) {
+		return []uint64{1}, nil
+	})
+	tb := tBuilder.Build()
+	ctx := orm.NewMockContext()
+
+	admin := sdk.AccAddress([]byte("admin-address"))
+	g1 := testdata.GroupInfo{Description: "my test 1", Admin: admin}
+	g2 := testdata.GroupInfo{Description: "my test 2", Admin: admin}
+	g3 := testdata.GroupInfo{Description: "my test 3", Admin: admin}
+	for _, g := range []testdata.GroupInfo{g1, g2, g3} {
+		_, err := tb.Create(ctx, &g)
+		require.NoError(t, err)
+	}
+
+	sources := map[string]struct {
+		src    func(pageReq *query.PageRequest) (orm.Iterator, error)
+		keyOf2 []byte
+	}{
+		"table": {
+			src: func(pageReq *query.PageRequest) (orm.Iterator, error) {
+				start := uint64(1)
+				if len(pageReq.Key) != 0 {
+					start = orm.DecodeSequence(pageReq.Key)
+				}
+				return tb.PrefixScan(ctx, start, math.MaxUint64)
+			},
+			keyOf2: orm.EncodeSequence(2),
+		},
+		"index": {
+			src: func(pageReq *query.PageRequest) (orm.Iterator, error) {
+				return idx.GetPaginated(ctx, admin, pageReq)
+			},
+			keyOf2: orm.EncodeCursor(admin, orm.EncodeSequence(2)),
+		},
+		"uint64 index": {
+			src: func(pageReq *query.PageRequest) (orm.Iterator, error) {
+				return uint64Idx.GetPaginated(ctx, 1, pageReq)
+			},
+			keyOf2: orm.EncodeCursor(orm.EncodeSequence(1), orm.EncodeSequence(2)),
+		},
+	}
+	for name, source := range sources {
+		specs := map[string]struct {
+			pageReq    *query.PageRequest
+			exp        []testdata.GroupInfo
+			expPageRes *query.PageResponse
+		}{
+			"nil key": {
+				pageReq:    &query.PageRequest{Limit: 1, CountTotal: true},
+				exp:        []testdata.GroupInfo{g1},
+				expPageRes: &query.PageResponse{Total: 3, NextKey: source.keyOf2},
+			},
+			"empty key": {
+				pageReq:    &query.PageRequest{Key: []byte{}, Limit: 1, CountTotal: true},
+				exp:        []testdata.GroupInfo{g1},
+				expPageRes: &query.PageResponse{Total: 3, NextKey: source.keyOf2},
+			},
+			"empty key with offset": {
+				pageReq:    &query.PageRequest{Key: []byte{}, Offset: 1, CountTotal: true},
+				exp:        []testdata.GroupInfo{g2, g3},
+				expPageRes: &query.PageResponse{Total: 3},
+			},
+			"valid key": {
+				pageReq:    &query.PageRequest{Key: source.keyOf2, Limit: 2},
+				exp:        []testdata.GroupInfo{g2, g3},
+				expPageRes: &query.PageResponse{},
+			},
+		}
+		for msg, spec := range specs {
+			t.Run(name+" "+msg, func(t *testing.T) {
+				it, err := source.src(spec.pageReq)
+				require.NoError(t, err)
+				var loaded []testdata.GroupInfo
+				res, err := orm.Paginate(it, spec.pageReq, &loaded)
+				require.NoError(t, err)
+				assert.Equal(t, spec.exp, loaded)
+				assert.EqualValues(t, spec.expPageRes.Total, res.Total)
+				assert.EqualValues(t, spec.expPageRes.NextKey, res.NextKey)
+			})
+		}
+	}
+}
+
+func TestPaginateOffsetWithoutRawIterator(t *testing.T) {
+	tb, _, ctx := newTestTableWithRows(t)
+
+	var groups []testdata.GroupInfo
+	for i := 1; i <= 5; i++ {
+		g := testdata.GroupInfo{Description: fmt.Sprintf("my test %d", i), Admin: sdk.AccAddress([]byte("admin-address"))}
+		_, err := tb.Create(ctx, &g)
+		require.NoError(t, err)
+		groups = append(groups, g)
+	}
+	pageReq := &query.PageRequest{Offset: 2, Limit: 2, CountTotal: true}
+	// the skipped elements are loaded one after another as the RawIterator is hidden
+	newIterator := func() orm.Iterator {
+		it, err := tb.PrefixScan(ctx, 1, 100)
+		require.NoError(t, err)
+		return orm.IteratorFunc(it.LoadNext)
+	}
+
+	t.Run("values", func(t *testing.T) {
+		var loaded []testdata.GroupInfo
+		res, err := orm.Paginate(newIterator(), pageReq, &loaded)
+		require.NoError(t, err)
+		assert.Equal(t, groups[2:4], loaded)
+		assert.Equal(t, orm.EncodeSequence(5), res.NextKey)
+		assert.Equal(t, uint64(5), res.Total)
+	})
+	t.Run("pointers", func(t *testing.T) {
+		var loaded []*testdata.GroupInfo
+		res, err := orm.Paginate(newIterator(), pageReq, &loaded)
+		require.NoError(t, err)
+		assert.Equal(t, []*testdata.GroupInfo{&groups[2], &groups[3]}, loaded)
+		assert.Equal(t, orm.EncodeSequence(5), res.NextKey)
+		assert.Equal(t, uint64(5), res.Total)
+	})
+}
+
+func TestPaginateErrorInOffset(t *testing.T) {
+	myErr := errors.Register("test", 3, "my error")
+	var calls int
+	it := orm.IteratorFunc(func(dest codec.ProtoMarshaler) (orm.RowID, error) {
+		calls++
+		if calls == 2 {
+			return nil, myErr
+		}
+		return orm.EncodeSequence(uint64(calls)), nil
+	})
+
+	var loaded []testdata.GroupInfo
+	_, err := orm.Paginate(it, &query.PageRequest{Offset: 5, Limit: 1}, &loaded)
+	require.True(t, myErr.Is(err), err)
+	assert.Equal(t, 2, calls)
+}
+
+func TestPaginateWithRowIDs(t *testing.T) {
+	groups := testGroups(3, nil)
+	g1, g2, g3 := groups[0], groups[1], groups[2]
+	tb, _, ctx := newTestTableWithRows(t, groups...)
+
+	specs := map[string]struct {
+		pageReq    *query.PageRequest
+		exp        []testdata.GroupInfo
+		expRowIDs  []orm.RowID
+		expNextKey []byte
+	}{
+		"first page": {
+			pageReq:    &query.PageRequest{Limit: 2},
+			exp:        []testdata.GroupInfo{g1, g2},
+			expRowIDs:  []orm.RowID{orm.EncodeSequence(1), orm.EncodeSequence(2)},
+			expNextKey: orm.EncodeSequence(3),
+		},
+		"with offset": {
+			pageReq:   &query.PageRequest{Offset: 1, Limit: 2},
+			exp:       []testdata.GroupInfo{g2, g3},
+			expRowIDs: []orm.RowID{orm.EncodeSequence(2), orm.EncodeSequence(3)},
+		},
+		"offset after end": {
+			pageReq: &query.PageRequest{Offset: 5, Limit: 2},
+			exp:     []testdata.GroupInfo{},
+		},
+	}
+	for msg, spec := range specs {
+		t.Run(msg, func(t *testing.T) {
+			it, err := tb.PrefixScan(ctx, 1, math.MaxUint64)
+			require.NoError(t, err)
+
+			var loaded []testdata.GroupInfo
+			rowIDs, res, err := orm.PaginateWithRowIDs(it, spec.pageReq, &loaded)
+			require.NoError(t, err)
+			assert.Equal(t, spec.exp, loaded)
+			assert.Equal(t, spec.expRowIDs, rowIDs)
+			assert.EqualValues(t, spec.expNextKey, res.NextKey)
+		})
+	}
+}
+
+func TestPaginateWithOpts(t *testing.T) {
+	groups := testGroups(3, nil)
+	g1, g2, g3 := groups[0], groups[1], groups[2]
+	tb, _, ctx := newTestTableWithRows(t, groups...)
+
+	specs := map[string]struct {
+		pageReq    *query.PageRequest
+		opts       orm.PaginateOpts
+		exp        []testdata.GroupInfo
+		expPageRes *query.PageResponse
+		expErr     *errors.Error
+	}{
+		"limit below max": {
+			pageReq:    &query.PageRequest{Limit: 1, CountTotal: true},
+			opts:       orm.PaginateOpts{MaxLimit: 2},
+			exp:        []testdata.GroupInfo{g1},
+			expPageRes: &query.PageResponse{Total: 3, NextKey: orm.EncodeSequence(2)},
+		},
+		"limit above max clamped": {
+			pageReq:    &query.PageRequest{Limit: 10, CountTotal: true},
+			opts:       orm.PaginateOpts{MaxLimit: 2},
+			exp:        []testdata.GroupInfo{g1, g2},
+			expPageRes: &query.PageResponse{Total: 3, NextKey: orm.EncodeSequence(3)},
+		},
+		"limit above max rejected": {
+			pageReq: &query.PageRequest{Limit: 10},
+			opts:    orm.PaginateOpts{MaxLimit: 2, RejectAboveMaxLimit: true},
+			expErr:  orm.ErrArgument,
+		},
+		"limit equal to max not rejected": {
+			pageReq:    &query.PageRequest{Limit: 2},
+			opts:       orm.PaginateOpts{MaxLimit: 2, RejectAboveMaxLimit: true},
+			exp:        []testdata.GroupInfo{g1, g2},
+			expPageRes: &query.PageResponse{NextKey: orm.EncodeSequence(3)},
+		},
+		"default limit clamped": {
+			pageReq:    nil,
+			opts:       orm.PaginateOpts{MaxLimit: 2, RejectAboveMaxLimit: true},
+			exp:        []testdata.GroupInfo{g1, g2},
+			expPageRes: &query.PageResponse{Total: 3, NextKey: orm.EncodeSequence(3)},
+		},
+		"clamped with offset": {
+			pageReq:    &query.PageRequest{Offset: 1, Limit: 10, CountTotal: true},
+			opts:       orm.PaginateOpts{MaxLimit: 1},
+			exp:        []testdata.GroupInfo{g2},
+			expPageRes: &query.PageResponse{Total: 3, NextKey: orm.EncodeSequence(3)},
+		},
+		"no max": {
+			pageReq:    &query.PageRequest{Limit: 10},
+			opts:       orm.PaginateOpts{RejectAboveMaxLimit: true},
+			exp:        []testdata.GroupInfo{g1, g2, g3},
+			expPageRes: &query.PageResponse{},
+		},
+	}
+	for msg, spec := range specs {
+		t.Run(msg, func(t *testing.T) {
+			it, err := tb.PrefixScan(ctx, 1, math.MaxUint64)
+			require.NoError(t, err)
+
+			var loaded []testdata.GroupInfo
+			res, err := orm.PaginateWithOpts(it, spec.pageReq, &loaded, spec.opts)
+			require.True(t, spec.expErr.Is(err), "expected %s but got %s", spec.expErr, err)
+			if spec.expErr != nil {
+				return
+			}
+			assert.Equal(t, spec.exp, loaded)
+			assert.EqualValues(t, spec.expPageRes.Total, res.Total)
+			assert.EqualValues(t, spec.expPageRes.NextKey, res.NextKey)
+		})
+	}
+}
+
+func TestSetDefaultMaxLimit(t *testing.T) {
+	tb, _, ctx := newTestTableWithRows(t)
+
+	var exp []testdata.GroupInfo
+	for i := 1; i <= 3; i++ {
+		g := testdata.GroupInfo{Description: fmt.Sprintf("my test %d", i)}
+		_, err := tb.Create(ctx, &g)
+		require.NoError(t, err)
+		exp = append(exp, g)
+	}
+
+	orm.SetDefaultMaxLimit(2)
+	defer orm.SetDefaultMaxLimit(0)
+
+	specs := map[string]struct {
+		pageReq    *query.PageRequest
+		opts       orm.PaginateOpts
+		exp        []testdata.GroupInfo
+		expPageRes *query.PageResponse
+		expErr     *errors.Error
+	}{
+		"limit up to default max": {
+			pageReq:    &query.PageRequest{Limit: 2},
+			exp:        exp[:2],
+			expPageRes: &query.PageResponse{NextKey: orm.EncodeSequence(3)},
+		},
+		"limit above default max clamped": {
+			pageReq:    &query.PageRequest{Limit: math.MaxUint64},
+			exp:        exp[:2],
+			expPageRes: &query.PageResponse{NextKey: orm.EncodeSequence(3)},
+		},
+		"limit above default max rejected": {
+			pageReq: &query.PageRequest{Limit: math.MaxUint64},
+			opts:    orm.PaginateOpts{RejectAboveMaxLimit: true},
+			expErr:  orm.ErrArgument,
+		},
+		"default limit clamped": {
+			pageReq:    nil,
+			exp:        exp[:2],
+			expPageRes: &query.PageResponse{Total: 3, NextKey: orm.EncodeSequence(3)},
+		},
+		"max limit of the endpoint": {
+			pageReq:    &query.PageRequest{Limit: 10},
+			opts:       orm.PaginateOpts{MaxLimit: 3},
+			exp:        exp,
+			expPageRes: &query.PageResponse{},
+		},
+	}
+	for msg, spec := range specs {
+		t.Run(msg, func(t *testing.T) {
+			it, err := tb.PrefixScan(ctx, 1, math.MaxUint64)
+			require.NoError(t, err)
+
+			var loaded []testdata.GroupInfo
+			res, err := orm.PaginateWithOpts(it, spec.pageReq, &loaded, spec.opts)
+			require.True(t, spec.expErr.Is(err), "expected %s but got %s", spec.expErr, err)
+			if spec.expErr != nil {
+				return
+			}
+			assert.Equal(t, spec.exp, loaded)
+			assert.EqualValues(t, spec.expPageRes.Total, res.Total)
+			assert.EqualValues(t, spec.expPageRes.NextKey, res.NextKey)
+		})
+	}
+	t.Run("paginate", func(t *testing.T) {
+		it, err := tb.PrefixScan(ctx, 1, math.MaxUint64)
+		require.NoError(t, err)
+		var loaded []testdata.GroupInfo
+		res, err := orm.Paginate(it, &query.PageRequest{Limit: 3}, &loaded)
+		require.NoError(t, err)
+		assert.Equal(t, exp[:2], loaded)
+		assert.EqualValues(t, orm.EncodeSequence(3), res.NextKey)
+	})
+}
+
+func TestPaginateWithTotal(t *testing.T) {
+	groups := testGroups(3, nil)
+	g1, g2, g3 := groups[0], groups[1], groups[2]
+	tb, _, ctx := newTestTableWithRows(t, groups...)
+	total, err := tb.Count(ctx)
+	require.NoError(t, err)
+
+	specs := map[string]struct {
+		pageReq    *query.PageRequest
+		start      uint64
+		exp        []testdata.GroupInfo
+		expPageRes *query.PageResponse
+		expScanned int
+	}{
+		"first page with total": {
+			pageReq:    &query.PageRequest{Limit: 1, CountTotal: true},
+			exp:        []testdata.GroupInfo{g1},
+			expPageRes: &query.PageResponse{Total: 3, NextKey: orm.EncodeSequence(2)},
+			expScanned: 2,
+		},
+		"without total": {
+			pageReq:    &query.PageRequest{Limit: 1},
+			exp:        []testdata.GroupInfo{g1},
+			expPageRes: &query.PageResponse{NextKey: orm.EncodeSequence(2)},
+			expScanned: 2,
+		},
+		"default limit": {
+			pageReq:    nil,
+			exp:        []testdata.GroupInfo{g1, g2, g3},
+			expPageRes: &query.PageResponse{Total: 3},
+			expScanned: 3,
+		},
+		"page by key": {
+			pageReq:    &query.PageRequest{Key: orm.EncodeSequence(2), Limit: 1, CountTotal: true},
+			start:      2,
+			exp:        []testdata.GroupInfo{g2},
+			expPageRes: &query.PageResponse{Total: 3, NextKey: orm.EncodeSequence(3)},
+			expScanned: 2,
+		},
+		"with offset": {
+			pageReq:    &query.PageRequest{Offset: 1, Limit: 1, CountTotal: true},
+			exp:        []testdata.GroupInfo{g2},
+			expPageRes: &query.PageResponse{Total: 3, NextKey: orm.EncodeSequence(3)},
+			expScanned: 3,
+		},
+	}
+	for msg, spec := range specs {
+		t.Run(msg, func(t *testing.T) {
+			start := spec.start
+			if start == 0 {
+				start = 1
+			}
+			it, err := tb.PrefixScan(ctx, start, math.MaxUint64)
+			require.NoError(t, err)
+			var scanned int
+			it = orm.InstrumentedIterator(it, nil, func(n int) { scanned = n })
+
+			var loaded []testdata.GroupInfo
+			res, err := orm.PaginateWithTotal(it, spec.pageReq, &loaded, total)
+			require.NoError(t, err)
+			assert.Equal(t, spec.exp, loaded)
+			assert.EqualValues(t, spec.expPageRes.Total, res.Total)
+			assert.EqualValues(t, spec.expPageRes.NextKey, res.NextKey)
+			// the elements after the page are not counted
+			assert.Equal(t, spec.expScanned, scanned)
+		})
+	}
+}
+
+func TestPaginateReverse(t *testing.T) {
+	admin := sdk.AccAddress([]byte("admin-address"))
+	g1 := testdata.GroupInfo{Description: "my test 1", Admin: admin}
+	g2 := testdata.GroupInfo{Description: "my test 2", Admin: admin}
+	g3 := testdata.GroupInfo{Description: "my test 3", Admin: sdk.AccAddress([]byte("other-admin-address"))}
+	g4 := testdata.GroupInfo{Description: "my test 4", Admin: admin}
+	_, idx, ctx := newTestTableWithRows(t, g1, g2, g3, g4)
+
+	specs := map[string]struct {
+		pageReq    *query.PageRequest
+		expPageRes *query.PageResponse
+		exp        []testdata.GroupInfo
+	}{
+		"one item": {
+			pageReq:    &query.PageRequest{Limit: 1},
+			exp:        []testdata.GroupInfo{g4},
+			expPageRes: &query.PageResponse{NextKey: orm.EncodeCursor(admin, orm.EncodeSequence(2))},
+		},
+		"with key and limit < number of elem": {
+			pageReq:    &query.PageRequest{Key: orm.EncodeCursor(admin, orm.EncodeSequence(2)), Limit: 1},
+			exp:        []testdata.GroupInfo{g2},
+			expPageRes: &query.PageResponse{NextKey: orm.EncodeCursor(admin, orm.EncodeSequence(1))},
+		},
+		"with key and limit >= number of elem": {
+			pageReq:    &query.PageRequest{Key: orm.EncodeSequence(2), Limit: 2},
+			exp:        []testdata.GroupInfo{g2, g1},
+			expPageRes: &query.PageResponse{},
+		},
+		"with key of other index value": {
+			pageReq:    &query.PageRequest{Key: orm.EncodeSequence(3), Limit: 5},
+			exp:        []testdata.GroupInfo{g2, g1},
+			expPageRes: &query.PageResponse{},
+		},
+		"with offset and count total": {
+			pageReq:    &query.PageRequest{Offset: 1, Limit: 1, CountTotal: true},
+			exp:        []testdata.GroupInfo{g2},
+			expPageRes: &query.PageResponse{Total: 3, NextKey: orm.EncodeCursor(admin, orm.EncodeSequence(1))},
+		},
+	}
+	for msg, spec := range specs {
+		t.Run(msg, func(t *testing.T) {
+			var loaded []testdata.GroupInfo
+
+			it, err := idx.ReverseGetPaginated(ctx, admin, spec.pageReq)
+			require.NoError(t, err)
+
+			res, err := orm.Paginate(it, spec.pageReq, &loaded)
+			require.NoError(t, err)
+			assert.EqualValues(t, spec.exp, loaded)
+			assert.EqualValues(t, spec.expPageRes.Total, res.Total)
+			assert.EqualValues(t, spec.expPageRes.NextKey, res.NextKey)
+		})
+	}
+}
+
+func TestRawIterator(t *testing.T) {
+	admin := sdk.AccAddress([]byte("admin-address"))
+	groups := testGroups(2, admin)
+	g1, g2 := groups[0], groups[1]
+	tb, idx, ctx := newTestTableWithRows(t, groups...)
+	bz1, err := g1.Marshal()
+	require.NoError(t, err)
+	bz2, err := g2.Marshal()
+	require.NoError(t, err)
+
+	tableIt, err := tb.PrefixScan(ctx, 1, 100)
+	require.NoError(t, err)
+	indexIt, err := idx.Get(ctx, admin)
+	require.NoError(t, err)
+
+	for msg, it := range map[string]orm.Iterator{"table": tableIt, "index": indexIt} {
+		t.Run(msg, func(t *testing.T) {
+			defer it.Close()
+			raw, ok := it.(orm.RawIterator)
+			require.True(t, ok)
+
+			rowID, bz, err := raw.RawNext()
+			require.NoError(t, err)
+			assert.Equal(t, orm.RowID(orm.EncodeSequence(1)), rowID)
+			assert.Equal(t, bz1, bz)
+
+			rowID, bz, err = raw.RawNext()
+			require.NoError(t, err)
+			assert.Equal(t, orm.RowID(orm.EncodeSequence(2)), rowID)
+			assert.Equal(t, bz2, bz)
+
+			_, _, err = raw.RawNext()
+			assert.True(t, orm.ErrIteratorDone.Is(err), err)
+		})
+	}
+}
+
+func TestFromKVStoreIterator(t *testing.T) {
+	storeKey := sdk.NewKVStoreKey("test")
+	ctx := orm.NewMockContext()
+	store := ctx.KVStore(storeKey)
+
+	g1 := testdata.GroupInfo{Description: "my test 1"}
+	g2 := testdata.GroupInfo{Description: "my test 2"}
+	for i, g := range []testdata.GroupInfo{g1, g2} {
+		bz, err := g.Marshal()
+		require.NoError(t, err)
+		store.Set(append([]byte{0x1}, orm.EncodeSequence(uint64(i+1))...), bz)
+	}
+	store.Set([]byte{0x2, 0x1}, []byte("other prefix"))
+
+	t.Run("read all", func(t *testing.T) {
+		it := orm.FromKVStoreIterator(store.Iterator(orm.PrefixRange([]byte{0x1})), 1)
+		var loaded []testdata.GroupInfo
+		rowIDs, err := orm.ReadAll(it, &loaded)
+		require.NoError(t, err)
+		assert.Equal(t, []testdata.GroupInfo{g1, g2}, loaded)
+		assert.Equal(t, []orm.RowID{orm.EncodeSequence(1), orm.EncodeSequence(2)}, rowIDs)
+	})
+	t.Run("paginate", func(t *testing.T) {
+		it := orm.FromKVStoreIterator(store.Iterator(orm.PrefixRange([]byte{0x1})), 1)
+		var loaded []testdata.GroupInfo
+		res, err := orm.Paginate(it, &query.PageRequest{Limit: 1, CountTotal: true}, &loaded)
+		require.NoError(t, err)
+		assert.Equal(t, []testdata.GroupInfo{g1}, loaded)
+		assert.Equal(t, orm.EncodeSequence(2), res.NextKey)
+		assert.Equal(t, uint64(2), res.Total)
+	})
+	t.Run("key not longer than prefix", func(t *testing.T) {
+		it := orm.FromKVStoreIterator(store.Iterator(orm.PrefixRange([]byte{0x2})), 2)
+		defer it.Close()
+		_, err := it.LoadNext(&testdata.GroupInfo{})
+		assert.True(t, orm.ErrArgument.Is(err), err)
+	})
+	t.Run("store iterator error", func(t *testing.T) {
+		var closed int
+		storeIt := &failingStoreIter{err: orm.ErrIteratorInvalid, close: func() error {
+			closed++
+			return nil
+		}}
+		it := orm.FromKVStoreIterator(storeIt, 0)
+		_, err := it.LoadNext(&testdata.GroupInfo{})
+		assert.True(t, orm.ErrIteratorInvalid.Is(err), err)
+
+		require.NoError(t, it.Close())
+		require.NoError(t, it.Close())
+		assert.Equal(t, 1, closed)
+	})
+	t.Run("invalid arguments", func(t *testing.T) {
+		it := orm.FromKVStoreIterator(nil, 0)
+		_, err := it.LoadNext(&testdata.GroupInfo{})
+		assert.True(t, orm.ErrArgument.Is(err), err)
+		require.NoError(t, it.Close())
+
+		// the store iterator is closed with the iterator
+		var closed int
+		it = orm.FromKVStoreIterator(&failingStoreIter{close: func() error {
+			closed++
+			return nil
+		}}, -1)
+		_, err = it.LoadNext(&testdata.GroupInfo{})
+		assert.True(t, orm.ErrArgument.Is(err), err)
+		_, _, err = it.(orm.RawIterator).RawNext()
+		assert.True(t, orm.ErrArgument.Is(err), err)
+		require.NoError(t, it.Close())
+		require.NoError(t, it.Close())
+		assert.Equal(t, 1, closed)
+	})
+}
+
+// failingStoreIter is an invalid store iterator that returns the error.
+type failingStoreIter struct {
+	err   error
+	close func() error
+}
+
+func (f *failingStoreIter) Domain() (start []byte, end []byte) { return nil, nil }
+func (f *failingStoreIter) Valid() bool                        { return false }
+func (f *failingStoreIter) Next()                              { panic("invalid iterator") }
+func (f *failingStoreIter) Key() (key []byte)                  { panic("invalid iterator") }
+func (f *failingStoreIter) Value() (value []byte)              { panic("invalid iterator") }
+func (f *failingStoreIter) Error() error                       { return f.err }
+func (f *failingStoreIter) Close() error                       { return f.close() }
+
+func TestBufferedIterator(t *testing.T) {
+	tb, _, ctx := newTestTableWithRows(t)
+
+	var exp []testdata.GroupInfo
+	var expRowIDs []orm.RowID
+	for i := 1; i <= 5; i++ {
+		g := testdata.GroupInfo{Description: fmt.Sprintf("my test %d", i)}
+		rowID, err := tb.Create(ctx, &g)
+		require.NoError(t, err)
+		exp = append(exp, g)
+		expRowIDs = append(expRowIDs, orm.EncodeSequence(rowID))
+	}
+
+	for _, batchSize := range []int{1, 2, 5, 10} {
+		t.Run(fmt.Sprintf("batch size %d", batchSize), func(t *testing.T) {
+			parent, err := tb.PrefixScan(ctx, 1, 100)
+			require.NoError(t, err)
+			var loaded []testdata.GroupInfo
+			rowIDs, err := orm.ReadAll(orm.BufferedIterator(parent.(orm.RawIterator), batchSize), &loaded)
+			require.NoError(t, err)
+			assert.Equal(t, exp, loaded)
+			assert.Equal(t, expRowIDs, rowIDs)
+		})
+	}
+	t.Run("parent error after buffered elements", func(t *testing.T) {
+		store := ctx.KVStore(testTableStoreKey)
+		// the key {0x1} is too short for the prefix length and fails after the table rows
+		store.Set([]byte{0x1}, []byte{})
+		defer store.Delete([]byte{0x1})
+		parent := orm.FromKVStoreIterator(store.Iterator(nil, []byte{0x2}), 1)
+		it := orm.BufferedIterator(parent.(orm.RawIterator), 10)
+
+		var loaded []testdata.GroupInfo
+		_, err := orm.ReadAll(it, &loaded)
+		assert.True(t, orm.ErrArgument.Is(err), err)
+	})
+	t.Run("table scan with batch size", func(t *testing.T) {
+		it, err := tb.PrefixScanWithOpts(ctx, 1, 100, orm.ScanOpts{BatchSize: 2})
+		require.NoError(t, err)
+		var loaded []testdata.GroupInfo
+		_, err = orm.ReadAll(it, &loaded)
+		require.NoError(t, err)
+		assert.Equal(t, exp, loaded)
+
+		it, err = tb.ReversePrefixScanWithOpts(ctx, 1, 100, orm.ScanOpts{BatchSize: 2})
+		require.NoError(t, err)
+		var reverse []testdata.GroupInfo
+		_, err = orm.ReadAll(it, &reverse)
+		require.NoError(t, err)
+		assert.Equal(t, []testdata.GroupInfo{exp[4], exp[3], exp[2], exp[1], exp[0]}, reverse)
+
+		it, err = tb.PrefixScanWithOpts(ctx, 1, 100, orm.ScanOpts{BatchSize: 2})
+		require.NoError(t, err)
+		_, err = it.LoadNext(&testdata.GroupMember{})
+		assert.True(t, orm.ErrType.Is(err), err)
+
+		_, err = tb.PrefixScanWithOpts(ctx, 1, 100, orm.ScanOpts{BatchSize: -1})
+		assert.True(t, orm.ErrArgument.Is(err), err)
+	})
+	t.Run("invalid arguments", func(t *testing.T) {
+		parent, err := tb.PrefixScan(ctx, 1, 100)
+		require.NoError(t, err)
+		var closed int
+		for _, it := range []orm.Iterator{
+			orm.BufferedIterator(nil, 1),
+			orm.BufferedIterator(closingRawIter{RawIterator: parent.(orm.RawIterator), close: func() error {
+				closed++
+				return parent.Close()
+			}}, 0),
+		} {
+			_, err := it.LoadNext(&testdata.GroupInfo{})
+			assert.True(t, orm.ErrArgument.Is(err), err)
+			require.NoError(t, it.Close())
+		}
+		// the parent is closed with the iterator
+		assert.Equal(t, 1, closed)
+	})
+}
+
+func BenchmarkBufferedIterator(b *testing.B) {
+	tb, _, ctx := newTestTableWithRows(b, testGroups(50000, nil)...)
+
+	for _, batchSize := range []int{0, 100, 1000} {
+		b.Run(fmt.Sprintf("batch size %d", batchSize), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				it, err := tb.PrefixScanWithOpts(ctx, 1, math.MaxUint64, orm.ScanOpts{BatchSize: batchSize})
+				require.NoError(b, err)
+				var loaded []testdata.GroupInfo
+				_, err = orm.ReadAll(it, &loaded)
+				require.NoError(b, err)
+			}
+		})
+	}
+}
+
+func TestSnapshotScan(t *testing.T) {
+	tb, _, ctx := newTestTableWithRows(t, testGroups(5, nil)...)
+
+	it, err := tb.PrefixScanWithOpts(ctx, 1, 100, orm.ScanOpts{Snapshot: true})
+	require.NoError(t, err)
+	defer it.Close()
+
+	var loaded []string
+	for {
+		var g testdata.GroupInfo
+		rowID, err := it.LoadNext(&g)
+		if orm.IsIteratorDone(err) {
+			break
+		}
+		require.NoError(t, err)
+		loaded = append(loaded, g.Description)
+
+		if orm.DecodeSequence(rowID) == 2 {
+			// an update of a row that is not read yet is returned
+			require.NoError(t, tb.Save(ctx, 3, &testdata.GroupInfo{Description: "updated"}))
+			// a deleted row that is not read yet is skipped
+			require.NoError(t, tb.Delete(ctx, 4))
+			// an insert into the scanned range is not returned
+			_, err := tb.Create(ctx, &testdata.GroupInfo{Description: "inserted"})
+			require.NoError(t, err)
+		}
+		// a write to the current row does not change the scanned rows
+		g.Description += " saved"
+		require.NoError(t, tb.Save(ctx, orm.DecodeSequence(rowID), &g))
+	}
+	assert.Equal(t, []string{"my test 1", "my test 2", "updated", "my test 5"}, loaded)
+
+	n, err := tb.Count(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(5), n)
+
+	t.Run("reverse", func(t *testing.T) {
+		it, err := tb.ReversePrefixScanWithOpts(ctx, 1, 100, orm.ScanOpts{Snapshot: true})
+		require.NoError(t, err)
+		defer it.Close()
+
+		require.NoError(t, tb.Delete(ctx, 6))
+		rowIDs, err := orm.ReadAll(it, &[]testdata.GroupInfo{})
+		require.NoError(t, err)
+		assert.Equal(t, []orm.RowID{orm.EncodeSequence(5), orm.EncodeSequence(3), orm.EncodeSequence(2), orm.EncodeSequence(1)}, rowIDs)
+	})
+	t.Run("raw", func(t *testing.T) {
+		it, err := tb.PrefixScanWithOpts(ctx, 1, 100, orm.ScanOpts{Snapshot: true})
+		require.NoError(t, err)
+		defer it.Close()
+
+		require.NoError(t, tb.Delete(ctx, 1))
+		rowID, value, err := it.(orm.RawIterator).RawNext()
+		require.NoError(t, err)
+		assert.Equal(t, orm.RowID(orm.EncodeSequence(2)), rowID)
+		var g testdata.GroupInfo
+		require.NoError(t, g.Unmarshal(value))
+		assert.Equal(t, "my test 2 saved", g.Description)
+	})
+	t.Run("with batch size", func(t *testing.T) {
+		_, err := tb.PrefixScanWithOpts(ctx, 1, 100, orm.ScanOpts{Snapshot: true, BatchSize: 2})
+		assert.True(t, orm.ErrArgument.Is(err), err)
+	})
+}
+
+func TestRewind(t *testing.T) {
+	tb, idx, _ := newTestTableWithRows(t)
+
+	admin := sdk.AccAddress([]byte("admin-address"))
+	g1 := testdata.GroupInfo{Description: "my test 1", Admin: admin}
+	g2 := testdata.GroupInfo{Description: "my test 2", Admin: admin}
+	newCtx := func(t *testing.T) orm.HasKVStore {
+		_, _, ctx := newTestTableWithRows(t, g1, g2)
+		return ctx
+	}
+
+	specs := map[string]struct {
+		src       func(ctx orm.HasKVStore) (orm.Iterator, error)
+		exp       []testdata.GroupInfo
+		expRewind []testdata.GroupInfo
+	}{
+		"table prefix scan": {
+			src:       func(ctx orm.HasKVStore) (orm.Iterator, error) { return tb.PrefixScan(ctx, 1, 100) },
+			exp:       []testdata.GroupInfo{g1, g2},
+			expRewind: []testdata.GroupInfo{g1, g2, {Description: "my test 3", Admin: admin}},
+		},
+		"table reverse prefix scan": {
+			src:       func(ctx orm.HasKVStore) (orm.Iterator, error) { return tb.ReversePrefixScan(ctx, 1, 100) },
+			exp:       []testdata.GroupInfo{g2, g1},
+			expRewind: []testdata.GroupInfo{{Description: "my test 3", Admin: admin}, g2, g1},
+		},
+		"table scan with batch size": {
+			src: func(ctx orm.HasKVStore) (orm.Iterator, error) {
+				return tb.PrefixScanWithOpts(ctx, 1, 100, orm.ScanOpts{BatchSize: 1})
+			},
+			exp:       []testdata.GroupInfo{g1, g2},
+			expRewind: []testdata.GroupInfo{g1, g2, {Description: "my test 3", Admin: admin}},
+		},
+		"table snapshot scan": {
+			src: func(ctx orm.HasKVStore) (orm.Iterator, error) {
+				return tb.PrefixScanWithOpts(ctx, 1, 100, orm.ScanOpts{Snapshot: true})
+			},
+			exp: []testdata.GroupInfo{g1, g2},
+			// the rows inserted after the scan start are not returned
+			expRewind: []testdata.GroupInfo{g1, g2},
+		},
+		"index get": {
+			src:       func(ctx orm.HasKVStore) (orm.Iterator, error) { return idx.Get(ctx, admin) },
+			exp:       []testdata.GroupInfo{g1, g2},
+			expRewind: []testdata.GroupInfo{g1, g2, {Description: "my test 3", Admin: admin}},
+		},
+		"index get paginated by key": {
+			src: func(ctx orm.HasKVStore) (orm.Iterator, error) {
+				key := orm.EncodeCursor(admin, orm.EncodeSequence(2))
+				return idx.GetPaginated(ctx, admin, &query.PageRequest{Key: key})
+			},
+			// the iterator is rewound to the page key
+			exp:       []testdata.GroupInfo{g2},
+			expRewind: []testdata.GroupInfo{g2, {Description: "my test 3", Admin: admin}},
+		},
+	}
+	for msg, spec := range specs {
+		t.Run(msg, func(t *testing.T) {
+			ctx := newCtx(t)
+			it, err := spec.src(ctx)
+			require.NoError(t, err)
+			defer it.Close()
+
+			var loaded []testdata.GroupInfo
+			for {
+				var g testdata.GroupInfo
+				_, err := it.LoadNext(&g)
+				if orm.IsIteratorDone(err) {
+					break
+				}
+				require.NoError(t, err)
+				loaded = append(loaded, g)
+			}
+			assert.Equal(t, spec.exp, loaded)
+
+			// writes since the creation are visible after the rewind, except for snapshots
+			_, err = tb.Create(ctx, &testdata.GroupInfo{Description: "my test 3", Admin: admin})
+			require.NoError(t, err)
+			require.NoError(t, orm.Rewind(it))
+			loaded = nil
+			_, err = orm.ReadAll(it, &loaded)
+			require.NoError(t, err)
+			assert.Equal(t, spec.expRewind, loaded)
+		})
+	}
+
+	t.Run("two pass pagination", func(t *testing.T) {
+		it, err := tb.PrefixScan(newCtx(t), 1, 100)
+		require.NoError(t, err)
+		var total uint64
+		for {
+			_, err := it.LoadNext(&testdata.GroupInfo{})
+			if orm.IsIteratorDone(err) {
+				break
+			}
+			require.NoError(t, err)
+			total++
+		}
+		require.NoError(t, orm.Rewind(it))
+
+		var loaded []testdata.GroupInfo
+		res, err := orm.PaginateWithTotal(it, &query.PageRequest{Limit: 1, CountTotal: true}, &loaded, total)
+		require.NoError(t, err)
+		assert.Equal(t, []testdata.GroupInfo{g1}, loaded)
+		assert.Equal(t, uint64(2), res.Total)
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		it, err := tb.PrefixScan(newCtx(t), 1, 100)
+		require.NoError(t, err)
+		limited := orm.LimitIterator(it, 1)
+		defer limited.Close()
+		err = orm.Rewind(limited)
+		assert.True(t, orm.ErrArgument.Is(err), err)
+		// the parent of a supported wrapper is rewound
+		err = orm.Rewind(orm.InstrumentedIterator(limited, nil, nil))
+		assert.True(t, orm.ErrArgument.Is(err), err)
+		require.NoError(t, orm.Rewind(orm.InstrumentedIterator(it, nil, nil)))
+	})
+}
+
+func TestPeekRowID(t *testing.T) {
+	tb, idx, _ := newTestTableWithRows(t)
+
+	admin := sdk.AccAddress([]byte("admin-address"))
+	newCtx := func(t *testing.T) orm.HasKVStore {
+		_, _, ctx := newTestTableWithRows(t, testGroups(2, admin)...)
+		return ctx
+	}
+	var reported int
+	specs := map[string]struct {
+		src         func(ctx orm.HasKVStore) (orm.Iterator, error)
+		expRowIDs   []orm.RowID
+		expReported int
+	}{
+		"table prefix scan": {
+			src:       func(ctx orm.HasKVStore) (orm.Iterator, error) { return tb.PrefixScan(ctx, 1, 100) },
+			expRowIDs: []orm.RowID{orm.EncodeSequence(1), orm.EncodeSequence(2)},
+		},
+		"table reverse prefix scan": {
+			src:       func(ctx orm.HasKVStore) (orm.Iterator, error) { return tb.ReversePrefixScan(ctx, 1, 100) },
+			expRowIDs: []orm.RowID{orm.EncodeSequence(2), orm.EncodeSequence(1)},
+		},
+		"table scan with batch size": {
+			src: func(ctx orm.HasKVStore) (orm.Iterator, error) {
+				return tb.PrefixScanWithOpts(ctx, 1, 100, orm.ScanOpts{BatchSize: 1})
+			},
+			expRowIDs: []orm.RowID{orm.EncodeSequence(1), orm.EncodeSequence(2)},
+		},
+		"table snapshot scan without deleted rows": {
+			src: func(ctx orm.HasKVStore) (orm.Iterator, error) {
+				it, err := tb.PrefixScanWithOpts(ctx, 1, 100, orm.ScanOpts{Snapshot: true})
+				if err != nil {
+					return nil, err
+				}
+				// the row is deleted after the scan start
+				return it, tb.Delete(ctx, 1)
+			},
+			expRowIDs: []orm.RowID{orm.EncodeSequence(2)},
+		},
+		"index get": {
+			src:       func(ctx orm.HasKVStore) (orm.Iterator, error) { return idx.Get(ctx, admin) },
+			expRowIDs: []orm.RowID{orm.EncodeSequence(1), orm.EncodeSequence(2)},
+		},
+		"slice": {
+			src: func(ctx orm.HasKVStore) (orm.Iterator, error) {
+				return orm.NewSliceIterator([]orm.RowID{[]byte("a"), []byte("b")}, [][]byte{{}, {}}), nil
+			},
+			expRowIDs: []orm.RowID{[]byte("a"), []byte("b")},
+		},
+		"peek iterator": {
+			src: func(ctx orm.HasKVStore) (orm.Iterator, error) {
+				it, err := tb.PrefixScan(ctx, 1, 100)
+				return orm.PeekIterator(it), err
+			},
+			expRowIDs: []orm.RowID{orm.EncodeSequence(1), orm.EncodeSequence(2)},
+		},
+		"instrumented": {
+			src: func(ctx orm.HasKVStore) (orm.Iterator, error) {
+				it, err := tb.PrefixScan(ctx, 1, 100)
+				return orm.InstrumentedIterator(it, func(orm.RowID, error) { reported++ }, nil), err
+			},
+			expRowIDs: []orm.RowID{orm.EncodeSequence(1), orm.EncodeSequence(2)},
+			// only the elements that are consumed and the final done are reported
+			expReported: 3,
+		},
+	}
+	for msg, spec := range specs {
+		t.Run(msg, func(t *testing.T) {
+			reported = 0
+			it, err := spec.src(newCtx(t))
+			require.NoError(t, err)
+			for _, exp := range spec.expRowIDs {
+				rowID, err := orm.PeekRowID(it)
+				require.NoError(t, err)
+				assert.Equal(t, exp, rowID)
+				// peek does not consume the element
+				rowID, err = orm.PeekRowID(it)
+				require.NoError(t, err)
+				assert.Equal(t, exp, rowID)
+				rowID, err = it.LoadNext(&testdata.GroupInfo{})
+				require.NoError(t, err)
+				assert.Equal(t, exp, rowID)
+			}
+			_, err = orm.PeekRowID(it)
+			assert.True(t, orm.ErrIteratorDone.Is(err), err)
+			_, err = it.LoadNext(&testdata.GroupInfo{})
+			assert.True(t, orm.ErrIteratorDone.Is(err), err)
+			assert.Equal(t, spec.expReported, reported)
+
+			require.NoError(t, it.Close())
+			_, err = orm.PeekRowID(it)
+			assert.True(t, orm.ErrIteratorClosed.Is(err), err)
+		})
+	}
+
+	t.Run("after peek", func(t *testing.T) {
+		it, err := tb.PrefixScan(newCtx(t), 2, 100)
+		require.NoError(t, err)
+		peekable := orm.PeekIterator(it)
+		defer peekable.Close()
+		var g testdata.GroupInfo
+		_, err = peekable.Peek(&g)
+		require.NoError(t, err)
+		// the rowID of the buffered element is returned
+		rowID, err := orm.PeekRowID(peekable)
+		require.NoError(t, err)
+		assert.Equal(t, orm.RowID(orm.EncodeSequence(2)), rowID)
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		it, err := tb.PrefixScan(newCtx(t), 1, 100)
+		require.NoError(t, err)
+		limited := orm.LimitIterator(it, 1)
+		defer limited.Close()
+		_, err = orm.PeekRowID(limited)
+		assert.True(t, orm.ErrArgument.Is(err), err)
+	})
+}
+
+func TestPrefixScanFrom(t *testing.T) {
+	g1 := testdata.GroupInfo{Description: "my test 1", Admin: sdk.AccAddress("admin-b")}
+	g2 := testdata.GroupInfo{Description: "my test 2", Admin: sdk.AccAddress("admin-a")}
+	g3 := testdata.GroupInfo{Description: "my test 3", Admin: sdk.AccAddress("admin-b")}
+	tb, idx, ctx := newTestTableWithRows(t, g1, g2, g3)
+
+	specs := map[string]struct {
+		scanFrom func(resumeKey []byte) (orm.Iterator, error)
+		exp      []testdata.GroupInfo
+	}{
+		"table": {
+			scanFrom: func(resumeKey []byte) (orm.Iterator, error) { return tb.Table().PrefixScanFrom(ctx, resumeKey) },
+			exp:      []testdata.GroupInfo{g1, g2, g3},
+		},
+		"index": {
+			scanFrom: func(resumeKey []byte) (orm.Iterator, error) { return idx.PrefixScanFrom(ctx, resumeKey) },
+			exp:      []testdata.GroupInfo{g2, g1, g3},
+		},
+	}
+	for msg, spec := range specs {
+		t.Run(msg, func(t *testing.T) {
+			// read one element per scan and resume after its position
+			var resumeKey []byte
+			var loaded []testdata.GroupInfo
+			for {
+				it, err := spec.scanFrom(resumeKey)
+				require.NoError(t, err)
+				assert.Nil(t, it.(orm.Positioner).Position())
+				var g testdata.GroupInfo
+				_, err = it.LoadNext(&g)
+				if orm.IsIteratorDone(err) {
+					require.NoError(t, it.Close())
+					break
+				}
+				require.NoError(t, err)
+				loaded = append(loaded, g)
+				resumeKey = it.(orm.Positioner).Position()
+				require.NoError(t, it.Close())
+			}
+			assert.Equal(t, spec.exp, loaded)
+		})
+	}
+}
+
+func TestValueDecoder(t *testing.T) {
+	tb, _, ctx := newTestTableWithRows(t, testGroups(3, nil)...)
+
+	// a view of a group that does not implement codec.ProtoMarshaler
+	type groupView struct {
+		ID          uint64
+		Description string
+	}
+	viewDecoder := func(value []byte, rowID orm.RowID) (interface{}, error) {
+		var g testdata.GroupInfo
+		if err := g.Unmarshal(value); err != nil {
+			return nil, err
+		}
+		return groupView{ID: orm.DecodeSequence(rowID), Description: g.Description}, nil
+	}
+	// a decoder of interfaces, returning different types by RowID
+	interfaceDecoder := func(value []byte, rowID orm.RowID) (interface{}, error) {
+		if orm.DecodeSequence(rowID)%2 == 0 {
+			return &testdata.GroupMember{Weight: orm.DecodeSequence(rowID)}, nil
+		}
+		var g testdata.GroupInfo
+		err := g.Unmarshal(value)
+		return &g, err
+	}
+
+	t.Run("read all into structs", func(t *testing.T) {
+		it, err := tb.PrefixScan(ctx, 1, 100)
+		require.NoError(t, err)
+		var loaded []groupView
+		rowIDs, err := orm.ReadAllDecoded(it, &loaded, viewDecoder)
+		require.NoError(t, err)
+		assert.Equal(t, []groupView{{1, "my test 1"}, {2, "my test 2"}, {3, "my test 3"}}, loaded)
+		assert.Equal(t, []orm.RowID{orm.EncodeSequence(1), orm.EncodeSequence(2), orm.EncodeSequence(3)}, rowIDs)
+	})
+	t.Run("read all into interfaces", func(t *testing.T) {
+		it, err := tb.PrefixScan(ctx, 1, 100)
+		require.NoError(t, err)
+		var loaded []codec.ProtoMarshaler
+		_, err = orm.ReadAllDecoded(it, &loaded, interfaceDecoder)
+		require.NoError(t, err)
+		assert.Equal(t, []codec.ProtoMarshaler{
+			&testdata.GroupInfo{Description: "my test 1"},
+			&testdata.GroupMember{Weight: 2},
+			&testdata.GroupInfo{Description: "my test 3"},
+		}, loaded)
+	})
+	t.Run("paginate into structs", func(t *testing.T) {
+		it, err := tb.PrefixScan(ctx, 1, 100)
+		require.NoError(t, err)
+		var page []groupView
+		res, err := orm.PaginateWithOpts(it, &query.PageRequest{Offset: 1, Limit: 1, CountTotal: true}, &page, orm.PaginateOpts{Decoder: viewDecoder})
+		require.NoError(t, err)
+		assert.Equal(t, []groupView{{2, "my test 2"}}, page)
+		assert.Equal(t, &query.PageResponse{NextKey: orm.EncodeSequence(3), Total: 3}, res)
+	})
+	t.Run("paginate into interfaces", func(t *testing.T) {
+		it, err := tb.PrefixScan(ctx, 1, 100)
+		require.NoError(t, err)
+		var page []interface{}
+		_, err = orm.PaginateWithOpts(it, &query.PageRequest{Limit: 2}, &page, orm.PaginateOpts{Decoder: interfaceDecoder})
+		require.NoError(t, err)
+		assert.Equal(t, []interface{}{&testdata.GroupInfo{Description: "my test 1"}, &testdata.GroupMember{Weight: 2}}, page)
+	})
+	t.Run("not assignable", func(t *testing.T) {
+		it, err := tb.PrefixScan(ctx, 1, 100)
+		require.NoError(t, err)
+		var loaded []string
+		_, err = orm.ReadAllDecoded(it, &loaded, viewDecoder)
+		assert.True(t, orm.ErrType.Is(err), err)
+	})
+	t.Run("decoder error", func(t *testing.T) {
+		it, err := tb.PrefixScan(ctx, 1, 100)
+		require.NoError(t, err)
+		var loaded []groupView
+		_, err = orm.ReadAllDecoded(it, &loaded, func([]byte, orm.RowID) (interface{}, error) { return nil, testdata.ErrTest })
+		assert.True(t, testdata.ErrTest.Is(err), err)
+	})
+	t.Run("no raw iterator", func(t *testing.T) {
+		it := mockIter(orm.EncodeSequence(1), &testdata.GroupInfo{Description: "my test"})
+		var loaded []groupView
+		_, err := orm.ReadAllDecoded(orm.FilterIterator(it, func(codec.ProtoMarshaler) bool { return true }), &loaded, viewDecoder)
+		assert.True(t, orm.ErrArgument.Is(err), err)
+	})
+}
+
+func TestReadAllRaw(t *testing.T) {
+	admin := sdk.AccAddress([]byte("admin-address"))
+	g1 := testdata.GroupInfo{Description: "my test 1", Admin: admin}
+	// all fields are set to their default values except the indexed one
+	g2 := testdata.GroupInfo{Admin: admin}
+	tb, idx, ctx := newTestTableWithRows(t, g1, g2)
+	bz1, err := g1.Marshal()
+	require.NoError(t, err)
+	bz2, err := g2.Marshal()
+	require.NoError(t, err)
+	// and a row with empty bytes
+	_, err = tb.Create(ctx, &testdata.GroupInfo{})
+	require.NoError(t, err)
+
+	t.Run("table", func(t *testing.T) {
+		it, err := tb.PrefixScan(ctx, 1, 100)
+		require.NoError(t, err)
+		rowIDs, values, err := orm.ReadAllRaw(it.(orm.RawIterator))
+		require.NoError(t, err)
+		assert.Equal(t, []orm.RowID{orm.EncodeSequence(1), orm.EncodeSequence(2), orm.EncodeSequence(3)}, rowIDs)
+		assert.Equal(t, [][]byte{bz1, bz2, {}}, values)
+		assert.NotNil(t, values[2])
+	})
+	t.Run("index", func(t *testing.T) {
+		it, err := idx.Get(ctx, admin)
+		require.NoError(t, err)
+		rowIDs, values, err := orm.ReadAllRaw(it.(orm.RawIterator))
+		require.NoError(t, err)
+		assert.Equal(t, []orm.RowID{orm.EncodeSequence(1), orm.EncodeSequence(2)}, rowIDs)
+		assert.Equal(t, [][]byte{bz1, bz2}, values)
+	})
+	t.Run("empty value by raw row getter", func(t *testing.T) {
+		getter := orm.NewRawRowGetter(testTableStoreKey, GroupTablePrefix)
+		bz, err := getter(ctx, orm.EncodeSequence(3))
+		require.NoError(t, err)
+		assert.Equal(t, []byte{}, bz)
+		assert.NotNil(t, bz)
+
+		_, err = getter(ctx, orm.EncodeSequence(4))
+		require.True(t, orm.ErrNotFound.Is(err), err)
+	})
+	t.Run("iterator is nil", func(t *testing.T) {
+		_, _, err := orm.ReadAllRaw(nil)
+		require.True(t, orm.ErrArgument.Is(err), err)
+	})
+}
+
+func BenchmarkPaginateOffset(b *testing.B) {
+	tb, _, ctx := newTestTableWithRows(b)
+
+	const numRows = 10000
+	for i := 0; i < numRows; i++ {
+		_, err := tb.Create(ctx, &testdata.GroupInfo{Description: "my test", Admin: sdk.AccAddress([]byte("admin-address"))})
+		require.NoError(b, err)
+	}
+	pageReq := &query.PageRequest{Offset: numRows - 100, Limit: 100}
+
+	b.Run("raw skip", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			it, err := tb.PrefixScan(ctx, 1, math.MaxUint64)
+			require.NoError(b, err)
+			var loaded []testdata.GroupInfo
+			_, err = orm.Paginate(it, pageReq, &loaded)
+			require.NoError(b, err)
+		}
+	})
+	b.Run("unmarshal skip", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			it, err := tb.PrefixScan(ctx, 1, math.MaxUint64)
+			require.NoError(b, err)
+			var loaded []testdata.GroupInfo
+			// hide the RawIterator implementation
+			_, err = orm.Paginate(orm.IteratorFunc(it.LoadNext), pageReq, &loaded)
+			require.NoError(b, err)
+			it.Close()
+		}
+	})
+	b.Run("unmarshal skip into pointers", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			it, err := tb.PrefixScan(ctx, 1, math.MaxUint64)
+			require.NoError(b, err)
+			var loaded []*testdata.GroupInfo
+			_, err = orm.Paginate(orm.IteratorFunc(it.LoadNext), pageReq, &loaded)
+			require.NoError(b, err)
+			it.Close()
+		}
+	})
 }
 
 // mockIter amino encodes + decodes value object.
@@ -261,3 +3234,243 @@ func noopIter() orm.Iterator {
 		return nil, nil
 	})
 }
+
+// groupInfoWrapper is a struct that implements codec.ProtoMarshaler only via its pointer type.
+type groupInfoWrapper struct {
+	testdata.GroupInfo
+}
+
+// closingIter calls the close function on Close.
+type closingIter struct {
+	orm.Iterator
+	close func() error
+}
+
+func (c closingIter) Close() error {
+	return c.close()
+}
+
+// closingRawIter is like closingIter for a RawIterator.
+type closingRawIter struct {
+	orm.RawIterator
+	close func() error
+}
+
+func (c closingRawIter) Close() error {
+	return c.close()
+}
+
+// testTableStoreKey is the store key of the tables created with newTestTableWithRows.
+var testTableStoreKey = sdk.NewKVStoreKey("test")
+
+const testTableCounterPrefix byte = 0x10
+
+// newTestTableWithRows returns a table of groups with a row counter and an index by admin and a new context
+// with the rows created in the given order, so that rows[n-1] has the RowID n. The tables of all calls are the
+// same, so that a table can be used with the context of another call.
+func newTestTableWithRows(t testing.TB, rows ...testdata.GroupInfo) (orm.AutoUInt64Table, orm.MultiKeyIndex, *orm.MockContext) {
+	tb, byAdmin, _, ctx := newTestTableWithIndexes(t, rows...)
+	return tb, byAdmin, ctx
+}
+
+// newTestTableWithIndexes returns the table of newTestTableWithRows with the index by description as well.
+func newTestTableWithIndexes(t testing.TB, rows ...testdata.GroupInfo) (orm.AutoUInt64Table, orm.MultiKeyIndex, orm.MultiKeyIndex, *orm.MockContext) {
+	interfaceRegistry := types.NewInterfaceRegistry()
+	cdc := codec.NewProtoCodec(interfaceRegistry)
+
+	tBuilder := orm.NewAutoUInt64TableBuilder(GroupTablePrefix, GroupTableSeqPrefix, testTableStoreKey, &testdata.GroupInfo{}, cdc)
+	tBuilder.WithRowCounter(testTableCounterPrefix)
+	byAdmin := orm.NewIndex(tBuilder, GroupByAdminIndexPrefix, func(val interface{}) ([]orm.RowID, error) {
+		return []orm.RowID{[]byte(val.(*testdata.GroupInfo).Admin)}, nil
+	})
+	byDescription := orm.NewIndex(tBuilder, GroupMemberByGroupIndexPrefix, func(val interface{}) ([]orm.RowID, error) {
+		return []orm.RowID{[]byte(val.(*testdata.GroupInfo).Description)}, nil
+	})
+	tb := tBuilder.Build()
+	ctx := orm.NewMockContext()
+
+	for _, g := range rows {
+		_, err := tb.Create(ctx, &g)
+		require.NoError(t, err)
+	}
+	return tb, byAdmin, byDescription, ctx
+}
+
+// testGroups returns n groups of the admin with the descriptions "my test 1" to "my test n".
+func testGroups(n int, admin sdk.AccAddress) []testdata.GroupInfo {
+	groups := make([]testdata.GroupInfo, n)
+	for i := range groups {
+		groups[i] = testdata.GroupInfo{Description: fmt.Sprintf("my test %d", i+1), Admin: admin}
+	}
+	return groups
+}
+
+func BenchmarkIntersectIterator(b *testing.B) {
+	tb, byAdmin, byDescription, ctx := newTestTableWithIndexes(b)
+
+	admin := sdk.AccAddress([]byte("admin-address"))
+	const numRows = 10000
+	for i := 0; i < numRows; i++ {
+		g := testdata.GroupInfo{Description: "other", Admin: sdk.AccAddress([]byte("other-admin-address"))}
+		if i%2 == 0 {
+			g.Admin = admin
+		}
+		if i%10 == 0 {
+			g.Description = "selected"
+		}
+		_, err := tb.Create(ctx, &g)
+		require.NoError(b, err)
+	}
+
+	b.Run("intersect", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			itA, err := byDescription.Get(ctx, []byte("selected"))
+			require.NoError(b, err)
+			itB, err := byAdmin.Get(ctx, admin)
+			require.NoError(b, err)
+			var loaded []testdata.GroupInfo
+			_, err = orm.ReadAll(orm.IntersectIterator(itA, itB), &loaded)
+			require.NoError(b, err)
+			require.Len(b, loaded, numRows/10)
+		}
+	})
+	b.Run("scan and filter", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			it, err := byAdmin.Get(ctx, admin)
+			require.NoError(b, err)
+			var loaded []testdata.GroupInfo
+			_, err = orm.ReadAll(orm.FilterIterator(it, func(m codec.ProtoMarshaler) bool {
+				return m.(*testdata.GroupInfo).Description == "selected"
+			}), &loaded)
+			require.NoError(b, err)
+			require.Len(b, loaded, numRows/10)
+		}
+	})
+}
+
+func TestIteratorCloseContract(t *testing.T) {
+	tb, idx, ctx := newTestTableWithRows(t)
+
+	admin := sdk.AccAddress([]byte("admin-address"))
+	_, err := tb.Create(ctx, &testdata.GroupInfo{Description: "my test", Admin: admin})
+	require.NoError(t, err)
+
+	sources := map[string]func() (orm.Iterator, error){
+		"table prefix scan":         func() (orm.Iterator, error) { return tb.PrefixScan(ctx, 1, 100) },
+		"table reverse prefix scan": func() (orm.Iterator, error) { return tb.ReversePrefixScan(ctx, 1, 100) },
+		"table scan with batch size": func() (orm.Iterator, error) {
+			return tb.PrefixScanWithOpts(ctx, 1, 100, orm.ScanOpts{BatchSize: 2})
+		},
+		"table snapshot scan": func() (orm.Iterator, error) {
+			return tb.PrefixScanWithOpts(ctx, 1, 100, orm.ScanOpts{Snapshot: true})
+		},
+		"index get": func() (orm.Iterator, error) { return idx.Get(ctx, admin) },
+		"kv store iterator": func() (orm.Iterator, error) {
+			return orm.FromKVStoreIterator(ctx.KVStore(testTableStoreKey).Iterator(nil, nil), 1), nil
+		},
+		"index reverse prefix scan": func() (orm.Iterator, error) { return idx.ReversePrefixScan(ctx, nil, nil) },
+		"single value": func() (orm.Iterator, error) {
+			return mockIter(orm.EncodeSequence(1), &testdata.GroupInfo{Description: "my test"}), nil
+		},
+		"invalid": func() (orm.Iterator, error) { return orm.NewInvalidIterator(), nil },
+		"slice": func() (orm.Iterator, error) {
+			return orm.NewSliceIterator([]orm.RowID{orm.EncodeSequence(1)}, [][]byte{{}}), nil
+		},
+	}
+	for msg, src := range sources {
+		t.Run(msg, func(t *testing.T) {
+			it, err := src()
+			require.NoError(t, err)
+			require.NoError(t, it.Close())
+			require.NoError(t, it.Close())
+
+			_, err = it.LoadNext(&testdata.GroupInfo{})
+			assert.True(t, orm.ErrIteratorClosed.Is(err), err)
+			if raw, ok := it.(orm.RawIterator); ok {
+				_, _, err = raw.RawNext()
+				assert.True(t, orm.ErrIteratorClosed.Is(err), err)
+			}
+			if _, ok := it.(orm.Rewinder); ok {
+				err = orm.Rewind(it)
+				assert.True(t, orm.ErrIteratorClosed.Is(err), err)
+			}
+		})
+	}
+	t.Run("index keys prefix scan", func(t *testing.T) {
+		it, err := idx.KeysPrefixScan(ctx, nil, nil)
+		require.NoError(t, err)
+		require.NoError(t, it.Close())
+		require.NoError(t, it.Close())
+
+		_, _, err = it.LoadNextKey()
+		assert.True(t, orm.ErrIteratorClosed.Is(err), err)
+	})
+
+	ascending := func(a, b orm.RowID) bool { return bytes.Compare(a, b) < 0 }
+	wrappers := map[string]struct {
+		parents int
+		wrap    func(parents ...orm.Iterator) orm.Iterator
+	}{
+		"limit": {parents: 1, wrap: func(p ...orm.Iterator) orm.Iterator { return orm.LimitIterator(p[0], 1) }},
+		"skip":  {parents: 1, wrap: func(p ...orm.Iterator) orm.Iterator { return orm.SkipIterator(p[0], 1) }},
+		"filter": {parents: 1, wrap: func(p ...orm.Iterator) orm.Iterator {
+			return orm.FilterIterator(p[0], func(codec.ProtoMarshaler) bool { return true })
+		}},
+		"map": {parents: 1, wrap: func(p ...orm.Iterator) orm.Iterator {
+			return orm.MapIterator(p[0], func(src codec.ProtoMarshaler, _ orm.RowID) (codec.ProtoMarshaler, error) {
+				return src, nil
+			}, func() codec.ProtoMarshaler { return &testdata.GroupInfo{} })
+		}},
+		"distinct": {parents: 1, wrap: func(p ...orm.Iterator) orm.Iterator { return orm.DistinctIterator(p[0], 0) }},
+		"peek":     {parents: 1, wrap: func(p ...orm.Iterator) orm.Iterator { return orm.PeekIterator(p[0]) }},
+		"ctx": {parents: 1, wrap: func(p ...orm.Iterator) orm.Iterator {
+			return orm.CtxIterator(context.Background(), p[0])
+		}},
+		"chain":     {parents: 3, wrap: orm.ChainIterator},
+		"merge":     {parents: 3, wrap: func(p ...orm.Iterator) orm.Iterator { return orm.MergeIterator(ascending, p...) }},
+		"union":     {parents: 2, wrap: func(p ...orm.Iterator) orm.Iterator { return orm.UnionIterator(p[0], p[1]) }},
+		"intersect": {parents: 2, wrap: func(p ...orm.Iterator) orm.Iterator { return orm.IntersectIterator(p[0], p[1]) }},
+		"take while": {parents: 1, wrap: func(p ...orm.Iterator) orm.Iterator {
+			return orm.TakeWhileIterator(p[0], func(codec.ProtoMarshaler, orm.RowID) bool { return true })
+		}},
+		"drop while": {parents: 1, wrap: func(p ...orm.Iterator) orm.Iterator {
+			return orm.DropWhileIterator(p[0], func(codec.ProtoMarshaler, orm.RowID) bool { return true })
+		}},
+		"instrumented": {parents: 1, wrap: func(p ...orm.Iterator) orm.Iterator {
+			return orm.InstrumentedIterator(p[0], nil, func(int) {})
+		}},
+		"join": {parents: 1, wrap: func(p ...orm.Iterator) orm.Iterator {
+			return orm.JoinIterator(ctx, p[0], tb.Table(), func(codec.ProtoMarshaler) orm.RowID { return nil }, orm.JoinOpts{})
+		}},
+		"transactional": {parents: 1, wrap: func(p ...orm.Iterator) orm.Iterator {
+			it, _ := orm.TransactionalIterator(ctx, tb.Table(), p[0])
+			return it
+		}},
+	}
+	for msg, spec := range wrappers {
+		t.Run(msg, func(t *testing.T) {
+			closed := make([]int, spec.parents)
+			parents := make([]orm.Iterator, spec.parents)
+			for i := range parents {
+				i := i
+				parents[i] = closingIter{Iterator: orm.NewInvalidIterator(), close: func() error {
+					closed[i]++
+					return orm.ErrIteratorInvalid
+				}}
+			}
+			it := spec.wrap(parents...)
+
+			err := it.Close()
+			assert.True(t, orm.ErrIteratorInvalid.Is(err), err)
+			require.NoError(t, it.Close())
+			for i, n := range closed {
+				assert.Equal(t, 1, n, "parent %d", i)
+			}
+
+			_, err = it.LoadNext(&testdata.GroupInfo{})
+			assert.True(t, orm.ErrIteratorClosed.Is(err), err)
+		})
+	}
+}
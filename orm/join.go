@@ -0,0 +1,104 @@
+package orm
+
+import (
+	"github.com/cosmos/cosmos-sdk/codec"
+	"github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+// JoinedModel is the destination model of a `JoinIterator`. It holds a child row and the parent row
+// that the child references.
+type JoinedModel interface {
+	codec.ProtoMarshaler
+	// JoinModels returns the models that the child and the parent row are loaded into.
+	// Both must not be nil.
+	JoinModels() (child codec.ProtoMarshaler, parent codec.ProtoMarshaler)
+}
+
+// JoinOpts are the options of a `JoinIterator`.
+type JoinOpts struct {
+	// SkipDangling skips the children that reference a parent that does not exist. By default an
+	// `ErrReferenceNotFound` error is returned for them.
+	SkipDangling bool
+}
+
+// JoinIterator returns a new iterator that loads the elements of the children iterator together with
+// the parent rows that they reference, so that a page of children can be rendered without a lookup per
+// element. The destination of LoadNext must be a `JoinedModel`: the child is loaded into its child model,
+// and the foreignKey function returns the RowID of the parent from it. The parent is loaded from the
+// parents table in the store of ctx into the parent model then. The RowID of the child is returned.
+//
+// A child with an empty foreign key or a foreign key of a parent that does not exist is a dangling
+// reference. An `ErrReferenceNotFound` error with both RowIDs is returned for it, unless dangling
+// references are skipped via `JoinOpts`.
+//
+// The iterator can be used with Paginate. The page keys are the RowIDs of the children, so the
+// children iterator of the next page must start at it, like a table scan.
+// The children iterator and foreignKey function must not be nil.
+func JoinIterator(ctx HasKVStore, children Iterator, parents Table, foreignKey func(child codec.ProtoMarshaler) RowID, opts JoinOpts) Iterator {
+	if children == nil {
+		panic("children iterator must not be nil")
+	}
+	if foreignKey == nil {
+		panic("foreign key function must not be nil")
+	}
+	return &joinIterator{ctx: ctx, parentIterator: children, parents: parents, foreignKey: foreignKey, opts: opts}
+}
+
+// joinIterator loads the parent row of every element of the parent iterator.
+type joinIterator struct {
+	ctx            HasKVStore
+	parentIterator Iterator
+	parents        Table
+	foreignKey     func(child codec.ProtoMarshaler) RowID
+	opts           JoinOpts
+	closed         bool
+}
+
+// LoadNext loads the next child and the parent that it references into the `JoinedModel` passed as
+// dest and returns the RowID of the child.
+// If there are no more items the `ErrIteratorDone` error is returned
+func (i *joinIterator) LoadNext(dest codec.ProtoMarshaler) (RowID, error) {
+	if i.closed {
+		return nil, ErrIteratorClosed
+	}
+	joined, ok := dest.(JoinedModel)
+	if !ok {
+		return nil, errors.Wrapf(ErrType, "%T does not implement JoinedModel", dest)
+	}
+	child, parent := joined.JoinModels()
+	if child == nil || parent == nil {
+		return nil, errors.Wrap(ErrArgument, "joined models must not be nil")
+	}
+	for {
+		// reset the models so that no data of a skipped element is merged into the next one
+		child.Reset()
+		parent.Reset()
+		rowID, err := i.parentIterator.LoadNext(child)
+		if err != nil {
+			return nil, err
+		}
+		parentRowID := i.foreignKey(child)
+		err = ErrNotFound
+		if len(parentRowID) != 0 {
+			err = i.parents.GetOne(i.ctx, parentRowID, parent)
+		}
+		switch {
+		case err == nil:
+			return rowID, nil
+		case !ErrNotFound.Is(err):
+			return nil, err
+		case !i.opts.SkipDangling:
+			return nil, errors.Wrapf(ErrReferenceNotFound, "child %x references parent %x", rowID, parentRowID)
+		}
+	}
+}
+
+// Close releases the iterator and should be called at the end of iteration.
+// Only the first call closes the children iterator.
+func (i *joinIterator) Close() error {
+	if i.closed {
+		return nil
+	}
+	i.closed = true
+	return i.parentIterator.Close()
+}
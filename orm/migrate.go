@@ -0,0 +1,79 @@
+package orm
+
+import (
+	"reflect"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	"github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+// MigrateFunc migrates a single row of a table in `MigrateInBatches`. The model is loaded from the store
+// and can be saved with the changes by the function.
+type MigrateFunc func(ctx HasKVStore, rowID RowID, model codec.ProtoMarshaler) error
+
+// MigrateInBatches calls fn for up to batchSize rows of the table that follow the resumeKey in ascending
+// order, so that a migration of a large table can be split over multiple invocations, like one per block,
+// with the progress persisted in between. The first invocation starts with an empty resumeKey and every
+// further one with the resumeKey returned by the previous one, until done is returned. The rows are read
+// with `Table.PrefixScanFrom`, so that no row is skipped or migrated twice.
+//
+// All rows of a batch are read before fn is called, so that fn is free to save, create or delete rows.
+// Rows created with a RowID after the returned resumeKey are passed to fn in a later batch.
+// An error of fn aborts the batch. The state written by the batch must be discarded then, as it is for a
+// failed transaction, and the migration continues with the previous resumeKey.
+// batchSize must be a positive number
+func MigrateInBatches(ctx HasKVStore, t TableExportable, resumeKey RowID, batchSize int, fn MigrateFunc) (next RowID, done bool, err error) {
+	if batchSize <= 0 {
+		return nil, false, errors.Wrap(ErrArgument, "batch size must be positive")
+	}
+	if fn == nil {
+		return nil, false, errors.Wrap(ErrArgument, "migrate function must not be nil")
+	}
+	table := t.Table()
+	rowIDs, models, done, err := readBatch(ctx, table, resumeKey, batchSize)
+	if err != nil {
+		return nil, false, err
+	}
+	for i, rowID := range rowIDs {
+		if err := fn(ctx, rowID, models[i]); err != nil {
+			return nil, false, errors.Wrapf(err, "migrate row %X", rowID)
+		}
+	}
+	if len(rowIDs) == 0 {
+		return resumeKey, true, nil
+	}
+	return rowIDs[len(rowIDs)-1], done, nil
+}
+
+// readBatch loads up to batchSize rows after the resumeKey and reports if there are no more rows after
+// them. The iterator is closed before the rows are returned.
+func readBatch(ctx HasKVStore, table Table, resumeKey RowID, batchSize int) ([]RowID, []codec.ProtoMarshaler, bool, error) {
+	it, err := table.PrefixScanFrom(ctx, resumeKey)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	defer it.Close()
+
+	var rowIDs []RowID
+	var models []codec.ProtoMarshaler
+	for len(rowIDs) < batchSize {
+		model := reflect.New(table.model).Interface().(codec.ProtoMarshaler)
+		rowID, err := it.LoadNext(model)
+		if IsIteratorDone(err) {
+			return rowIDs, models, true, nil
+		}
+		if err != nil {
+			return nil, nil, false, err
+		}
+		rowIDs = append(rowIDs, rowID)
+		models = append(models, model)
+	}
+	_, err = nextRowID(it, reflect.New(table.model).Interface().(codec.ProtoMarshaler))
+	switch {
+	case IsIteratorDone(err):
+		return rowIDs, models, true, nil
+	case err != nil:
+		return nil, nil, false, err
+	}
+	return rowIDs, models, false, nil
+}
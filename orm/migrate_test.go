@@ -0,0 +1,94 @@
+package orm_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	"github.com/cosmos/cosmos-sdk/codec/types"
+	"github.com/cosmos/cosmos-sdk/store"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tendermint/tendermint/libs/log"
+	tmproto "github.com/tendermint/tendermint/proto/tendermint/types"
+	dbm "github.com/tendermint/tm-db"
+
+	"github.com/regen-network/regen-ledger/orm"
+	"github.com/regen-network/regen-ledger/testutil/testdata"
+)
+
+func TestMigrateInBatches(t *testing.T) {
+	interfaceRegistry := types.NewInterfaceRegistry()
+	cdc := codec.NewProtoCodec(interfaceRegistry)
+
+	storeKey := sdk.NewKVStoreKey("test")
+	const (
+		testTablePrefix = iota
+		testTableSeqPrefix
+	)
+	db := dbm.NewMemDB()
+	cms := store.NewCommitMultiStore(db)
+	cms.MountStoreWithDB(storeKey, sdk.StoreTypeIAVL, db)
+	require.NoError(t, cms.LoadLatestVersion())
+	ctx := sdk.NewContext(cms, tmproto.Header{}, false, log.NewNopLogger())
+
+	tb := orm.NewAutoUInt64TableBuilder(testTablePrefix, testTableSeqPrefix, storeKey, &testdata.GroupInfo{}, cdc).Build()
+	for i := 1; i <= 5; i++ {
+		_, err := tb.Create(ctx, &testdata.GroupInfo{Description: fmt.Sprintf("my test %d", i)})
+		require.NoError(t, err)
+	}
+
+	var migrated []orm.RowID
+	migrate := func(failAt uint64) orm.MigrateFunc {
+		return func(ctx orm.HasKVStore, rowID orm.RowID, model codec.ProtoMarshaler) error {
+			if orm.DecodeSequence(rowID) == failAt {
+				return testdata.ErrTest
+			}
+			g := model.(*testdata.GroupInfo)
+			g.Description += " migrated"
+			migrated = append(migrated, rowID)
+			return tb.Save(ctx, orm.DecodeSequence(rowID), g)
+		}
+	}
+
+	// the first block migrates the first batch
+	resumeKey, done, err := orm.MigrateInBatches(ctx, tb, nil, 2, migrate(0))
+	require.NoError(t, err)
+	assert.False(t, done)
+	assert.Equal(t, orm.RowID(orm.EncodeSequence(2)), resumeKey)
+
+	// the second block is interrupted in the middle of the batch and its state discarded
+	cacheCtx, _ := ctx.CacheContext()
+	_, _, err = orm.MigrateInBatches(cacheCtx, tb, resumeKey, 2, migrate(4))
+	assert.True(t, testdata.ErrTest.Is(err), err)
+	migrated = migrated[:2]
+
+	// the following blocks resume with the persisted key
+	for !done {
+		resumeKey, done, err = orm.MigrateInBatches(ctx, tb, resumeKey, 2, migrate(0))
+		require.NoError(t, err)
+	}
+	assert.Equal(t, orm.RowID(orm.EncodeSequence(5)), resumeKey)
+
+	// every row is migrated exactly once
+	exp := []orm.RowID{orm.EncodeSequence(1), orm.EncodeSequence(2), orm.EncodeSequence(3), orm.EncodeSequence(4), orm.EncodeSequence(5)}
+	assert.Equal(t, exp, migrated)
+	it, err := tb.PrefixScan(ctx, 1, 100)
+	require.NoError(t, err)
+	var loaded []testdata.GroupInfo
+	_, err = orm.ReadAll(it, &loaded)
+	require.NoError(t, err)
+	for i, g := range loaded {
+		assert.Equal(t, fmt.Sprintf("my test %d migrated", i+1), g.Description)
+	}
+
+	// a migration that is done already returns done again
+	next, done, err := orm.MigrateInBatches(ctx, tb, resumeKey, 2, migrate(0))
+	require.NoError(t, err)
+	assert.True(t, done)
+	assert.Equal(t, resumeKey, next)
+
+	_, _, err = orm.MigrateInBatches(ctx, tb, nil, 0, migrate(0))
+	assert.True(t, orm.ErrArgument.Is(err), err)
+}
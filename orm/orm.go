@@ -4,6 +4,7 @@ Package orm is a convenient object to data store mapper.
 package orm
 
 import (
+	stdErrors "errors"
 	"io"
 	"reflect"
 
@@ -16,21 +17,39 @@ import (
 
 const ormCodespace = "orm"
 
+// The errors can be checked with `errors.Is` and `errors.As` of the standard library, also when they are
+// wrapped, for example with `errors.Wrap` or `fmt.Errorf` and the `%w` verb.
 var (
 	ErrNotFound          = errors.Register(ormCodespace, 100, "not found")
 	ErrIteratorDone      = errors.Register(ormCodespace, 101, "iterator done")
 	ErrIteratorInvalid   = errors.Register(ormCodespace, 102, "iterator invalid")
+	ErrIteratorClosed    = errors.Register(ormCodespace, 103, "iterator closed")
 	ErrType              = errors.Register(ormCodespace, 110, "invalid type")
 	ErrUniqueConstraint  = errors.Register(ormCodespace, 111, "unique constraint violation")
 	ErrArgument          = errors.Register(ormCodespace, 112, "invalid argument")
 	ErrIndexKeyMaxLength = errors.Register(ormCodespace, 113, "index key exceeds max length")
+	ErrLimit             = errors.Register(ormCodespace, 114, "limit exceeded")
+	ErrReferenceNotFound = errors.Register(ormCodespace, 115, "reference not found")
+	ErrReadOnly          = errors.Register(ormCodespace, 116, "read only")
 )
 
+// IsIteratorDone returns true when err is or wraps `ErrIteratorDone`, that is returned by an Iterator
+// without more elements. Unlike `ErrIteratorDone.Is` it also follows the errors wrapped with `fmt.Errorf`.
+func IsIteratorDone(err error) bool {
+	return stdErrors.Is(err, ErrIteratorDone)
+}
+
 // HasKVStore is a subset of the cosmos-sdk context defined for loose coupling and simpler test setups.
 type HasKVStore interface {
 	KVStore(key sdk.StoreKey) sdk.KVStore
 }
 
+// HasSDKContext is implemented by the contexts that wrap a cosmos-sdk context, so that the table hooks
+// can run with them, see `TableBuilder.OnCreate`.
+type HasSDKContext interface {
+	SDKContext() sdk.Context
+}
+
 // Unique identifier of a persistent table.
 type RowID []byte
 
@@ -71,10 +90,18 @@ type Index interface {
 	// searchKey must not be nil.
 	Get(ctx HasKVStore, searchKey []byte) (Iterator, error)
 
+	// ReverseGet returns a result iterator for the searchKey in descending order.
+	// searchKey must not be nil.
+	ReverseGet(ctx HasKVStore, searchKey []byte) (Iterator, error)
+
 	// GetPaginated returns a result iterator for the searchKey and optional pageRequest.
 	// searchKey must not be nil.
 	GetPaginated(ctx HasKVStore, searchKey []byte, pageRequest *query.PageRequest) (Iterator, error)
 
+	// ReverseGetPaginated returns a result iterator in descending order for the searchKey and optional pageRequest.
+	// searchKey must not be nil.
+	ReverseGetPaginated(ctx HasKVStore, searchKey []byte, pageRequest *query.PageRequest) (Iterator, error)
+
 	// PrefixScan returns an Iterator over a domain of keys in ascending order. End is exclusive.
 	// Start is an MultiKeyIndex key or prefix. It must be less than end, or the Iterator is invalid and error is returned.
 	// Iterator must be closed by caller.
@@ -106,6 +133,9 @@ type Index interface {
 }
 
 // Iterator allows iteration through a sequence of key value pairs
+//
+// CONTRACT: Close is safe to be called multiple times. Only the first call releases the iterator and
+// any parent iterators, further calls return nil. LoadNext returns `ErrIteratorClosed` after Close.
 type Iterator interface {
 	// LoadNext loads the next value in the sequence into the pointer passed as dest and returns the key. If there
 	// are no more items the ErrIteratorDone error is returned
@@ -115,6 +145,55 @@ type Iterator interface {
 	io.Closer
 }
 
+// RawIterator is an Iterator that can also return the persisted bytes without unmarshaling them.
+// The table and index iterators implement it so that elements can be skipped cheaply.
+type RawIterator interface {
+	Iterator
+	// RawNext returns the key and the persisted value bytes of the next element in the sequence.
+	// The value of an element persisted with empty bytes is an empty slice and never nil.
+	// If there are no more items the ErrIteratorDone error is returned
+	// The key is the rowID and not any MultiKeyIndex key.
+	RawNext() (RowID, []byte, error)
+}
+
+// Positioner is implemented by iterators that can report their position, like the table and index iterators,
+// so that a long running process, like a store migration, can continue an iteration later. See
+// `Table.PrefixScanFrom` and `MigrateInBatches`.
+type Positioner interface {
+	// Position returns the store key of the last element that was returned, which is the RowID for the
+	// table iterators and the persisted index key for the index iterators. It is nil before the first element.
+	Position() []byte
+}
+
+// RowIDPeeker is implemented by iterators that can return the RowID of the next element without consuming
+// or reading it, like the table and index iterators, so that callers that only need the keys, like for
+// existence checks or joins, do not pay for the unmarshaling of the value. See `PeekRowID`.
+type RowIDPeeker interface {
+	// PeekRowID returns the RowID of the next element, so that the same element is returned by the next
+	// call of PeekRowID or LoadNext. If there are no more items the ErrIteratorDone error is returned
+	// After Close the ErrIteratorClosed error is returned
+	PeekRowID() (RowID, error)
+}
+
+// Rewinder is implemented by iterators that can be reset to the start of their domain, like the table and
+// index iterators, so that two-pass algorithms do not have to create the scan again. See `Rewind`.
+type Rewinder interface {
+	// Rewind resets the iterator to the first element of the domain that it was created for. The elements
+	// are read from the store again, so that writes since the creation are visible.
+	// After Close the ErrIteratorClosed error is returned
+	Rewind() error
+}
+
+// IndexKeyIterator allows iteration through the keys of a MultiKeyIndex without loading the table rows.
+// It follows the same Close contract as the Iterator.
+type IndexKeyIterator interface {
+	// LoadNextKey returns the persisted index key and the RowID of the next element in the sequence.
+	// If there are no more items the ErrIteratorDone error is returned
+	LoadNextKey() (indexKey []byte, rowID RowID, err error)
+	// Close releases the iterator and should be called at the end of iteration
+	io.Closer
+}
+
 // IndexKeyCodec defines the encoding/ decoding methods for building/ splitting index keys.
 type IndexKeyCodec interface {
 	// BuildIndexKey encodes a searchable key and the target RowID.
@@ -129,6 +208,7 @@ type IndexKeyCodec interface {
 type Indexable interface {
 	StoreKey() sdk.StoreKey
 	RowGetter() RowGetter
+	RawRowGetter() RawRowGetter
 	IndexKeyCodec() IndexKeyCodec
 	AddAfterSaveInterceptor(interceptor AfterSaveInterceptor)
 	AddAfterDeleteInterceptor(interceptor AfterDeleteInterceptor)
@@ -140,6 +220,12 @@ type AfterSaveInterceptor func(ctx HasKVStore, rowID RowID, newValue, oldValue c
 // AfterDeleteInterceptor defines a callback function to be called on Delete operations.
 type AfterDeleteInterceptor func(ctx HasKVStore, rowID RowID, value codec.ProtoMarshaler) error
 
+// OnCreateHook defines a callback function to be called before an object is created, see `TableBuilder.OnCreate`.
+type OnCreateHook func(ctx sdk.Context, model codec.ProtoMarshaler, rowID RowID) error
+
+// OnDeleteHook defines a callback function to be called before an object is deleted, see `TableBuilder.OnDelete`.
+type OnDeleteHook func(ctx sdk.Context, rowID RowID) error
+
 // RowGetter loads a persistent object by row ID into the destination object. The dest parameter must therefore be a pointer.
 // Any implementation must return `ErrNotFound` when no object for the rowID exists
 type RowGetter func(ctx HasKVStore, rowID RowID, dest codec.ProtoMarshaler) error
@@ -164,6 +250,29 @@ func NewTypeSafeRowGetter(storeKey sdk.StoreKey, prefixKey byte, model reflect.T
 	}
 }
 
+// RawRowGetter loads the persisted bytes of an object by row ID.
+// Any implementation must return `ErrNotFound` when no object for the rowID exists and an empty
+// slice, not nil, for an object persisted with empty bytes.
+type RawRowGetter func(ctx HasKVStore, rowID RowID) ([]byte, error)
+
+// NewRawRowGetter returns a `RawRowGetter` for the table data stored under the prefixKey.
+func NewRawRowGetter(storeKey sdk.StoreKey, prefixKey byte) RawRowGetter {
+	return func(ctx HasKVStore, rowID RowID) ([]byte, error) {
+		if len(rowID) == 0 {
+			return nil, errors.Wrap(ErrArgument, "key must not be nil")
+		}
+		store := prefix.NewStore(ctx.KVStore(storeKey), []byte{prefixKey})
+		bz := store.Get(rowID)
+		if bz == nil {
+			if !store.Has(rowID) {
+				return nil, ErrNotFound
+			}
+			bz = []byte{}
+		}
+		return bz, nil
+	}
+}
+
 func assertCorrectType(model reflect.Type, obj codec.ProtoMarshaler) error {
 	tp := reflect.TypeOf(obj)
 	if tp.Kind() != reflect.Ptr {
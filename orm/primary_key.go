@@ -3,6 +3,7 @@ package orm
 import (
 	"github.com/cosmos/cosmos-sdk/codec"
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/errors"
 )
 
 var _ Indexable = &PrimaryKeyTableBuilder{}
@@ -16,10 +17,26 @@ func NewPrimaryKeyTableBuilder(prefixData byte, storeKey sdk.StoreKey, model Pri
 
 type PrimaryKeyTableBuilder struct {
 	*TableBuilder
+	composite *CompositeRowID
+}
+
+// WithCompositeRowID makes the table validate that the primary keys of new objects are encoded with the
+// given `CompositeRowID`. The RowIDs of a composite have different lengths, so that the table must use an
+// `IndexKeyCodec` for dynamic lengths, like `Max255DynamicLengthIndexKeyCodec`. Otherwise the RowIDs could
+// not be told apart in the index keys and it panics.
+func (a *PrimaryKeyTableBuilder) WithCompositeRowID(c CompositeRowID) {
+	if c.parts <= 0 {
+		panic("composite RowID must be created with NewCompositeRowID")
+	}
+	switch a.indexKeyCodec.(type) {
+	case FixLengthIndexKeyCodec, *FixLengthIndexKeyCodec:
+		panic("composite RowIDs require an IndexKeyCodec for dynamic lengths")
+	}
+	a.composite = &c
 }
 
 func (a PrimaryKeyTableBuilder) Build() PrimaryKeyTable {
-	return PrimaryKeyTable{table: a.TableBuilder.Build()}
+	return PrimaryKeyTable{table: a.TableBuilder.Build(), composite: a.composite}
 
 }
 
@@ -39,20 +56,59 @@ var _ TableExportable = &PrimaryKeyTable{}
 // PrimaryKeyTable provides simpler object style orm methods without passing database RowIDs.
 // Entries are persisted and loaded with a reference to their unique primary key.
 type PrimaryKeyTable struct {
-	table Table
+	table     Table
+	composite *CompositeRowID
 }
 
 // Create persists the given object under their primary key. It checks if the
 // key already exists and may return an `ErrUniqueConstraint`.
 // Create iterates though the registered callbacks and may add secondary index keys by them.
+// With a `CompositeRowID` an `ErrArgument` error is returned for a primary key that it did not encode.
 func (a PrimaryKeyTable) Create(ctx HasKVStore, obj PrimaryKeyed) error {
 	rowID := obj.PrimaryKey()
+	if err := a.assertPrimaryKey(rowID); err != nil {
+		return err
+	}
 	if a.table.Has(ctx, rowID) {
 		return ErrUniqueConstraint
 	}
 	return a.table.Create(ctx, rowID, obj)
 }
 
+// BulkCreate persists the given objects under their primary keys like Create and returns the keys, for
+// example for a genesis import of many rows. It returns an `ErrUniqueConstraint` when a key exists already
+// or more than once within the objects, before any object is persisted. See `Table.BulkCreate`.
+func (a PrimaryKeyTable) BulkCreate(ctx HasKVStore, objs []PrimaryKeyed) ([]RowID, error) {
+	rowIDs := make([]RowID, len(objs))
+	models := make([]codec.ProtoMarshaler, len(objs))
+	seen := make(map[string]struct{}, len(objs))
+	for i, obj := range objs {
+		rowID := obj.PrimaryKey()
+		if err := a.assertPrimaryKey(rowID); err != nil {
+			return nil, errors.Wrapf(err, "object %d", i)
+		}
+		if _, ok := seen[string(rowID)]; ok || a.table.Has(ctx, rowID) {
+			return nil, errors.Wrapf(ErrUniqueConstraint, "object %d", i)
+		}
+		seen[string(rowID)] = struct{}{}
+		rowIDs[i] = rowID
+		models[i] = obj
+	}
+	if err := a.table.BulkCreate(ctx, rowIDs, models); err != nil {
+		return nil, err
+	}
+	return rowIDs, nil
+}
+
+// assertPrimaryKey checks that the primary key is encoded with the `CompositeRowID` of the table, if any.
+func (a PrimaryKeyTable) assertPrimaryKey(rowID RowID) error {
+	if a.composite == nil {
+		return nil
+	}
+	_, err := a.composite.Decode(rowID)
+	return err
+}
+
 // Save updates the given object under the primary key. It expects the key to exists already
 // and fails with an `ErrNotFound` otherwise. Any caller must therefore make sure that this contract
 // is fulfilled. Parameters must not be nil.
@@ -84,12 +140,33 @@ func (a PrimaryKeyTable) Contains(ctx HasKVStore, obj PrimaryKeyed) bool {
 	return a.table.Has(ctx, obj.PrimaryKey())
 }
 
+// Count returns the number of rows in the table. See `Table.Count`.
+func (a PrimaryKeyTable) Count(ctx HasKVStore) (uint64, error) {
+	return a.table.Count(ctx)
+}
+
+// Stats returns the stats of the table. See `Table.Stats`.
+func (a PrimaryKeyTable) Stats(ctx HasKVStore) (TableStats, error) {
+	return a.table.Stats(ctx)
+}
+
+// StatsWithOpts returns the stats of the table with the given options. See `Table.StatsWithOpts`.
+func (a PrimaryKeyTable) StatsWithOpts(ctx HasKVStore, opts StatsOpts) (TableStats, error) {
+	return a.table.StatsWithOpts(ctx, opts)
+}
+
 // GetOne load the object persisted for the given primary Key into the dest parameter.
 // If none exists `ErrNotFound` is returned instead. Parameters must not be nil.
 func (a PrimaryKeyTable) GetOne(ctx HasKVStore, primKey RowID, dest codec.ProtoMarshaler) error {
 	return a.table.GetOne(ctx, primKey, dest)
 }
 
+// MultiRead loads the objects persisted for the given primary keys into a new slice at the passed ModelSlicePtr.
+// Primary keys without an object are skipped and returned instead. See `Table.MultiRead`.
+func (a PrimaryKeyTable) MultiRead(ctx HasKVStore, primaryKeys []RowID, dest ModelSlicePtr) ([]RowID, error) {
+	return a.table.MultiRead(ctx, primaryKeys, dest)
+}
+
 // PrefixScan returns an Iterator over a domain of keys in ascending order. End is exclusive.
 // Start is an MultiKeyIndex key or prefix. It must be less than end, or the Iterator is invalid and error is returned.
 // Iterator must be closed by caller.
@@ -110,6 +187,11 @@ func (a PrimaryKeyTable) PrefixScan(ctx HasKVStore, start, end []byte) (Iterator
 	return a.table.PrefixScan(ctx, start, end)
 }
 
+// PrefixScanWithOpts returns an Iterator like PrefixScan with the given options. See `Table.PrefixScanWithOpts`.
+func (a PrimaryKeyTable) PrefixScanWithOpts(ctx HasKVStore, start, end []byte, opts ScanOpts) (Iterator, error) {
+	return a.table.PrefixScanWithOpts(ctx, start, end, opts)
+}
+
 // ReversePrefixScan returns an Iterator over a domain of keys in descending order. End is exclusive.
 // Start is an MultiKeyIndex key or prefix. It must be less than end, or the Iterator is invalid  and error is returned.
 // Iterator must be closed by caller.
@@ -123,6 +205,12 @@ func (a PrimaryKeyTable) ReversePrefixScan(ctx HasKVStore, start, end []byte) (I
 	return a.table.ReversePrefixScan(ctx, start, end)
 }
 
+// ReversePrefixScanWithOpts returns an Iterator like ReversePrefixScan with the given options.
+// See `Table.PrefixScanWithOpts`.
+func (a PrimaryKeyTable) ReversePrefixScanWithOpts(ctx HasKVStore, start, end []byte, opts ScanOpts) (Iterator, error) {
+	return a.table.ReversePrefixScanWithOpts(ctx, start, end, opts)
+}
+
 // Table satisfies the TableExportable interface and must not be used otherwise.
 func (a PrimaryKeyTable) Table() Table {
 	return a.table
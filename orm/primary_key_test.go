@@ -278,3 +278,48 @@ func TestContains(t *testing.T) {
 type mockPrimaryKeyed struct {
 	*testdata.GroupMember
 }
+
+func TestPrimaryKeyTableBulkCreate(t *testing.T) {
+	interfaceRegistry := types.NewInterfaceRegistry()
+	cdc := codec.NewProtoCodec(interfaceRegistry)
+
+	storeKey := sdk.NewKVStoreKey("test")
+	const testTablePrefix = iota
+
+	tb := orm.NewPrimaryKeyTableBuilder(testTablePrefix, storeKey, &testdata.GroupMember{}, orm.Max255DynamicLengthIndexKeyCodec{}, cdc).
+		Build()
+
+	ctx := orm.NewMockContext()
+
+	m1 := testdata.GroupMember{Group: []byte("group-a"), Member: []byte("member-one"), Weight: 1}
+	m2 := testdata.GroupMember{Group: []byte("group-a"), Member: []byte("member-two"), Weight: 1}
+	m3 := testdata.GroupMember{Group: []byte("group-b"), Member: []byte("member-two"), Weight: 1}
+	require.NoError(t, tb.Create(ctx, &m1))
+
+	rowIDs, err := tb.BulkCreate(ctx, []orm.PrimaryKeyed{&m2, &m3})
+	require.NoError(t, err)
+	assert.Equal(t, []orm.RowID{m2.PrimaryKey(), m3.PrimaryKey()}, rowIDs)
+	assert.True(t, tb.Contains(ctx, &m3))
+
+	specs := map[string]struct {
+		src []orm.PrimaryKeyed
+	}{
+		"existing key": {
+			src: []orm.PrimaryKeyed{&testdata.GroupMember{Group: []byte("group-c"), Member: []byte("member-one"), Weight: 1}, &m1},
+		},
+		"duplicate key in batch": {
+			src: []orm.PrimaryKeyed{
+				&testdata.GroupMember{Group: []byte("group-c"), Member: []byte("member-one"), Weight: 1},
+				&testdata.GroupMember{Group: []byte("group-c"), Member: []byte("member-one"), Weight: 2},
+			},
+		},
+	}
+	for msg, spec := range specs {
+		t.Run(msg, func(t *testing.T) {
+			_, err := tb.BulkCreate(ctx, spec.src)
+			assert.True(t, orm.ErrUniqueConstraint.Is(err))
+			// nothing is persisted
+			assert.False(t, tb.Contains(ctx, spec.src[0]))
+		})
+	}
+}
@@ -34,6 +34,14 @@ func (s Sequence) NextVal(ctx HasKVStore) uint64 {
 	return seq
 }
 
+// nextVals increments and persists the counter by n and returns the first of the n values.
+func (s Sequence) nextVals(ctx HasKVStore, n uint64) uint64 {
+	store := prefix.NewStore(ctx.KVStore(s.storeKey), []byte{s.prefix})
+	seq := DecodeSequence(store.Get(sequenceStorageKey))
+	store.Set(sequenceStorageKey, EncodeSequence(seq+n))
+	return seq + 1
+}
+
 // CurVal returns the last value used. 0 if none.
 func (s Sequence) CurVal(ctx HasKVStore) uint64 {
 	store := prefix.NewStore(ctx.KVStore(s.storeKey), []byte{s.prefix})
@@ -63,6 +71,39 @@ func (s Sequence) InitVal(ctx HasKVStore, seq uint64) error {
 	return nil
 }
 
+// scopedSequenceKeyPrefix is the prefix of the sequences per key of an `AutoUInt64Table`, see `SequenceKeyFunc`.
+const scopedSequenceKeyPrefix = 0x2
+
+// nextScopedVal increments and persists the counter of the sequence key by one and returns the value.
+func (s Sequence) nextScopedVal(ctx HasKVStore, seqKey []byte) uint64 {
+	store := prefix.NewStore(ctx.KVStore(s.storeKey), []byte{s.prefix, scopedSequenceKeyPrefix})
+	seq := DecodeSequence(store.Get(seqKey)) + 1
+	store.Set(seqKey, EncodeSequence(seq))
+	return seq
+}
+
+// raiseScopedVal sets the counter of the sequence key to the given value when it is lower.
+func (s Sequence) raiseScopedVal(ctx HasKVStore, seqKey []byte, seq uint64) {
+	store := prefix.NewStore(ctx.KVStore(s.storeKey), []byte{s.prefix, scopedSequenceKeyPrefix})
+	if DecodeSequence(store.Get(seqKey)) < seq {
+		store.Set(seqKey, EncodeSequence(seq))
+	}
+}
+
+// clearScopedVals deletes the counters of all sequence keys.
+func (s Sequence) clearScopedVals(ctx HasKVStore) {
+	store := prefix.NewStore(ctx.KVStore(s.storeKey), []byte{s.prefix, scopedSequenceKeyPrefix})
+	it := store.Iterator(nil, nil)
+	var keys [][]byte
+	for ; it.Valid(); it.Next() {
+		keys = append(keys, it.Key())
+	}
+	it.Close()
+	for _, key := range keys {
+		store.Delete(key)
+	}
+}
+
 // DecodeSequence converts the binary representation into an Uint64 value.
 func DecodeSequence(bz []byte) uint64 {
 	if bz == nil {
@@ -0,0 +1,59 @@
+package orm
+
+// TableStats are runtime statistics of a table, for example to export metrics or for debugging.
+type TableStats struct {
+	// RowCount is the number of rows in the table. See `Table.Count`.
+	RowCount uint64
+	// IndexCount is the number of indexes of the table.
+	IndexCount uint64
+	// StorageBytes is the size of the keys and values of the table rows, the index entries and the
+	// row counter in the store. It is only set when requested via `StatsOpts`.
+	StorageBytes uint64
+}
+
+// StatsOpts are the options of `Table.StatsWithOpts`.
+type StatsOpts struct {
+	// StorageBytes enables the computation of `TableStats.StorageBytes`.
+	StorageBytes bool
+}
+
+// Stats returns the row and index count of the table. The row count is read from the row counter
+// when one is enabled via `TableBuilder.WithRowCounter`.
+//
+// WARNING: Without a row counter, Stats iterates all rows and can be very expensive in terms of Gas.
+func (a Table) Stats(ctx HasKVStore) (TableStats, error) {
+	return a.StatsWithOpts(ctx, StatsOpts{})
+}
+
+// StatsWithOpts returns the stats of the table like Stats with the given options.
+//
+// WARNING: The computation of the storage bytes iterates all rows and index entries.
+func (a Table) StatsWithOpts(ctx HasKVStore, opts StatsOpts) (TableStats, error) {
+	n, err := a.Count(ctx)
+	if err != nil {
+		return TableStats{}, err
+	}
+	stats := TableStats{RowCount: n, IndexCount: uint64(len(a.indexes))}
+	if !opts.StorageBytes {
+		return stats, nil
+	}
+	prefixes := append([]byte{a.prefix}, a.indexes...)
+	if a.counter != nil {
+		prefixes = append(prefixes, a.counter.prefix)
+	}
+	for _, p := range prefixes {
+		stats.StorageBytes += storageBytes(ctx, a, p)
+	}
+	return stats, nil
+}
+
+// storageBytes sums up the size of the keys and values stored under the prefix.
+func storageBytes(ctx HasKVStore, table Table, prefix byte) uint64 {
+	it := ctx.KVStore(table.storeKey).Iterator(PrefixRange([]byte{prefix}))
+	defer it.Close()
+	var n uint64
+	for ; it.Valid(); it.Next() {
+		n += uint64(len(it.Key()) + len(it.Value()))
+	}
+	return n
+}
@@ -0,0 +1,162 @@
+package orm
+
+import (
+	"github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/cosmos/cosmos-sdk/types/query"
+)
+
+// stringIndexKey encodes the string components as index key of a `StringIndex`, that is the `CompositeKey`
+// of their `StringField`s.
+func stringIndexKey(components []string) []byte {
+	fields := make([]KeyField, len(components))
+	for i, c := range components {
+		fields[i] = StringField(c)
+	}
+	return CompositeKey(fields...)
+}
+
+// decodeStringIndexKey returns the components of a key created with `stringIndexKey`.
+// An `ErrArgument` error is returned for keys that are not a sequence of terminated `StringField`s.
+func decodeStringIndexKey(key []byte) ([]string, error) {
+	var components []string
+	var component []byte
+	for i := 0; i < len(key); i++ {
+		if key[i] != 0x00 {
+			component = append(component, key[i])
+			continue
+		}
+		if i++; i == len(key) {
+			return nil, errors.Wrap(ErrArgument, "key without terminated component")
+		}
+		switch key[i] {
+		case 0xff:
+			component = append(component, 0x00)
+		case 0x00:
+			components = append(components, string(component))
+			component = nil
+		default:
+			return nil, errors.Wrapf(ErrArgument, "invalid escape byte %X at %d", key[i], i)
+		}
+	}
+	if len(components) == 0 || component != nil {
+		return nil, errors.Wrap(ErrArgument, "key without terminated component")
+	}
+	return components, nil
+}
+
+// StringPrefixRange returns the start and the exclusive end of a scan over all keys whose leading
+// components are exactly the given ones, for example for all keys with an exact match on the first
+// component. At least one component is required.
+func StringPrefixRange(leading ...string) ([]byte, []byte, error) {
+	if len(leading) == 0 {
+		return nil, nil, errors.Wrap(ErrArgument, "components must not be empty")
+	}
+	start, end := PrefixRange(stringIndexKey(leading))
+	return start, end, nil
+}
+
+// DecodeStringCursor returns the components and the RowID of a page key that was returned as NextKey
+// for a paginated `StringIndex` query.
+func DecodeStringCursor(cursor []byte) ([]string, RowID, error) {
+	indexKey, rowID, err := DecodeCursor(cursor)
+	if err != nil {
+		return nil, nil, err
+	}
+	components, err := decodeStringIndexKey(indexKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	return components, rowID, nil
+}
+
+// StringIndexerFunc creates one or multiple multiKeyIndex keys for the source object. Every key is the
+// list of its string components. All keys of an index should have the same number of components.
+type StringIndexerFunc func(value interface{}) ([][]string, error)
+
+// StringMultiKeyAdapter converts StringIndexerFunc to IndexerFunc
+func StringMultiKeyAdapter(indexer StringIndexerFunc) IndexerFunc {
+	return func(value interface{}) ([]RowID, error) {
+		d, err := indexer(value)
+		if err != nil {
+			return nil, err
+		}
+		r := make([]RowID, len(d))
+		for i, v := range d {
+			r[i] = stringIndexKey(v)
+		}
+		return r, nil
+	}
+}
+
+// StringIndex is a typed index with keys of string components, like a denom or a class ID followed by
+// a project name. The keys are encoded like the ones of a `CompositeIndex` of `StringField`s, so that the
+// boundaries of the components are never ambiguous and the byte order of the keys matches the order of
+// their components as Go strings. The components can contain any bytes.
+type StringIndex struct {
+	multiKeyIndex MultiKeyIndex
+}
+
+// NewStringIndex creates a typed secondary index
+func NewStringIndex(builder Indexable, prefix byte, indexer StringIndexerFunc) StringIndex {
+	return StringIndex{
+		multiKeyIndex: NewIndex(builder, prefix, StringMultiKeyAdapter(indexer)),
+	}
+}
+
+// Has checks if a key with the given leading components exists.
+func (i StringIndex) Has(ctx HasKVStore, components ...string) (bool, error) {
+	start, _, err := StringPrefixRange(components...)
+	if err != nil {
+		return false, err
+	}
+	return i.multiKeyIndex.Has(ctx, start), nil
+}
+
+// Get returns a result iterator for the key with the given components. Keys with more components are
+// returned as well, like by PrefixScan.
+func (i StringIndex) Get(ctx HasKVStore, components ...string) (Iterator, error) {
+	start, _, err := StringPrefixRange(components...)
+	if err != nil {
+		return NewInvalidIterator(), err
+	}
+	return i.multiKeyIndex.Get(ctx, start)
+}
+
+// GetPaginated creates an iterator for the key with the given components
+// starting from pageRequest.Key if provided.
+// The pageRequest.Key is a cursor, see `MultiKeyIndex.GetPaginated`, that `DecodeStringCursor` decodes.
+func (i StringIndex) GetPaginated(ctx HasKVStore, pageRequest *query.PageRequest, components ...string) (Iterator, error) {
+	start, _, err := StringPrefixRange(components...)
+	if err != nil {
+		return NewInvalidIterator(), err
+	}
+	return i.multiKeyIndex.GetPaginated(ctx, start, pageRequest)
+}
+
+// PrefixScan returns an Iterator over all objects with keys whose leading components are exactly the
+// given ones, in ascending order of the remaining components.
+// Iterator must be closed by caller.
+//
+// WARNING: The use of a PrefixScan can be very expensive in terms of Gas. Please make sure you do not expose
+// this as an endpoint to the public without further limits. See `LimitIterator`
+//
+// CONTRACT: No writes may happen within a domain while an iterator exists over it.
+func (i StringIndex) PrefixScan(ctx HasKVStore, leading ...string) (Iterator, error) {
+	start, end, err := StringPrefixRange(leading...)
+	if err != nil {
+		return NewInvalidIterator(), err
+	}
+	return i.multiKeyIndex.PrefixScan(ctx, start, end)
+}
+
+// ReversePrefixScan returns an Iterator like PrefixScan in descending order.
+// Iterator must be closed by caller.
+//
+// CONTRACT: No writes may happen within a domain while an iterator exists over it.
+func (i StringIndex) ReversePrefixScan(ctx HasKVStore, leading ...string) (Iterator, error) {
+	start, end, err := StringPrefixRange(leading...)
+	if err != nil {
+		return NewInvalidIterator(), err
+	}
+	return i.multiKeyIndex.ReversePrefixScan(ctx, start, end)
+}
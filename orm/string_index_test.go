@@ -0,0 +1,212 @@
+package orm_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	"github.com/cosmos/cosmos-sdk/codec/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/query"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/regen-network/regen-ledger/orm"
+	"github.com/regen-network/regen-ledger/testutil/testdata"
+)
+
+// adversarialStrings returns all strings up to 3 bytes of an alphabet with the escaped byte, the largest byte
+// and the bytes of a prefix.
+func adversarialStrings() []string {
+	alphabet := []string{"\x00", "a", "b", "\xff"}
+	res := []string{""}
+	last := []string{""}
+	for n := 0; n < 3; n++ {
+		var next []string
+		for _, s := range last {
+			for _, c := range alphabet {
+				next = append(next, s+c)
+			}
+		}
+		res = append(res, next...)
+		last = next
+	}
+	return res
+}
+
+func TestStringIndexKeyOrder(t *testing.T) {
+	strs := adversarialStrings()
+	type tuple struct {
+		a, b string
+		key  []byte
+	}
+	var tuples []tuple
+	for _, a := range strs {
+		for _, b := range []string{"", "\x00", "a", "ab", "\xff"} {
+			key := orm.CompositeKey(orm.StringField(a), orm.StringField(b))
+			tuples = append(tuples, tuple{a: a, b: b, key: key})
+		}
+	}
+	for _, x := range tuples {
+		for _, y := range tuples {
+			exp := strings.Compare(x.a, y.a)
+			if exp == 0 {
+				exp = strings.Compare(x.b, y.b)
+			}
+			require.Equal(t, exp, bytes.Compare(x.key, y.key), "(%q, %q) vs (%q, %q)", x.a, x.b, y.a, y.b)
+
+			// a scan by the first component matches exactly the keys with it
+			start, end, err := orm.StringPrefixRange(x.a)
+			require.NoError(t, err)
+			inRange := bytes.Compare(start, y.key) <= 0 && (end == nil || bytes.Compare(y.key, end) < 0)
+			require.Equal(t, x.a == y.a, inRange, "scan %q with key (%q, %q)", x.a, y.a, y.b)
+		}
+	}
+}
+
+func TestDecodeStringCursor(t *testing.T) {
+	rowID := orm.RowID(orm.EncodeSequence(1))
+	for msg, components := range map[string][]string{
+		"single":           {"abc"},
+		"multiple":         {"abc", "d"},
+		"empty components": {"", ""},
+		"escaped byte":     {"a\x00b", "\x00"},
+	} {
+		t.Run(msg, func(t *testing.T) {
+			fields := make([]orm.KeyField, len(components))
+			for i, c := range components {
+				fields[i] = orm.StringField(c)
+			}
+			decoded, decodedRowID, err := orm.DecodeStringCursor(orm.EncodeCursor(orm.CompositeKey(fields...), rowID))
+			require.NoError(t, err)
+			assert.Equal(t, components, decoded)
+			assert.Equal(t, rowID, decodedRowID)
+		})
+	}
+
+	for msg, key := range map[string][]byte{
+		"empty":          {},
+		"not terminated": []byte("abc\x00\x00d"),
+		"invalid escape": []byte("a\x00\x01\x00\x00"),
+		"escape at end":  []byte("abc\x00"),
+	} {
+		t.Run("decode "+msg, func(t *testing.T) {
+			_, _, err := orm.DecodeStringCursor(orm.EncodeCursor(key, rowID))
+			assert.True(t, orm.ErrArgument.Is(err), err)
+		})
+	}
+	_, _, err := orm.StringPrefixRange()
+	assert.True(t, orm.ErrArgument.Is(err), err)
+}
+
+func TestStringIndex(t *testing.T) {
+	interfaceRegistry := types.NewInterfaceRegistry()
+	cdc := codec.NewProtoCodec(interfaceRegistry)
+
+	storeKey := sdk.NewKVStoreKey("test")
+	const (
+		testTablePrefix = iota
+		testTableSeqPrefix
+	)
+	tBuilder := orm.NewAutoUInt64TableBuilder(testTablePrefix, testTableSeqPrefix, storeKey, &testdata.GroupInfo{}, cdc)
+	// the description is a class ID and a project name separated by a slash for the test
+	idx := orm.NewStringIndex(tBuilder, GroupByAdminIndexPrefix, func(val interface{}) ([][]string, error) {
+		return [][]string{strings.SplitN(val.(*testdata.GroupInfo).Description, "/", 2)}, nil
+	})
+	tb := tBuilder.Build()
+
+	ctx := orm.NewMockContext()
+	g1 := testdata.GroupInfo{GroupId: 1, Description: "abc/x"}
+	g2 := testdata.GroupInfo{GroupId: 2, Description: "abcd/a"}
+	g3 := testdata.GroupInfo{GroupId: 3, Description: "abc/"}
+	g4 := testdata.GroupInfo{GroupId: 4, Description: "ab/cx"}
+	for _, g := range []testdata.GroupInfo{g1, g2, g3, g4} {
+		_, err := tb.Create(ctx, &g)
+		require.NoError(t, err)
+	}
+	// components can contain a 0x00 byte
+	g5 := testdata.GroupInfo{GroupId: 5, Description: "a\x00b/c"}
+	_, err := tb.Create(ctx, &g5)
+	require.NoError(t, err)
+
+	specs := map[string]struct {
+		leading []string
+		exp     []testdata.GroupInfo
+	}{
+		"exact first component": {
+			leading: []string{"abc"},
+			exp:     []testdata.GroupInfo{g3, g1},
+		},
+		"longer first component": {
+			leading: []string{"abcd"},
+			exp:     []testdata.GroupInfo{g2},
+		},
+		"shorter first component": {
+			leading: []string{"ab"},
+			exp:     []testdata.GroupInfo{g4},
+		},
+		"not a component": {
+			leading: []string{"a"},
+			exp:     []testdata.GroupInfo{},
+		},
+		"component with 0x00 byte": {
+			leading: []string{"a\x00b"},
+			exp:     []testdata.GroupInfo{g5},
+		},
+		"all components": {
+			leading: []string{"abc", "x"},
+			exp:     []testdata.GroupInfo{g1},
+		},
+		"empty second component": {
+			leading: []string{"abc", ""},
+			exp:     []testdata.GroupInfo{g3},
+		},
+	}
+	for msg, spec := range specs {
+		t.Run(msg, func(t *testing.T) {
+			it, err := idx.PrefixScan(ctx, spec.leading...)
+			require.NoError(t, err)
+			var loaded []testdata.GroupInfo
+			_, err = orm.ReadAll(it, &loaded)
+			require.NoError(t, err)
+			assert.Equal(t, spec.exp, loaded)
+
+			it, err = idx.ReversePrefixScan(ctx, spec.leading...)
+			require.NoError(t, err)
+			loaded = nil
+			_, err = orm.ReadAll(it, &loaded)
+			require.NoError(t, err)
+			assert.Len(t, loaded, len(spec.exp))
+
+			exists, err := idx.Has(ctx, spec.leading...)
+			require.NoError(t, err)
+			assert.Equal(t, len(spec.exp) != 0, exists)
+		})
+	}
+
+	it, err := idx.Get(ctx, "abc", "x")
+	require.NoError(t, err)
+	var loaded []testdata.GroupInfo
+	_, err = orm.ReadAll(it, &loaded)
+	require.NoError(t, err)
+	assert.Equal(t, []testdata.GroupInfo{g1}, loaded)
+
+	_, err = idx.PrefixScan(ctx)
+	assert.True(t, orm.ErrArgument.Is(err), err)
+	_, err = idx.Get(ctx)
+	assert.True(t, orm.ErrArgument.Is(err), err)
+
+	// the NextKey of a page decodes to the components
+	pageReq := &query.PageRequest{Limit: 1}
+	it, err = idx.GetPaginated(ctx, pageReq, "abc")
+	require.NoError(t, err)
+	loaded = nil
+	res, err := orm.Paginate(it, pageReq, &loaded)
+	require.NoError(t, err)
+	assert.Equal(t, []testdata.GroupInfo{g3}, loaded)
+	components, rowID, err := orm.DecodeStringCursor(res.NextKey)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"abc", "x"}, components)
+	assert.Equal(t, orm.RowID(orm.EncodeSequence(1)), rowID)
+}
@@ -20,7 +20,11 @@ type TableBuilder struct {
 	indexKeyCodec IndexKeyCodec
 	afterSave     []AfterSaveInterceptor
 	afterDelete   []AfterDeleteInterceptor
+	onCreate      []OnCreateHook
+	onDelete      []OnDeleteHook
 	cdc           codec.Marshaler
+	counter       *rowCounter
+	indexPrefixes []byte
 }
 
 // NewTableBuilder creates a builder to setup a Table object.
@@ -56,6 +60,11 @@ func (a TableBuilder) RowGetter() RowGetter {
 	return NewTypeSafeRowGetter(a.storeKey, a.prefixData, a.model, a.cdc)
 }
 
+// RawRowGetter returns a RawRowGetter for the table data.
+func (a TableBuilder) RawRowGetter() RawRowGetter {
+	return NewRawRowGetter(a.storeKey, a.prefixData)
+}
+
 func (a TableBuilder) StoreKey() sdk.StoreKey {
 	return a.storeKey
 }
@@ -68,7 +77,11 @@ func (a TableBuilder) Build() Table {
 		storeKey:    a.storeKey,
 		afterSave:   a.afterSave,
 		afterDelete: a.afterDelete,
+		onCreate:    a.onCreate,
+		onDelete:    a.onDelete,
 		cdc:         a.cdc,
+		counter:     a.counter,
+		indexes:     a.indexPrefixes,
 	}
 }
 
@@ -77,11 +90,56 @@ func (a *TableBuilder) AddAfterSaveInterceptor(interceptor AfterSaveInterceptor)
 	a.afterSave = append(a.afterSave, interceptor)
 }
 
+// WithRowCounter enables a persistent counter of the table rows that is stored under the given prefix.
+// The counter is maintained on Create and Delete so that `Table.Count` does not have to scan the table.
+// Existing tables that enable the counter must initialize it via `Table.InitRowCounter` once.
+// The prefix must differ from the one of the table and of its indexes.
+func (a *TableBuilder) WithRowCounter(prefixCounter byte) {
+	if prefixCounter == a.prefixData {
+		panic("prefixData and prefixCounter must be unique")
+	}
+	for _, p := range a.indexPrefixes {
+		if prefixCounter == p {
+			panic("index prefix and prefixCounter must be unique")
+		}
+	}
+	a.counter = newRowCounter(a.storeKey, prefixCounter)
+}
+
+// addIndexPrefix registers the prefix of an index of the table for `Table.Stats`.
+func (a *TableBuilder) addIndexPrefix(prefix byte) {
+	if a.counter != nil && prefix == a.counter.prefix {
+		panic("index prefix and prefixCounter must be unique")
+	}
+	a.indexPrefixes = append(a.indexPrefixes, prefix)
+}
+
 // AddAfterDeleteInterceptor can be used to register a callback function that is executed after an object is deleted.
 func (a *TableBuilder) AddAfterDeleteInterceptor(interceptor AfterDeleteInterceptor) {
 	a.afterDelete = append(a.afterDelete, interceptor)
 }
 
+// OnCreate registers a hook that is executed before an object is persisted by Create or BulkCreate, so that
+// modules can attach side effects like events to the table instead of every call site. The hooks run with
+// the sdk.Context of the operation in the order of registration, after the object was validated. An error
+// of a hook aborts the operation before anything is persisted. The context passed to the operation must
+// be a sdk.Context or implement `HasSDKContext` then, or an `ErrArgument` error is returned.
+func (a *TableBuilder) OnCreate(hook OnCreateHook) {
+	if hook == nil {
+		panic("hook must not be nil")
+	}
+	a.onCreate = append(a.onCreate, hook)
+}
+
+// OnDelete registers a hook that is executed before an existing object is removed by Delete. Like for
+// OnCreate, an error of a hook aborts the operation before anything is removed.
+func (a *TableBuilder) OnDelete(hook OnDeleteHook) {
+	if hook == nil {
+		panic("hook must not be nil")
+	}
+	a.onDelete = append(a.onDelete, hook)
+}
+
 var _ TableExportable = &Table{}
 
 // Table is the high level object to storage mapper functionality. Persistent entities are stored by an unique identifier
@@ -94,7 +152,11 @@ type Table struct {
 	storeKey    sdk.StoreKey
 	afterSave   []AfterSaveInterceptor
 	afterDelete []AfterDeleteInterceptor
+	onCreate    []OnCreateHook
+	onDelete    []OnDeleteHook
 	cdc         codec.Marshaler
+	counter     *rowCounter
+	indexes     []byte
 }
 
 // Create persists the given object under the rowID key. It does not check if the
@@ -103,6 +165,7 @@ type Table struct {
 // by checking the state via `Has` function before.
 //
 // Create iterates though the registered callbacks and may add secondary index keys by them.
+// The hooks registered with `TableBuilder.OnCreate` run before the object is persisted.
 func (a Table) Create(ctx HasKVStore, rowID RowID, obj codec.ProtoMarshaler) error {
 	if err := assertCorrectType(a.model, obj); err != nil {
 		return err
@@ -110,12 +173,18 @@ func (a Table) Create(ctx HasKVStore, rowID RowID, obj codec.ProtoMarshaler) err
 	if err := assertValid(obj); err != nil {
 		return err
 	}
+	if err := a.runCreateHooks(ctx, rowID, obj); err != nil {
+		return err
+	}
 	store := prefix.NewStore(ctx.KVStore(a.storeKey), []byte{a.prefix})
 	v, err := a.cdc.MarshalBinaryBare(obj)
 	if err != nil {
 		return errors.Wrapf(err, "failed to serialize %T", obj)
 	}
 	store.Set(rowID, v)
+	if a.counter != nil {
+		a.counter.Inc(ctx)
+	}
 	for i, itc := range a.afterSave {
 		if err := itc(ctx, rowID, obj, nil); err != nil {
 			return errors.Wrapf(err, "interceptor %d failed", i)
@@ -124,6 +193,56 @@ func (a Table) Create(ctx HasKVStore, rowID RowID, obj codec.ProtoMarshaler) err
 	return nil
 }
 
+// BulkCreate persists the given objects under the rowID keys at the same positions like Create, for
+// example for a genesis import of many rows. All objects are validated and serialized before the first
+// one is persisted and the row counter is updated once. The same contract as for Create applies to
+// the rowIDs, which must not exist yet and must be unique within the batch.
+//
+// The hooks registered with `TableBuilder.OnCreate` run for all objects before the first one is persisted.
+// BulkCreate iterates though the registered callbacks for every object after all objects are persisted
+// and may add secondary index keys by them. When a callback fails, for example on a violated unique
+// constraint, the objects before are persisted already, like with Create in a loop, and the state must
+// be discarded, as it is done for a failed transaction or genesis import.
+func (a Table) BulkCreate(ctx HasKVStore, rowIDs []RowID, objs []codec.ProtoMarshaler) error {
+	if len(rowIDs) != len(objs) {
+		return errors.Wrapf(ErrArgument, "%d rowIDs for %d objects", len(rowIDs), len(objs))
+	}
+	values := make([][]byte, len(objs))
+	for i, obj := range objs {
+		if err := assertCorrectType(a.model, obj); err != nil {
+			return errors.Wrapf(err, "object %d", i)
+		}
+		if err := assertValid(obj); err != nil {
+			return errors.Wrapf(err, "object %d", i)
+		}
+		v, err := a.cdc.MarshalBinaryBare(obj)
+		if err != nil {
+			return errors.Wrapf(err, "failed to serialize %T", obj)
+		}
+		values[i] = v
+	}
+	for i, rowID := range rowIDs {
+		if err := a.runCreateHooks(ctx, rowID, objs[i]); err != nil {
+			return errors.Wrapf(err, "object %d", i)
+		}
+	}
+	store := prefix.NewStore(ctx.KVStore(a.storeKey), []byte{a.prefix})
+	for i, rowID := range rowIDs {
+		store.Set(rowID, values[i])
+	}
+	if a.counter != nil && len(rowIDs) != 0 {
+		a.counter.Set(ctx, a.counter.Get(ctx)+uint64(len(rowIDs)))
+	}
+	for j, rowID := range rowIDs {
+		for i, itc := range a.afterSave {
+			if err := itc(ctx, rowID, objs[j], nil); err != nil {
+				return errors.Wrapf(err, "object %d: interceptor %d failed", j, i)
+			}
+		}
+	}
+	return nil
+}
+
 // Save updates the given object under the rowID key. It expects the key to exists already
 // and fails with an `ErrNotFound` otherwise. Any caller must therefore make sure that this contract
 // is fulfilled. Parameters must not be nil.
@@ -157,6 +276,63 @@ func (a Table) Save(ctx HasKVStore, rowID RowID, newValue codec.ProtoMarshaler)
 	return nil
 }
 
+// restore persists the raw value under the rowID key, as it was read before, and runs the registered
+// callbacks to update the secondary index keys. The row counter is increased when the row does not
+// exist. Unlike Create, it does not validate the object or run the hooks of `TableBuilder.OnCreate`.
+func (a Table) restore(ctx HasKVStore, rowID RowID, value []byte) error {
+	newValue := reflect.New(a.model).Interface().(codec.ProtoMarshaler)
+	if err := a.cdc.UnmarshalBinaryBare(value, newValue); err != nil {
+		return errors.Wrapf(err, "failed to deserialize %T", newValue)
+	}
+	var oldValue codec.ProtoMarshaler
+	if a.Has(ctx, rowID) {
+		oldValue = reflect.New(a.model).Interface().(codec.ProtoMarshaler)
+		if err := a.GetOne(ctx, rowID, oldValue); err != nil {
+			return errors.Wrap(err, "load old value")
+		}
+	}
+	store := prefix.NewStore(ctx.KVStore(a.storeKey), []byte{a.prefix})
+	store.Set(rowID, value)
+	if oldValue == nil && a.counter != nil {
+		a.counter.Inc(ctx)
+	}
+	for i, itc := range a.afterSave {
+		if err := itc(ctx, rowID, newValue, oldValue); err != nil {
+			return errors.Wrapf(err, "interceptor %d failed", i)
+		}
+	}
+	return nil
+}
+
+// runCreateHooks runs the hooks registered with `TableBuilder.OnCreate` for the object.
+func (a Table) runCreateHooks(ctx HasKVStore, rowID RowID, obj codec.ProtoMarshaler) error {
+	if len(a.onCreate) == 0 {
+		return nil
+	}
+	sdkCtx, err := hookContext(ctx)
+	if err != nil {
+		return err
+	}
+	for i, hook := range a.onCreate {
+		if err := hook(sdkCtx, obj, rowID); err != nil {
+			return errors.Wrapf(err, "create hook %d failed", i)
+		}
+	}
+	return nil
+}
+
+// hookContext returns the sdk.Context of the operation that the table hooks run with.
+func hookContext(ctx HasKVStore) (sdk.Context, error) {
+	switch c := ctx.(type) {
+	case sdk.Context:
+		return c, nil
+	case HasSDKContext:
+		return c.SDKContext(), nil
+	default:
+		return sdk.Context{}, errors.Wrapf(ErrArgument, "table hooks require a sdk.Context or HasSDKContext, got %T", ctx)
+	}
+}
+
 func assertValid(obj codec.ProtoMarshaler) error {
 	if v, ok := obj.(Validateable); ok {
 		if err := v.ValidateBasic(); err != nil {
@@ -171,6 +347,7 @@ func assertValid(obj codec.ProtoMarshaler) error {
 // is fulfilled.
 //
 // Delete iterates though the registered callbacks and removes secondary index keys by them.
+// The hooks registered with `TableBuilder.OnDelete` run before the object is removed.
 func (a Table) Delete(ctx HasKVStore, rowID RowID) error {
 	store := prefix.NewStore(ctx.KVStore(a.storeKey), []byte{a.prefix})
 
@@ -178,7 +355,21 @@ func (a Table) Delete(ctx HasKVStore, rowID RowID) error {
 	if err := a.GetOne(ctx, rowID, oldValue); err != nil {
 		return errors.Wrap(err, "load old value")
 	}
+	if len(a.onDelete) != 0 {
+		sdkCtx, err := hookContext(ctx)
+		if err != nil {
+			return err
+		}
+		for i, hook := range a.onDelete {
+			if err := hook(sdkCtx, rowID); err != nil {
+				return errors.Wrapf(err, "delete hook %d failed", i)
+			}
+		}
+	}
 	store.Delete(rowID)
+	if a.counter != nil {
+		a.counter.Dec(ctx)
+	}
 
 	for i, itc := range a.afterDelete {
 		if err := itc(ctx, rowID, oldValue); err != nil {
@@ -196,6 +387,40 @@ func (a Table) Has(ctx HasKVStore, rowID RowID) bool {
 	return it.Valid()
 }
 
+// Count returns the number of rows in the table. With a row counter enabled via `TableBuilder.WithRowCounter`
+// the persisted value is returned, otherwise all rows are iterated.
+//
+// WARNING: Without a row counter, Count can be very expensive in terms of Gas.
+func (a Table) Count(ctx HasKVStore) (uint64, error) {
+	if a.counter != nil {
+		return a.counter.Get(ctx), nil
+	}
+	it, err := a.PrefixScan(ctx, nil, nil)
+	if err != nil {
+		return 0, err
+	}
+	return Count(it)
+}
+
+// InitRowCounter sets the row counter to the number of rows in the table. It can be used in a
+// migration for existing tables that enable the counter. An `ErrArgument` error is returned when
+// the table has no row counter.
+func (a Table) InitRowCounter(ctx HasKVStore) error {
+	if a.counter == nil {
+		return errors.Wrap(ErrArgument, "table has no row counter")
+	}
+	it, err := a.PrefixScan(ctx, nil, nil)
+	if err != nil {
+		return err
+	}
+	n, err := Count(it)
+	if err != nil {
+		return err
+	}
+	a.counter.Set(ctx, n)
+	return nil
+}
+
 // GetOne load the object persisted for the given RowID into the dest parameter.
 // If none exists `ErrNotFound` is returned instead. Parameters must not be nil.
 func (a Table) GetOne(ctx HasKVStore, rowID RowID, dest codec.ProtoMarshaler) error {
@@ -203,6 +428,34 @@ func (a Table) GetOne(ctx HasKVStore, rowID RowID, dest codec.ProtoMarshaler) er
 	return x(ctx, rowID, dest)
 }
 
+// MultiRead loads the objects persisted for the given RowIDs into a new slice at the passed ModelSlicePtr
+// in the order of the RowIDs. RowIDs without an object are skipped and returned instead.
+// The destination slice can be empty but not nil.
+func (a Table) MultiRead(ctx HasKVStore, rowIDs []RowID, dest ModelSlicePtr) ([]RowID, error) {
+	getter := NewTypeSafeRowGetter(a.storeKey, a.prefix, a.model, a.cdc)
+	var notFound []RowID
+	var pos int
+	it := IteratorFunc(func(dest codec.ProtoMarshaler) (RowID, error) {
+		for ; pos < len(rowIDs); pos++ {
+			rowID := rowIDs[pos]
+			switch err := getter(ctx, rowID, dest); {
+			case err == nil:
+				pos++
+				return rowID, nil
+			case ErrNotFound.Is(err):
+				notFound = append(notFound, rowID)
+			default:
+				return nil, err
+			}
+		}
+		return nil, ErrIteratorDone
+	})
+	if _, err := ReadAll(it, dest); err != nil {
+		return nil, err
+	}
+	return notFound, nil
+}
+
 // PrefixScan returns an Iterator over a domain of keys in ascending order. End is exclusive.
 // Start is an MultiKeyIndex key or prefix. It must be less than end, or the Iterator is invalid.
 // Iterator must be closed by caller.
@@ -220,15 +473,65 @@ func (a Table) GetOne(ctx HasKVStore, rowID RowID, dest codec.ProtoMarshaler) er
 //
 // CONTRACT: No writes may happen within a domain while an iterator exists over it.
 func (a Table) PrefixScan(ctx HasKVStore, start, end RowID) (Iterator, error) {
+	return a.PrefixScanWithOpts(ctx, start, end, ScanOpts{})
+}
+
+// ScanOpts are the options of a table scan.
+type ScanOpts struct {
+	// BatchSize is the number of rows that are read ahead from the store, see `BufferedIterator`.
+	// The default 0 reads the rows one by one.
+	BatchSize int
+	// Snapshot enables a snapshot scan that reads the rowIDs of all rows in the scanned range when
+	// the scan is created and loads the values when the elements are read. This allows callers, like
+	// an EndBlocker, to write to the table while they iterate it:
+	//   - the rows are returned in the order of the store iterator at scan start
+	//   - every row returns the value that is persisted when it is read, including updates made
+	//     after the scan start
+	//   - rows that are deleted before they are read are skipped
+	//   - rows that are inserted after the scan start are never returned, even when their rowID is
+	//     within the scanned range
+	//
+	// The rowIDs of all rows in the range are held in memory, so the range should be bound.
+	// Snapshot can not be combined with a BatchSize.
+	Snapshot bool
+}
+
+// PrefixScanFrom returns an Iterator over all rows in ascending order that starts strictly after the
+// resumeKey, like the `Positioner` position of a previous scan, so that a long running process can
+// continue an iteration where it stopped, for example a store migration over multiple blocks. See
+// `MigrateInBatches`. An empty resumeKey starts at the first row.
+// Iterator must be closed by caller.
+//
+// CONTRACT: No writes may happen within a domain while an iterator exists over it.
+func (a Table) PrefixScanFrom(ctx HasKVStore, resumeKey RowID) (Iterator, error) {
+	return a.PrefixScan(ctx, keyAfter(resumeKey), nil)
+}
+
+// keyAfter returns the smallest key after the given key, or nil for an empty key.
+func keyAfter(key []byte) []byte {
+	if len(key) == 0 {
+		return nil
+	}
+	return append(append(make([]byte, 0, len(key)+1), key...), 0)
+}
+
+// PrefixScanWithOpts returns an Iterator like PrefixScan with the given options, that query servers
+// can use to tune the store reads of large scans.
+//
+// CONTRACT: No writes may happen within a domain while an iterator exists over it, unless
+// `ScanOpts.Snapshot` is set.
+func (a Table) PrefixScanWithOpts(ctx HasKVStore, start, end RowID, opts ScanOpts) (Iterator, error) {
 	if start != nil && end != nil && bytes.Compare(start, end) >= 0 {
 		return NewInvalidIterator(), errors.Wrap(ErrArgument, "start must be before end")
 	}
+	if opts.BatchSize < 0 {
+		return NewInvalidIterator(), errors.Wrap(ErrArgument, "batch size must not be negative")
+	}
+	if opts.Snapshot && opts.BatchSize != 0 {
+		return NewInvalidIterator(), errors.Wrap(ErrArgument, "snapshot scans can not be buffered")
+	}
 	store := prefix.NewStore(ctx.KVStore(a.storeKey), []byte{a.prefix})
-	return &typeSafeIterator{
-		ctx:       ctx,
-		rowGetter: NewTypeSafeRowGetter(a.storeKey, a.prefix, a.model, a.cdc),
-		it:        store.Iterator(start, end),
-	}, nil
+	return a.newIterator(ctx, func() types.Iterator { return store.Iterator(start, end) }, opts), nil
 }
 
 // ReversePrefixScan returns an Iterator over a domain of keys in descending order. End is exclusive.
@@ -241,38 +544,271 @@ func (a Table) PrefixScan(ctx HasKVStore, start, end RowID) (Iterator, error) {
 //
 // CONTRACT: No writes may happen within a domain while an iterator exists over it.
 func (a Table) ReversePrefixScan(ctx HasKVStore, start, end RowID) (Iterator, error) {
+	return a.ReversePrefixScanWithOpts(ctx, start, end, ScanOpts{})
+}
+
+// ReversePrefixScanWithOpts returns an Iterator like ReversePrefixScan with the given options.
+// See `PrefixScanWithOpts`.
+//
+// CONTRACT: No writes may happen within a domain while an iterator exists over it, unless
+// `ScanOpts.Snapshot` is set.
+func (a Table) ReversePrefixScanWithOpts(ctx HasKVStore, start, end RowID, opts ScanOpts) (Iterator, error) {
 	if start != nil && end != nil && bytes.Compare(start, end) >= 0 {
 		return NewInvalidIterator(), errors.Wrap(ErrArgument, "start must be before end")
 	}
+	if opts.BatchSize < 0 {
+		return NewInvalidIterator(), errors.Wrap(ErrArgument, "batch size must not be negative")
+	}
+	if opts.Snapshot && opts.BatchSize != 0 {
+		return NewInvalidIterator(), errors.Wrap(ErrArgument, "snapshot scans can not be buffered")
+	}
 	store := prefix.NewStore(ctx.KVStore(a.storeKey), []byte{a.prefix})
-	return &typeSafeIterator{
+	return a.newIterator(ctx, func() types.Iterator { return store.ReverseIterator(start, end) }, opts), nil
+}
+
+// newIterator returns the iterator of a scan over the store iterator that open returns. open is called
+// again when the iterator is rewound.
+func (a Table) newIterator(ctx HasKVStore, open func() types.Iterator, opts ScanOpts) Iterator {
+	if opts.Snapshot {
+		return instrumentScan(a.storeKey, a.prefix, false, a.newSnapshotIterator(ctx, open()))
+	}
+	res := &typeSafeIterator{
 		ctx:       ctx,
 		rowGetter: NewTypeSafeRowGetter(a.storeKey, a.prefix, a.model, a.cdc),
-		it:        store.ReverseIterator(start, end),
-	}, nil
+		it:        open(),
+		open:      open,
+		scan:      ScanInfo{StoreKey: a.storeKey.Name(), Prefix: a.prefix},
+	}
+	if opts.BatchSize == 0 {
+		return instrumentScan(a.storeKey, a.prefix, false, res)
+	}
+	// the rows are unmarshaled with the codec like by the RowGetter
+	return instrumentScan(a.storeKey, a.prefix, false, newBufferedIterator(res, opts.BatchSize, func(value []byte, dest codec.ProtoMarshaler) error {
+		if err := assertCorrectType(a.model, dest); err != nil {
+			return err
+		}
+		return a.cdc.UnmarshalBinaryBare(value, dest)
+	}))
 }
 
 func (a Table) Table() Table {
 	return a
 }
 
+var (
+	_ RawIterator = &typeSafeIterator{}
+	_ Rewinder    = &typeSafeIterator{}
+	_ Positioner  = &typeSafeIterator{}
+	_ RowIDPeeker = &typeSafeIterator{}
+)
+
 // typeSafeIterator is initialized with a type safe RowGetter only.
 type typeSafeIterator struct {
 	ctx       HasKVStore
 	rowGetter RowGetter
 	it        types.Iterator
+	// open returns a new store iterator over the domain of the scan
+	open func() types.Iterator
+	// last is the rowID of the last element returned
+	last RowID
+	// scan describes the table for `Explain`
+	scan   ScanInfo
+	closed bool
 }
 
-func (i typeSafeIterator) LoadNext(dest codec.ProtoMarshaler) (RowID, error) {
+func (i *typeSafeIterator) LoadNext(dest codec.ProtoMarshaler) (RowID, error) {
+	if i.closed {
+		return nil, ErrIteratorClosed
+	}
 	if !i.it.Valid() {
 		return nil, ErrIteratorDone
 	}
 	rowID := i.it.Key()
 	i.it.Next()
+	i.last = rowID
 	return rowID, i.rowGetter(i.ctx, rowID, dest)
 }
 
-func (i typeSafeIterator) Close() error {
-	i.it.Close()
+// RawNext returns the rowID and the persisted bytes of the next element without unmarshaling them.
+func (i *typeSafeIterator) RawNext() (RowID, []byte, error) {
+	if i.closed {
+		return nil, nil, ErrIteratorClosed
+	}
+	if !i.it.Valid() {
+		return nil, nil, ErrIteratorDone
+	}
+	rowID, value := i.it.Key(), i.it.Value()
+	if value == nil {
+		value = []byte{}
+	}
+	i.it.Next()
+	i.last = rowID
+	return rowID, value, nil
+}
+
+// nextRowID returns the rowID of the next element without unmarshaling it.
+func (i *typeSafeIterator) nextRowID() (RowID, error) {
+	if i.closed {
+		return nil, ErrIteratorClosed
+	}
+	if !i.it.Valid() {
+		return nil, ErrIteratorDone
+	}
+	rowID := i.it.Key()
+	i.it.Next()
+	i.last = rowID
+	return rowID, nil
+}
+
+// PeekRowID returns the rowID of the next element without consuming or reading it.
+func (i *typeSafeIterator) PeekRowID() (RowID, error) {
+	if i.closed {
+		return nil, ErrIteratorClosed
+	}
+	if !i.it.Valid() {
+		return nil, ErrIteratorDone
+	}
+	return i.it.Key(), nil
+}
+
+// Rewind replaces the store iterator with a new one from the start of the scanned domain.
+func (i *typeSafeIterator) Rewind() error {
+	if i.closed {
+		return ErrIteratorClosed
+	}
+	if err := i.it.Close(); err != nil {
+		return err
+	}
+	i.it = i.open()
+	i.last = nil
+	return nil
+}
+
+// Position returns the rowID of the last element returned.
+func (i *typeSafeIterator) Position() []byte {
+	return i.last
+}
+
+func (i *typeSafeIterator) Close() error {
+	if i.closed {
+		return nil
+	}
+	i.closed = true
+	return i.it.Close()
+}
+
+var (
+	_ RawIterator = &snapshotIterator{}
+	_ Rewinder    = &snapshotIterator{}
+	_ Positioner  = &snapshotIterator{}
+	_ RowIDPeeker = &snapshotIterator{}
+)
+
+// snapshotIterator loads the rows of a fixed set of rowIDs that were collected at scan start.
+// Rows that do not exist anymore are skipped.
+type snapshotIterator struct {
+	ctx          HasKVStore
+	rowGetter    RowGetter
+	rawRowGetter RawRowGetter
+	// rowIDs are the rowIDs that were not read yet out of all rowIDs of the snapshot
+	rowIDs []RowID
+	all    []RowID
+	// last is the rowID of the last element returned
+	last RowID
+	// scan describes the table for `Explain`
+	scan   ScanInfo
+	closed bool
+}
+
+func (a Table) newSnapshotIterator(ctx HasKVStore, it types.Iterator) *snapshotIterator {
+	defer it.Close()
+	var rowIDs []RowID
+	for ; it.Valid(); it.Next() {
+		rowIDs = append(rowIDs, append(RowID{}, it.Key()...))
+	}
+	return &snapshotIterator{
+		ctx:          ctx,
+		rowGetter:    NewTypeSafeRowGetter(a.storeKey, a.prefix, a.model, a.cdc),
+		rawRowGetter: NewRawRowGetter(a.storeKey, a.prefix),
+		rowIDs:       rowIDs,
+		all:          rowIDs,
+		scan:         ScanInfo{StoreKey: a.storeKey.Name(), Prefix: a.prefix},
+	}
+}
+
+func (i *snapshotIterator) LoadNext(dest codec.ProtoMarshaler) (RowID, error) {
+	if i.closed {
+		return nil, ErrIteratorClosed
+	}
+	for len(i.rowIDs) != 0 {
+		rowID := i.rowIDs[0]
+		i.rowIDs = i.rowIDs[1:]
+		switch err := i.rowGetter(i.ctx, rowID, dest); {
+		case err == nil:
+			i.last = rowID
+			return rowID, nil
+		case !ErrNotFound.Is(err):
+			return rowID, err
+		}
+	}
+	return nil, ErrIteratorDone
+}
+
+// RawNext returns the rowID and the persisted bytes of the next row that was not deleted.
+func (i *snapshotIterator) RawNext() (RowID, []byte, error) {
+	if i.closed {
+		return nil, nil, ErrIteratorClosed
+	}
+	for len(i.rowIDs) != 0 {
+		rowID := i.rowIDs[0]
+		i.rowIDs = i.rowIDs[1:]
+		switch value, err := i.rawRowGetter(i.ctx, rowID); {
+		case err == nil:
+			i.last = rowID
+			return rowID, value, nil
+		case !ErrNotFound.Is(err):
+			return rowID, nil, err
+		}
+	}
+	return nil, nil, ErrIteratorDone
+}
+
+// PeekRowID returns the rowID of the next row that was not deleted without consuming it. The rowIDs of
+// deleted rows before it are dropped.
+func (i *snapshotIterator) PeekRowID() (RowID, error) {
+	if i.closed {
+		return nil, ErrIteratorClosed
+	}
+	for len(i.rowIDs) != 0 {
+		rowID := i.rowIDs[0]
+		switch _, err := i.rawRowGetter(i.ctx, rowID); {
+		case err == nil:
+			return rowID, nil
+		case !ErrNotFound.Is(err):
+			return rowID, err
+		}
+		i.rowIDs = i.rowIDs[1:]
+	}
+	return nil, ErrIteratorDone
+}
+
+// Rewind restarts the iteration at the first rowID of the snapshot. The snapshot is not taken again, so
+// rows that were inserted since the scan start are still not returned.
+func (i *snapshotIterator) Rewind() error {
+	if i.closed {
+		return ErrIteratorClosed
+	}
+	i.rowIDs, i.last = i.all, nil
+	return nil
+}
+
+// Position returns the rowID of the last element returned.
+func (i *snapshotIterator) Position() []byte {
+	return i.last
+}
+
+func (i *snapshotIterator) Close() error {
+	i.closed = true
+	i.rowIDs, i.all = nil, nil
 	return nil
 }
@@ -6,13 +6,18 @@ import (
 
 	"github.com/cosmos/cosmos-sdk/codec"
 	"github.com/cosmos/cosmos-sdk/codec/types"
+	"github.com/cosmos/cosmos-sdk/store"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/types/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/tendermint/tendermint/libs/log"
+	tmproto "github.com/tendermint/tendermint/proto/tendermint/types"
+	dbm "github.com/tendermint/tm-db"
 
 	"github.com/regen-network/regen-ledger/orm"
 	"github.com/regen-network/regen-ledger/testutil/testdata"
+	regentypes "github.com/regen-network/regen-ledger/types"
 )
 
 func TestCreate(t *testing.T) {
@@ -127,3 +132,290 @@ func TestUpdate(t *testing.T) {
 	}
 
 }
+
+func TestTableCount(t *testing.T) {
+	interfaceRegistry := types.NewInterfaceRegistry()
+	cdc := codec.NewProtoCodec(interfaceRegistry)
+	storeKey := sdk.NewKVStoreKey("test")
+	const (
+		anyPrefix     = 0x10
+		counterPrefix = 0x11
+	)
+	specs := map[string]struct {
+		withCounter bool
+	}{
+		"with row counter":    {withCounter: true},
+		"without row counter": {withCounter: false},
+	}
+	for msg, spec := range specs {
+		t.Run(msg, func(t *testing.T) {
+			tableBuilder := orm.NewTableBuilder(anyPrefix, storeKey, &testdata.GroupInfo{}, orm.Max255DynamicLengthIndexKeyCodec{}, cdc)
+			if spec.withCounter {
+				tableBuilder.WithRowCounter(counterPrefix)
+			}
+			myTable := tableBuilder.Build()
+			ctx := orm.NewMockContext()
+
+			n, err := myTable.Count(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, uint64(0), n)
+
+			for _, id := range []string{"my-id-1", "my-id-2", "my-id-3"} {
+				require.NoError(t, myTable.Create(ctx, []byte(id), &testdata.GroupInfo{Description: id}))
+			}
+			require.NoError(t, myTable.Delete(ctx, []byte("my-id-2")))
+
+			n, err = myTable.Count(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, uint64(2), n)
+		})
+	}
+}
+
+func TestRowCounterPrefix(t *testing.T) {
+	interfaceRegistry := types.NewInterfaceRegistry()
+	cdc := codec.NewProtoCodec(interfaceRegistry)
+	storeKey := sdk.NewKVStoreKey("test")
+	const (
+		anyPrefix     = 0x10
+		indexPrefix   = 0x11
+		counterPrefix = 0x12
+	)
+	byDescription := func(val interface{}) ([]orm.RowID, error) {
+		return []orm.RowID{[]byte(val.(*testdata.GroupInfo).Description)}, nil
+	}
+	specs := map[string]struct {
+		withCounter func(*orm.TableBuilder)
+	}{
+		"table prefix": {
+			withCounter: func(b *orm.TableBuilder) { b.WithRowCounter(anyPrefix) },
+		},
+		"index prefix": {
+			withCounter: func(b *orm.TableBuilder) {
+				orm.NewIndex(b, indexPrefix, byDescription)
+				b.WithRowCounter(indexPrefix)
+			},
+		},
+		"index added after the counter": {
+			withCounter: func(b *orm.TableBuilder) {
+				b.WithRowCounter(counterPrefix)
+				orm.NewIndex(b, counterPrefix, byDescription)
+			},
+		},
+	}
+	for msg, spec := range specs {
+		t.Run(msg, func(t *testing.T) {
+			tableBuilder := orm.NewTableBuilder(anyPrefix, storeKey, &testdata.GroupInfo{}, orm.Max255DynamicLengthIndexKeyCodec{}, cdc)
+			assert.Panics(t, func() { spec.withCounter(tableBuilder) })
+		})
+	}
+	t.Run("auto uint64 table", func(t *testing.T) {
+		tableBuilder := orm.NewAutoUInt64TableBuilder(anyPrefix, counterPrefix, storeKey, &testdata.GroupInfo{}, cdc)
+		orm.NewIndex(tableBuilder, indexPrefix, byDescription)
+		assert.Panics(t, func() { tableBuilder.WithRowCounter(counterPrefix) })
+		assert.Panics(t, func() { tableBuilder.WithRowCounter(indexPrefix) })
+		tableBuilder.WithRowCounter(0x13)
+	})
+}
+
+func TestInitRowCounter(t *testing.T) {
+	interfaceRegistry := types.NewInterfaceRegistry()
+	cdc := codec.NewProtoCodec(interfaceRegistry)
+	storeKey := sdk.NewKVStoreKey("test")
+	const (
+		anyPrefix     = 0x10
+		counterPrefix = 0x11
+	)
+	ctx := orm.NewMockContext()
+
+	// rows persisted before the counter was enabled
+	legacyTable := orm.NewTableBuilder(anyPrefix, storeKey, &testdata.GroupInfo{}, orm.Max255DynamicLengthIndexKeyCodec{}, cdc).Build()
+	for _, id := range []string{"my-id-1", "my-id-2"} {
+		require.NoError(t, legacyTable.Create(ctx, []byte(id), &testdata.GroupInfo{Description: id}))
+	}
+	err := legacyTable.InitRowCounter(ctx)
+	require.True(t, orm.ErrArgument.Is(err), err)
+
+	tableBuilder := orm.NewTableBuilder(anyPrefix, storeKey, &testdata.GroupInfo{}, orm.Max255DynamicLengthIndexKeyCodec{}, cdc)
+	tableBuilder.WithRowCounter(counterPrefix)
+	myTable := tableBuilder.Build()
+
+	n, err := myTable.Count(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(0), n)
+
+	require.NoError(t, myTable.InitRowCounter(ctx))
+	n, err = myTable.Count(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), n)
+}
+
+func TestRowCounterInvariant(t *testing.T) {
+	interfaceRegistry := types.NewInterfaceRegistry()
+	cdc := codec.NewProtoCodec(interfaceRegistry)
+	storeKey := sdk.NewKVStoreKey("test")
+	const (
+		anyPrefix     = 0x10
+		counterPrefix = 0x11
+	)
+	db := dbm.NewMemDB()
+	cms := store.NewCommitMultiStore(db)
+	cms.MountStoreWithDB(storeKey, sdk.StoreTypeIAVL, db)
+	require.NoError(t, cms.LoadLatestVersion())
+	ctx := sdk.NewContext(cms, tmproto.Header{}, false, log.NewNopLogger())
+
+	tableBuilder := orm.NewTableBuilder(anyPrefix, storeKey, &testdata.GroupInfo{}, orm.Max255DynamicLengthIndexKeyCodec{}, cdc)
+	tableBuilder.WithRowCounter(counterPrefix)
+	myTable := tableBuilder.Build()
+	invariant := orm.RowCounterInvariant("test", "row-counter", myTable)
+
+	_, broken := invariant(ctx)
+	assert.False(t, broken)
+
+	for _, id := range []string{"my-id-1", "my-id-2"} {
+		require.NoError(t, myTable.Create(ctx, []byte(id), &testdata.GroupInfo{Description: id}))
+	}
+	_, broken = invariant(ctx)
+	assert.False(t, broken)
+
+	// rows persisted without the counter
+	legacyTable := orm.NewTableBuilder(anyPrefix, storeKey, &testdata.GroupInfo{}, orm.Max255DynamicLengthIndexKeyCodec{}, cdc).Build()
+	require.NoError(t, legacyTable.Create(ctx, []byte("my-id-3"), &testdata.GroupInfo{Description: "my-id-3"}))
+	msg, broken := invariant(ctx)
+	assert.True(t, broken)
+	assert.Contains(t, msg, "row counter of table 0x10 is 2 for 3 rows")
+
+	assert.Panics(t, func() { orm.RowCounterInvariant("test", "row-counter", legacyTable) })
+}
+
+func TestTableStats(t *testing.T) {
+	interfaceRegistry := types.NewInterfaceRegistry()
+	cdc := codec.NewProtoCodec(interfaceRegistry)
+	storeKey := sdk.NewKVStoreKey("test")
+	const (
+		anyPrefix     = 0x10
+		counterPrefix = 0x11
+		indexPrefix   = 0x12
+	)
+	specs := map[string]struct {
+		withCounter bool
+	}{
+		"with row counter":    {withCounter: true},
+		"without row counter": {withCounter: false},
+	}
+	for msg, spec := range specs {
+		t.Run(msg, func(t *testing.T) {
+			tableBuilder := orm.NewTableBuilder(anyPrefix, storeKey, &testdata.GroupInfo{}, orm.Max255DynamicLengthIndexKeyCodec{}, cdc)
+			if spec.withCounter {
+				tableBuilder.WithRowCounter(counterPrefix)
+			}
+			orm.NewIndex(tableBuilder, indexPrefix, func(val interface{}) ([]orm.RowID, error) {
+				return []orm.RowID{[]byte(val.(*testdata.GroupInfo).Admin)}, nil
+			})
+			myTable := tableBuilder.Build()
+			ctx := orm.NewMockContext()
+
+			stats, err := myTable.StatsWithOpts(ctx, orm.StatsOpts{StorageBytes: true})
+			require.NoError(t, err)
+			assert.Equal(t, orm.TableStats{IndexCount: 1}, stats)
+
+			for _, id := range []string{"my-id-1", "my-id-2", "my-id-3"} {
+				obj := &testdata.GroupInfo{Description: id, Admin: sdk.AccAddress("admin-" + id)}
+				require.NoError(t, myTable.Create(ctx, []byte(id), obj))
+			}
+			require.NoError(t, myTable.Delete(ctx, []byte("my-id-2")))
+
+			stats, err = myTable.Stats(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, orm.TableStats{RowCount: 2, IndexCount: 1}, stats)
+
+			// the store holds the table only
+			var storageBytes uint64
+			it := ctx.KVStore(storeKey).Iterator(nil, nil)
+			for ; it.Valid(); it.Next() {
+				storageBytes += uint64(len(it.Key()) + len(it.Value()))
+			}
+			require.NoError(t, it.Close())
+
+			stats, err = myTable.StatsWithOpts(ctx, orm.StatsOpts{StorageBytes: true})
+			require.NoError(t, err)
+			assert.Equal(t, orm.TableStats{RowCount: 2, IndexCount: 1, StorageBytes: storageBytes}, stats)
+		})
+	}
+}
+
+func TestTableHooks(t *testing.T) {
+	interfaceRegistry := types.NewInterfaceRegistry()
+	cdc := codec.NewProtoCodec(interfaceRegistry)
+	storeKey := sdk.NewKVStoreKey("test")
+	const (
+		anyPrefix     = 0x10
+		counterPrefix = 0x11
+	)
+	db := dbm.NewMemDB()
+	cms := store.NewCommitMultiStore(db)
+	cms.MountStoreWithDB(storeKey, sdk.StoreTypeIAVL, db)
+	require.NoError(t, cms.LoadLatestVersion())
+	ctx := sdk.NewContext(cms, tmproto.Header{}, false, log.NewNopLogger())
+
+	tableBuilder := orm.NewTableBuilder(anyPrefix, storeKey, &testdata.GroupInfo{}, orm.Max255DynamicLengthIndexKeyCodec{}, cdc)
+	tableBuilder.WithRowCounter(counterPrefix)
+	idx := orm.NewIndex(tableBuilder, GroupByAdminIndexPrefix, func(val interface{}) ([]orm.RowID, error) {
+		return []orm.RowID{[]byte(val.(*testdata.GroupInfo).Description)}, nil
+	})
+	tableBuilder.OnCreate(func(ctx sdk.Context, model codec.ProtoMarshaler, rowID orm.RowID) error {
+		if model.(*testdata.GroupInfo).Description == "rejected" {
+			return testdata.ErrTest
+		}
+		ctx.EventManager().EmitEvent(sdk.NewEvent("create", sdk.NewAttribute("row_id", string(rowID))))
+		return nil
+	})
+	tableBuilder.OnDelete(func(ctx sdk.Context, rowID orm.RowID) error {
+		if string(rowID) == "my-id-2" {
+			return testdata.ErrTest
+		}
+		ctx.EventManager().EmitEvent(sdk.NewEvent("delete", sdk.NewAttribute("row_id", string(rowID))))
+		return nil
+	})
+	myTable := tableBuilder.Build()
+
+	require.NoError(t, myTable.Create(ctx, []byte("my-id-1"), &testdata.GroupInfo{Description: "my test"}))
+	// the hooks run with a regen types.Context as well
+	require.NoError(t, myTable.Create(regentypes.Context{Context: ctx}, []byte("my-id-2"), &testdata.GroupInfo{Description: "my test"}))
+	assert.Equal(t, sdk.Events{
+		sdk.NewEvent("create", sdk.NewAttribute("row_id", "my-id-1")),
+		sdk.NewEvent("create", sdk.NewAttribute("row_id", "my-id-2")),
+	}, ctx.EventManager().Events())
+
+	// an error of a hook aborts the operation
+	err := myTable.Create(ctx, []byte("my-id-3"), &testdata.GroupInfo{Description: "rejected"})
+	assert.True(t, testdata.ErrTest.Is(err), err)
+	assert.False(t, myTable.Has(ctx, []byte("my-id-3")))
+	assert.False(t, idx.Has(ctx, []byte("rejected")))
+	n, err := myTable.Count(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), n)
+
+	err = myTable.BulkCreate(ctx, []orm.RowID{[]byte("my-id-3"), []byte("my-id-4")}, []codec.ProtoMarshaler{
+		&testdata.GroupInfo{Description: "my test"},
+		&testdata.GroupInfo{Description: "rejected"},
+	})
+	assert.True(t, testdata.ErrTest.Is(err), err)
+	assert.False(t, myTable.Has(ctx, []byte("my-id-3")))
+
+	err = myTable.Delete(ctx, []byte("my-id-2"))
+	assert.True(t, testdata.ErrTest.Is(err), err)
+	assert.True(t, myTable.Has(ctx, []byte("my-id-2")))
+
+	ctx = ctx.WithEventManager(sdk.NewEventManager())
+	require.NoError(t, myTable.Delete(ctx, []byte("my-id-1")))
+	assert.Equal(t, sdk.Events{sdk.NewEvent("delete", sdk.NewAttribute("row_id", "my-id-1"))}, ctx.EventManager().Events())
+
+	// the hooks run with any HasSDKContext
+	require.NoError(t, myTable.Create(orm.NewMockContext(), []byte("my-id-5"), &testdata.GroupInfo{Description: "my test"}))
+
+	// and a context without a sdk.Context is rejected
+	err = myTable.Create(orm.NewGasCountingMockContext(ctx), []byte("my-id-6"), &testdata.GroupInfo{Description: "my test"})
+	assert.True(t, orm.ErrArgument.Is(err), err)
+	assert.False(t, myTable.Has(ctx, []byte("my-id-6")))
+}
@@ -8,6 +8,8 @@ import (
 	"github.com/cosmos/cosmos-sdk/store/gaskv"
 	"github.com/cosmos/cosmos-sdk/store/types"
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/tendermint/tendermint/libs/log"
+	tmproto "github.com/tendermint/tendermint/proto/tendermint/types"
 	dbm "github.com/tendermint/tm-db"
 )
 
@@ -35,6 +37,12 @@ func (m MockContext) KVStore(key sdk.StoreKey) sdk.KVStore {
 	return m.store.GetCommitKVStore(key)
 }
 
+// SDKContext returns a sdk.Context of the store, so that the table hooks run in tests. Only the stores
+// that were accessed with `KVStore` before are mounted.
+func (m MockContext) SDKContext() sdk.Context {
+	return sdk.NewContext(m.store, tmproto.Header{}, false, log.NewNopLogger())
+}
+
 type debuggingGasMeter struct {
 	g types.GasMeter
 }
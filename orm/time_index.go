@@ -0,0 +1,181 @@
+package orm
+
+import (
+	"encoding/binary"
+	"math"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/cosmos/cosmos-sdk/types/query"
+)
+
+// UnixNanoTimeLength is the number of bytes of a timestamp encoded with `EncodeUnixNanoTime`.
+const UnixNanoTimeLength = 8
+
+var (
+	// minEncodedTime and maxEncodedTime are the bounds of the timestamps that can be encoded in unix nanos.
+	// The smallest int64 is reserved for the zero value of time.Time.
+	minEncodedTime = time.Unix(0, math.MinInt64+1)
+	maxEncodedTime = time.Unix(0, math.MaxInt64)
+)
+
+// EncodeUnixNanoTime encodes a timestamp as index key of a `TimeIndex` with a fixed width of 8 bytes: the
+// unix nanos in big endian order with the sign bit flipped, so that the byte order of the keys matches the
+// order of the timestamps, including times before 1970 and independent of their location. The zero value of
+// time.Time is encoded with 8 zero bytes and sorts before all other timestamps.
+// This is not the encoding of a `TimeField` of a `CompositeIndex`, which has 12 bytes to cover all years,
+// so that keys of one can not be compared with or decoded as keys of the other.
+// An `ErrArgument` error is returned for timestamps that can not be represented in unix nanos, which are
+// the ones before the year 1678 or after the year 2262.
+func EncodeUnixNanoTime(t time.Time) ([]byte, error) {
+	bz := make([]byte, UnixNanoTimeLength)
+	if t.IsZero() {
+		return bz, nil
+	}
+	if t.Before(minEncodedTime) || t.After(maxEncodedTime) {
+		return nil, errors.Wrapf(ErrArgument, "time %s out of range for unix nanos", t)
+	}
+	binary.BigEndian.PutUint64(bz, uint64(t.UnixNano())^1<<63)
+	return bz, nil
+}
+
+// DecodeUnixNanoTime returns the timestamp of a key encoded with `EncodeUnixNanoTime` in UTC. 8 zero bytes
+// are decoded to the zero value of time.Time.
+// An `ErrArgument` error is returned for keys that are not 8 bytes long.
+func DecodeUnixNanoTime(bz []byte) (time.Time, error) {
+	if len(bz) != UnixNanoTimeLength {
+		return time.Time{}, errors.Wrapf(ErrArgument, "expected %d bytes but got %d", UnixNanoTimeLength, len(bz))
+	}
+	n := binary.BigEndian.Uint64(bz)
+	if n == 0 {
+		return time.Time{}, nil
+	}
+	return time.Unix(0, int64(n^1<<63)).UTC(), nil
+}
+
+// DecodeTimeCursor returns the timestamp and the RowID of a page key that was returned as NextKey for a
+// paginated `TimeIndex` query, so that query servers can report where a page ends.
+func DecodeTimeCursor(cursor []byte) (time.Time, RowID, error) {
+	indexKey, rowID, err := DecodeCursor(cursor)
+	if err != nil {
+		return time.Time{}, nil, err
+	}
+	t, err := DecodeUnixNanoTime(indexKey)
+	if err != nil {
+		return time.Time{}, nil, err
+	}
+	return t, rowID, nil
+}
+
+// TimeIndexerFunc creates one or multiple multiKeyIndex keys of type time.Time for the source object.
+type TimeIndexerFunc func(value interface{}) ([]time.Time, error)
+
+// TimeMultiKeyAdapter converts TimeIndexerFunc to IndexerFunc
+func TimeMultiKeyAdapter(indexer TimeIndexerFunc) IndexerFunc {
+	return func(value interface{}) ([]RowID, error) {
+		d, err := indexer(value)
+		if err != nil {
+			return nil, err
+		}
+		r := make([]RowID, len(d))
+		for i, v := range d {
+			if r[i], err = EncodeUnixNanoTime(v); err != nil {
+				return nil, err
+			}
+		}
+		return r, nil
+	}
+}
+
+// TimeIndex is a typed index with timestamp keys, like the start dates of credit batches. The keys are
+// encoded with `EncodeUnixNanoTime`.
+type TimeIndex struct {
+	multiKeyIndex MultiKeyIndex
+}
+
+// NewTimeIndex creates a typed secondary index
+func NewTimeIndex(builder Indexable, prefix byte, indexer TimeIndexerFunc) TimeIndex {
+	return TimeIndex{
+		multiKeyIndex: NewIndex(builder, prefix, TimeMultiKeyAdapter(indexer)),
+	}
+}
+
+// WithMaxCountTotal returns a copy of the index that counts at most max elements for the total of a
+// page requested by key. See `MultiKeyIndex.WithMaxCountTotal`.
+func (i TimeIndex) WithMaxCountTotal(max uint64) TimeIndex {
+	i.multiKeyIndex = i.multiKeyIndex.WithMaxCountTotal(max)
+	return i
+}
+
+// Has checks if a key exists.
+func (i TimeIndex) Has(ctx HasKVStore, key time.Time) (bool, error) {
+	bz, err := EncodeUnixNanoTime(key)
+	if err != nil {
+		return false, err
+	}
+	return i.multiKeyIndex.Has(ctx, bz), nil
+}
+
+// Get returns a result iterator for the searchKey.
+func (i TimeIndex) Get(ctx HasKVStore, searchKey time.Time) (Iterator, error) {
+	bz, err := EncodeUnixNanoTime(searchKey)
+	if err != nil {
+		return NewInvalidIterator(), err
+	}
+	return i.multiKeyIndex.Get(ctx, bz)
+}
+
+// GetPaginated creates an iterator for the searchKey
+// starting from pageRequest.Key if provided.
+// The pageRequest.Key is a cursor, see `MultiKeyIndex.GetPaginated`, that `DecodeTimeCursor` decodes.
+func (i TimeIndex) GetPaginated(ctx HasKVStore, searchKey time.Time, pageRequest *query.PageRequest) (Iterator, error) {
+	bz, err := EncodeUnixNanoTime(searchKey)
+	if err != nil {
+		return NewInvalidIterator(), err
+	}
+	return i.multiKeyIndex.GetPaginated(ctx, bz, pageRequest)
+}
+
+// ScanBetween returns an Iterator over the objects with keys from `from` to the exclusive `to` in ascending
+// order. From must be before to, or the Iterator is invalid and error is returned.
+// Iterator must be closed by caller.
+//
+// WARNING: The use of a ScanBetween can be very expensive in terms of Gas. Please make sure you do not expose
+// this as an endpoint to the public without further limits. See `LimitIterator`
+//
+// CONTRACT: No writes may happen within a domain while an iterator exists over it.
+func (i TimeIndex) ScanBetween(ctx HasKVStore, from, to time.Time) (Iterator, error) {
+	start, end, err := encodeTimeRange(from, to)
+	if err != nil {
+		return NewInvalidIterator(), err
+	}
+	return i.multiKeyIndex.PrefixScan(ctx, start, end)
+}
+
+// ReverseScanBetween returns an Iterator over the objects with keys from `from` to the exclusive `to` in
+// descending order. From must be before to, or the Iterator is invalid and error is returned.
+// Iterator must be closed by caller.
+//
+// CONTRACT: No writes may happen within a domain while an iterator exists over it.
+func (i TimeIndex) ReverseScanBetween(ctx HasKVStore, from, to time.Time) (Iterator, error) {
+	start, end, err := encodeTimeRange(from, to)
+	if err != nil {
+		return NewInvalidIterator(), err
+	}
+	return i.multiKeyIndex.ReversePrefixScan(ctx, start, end)
+}
+
+func encodeTimeRange(from, to time.Time) ([]byte, []byte, error) {
+	if !from.Before(to) {
+		return nil, nil, errors.Wrap(ErrArgument, "from must be before to")
+	}
+	start, err := EncodeUnixNanoTime(from)
+	if err != nil {
+		return nil, nil, err
+	}
+	end, err := EncodeUnixNanoTime(to)
+	if err != nil {
+		return nil, nil, err
+	}
+	return start, end, nil
+}
@@ -0,0 +1,223 @@
+package orm_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	"github.com/cosmos/cosmos-sdk/codec/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/query"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/regen-network/regen-ledger/orm"
+	"github.com/regen-network/regen-ledger/testutil/testdata"
+)
+
+var (
+	// the daylight saving time of New York ends on 2021-11-07 at 2:00 EDT, when the clock is set back to 1:00 EST
+	edt = time.FixedZone("EDT", -4*60*60)
+	est = time.FixedZone("EST", -5*60*60)
+)
+
+func TestTimeEncodingOrder(t *testing.T) {
+	// timestamps in ascending order
+	times := []time.Time{
+		{},
+		time.Date(1678, 1, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(1969, 12, 31, 23, 59, 59, 0, time.UTC),
+		time.Date(1969, 12, 31, 23, 59, 59, 999999999, time.UTC),
+		time.Unix(0, 0),
+		time.Unix(0, 1),
+		time.Unix(0, 999999999),
+		time.Unix(1, 0),
+		// the wall clock of the second timestamp is earlier, after the change to standard time
+		time.Date(2021, 11, 7, 1, 30, 0, 0, edt),
+		time.Date(2021, 11, 7, 1, 10, 0, 0, est),
+		time.Date(2021, 11, 7, 1, 10, 0, 1, est),
+		time.Date(2021, 11, 7, 6, 10, 0, 2, time.UTC),
+		time.Date(2262, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	var last []byte
+	for i, ts := range times {
+		bz, err := orm.EncodeUnixNanoTime(ts)
+		require.NoError(t, err)
+		require.Len(t, bz, orm.UnixNanoTimeLength)
+		if i != 0 {
+			assert.Equal(t, -1, bytes.Compare(last, bz), "time %d: %s", i, ts)
+		}
+		last = bz
+
+		decoded, err := orm.DecodeUnixNanoTime(bz)
+		require.NoError(t, err)
+		assert.True(t, ts.Equal(decoded), "time %d: %s != %s", i, ts, decoded)
+	}
+
+	// the location is not encoded
+	bz1, err := orm.EncodeUnixNanoTime(time.Date(2021, 11, 7, 1, 10, 0, 0, est))
+	require.NoError(t, err)
+	bz2, err := orm.EncodeUnixNanoTime(time.Date(2021, 11, 7, 2, 10, 0, 0, edt))
+	require.NoError(t, err)
+	assert.Equal(t, bz1, bz2)
+
+	// the zero value
+	bz, err := orm.EncodeUnixNanoTime(time.Time{})
+	require.NoError(t, err)
+	assert.Equal(t, make([]byte, orm.UnixNanoTimeLength), bz)
+	decoded, err := orm.DecodeUnixNanoTime(bz)
+	require.NoError(t, err)
+	assert.True(t, decoded.IsZero())
+
+	for msg, ts := range map[string]time.Time{
+		"before 1678": time.Date(1677, 1, 1, 0, 0, 0, 0, time.UTC),
+		"after 2262":  time.Date(2263, 1, 1, 0, 0, 0, 0, time.UTC),
+		"year 1":      time.Date(1, 1, 1, 0, 0, 0, 1, time.UTC),
+	} {
+		t.Run(msg, func(t *testing.T) {
+			_, err := orm.EncodeUnixNanoTime(ts)
+			assert.True(t, orm.ErrArgument.Is(err), err)
+		})
+	}
+	_, err = orm.DecodeUnixNanoTime([]byte{1, 2, 3})
+	assert.True(t, orm.ErrArgument.Is(err), err)
+}
+
+func TestTimeIndex(t *testing.T) {
+	interfaceRegistry := types.NewInterfaceRegistry()
+	cdc := codec.NewProtoCodec(interfaceRegistry)
+
+	storeKey := sdk.NewKVStoreKey("test")
+	const (
+		testTablePrefix = iota
+		testTableSeqPrefix
+	)
+	tBuilder := orm.NewAutoUInt64TableBuilder(testTablePrefix, testTableSeqPrefix, storeKey, &testdata.GroupInfo{}, cdc)
+	// the description is the creation time of the group for the test
+	byTimeIdx := orm.NewTimeIndex(tBuilder, GroupByAdminIndexPrefix, func(val interface{}) ([]time.Time, error) {
+		ts, err := time.Parse(time.RFC3339Nano, val.(*testdata.GroupInfo).Description)
+		if err != nil {
+			return nil, err
+		}
+		return []time.Time{ts}, nil
+	})
+	tb := tBuilder.Build()
+
+	ctx := orm.NewMockContext()
+	g1 := testdata.GroupInfo{GroupId: 1, Description: "2021-11-07T01:30:00-04:00"}
+	g2 := testdata.GroupInfo{GroupId: 2, Description: "2021-11-07T01:10:00-05:00"}
+	g3 := testdata.GroupInfo{GroupId: 3, Description: "2021-11-07T01:10:00.000000001-05:00"}
+	g4 := testdata.GroupInfo{GroupId: 4, Description: "1969-07-20T20:17:40Z"}
+	for _, g := range []testdata.GroupInfo{g1, g2, g3, g4} {
+		_, err := tb.Create(ctx, &g)
+		require.NoError(t, err)
+	}
+	_, err := tb.Create(ctx, &testdata.GroupInfo{Description: "0001-01-01T00:00:00.000000001Z"})
+	assert.True(t, orm.ErrArgument.Is(err), err)
+
+	// Has and Get
+	ts2 := time.Date(2021, 11, 7, 1, 10, 0, 0, est)
+	exists, err := byTimeIdx.Has(ctx, ts2.UTC())
+	require.NoError(t, err)
+	assert.True(t, exists)
+	exists, err = byTimeIdx.Has(ctx, ts2.Add(time.Nanosecond*2))
+	require.NoError(t, err)
+	assert.False(t, exists)
+	it, err := byTimeIdx.Get(ctx, ts2)
+	require.NoError(t, err)
+	var loaded []testdata.GroupInfo
+	_, err = orm.ReadAll(it, &loaded)
+	require.NoError(t, err)
+	assert.Equal(t, []testdata.GroupInfo{g2}, loaded)
+
+	specs := map[string]struct {
+		from, to time.Time
+		exp      []testdata.GroupInfo
+		expErr   bool
+	}{
+		"all": {
+			from: time.Date(1900, 1, 1, 0, 0, 0, 0, time.UTC),
+			to:   time.Date(2100, 1, 1, 0, 0, 0, 0, time.UTC),
+			exp:  []testdata.GroupInfo{g4, g1, g2, g3},
+		},
+		"from the zero value": {
+			from: time.Time{},
+			to:   time.Unix(0, 0),
+			exp:  []testdata.GroupInfo{g4},
+		},
+		"to is exclusive": {
+			from: time.Date(2021, 11, 7, 1, 30, 0, 0, edt),
+			to:   time.Date(2021, 11, 7, 1, 10, 0, 1, est),
+			exp:  []testdata.GroupInfo{g1, g2},
+		},
+		"sub second": {
+			from: time.Date(2021, 11, 7, 1, 10, 0, 1, est),
+			to:   time.Date(2021, 11, 7, 1, 10, 0, 2, est),
+			exp:  []testdata.GroupInfo{g3},
+		},
+		"none": {
+			from: time.Date(2021, 11, 7, 1, 10, 1, 0, est),
+			to:   time.Date(2100, 1, 1, 0, 0, 0, 0, time.UTC),
+			exp:  []testdata.GroupInfo{},
+		},
+		"from not before to": {
+			from:   ts2,
+			to:     ts2,
+			expErr: true,
+		},
+		"out of range": {
+			from:   time.Date(1, 1, 1, 0, 0, 0, 1, time.UTC),
+			to:     ts2,
+			expErr: true,
+		},
+	}
+	for msg, spec := range specs {
+		t.Run(msg, func(t *testing.T) {
+			it, err := byTimeIdx.ScanBetween(ctx, spec.from, spec.to)
+			if spec.expErr {
+				assert.True(t, orm.ErrArgument.Is(err), err)
+				return
+			}
+			require.NoError(t, err)
+			var loaded []testdata.GroupInfo
+			_, err = orm.ReadAll(it, &loaded)
+			require.NoError(t, err)
+			assert.Equal(t, spec.exp, loaded)
+
+			it, err = byTimeIdx.ReverseScanBetween(ctx, spec.from, spec.to)
+			require.NoError(t, err)
+			loaded = nil
+			_, err = orm.ReadAll(it, &loaded)
+			require.NoError(t, err)
+			for i, j := 0, len(loaded)-1; i < j; i, j = i+1, j-1 {
+				loaded[i], loaded[j] = loaded[j], loaded[i]
+			}
+			assert.Equal(t, spec.exp, loaded)
+		})
+	}
+
+	t.Run("decode cursor", func(t *testing.T) {
+		g5 := testdata.GroupInfo{GroupId: 5, Description: g2.Description}
+		id5, err := tb.Create(ctx, &g5)
+		require.NoError(t, err)
+		pageReq := &query.PageRequest{Limit: 1}
+		it, err := byTimeIdx.GetPaginated(ctx, ts2, pageReq)
+		require.NoError(t, err)
+		var loaded []testdata.GroupInfo
+		res, err := orm.Paginate(it, pageReq, &loaded)
+		require.NoError(t, err)
+		require.Equal(t, []testdata.GroupInfo{g2}, loaded)
+
+		// the next key is the cursor of g5
+		ts, rowID, err := orm.DecodeTimeCursor(res.NextKey)
+		require.NoError(t, err)
+		assert.True(t, ts2.Equal(ts))
+		assert.Equal(t, orm.RowID(orm.EncodeSequence(id5)), rowID)
+
+		_, _, err = orm.DecodeTimeCursor([]byte("invalid"))
+		assert.True(t, orm.ErrArgument.Is(err), err)
+		_, _, err = orm.DecodeTimeCursor(orm.EncodeCursor([]byte{1}, orm.EncodeSequence(2)))
+		assert.True(t, orm.ErrArgument.Is(err), err)
+	})
+}
@@ -0,0 +1,100 @@
+package orm
+
+import (
+	"github.com/cosmos/cosmos-sdk/codec"
+	"github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+// TransactionalIterator returns a new iterator over the rows of the table that the parent iterator returns,
+// like a table or index scan, together with a rollback function for "process or undo" operations. The
+// iterator records a snapshot of every row that it returns, before the caller can modify it. The rollback
+// function closes the iterator and restores the persisted bytes of the snapshots in the store of ctx in
+// reverse order, for rows that were modified or deleted, and updates the indexes and the row counter of the
+// table. The hooks of `TableBuilder.OnCreate` do not run again for restored rows. Rows that were not
+// returned by the iterator, like new rows created by the caller, are not touched. The snapshots are
+// released then and further calls return nil.
+//
+// The snapshots are held in memory, so the number of rows should be bound, for example with a
+// `LimitIterator`. Within a transaction that can fail as a whole, a cached context is the cheaper way to
+// discard the writes.
+// The parent iterator must not be nil.
+func TransactionalIterator(ctx HasKVStore, table Table, parent Iterator) (Iterator, func() error) {
+	if parent == nil {
+		panic("parent iterator must not be nil")
+	}
+	it := &transactionalIterator{
+		ctx:            ctx,
+		table:          table,
+		parentIterator: parent,
+		rawRowGetter:   NewRawRowGetter(table.storeKey, table.prefix),
+		seen:           make(map[string]struct{}),
+	}
+	return it, it.rollback
+}
+
+// rowSnapshot is the persisted value of a row when it was returned by a transactionalIterator.
+type rowSnapshot struct {
+	rowID RowID
+	value []byte
+}
+
+// transactionalIterator records the snapshots of the rows returned by the parent iterator.
+type transactionalIterator struct {
+	ctx            HasKVStore
+	table          Table
+	parentIterator Iterator
+	rawRowGetter   RawRowGetter
+	snapshots      []rowSnapshot
+	seen           map[string]struct{}
+	closed         bool
+}
+
+// LoadNext loads the next value of the parent iterator into the pointer passed as dest and records the
+// persisted value of the row for a rollback.
+// If there are no more items the `ErrIteratorDone` error is returned
+func (i *transactionalIterator) LoadNext(dest codec.ProtoMarshaler) (RowID, error) {
+	if i.closed {
+		return nil, ErrIteratorClosed
+	}
+	rowID, err := i.parentIterator.LoadNext(dest)
+	if err != nil {
+		return rowID, err
+	}
+	// a row can be returned more than once, like by an index with multiple keys for it
+	if _, ok := i.seen[string(rowID)]; ok {
+		return rowID, nil
+	}
+	value, err := i.rawRowGetter(i.ctx, rowID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "snapshot of row %X", rowID)
+	}
+	i.seen[string(rowID)] = struct{}{}
+	i.snapshots = append(i.snapshots, rowSnapshot{rowID: append(RowID{}, rowID...), value: value})
+	return rowID, nil
+}
+
+// Close releases the parent iterator and should be called at the end of iteration.
+// The snapshots are kept for a rollback. Only the first call closes the parent iterator.
+func (i *transactionalIterator) Close() error {
+	if i.closed {
+		return nil
+	}
+	i.closed = true
+	return i.parentIterator.Close()
+}
+
+// rollback closes the iterator and restores the snapshots in reverse order.
+func (i *transactionalIterator) rollback() error {
+	if err := i.Close(); err != nil {
+		return err
+	}
+	for n := len(i.snapshots) - 1; n >= 0; n-- {
+		s := i.snapshots[n]
+		if err := i.table.restore(i.ctx, s.rowID, s.value); err != nil {
+			return errors.Wrapf(err, "restore row %X", s.rowID)
+		}
+		i.snapshots = i.snapshots[:n]
+	}
+	i.seen = nil
+	return nil
+}
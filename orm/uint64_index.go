@@ -34,6 +34,13 @@ func NewUInt64Index(builder Indexable, prefix byte, indexer UInt64IndexerFunc) U
 	}
 }
 
+// WithMaxCountTotal returns a copy of the index that counts at most max elements for the total of a
+// page requested by key. See `MultiKeyIndex.WithMaxCountTotal`.
+func (i UInt64Index) WithMaxCountTotal(max uint64) UInt64Index {
+	i.multiKeyIndex = i.multiKeyIndex.WithMaxCountTotal(max)
+	return i
+}
+
 // Has checks if a key exists. Panics on nil key.
 func (i UInt64Index) Has(ctx HasKVStore, key uint64) bool {
 	return i.multiKeyIndex.Has(ctx, EncodeSequence(key))
@@ -44,13 +51,26 @@ func (i UInt64Index) Get(ctx HasKVStore, searchKey uint64) (Iterator, error) {
 	return i.multiKeyIndex.Get(ctx, EncodeSequence(searchKey))
 }
 
+// ReverseGet returns a result iterator for the searchKey in descending order. Parameters must not be nil.
+func (i UInt64Index) ReverseGet(ctx HasKVStore, searchKey uint64) (Iterator, error) {
+	return i.multiKeyIndex.ReverseGet(ctx, EncodeSequence(searchKey))
+}
+
 // GetPaginated creates an iterator for the searchKey
 // starting from pageRequest.Key if provided.
 // The pageRequest.Key is the rowID while searchKey is a MultiKeyIndex key.
+// An empty pageRequest.Key is treated like a nil one, see `MultiKeyIndex.GetPaginated`.
 func (i UInt64Index) GetPaginated(ctx HasKVStore, searchKey uint64, pageRequest *query.PageRequest) (Iterator, error) {
 	return i.multiKeyIndex.GetPaginated(ctx, EncodeSequence(searchKey), pageRequest)
 }
 
+// ReverseGetPaginated creates an iterator for the searchKey in descending order
+// starting from pageRequest.Key if provided.
+// The pageRequest.Key is the rowID while searchKey is a MultiKeyIndex key.
+func (i UInt64Index) ReverseGetPaginated(ctx HasKVStore, searchKey uint64, pageRequest *query.PageRequest) (Iterator, error) {
+	return i.multiKeyIndex.ReverseGetPaginated(ctx, EncodeSequence(searchKey), pageRequest)
+}
+
 // PrefixScan returns an Iterator over a domain of keys in ascending order. End is exclusive.
 // Start is an MultiKeyIndex key or prefix. It must be less than end, or the Iterator is invalid and error is returned.
 // Iterator must be closed by caller.
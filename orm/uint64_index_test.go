@@ -52,6 +52,14 @@ func TestUInt64Index(t *testing.T) {
 	require.Equal(t, uint64(1), orm.DecodeSequence(rowID))
 	require.Equal(t, m, loaded)
 
+	// ReverseGet
+	it, err = myIndex.ReverseGet(ctx, indexedKey)
+	require.NoError(t, err)
+	rowID, err = it.LoadNext(&loaded)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), orm.DecodeSequence(rowID))
+	require.Equal(t, m, loaded)
+
 	// GetPaginated
 	cases := map[string]struct {
 		pageReq *query.PageRequest
@@ -82,6 +90,21 @@ func TestUInt64Index(t *testing.T) {
 		})
 	}
 
+	// ReverseGetPaginated
+	for testName, pageReq := range map[string]*query.PageRequest{
+		"reverse nil key":      {Key: nil},
+		"reverse existing key": {Key: m.PrimaryKey()},
+	} {
+		t.Run(testName, func(t *testing.T) {
+			it, err := myIndex.ReverseGetPaginated(ctx, indexedKey, pageReq)
+			require.NoError(t, err)
+			rowID, err := it.LoadNext(&loaded)
+			require.NoError(t, err)
+			require.Equal(t, orm.RowID(m.PrimaryKey()), rowID)
+			require.Equal(t, m, loaded)
+		})
+	}
+
 	// PrefixScan match
 	it, err = myIndex.PrefixScan(ctx, 0, 255)
 	require.NoError(t, err)
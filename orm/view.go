@@ -0,0 +1,129 @@
+package orm
+
+import (
+	"bytes"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	"github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+// TableReader is the read access to the rows of a `Table` or a `ReadOnlyTable`, so that module handlers can
+// work with the same methods whether the rows come from a table or from a view of it.
+type TableReader interface {
+	// Has checks if a key exists. Panics on nil key.
+	Has(ctx HasKVStore, key RowID) bool
+	// GetOne loads the object persisted for the given key into the dest parameter.
+	// If none exists `ErrNotFound` is returned instead. Parameters must not be nil.
+	GetOne(ctx HasKVStore, key RowID, dest codec.ProtoMarshaler) error
+	// PrefixScan returns an Iterator over a domain of keys in ascending order. End is exclusive.
+	PrefixScan(ctx HasKVStore, start, end RowID) (Iterator, error)
+	// ReversePrefixScan returns an Iterator over a domain of keys in descending order. End is exclusive.
+	ReversePrefixScan(ctx HasKVStore, start, end RowID) (Iterator, error)
+}
+
+var (
+	_ TableReader = Table{}
+	_ TableReader = ReadOnlyTable{}
+)
+
+// ReadOnlyTable is a read only view of the rows of a table that are indexed with a key that starts with
+// a prefix, like all groups of an admin. The keys of the view are the index keys without the prefix and
+// all reads delegate to the index. Create, Save and Delete return an `ErrReadOnly` error, so that the view
+// can be passed where a table is written by mistake without corrupting the index.
+type ReadOnlyTable struct {
+	index  Index
+	prefix []byte
+}
+
+// NewReadOnlyTable creates a read only view of the rows that are indexed with keys that start with the
+// prefix. An empty prefix covers all rows of the index.
+// The index must not be nil.
+func NewReadOnlyTable(index Index, prefix []byte) ReadOnlyTable {
+	if index == nil {
+		panic("index must not be nil")
+	}
+	return ReadOnlyTable{index: index, prefix: append([]byte{}, prefix...)}
+}
+
+// Has checks if an index key with the prefix and the key exists. Panics on nil key.
+func (a ReadOnlyTable) Has(ctx HasKVStore, key RowID) bool {
+	if key == nil {
+		panic("nil key not allowed")
+	}
+	return a.index.Has(ctx, a.indexKey(key))
+}
+
+// GetOne loads the first object that is indexed with the prefix and the key into the dest parameter.
+// If none exists `ErrNotFound` is returned instead. Parameters must not be nil.
+func (a ReadOnlyTable) GetOne(ctx HasKVStore, key RowID, dest codec.ProtoMarshaler) error {
+	it, err := a.index.Get(ctx, a.indexKey(key))
+	if err != nil {
+		return err
+	}
+	defer it.Close()
+	_, err = it.LoadNext(dest)
+	if IsIteratorDone(err) {
+		return ErrNotFound
+	}
+	return err
+}
+
+// PrefixScan returns an Iterator over the rows of the view with keys from start to the exclusive end in
+// ascending order. Nil start or end do not limit the domain. The RowIDs of the rows are returned.
+// Iterator must be closed by caller.
+//
+// CONTRACT: No writes may happen within a domain while an iterator exists over it.
+func (a ReadOnlyTable) PrefixScan(ctx HasKVStore, start, end RowID) (Iterator, error) {
+	start, end, err := a.domain(start, end)
+	if err != nil {
+		return NewInvalidIterator(), err
+	}
+	return a.index.PrefixScan(ctx, start, end)
+}
+
+// ReversePrefixScan returns an Iterator over the rows of the view like PrefixScan in descending order.
+// Iterator must be closed by caller.
+//
+// CONTRACT: No writes may happen within a domain while an iterator exists over it.
+func (a ReadOnlyTable) ReversePrefixScan(ctx HasKVStore, start, end RowID) (Iterator, error) {
+	start, end, err := a.domain(start, end)
+	if err != nil {
+		return NewInvalidIterator(), err
+	}
+	return a.index.ReversePrefixScan(ctx, start, end)
+}
+
+// Create returns an `ErrReadOnly` error.
+func (a ReadOnlyTable) Create(ctx HasKVStore, rowID RowID, obj codec.ProtoMarshaler) error {
+	return errors.Wrap(ErrReadOnly, "can not create in view")
+}
+
+// Save returns an `ErrReadOnly` error.
+func (a ReadOnlyTable) Save(ctx HasKVStore, rowID RowID, newValue codec.ProtoMarshaler) error {
+	return errors.Wrap(ErrReadOnly, "can not update in view")
+}
+
+// Delete returns an `ErrReadOnly` error.
+func (a ReadOnlyTable) Delete(ctx HasKVStore, rowID RowID) error {
+	return errors.Wrap(ErrReadOnly, "can not delete in view")
+}
+
+// indexKey returns the index key for a key of the view.
+func (a ReadOnlyTable) indexKey(key []byte) []byte {
+	return append(append(make([]byte, 0, len(a.prefix)+len(key)), a.prefix...), key...)
+}
+
+// domain returns the domain of index keys for the keys of the view from start to end.
+func (a ReadOnlyTable) domain(start, end RowID) ([]byte, []byte, error) {
+	if start != nil && end != nil && bytes.Compare(start, end) >= 0 {
+		return nil, nil, errors.Wrap(ErrArgument, "start must be less than end")
+	}
+	prefixStart, prefixEnd := PrefixRange(a.prefix)
+	if start != nil {
+		prefixStart = a.indexKey(start)
+	}
+	if end != nil {
+		prefixEnd = a.indexKey(end)
+	}
+	return prefixStart, prefixEnd, nil
+}
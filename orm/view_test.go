@@ -0,0 +1,104 @@
+package orm_test
+
+import (
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	"github.com/cosmos/cosmos-sdk/codec/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/regen-network/regen-ledger/orm"
+	"github.com/regen-network/regen-ledger/testutil/testdata"
+)
+
+func TestReadOnlyTable(t *testing.T) {
+	interfaceRegistry := types.NewInterfaceRegistry()
+	cdc := codec.NewProtoCodec(interfaceRegistry)
+
+	storeKey := sdk.NewKVStoreKey("test")
+	const (
+		testTablePrefix = iota
+		testTableSeqPrefix
+	)
+	tBuilder := orm.NewAutoUInt64TableBuilder(testTablePrefix, testTableSeqPrefix, storeKey, &testdata.GroupInfo{}, cdc)
+	idx := orm.NewIndex(tBuilder, GroupByAdminIndexPrefix, func(val interface{}) ([]orm.RowID, error) {
+		g := val.(*testdata.GroupInfo)
+		return []orm.RowID{append([]byte(g.Admin), g.Description...)}, nil
+	})
+	tb := tBuilder.Build()
+	ctx := orm.NewMockContext()
+
+	g1 := testdata.GroupInfo{Description: "a", Admin: sdk.AccAddress("admin-1/")}
+	g2 := testdata.GroupInfo{Description: "b", Admin: sdk.AccAddress("admin-2/")}
+	g3 := testdata.GroupInfo{Description: "c", Admin: sdk.AccAddress("admin-1/")}
+	for _, g := range []testdata.GroupInfo{g1, g2, g3} {
+		_, err := tb.Create(ctx, &g)
+		require.NoError(t, err)
+	}
+
+	var view orm.TableReader = orm.NewReadOnlyTable(idx, []byte("admin-1/"))
+	assert.True(t, view.Has(ctx, []byte("a")))
+	assert.False(t, view.Has(ctx, []byte("b")))
+
+	var loaded testdata.GroupInfo
+	require.NoError(t, view.GetOne(ctx, []byte("c"), &loaded))
+	assert.Equal(t, g3, loaded)
+	err := view.GetOne(ctx, []byte("b"), &loaded)
+	assert.True(t, orm.ErrNotFound.Is(err), err)
+
+	specs := map[string]struct {
+		scan       func(ctx orm.HasKVStore, start, end orm.RowID) (orm.Iterator, error)
+		start, end orm.RowID
+		exp        []testdata.GroupInfo
+		expRowIDs  []orm.RowID
+	}{
+		"all": {
+			scan:      view.PrefixScan,
+			exp:       []testdata.GroupInfo{g1, g3},
+			expRowIDs: []orm.RowID{orm.EncodeSequence(1), orm.EncodeSequence(3)},
+		},
+		"all reverse": {
+			scan:      view.ReversePrefixScan,
+			exp:       []testdata.GroupInfo{g3, g1},
+			expRowIDs: []orm.RowID{orm.EncodeSequence(3), orm.EncodeSequence(1)},
+		},
+		"from start": {
+			scan:      view.PrefixScan,
+			start:     []byte("b"),
+			exp:       []testdata.GroupInfo{g3},
+			expRowIDs: []orm.RowID{orm.EncodeSequence(3)},
+		},
+		"to end": {
+			scan:      view.PrefixScan,
+			end:       []byte("b"),
+			exp:       []testdata.GroupInfo{g1},
+			expRowIDs: []orm.RowID{orm.EncodeSequence(1)},
+		},
+	}
+	for msg, spec := range specs {
+		t.Run(msg, func(t *testing.T) {
+			it, err := spec.scan(ctx, spec.start, spec.end)
+			require.NoError(t, err)
+			var loaded []testdata.GroupInfo
+			rowIDs, err := orm.ReadAll(it, &loaded)
+			require.NoError(t, err)
+			assert.Equal(t, spec.exp, loaded)
+			assert.Equal(t, spec.expRowIDs, rowIDs)
+		})
+	}
+
+	_, err = view.PrefixScan(ctx, []byte("b"), []byte("a"))
+	assert.True(t, orm.ErrArgument.Is(err), err)
+
+	// the view can not be written
+	ro := orm.NewReadOnlyTable(idx, []byte("admin-1/"))
+	err = ro.Create(ctx, orm.EncodeSequence(4), &g1)
+	assert.True(t, orm.ErrReadOnly.Is(err), err)
+	err = ro.Save(ctx, orm.EncodeSequence(1), &g1)
+	assert.True(t, orm.ErrReadOnly.Is(err), err)
+	err = ro.Delete(ctx, orm.EncodeSequence(1))
+	assert.True(t, orm.ErrReadOnly.Is(err), err)
+	assert.True(t, tb.Has(ctx, 1))
+}
@@ -144,34 +144,90 @@ func (m *GroupMember) GetWeight() uint64 {
 	return 0
 }
 
+type GroupMemberWithGroup struct {
+	Member *GroupMember `protobuf:"bytes,1,opt,name=member,proto3" json:"member,omitempty"`
+	Group  *GroupInfo   `protobuf:"bytes,2,opt,name=group,proto3" json:"group,omitempty"`
+}
+
+func (m *GroupMemberWithGroup) Reset()         { *m = GroupMemberWithGroup{} }
+func (m *GroupMemberWithGroup) String() string { return proto.CompactTextString(m) }
+func (*GroupMemberWithGroup) ProtoMessage()    {}
+func (*GroupMemberWithGroup) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9610d574777ab505, []int{2}
+}
+func (m *GroupMemberWithGroup) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *GroupMemberWithGroup) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_GroupMemberWithGroup.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *GroupMemberWithGroup) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GroupMemberWithGroup.Merge(m, src)
+}
+func (m *GroupMemberWithGroup) XXX_Size() int {
+	return m.Size()
+}
+func (m *GroupMemberWithGroup) XXX_DiscardUnknown() {
+	xxx_messageInfo_GroupMemberWithGroup.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GroupMemberWithGroup proto.InternalMessageInfo
+
+func (m *GroupMemberWithGroup) GetMember() *GroupMember {
+	if m != nil {
+		return m.Member
+	}
+	return nil
+}
+
+func (m *GroupMemberWithGroup) GetGroup() *GroupInfo {
+	if m != nil {
+		return m.Group
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*GroupInfo)(nil), "testdata.GroupInfo")
 	proto.RegisterType((*GroupMember)(nil), "testdata.GroupMember")
+	proto.RegisterType((*GroupMemberWithGroup)(nil), "testdata.GroupMemberWithGroup")
 }
 
 func init() { proto.RegisterFile("codec.proto", fileDescriptor_9610d574777ab505) }
 
 var fileDescriptor_9610d574777ab505 = []byte{
-	// 301 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x91, 0xb1, 0x4e, 0xf3, 0x30,
-	0x14, 0x85, 0xeb, 0xff, 0x2f, 0xa5, 0x75, 0x99, 0x2c, 0x84, 0x0a, 0x83, 0x89, 0x3a, 0x75, 0x69,
-	0x23, 0xc4, 0xc0, 0xdc, 0x2e, 0x55, 0x06, 0x06, 0x32, 0xb2, 0xa0, 0xc4, 0xbe, 0xb8, 0x56, 0x9b,
-	0xdc, 0xc8, 0x76, 0x54, 0xf1, 0x16, 0xf0, 0x38, 0xbc, 0x01, 0x63, 0x47, 0x26, 0x84, 0x92, 0xb7,
-	0x60, 0x42, 0x71, 0x83, 0xd4, 0xb9, 0x93, 0x7d, 0xae, 0x75, 0x3e, 0x9f, 0xab, 0x43, 0x87, 0x02,
-	0x25, 0x88, 0x59, 0x61, 0xd0, 0x21, 0xeb, 0x3b, 0xb0, 0x4e, 0x26, 0x2e, 0xb9, 0x3a, 0x57, 0xa8,
-	0xd0, 0x0f, 0xc3, 0xe6, 0xb6, 0x7f, 0x1f, 0xbf, 0x11, 0x3a, 0x58, 0x1a, 0x2c, 0x8b, 0x28, 0x7f,
-	0x46, 0x76, 0x49, 0xfb, 0xaa, 0x11, 0x4f, 0x5a, 0x8e, 0x48, 0x40, 0x26, 0xdd, 0xf8, 0xd4, 0xeb,
-	0x48, 0xb2, 0x80, 0x0e, 0x25, 0x58, 0x61, 0x74, 0xe1, 0x34, 0xe6, 0xa3, 0x7f, 0x01, 0x99, 0x0c,
-	0xe2, 0xc3, 0x11, 0x5b, 0xd2, 0x93, 0x44, 0x66, 0x3a, 0x1f, 0xfd, 0x0f, 0xc8, 0xe4, 0x6c, 0x71,
-	0xf3, 0xf3, 0x75, 0x3d, 0x55, 0xda, 0xad, 0xca, 0x74, 0x26, 0x30, 0x0b, 0x05, 0xda, 0x0c, 0x6d,
-	0x7b, 0x4c, 0xad, 0x5c, 0x87, 0xee, 0xa5, 0x00, 0x3b, 0x9b, 0x0b, 0x31, 0x97, 0xd2, 0x80, 0xb5,
-	0xf1, 0xde, 0x3f, 0x7e, 0x27, 0x74, 0xe8, 0x33, 0xdd, 0x43, 0x96, 0x82, 0x69, 0xc0, 0x3e, 0x85,
-	0x8f, 0x74, 0x1c, 0xd8, 0xfb, 0x59, 0x44, 0x7b, 0x99, 0x47, 0xfa, 0xf8, 0x47, 0x91, 0x5a, 0x00,
-	0xbb, 0xa0, 0xbd, 0x2d, 0x68, 0xb5, 0x72, 0x7e, 0xdb, 0x6e, 0xdc, 0xaa, 0xc5, 0xc3, 0x47, 0xc5,
-	0xc9, 0xae, 0xe2, 0xe4, 0xbb, 0xe2, 0xe4, 0xb5, 0xe6, 0x9d, 0x5d, 0xcd, 0x3b, 0x9f, 0x35, 0xef,
-	0x3c, 0xde, 0x1d, 0x7c, 0x64, 0x40, 0x41, 0x3e, 0xcd, 0xc1, 0x6d, 0xd1, 0xac, 0x5b, 0xb5, 0x01,
-	0xa9, 0xc0, 0x84, 0x4d, 0x5f, 0xa5, 0xd3, 0x9b, 0xf0, 0xaf, 0xb8, 0xb4, 0xe7, 0x9b, 0xba, 0xfd,
-	0x0d, 0x00, 0x00, 0xff, 0xff, 0x30, 0x1e, 0x1d, 0xe3, 0xd8, 0x01, 0x00, 0x00,
+	// 338 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x92, 0xb1, 0x4e, 0xc3, 0x30,
+	0x10, 0x86, 0xeb, 0x52, 0x4a, 0xeb, 0x30, 0x99, 0x82, 0x02, 0x43, 0x88, 0x3a, 0x95, 0x21, 0x89,
+	0x80, 0x81, 0xb9, 0x5d, 0xaa, 0x0e, 0x0c, 0x64, 0x41, 0x62, 0x41, 0xad, 0x7d, 0xb8, 0x56, 0x9b,
+	0x38, 0xb2, 0x5d, 0x55, 0xbc, 0x05, 0x3c, 0x0e, 0x6f, 0xc0, 0xd8, 0x91, 0x09, 0xa1, 0xf6, 0x2d,
+	0x98, 0x50, 0xdc, 0xa4, 0x8a, 0x18, 0x3b, 0xd9, 0xff, 0xf9, 0xee, 0xbb, 0xfb, 0x75, 0xc6, 0x0e,
+	0x95, 0x0c, 0x68, 0x98, 0x29, 0x69, 0x24, 0x69, 0x19, 0xd0, 0x86, 0x8d, 0xcd, 0xf8, 0xa2, 0xc3,
+	0x25, 0x97, 0x36, 0x18, 0xe5, 0xb7, 0xed, 0x7b, 0xf7, 0x1d, 0xe1, 0xf6, 0x50, 0xc9, 0x45, 0x36,
+	0x4a, 0x5f, 0x24, 0x39, 0xc7, 0x2d, 0x9e, 0x8b, 0x67, 0xc1, 0x5c, 0xe4, 0xa3, 0x5e, 0x23, 0x3e,
+	0xb2, 0x7a, 0xc4, 0x88, 0x8f, 0x1d, 0x06, 0x9a, 0x2a, 0x91, 0x19, 0x21, 0x53, 0xb7, 0xee, 0xa3,
+	0x5e, 0x3b, 0xae, 0x86, 0xc8, 0x10, 0x1f, 0x8e, 0x59, 0x22, 0x52, 0xf7, 0xc0, 0x47, 0xbd, 0xe3,
+	0xc1, 0xf5, 0xef, 0xf7, 0x65, 0xc0, 0x85, 0x99, 0x2e, 0x26, 0x21, 0x95, 0x49, 0x44, 0xa5, 0x4e,
+	0xa4, 0x2e, 0x8e, 0x40, 0xb3, 0x59, 0x64, 0x5e, 0x33, 0xd0, 0x61, 0x9f, 0xd2, 0x3e, 0x63, 0x0a,
+	0xb4, 0x8e, 0xb7, 0xf5, 0xdd, 0x0f, 0x84, 0x1d, 0x3b, 0xd3, 0x3d, 0x24, 0x13, 0x50, 0x39, 0xd8,
+	0x4e, 0xe1, 0xa2, 0xbd, 0xc1, 0xb6, 0x9e, 0x8c, 0x70, 0x33, 0xb1, 0x48, 0xb7, 0xbe, 0x2f, 0xa9,
+	0x00, 0x90, 0x33, 0xdc, 0x5c, 0x82, 0xe0, 0x53, 0x63, 0xdd, 0x36, 0xe2, 0x42, 0x75, 0x33, 0xdc,
+	0xa9, 0x8c, 0xfe, 0x28, 0xcc, 0xd4, 0x4a, 0x12, 0xec, 0x5a, 0xe7, 0x26, 0x9c, 0x9b, 0xd3, 0xb0,
+	0x5c, 0x4c, 0x58, 0xc9, 0xdf, 0xe1, 0xaf, 0x4a, 0xcb, 0x75, 0x9b, 0x7d, 0xf2, 0x2f, 0x3b, 0x5f,
+	0x56, 0x61, 0x6a, 0xf0, 0xf0, 0xb9, 0xf6, 0xd0, 0x6a, 0xed, 0xa1, 0x9f, 0xb5, 0x87, 0xde, 0x36,
+	0x5e, 0x6d, 0xb5, 0xf1, 0x6a, 0x5f, 0x1b, 0xaf, 0xf6, 0x74, 0x57, 0xb1, 0xa6, 0x80, 0x43, 0x1a,
+	0xa4, 0x60, 0x96, 0x52, 0xcd, 0x0a, 0x35, 0x07, 0xc6, 0x41, 0x45, 0x39, 0x7a, 0x61, 0xc4, 0x3c,
+	0x2a, 0x7b, 0x4c, 0x9a, 0xf6, 0x6f, 0xdc, 0xfe, 0x0d, 0x00, 0x7c, 0xc4, 0xb9, 0xb9, 0x4a, 0x02,
+	0x00, 0x00,
 }
 
 func (m *GroupInfo) Marshal() (dAtA []byte, err error) {
@@ -258,6 +314,53 @@ func (m *GroupMember) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
+func (m *GroupMemberWithGroup) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *GroupMemberWithGroup) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *GroupMemberWithGroup) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Group != nil {
+		{
+			size, err := m.Group.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintCodec(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x12
+	}
+	if m.Member != nil {
+		{
+			size, err := m.Member.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintCodec(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
 func encodeVarintCodec(dAtA []byte, offset int, v uint64) int {
 	offset -= sovCodec(v)
 	base := offset
@@ -309,6 +412,23 @@ func (m *GroupMember) Size() (n int) {
 	return n
 }
 
+func (m *GroupMemberWithGroup) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Member != nil {
+		l = m.Member.Size()
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	if m.Group != nil {
+		l = m.Group.Size()
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+
 func sovCodec(x uint64) (n int) {
 	return (math_bits.Len64(x|1) + 6) / 7
 }
@@ -593,6 +713,131 @@ func (m *GroupMember) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
+func (m *GroupMemberWithGroup) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowCodec
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: GroupMemberWithGroup: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: GroupMemberWithGroup: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Member", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthCodec
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Member == nil {
+				m.Member = &GroupMember{}
+			}
+			if err := m.Member.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Group", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthCodec
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Group == nil {
+				m.Group = &GroupInfo{}
+			}
+			if err := m.Group.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipCodec(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthCodec
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthCodec
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func skipCodec(dAtA []byte) (n int, err error) {
 	l := len(dAtA)
 	iNdEx := 0
@@ -1,6 +1,7 @@
 package testdata
 
 import (
+	"github.com/cosmos/cosmos-sdk/codec"
 	"github.com/cosmos/cosmos-sdk/types/errors"
 	"github.com/regen-network/regen-ledger/orm"
 )
@@ -30,3 +31,13 @@ func (g GroupInfo) ValidateBasic() error {
 func (g GroupMember) ValidateBasic() error {
 	return nil
 }
+
+func (g *GroupMemberWithGroup) JoinModels() (codec.ProtoMarshaler, codec.ProtoMarshaler) {
+	if g.Member == nil {
+		g.Member = &GroupMember{}
+	}
+	if g.Group == nil {
+		g.Group = &GroupInfo{}
+	}
+	return g.Member, g.Group
+}
@@ -25,6 +25,11 @@ func (c Context) Err() error {
 	return c.Context.Context().Err()
 }
 
+// SDKContext returns the wrapped sdk.Context.
+func (c Context) SDKContext() sdk.Context {
+	return c.Context
+}
+
 func UnwrapSDKContext(ctx context.Context) Context {
 	if sdkCtx, ok := ctx.(Context); ok {
 		return sdkCtx
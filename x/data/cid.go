@@ -0,0 +1,58 @@
+package data
+
+import (
+	"fmt"
+
+	gocid "github.com/ipfs/go-cid"
+	"github.com/multiformats/go-multihash"
+)
+
+// ContentHashFromCID converts the bytes of an IPLD CID v1 for raw data to a ContentHash_Raw. The
+// digest algorithm is determined by the multihash function code of the CID and must be supported and
+// not weaker than the minimum of 256 bits. CIDs do not carry a media type, so the returned hash has
+// MEDIA_TYPE_UNSPECIFIED. It is used by the CLI to anchor and query data by CID.
+func ContentHashFromCID(cidBz []byte) (*ContentHash, error) {
+	cid, err := gocid.Cast(cidBz)
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := cid.Prefix()
+	if prefix.Version != 1 {
+		return nil, fmt.Errorf("expected CID v1, got v%d", prefix.Version)
+	}
+
+	if prefix.Codec != gocid.Raw {
+		return nil, fmt.Errorf("expected raw multicodec 0x%x, got 0x%x", gocid.Raw, prefix.Codec)
+	}
+
+	decoded, err := multihash.Decode(cid.Hash())
+	if err != nil {
+		return nil, err
+	}
+
+	digestAlgorithm, ok := multihashDigestAlgorithms[decoded.Code]
+	if !ok {
+		return nil, fmt.Errorf("unsupported multihash function 0x%x", decoded.Code)
+	}
+
+	raw := &ContentHash_Raw{
+		Hash:            decoded.Digest,
+		DigestAlgorithm: digestAlgorithm,
+		MediaType:       MediaType_MEDIA_TYPE_UNSPECIFIED,
+	}
+	err = raw.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	return &ContentHash{Sum: &ContentHash_Raw_{Raw: raw}}, nil
+}
+
+// multihashDigestAlgorithms are the digest algorithms of the multihash function codes,
+// see https://github.com/multiformats/multicodec/blob/master/table.csv.
+var multihashDigestAlgorithms = map[uint64]DigestAlgorithm{
+	multihash.BLAKE2B_MIN + 31: DigestAlgorithm_DIGEST_ALGORITHM_BLAKE2B_256,
+	multihash.SHA3_256:         DigestAlgorithm_DIGEST_ALGORITHM_SHA3_256,
+	multihash.SHA2_512:         DigestAlgorithm_DIGEST_ALGORITHM_SHA2_512,
+}
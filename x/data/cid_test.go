@@ -0,0 +1,86 @@
+package data
+
+import (
+	"testing"
+
+	gocid "github.com/ipfs/go-cid"
+	"github.com/multiformats/go-multihash"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContentHashFromCID(t *testing.T) {
+	content := []byte("xyzabc123")
+	cid := func(version uint64, codec uint64, mhType uint64) []byte {
+		c, err := gocid.Prefix{Version: version, Codec: codec, MhType: mhType, MhLength: -1}.Sum(content)
+		require.NoError(t, err)
+		return c.Bytes()
+	}
+	digest := func(mhType uint64) []byte {
+		mh, err := multihash.Sum(content, mhType, -1)
+		require.NoError(t, err)
+		decoded, err := multihash.Decode(mh)
+		require.NoError(t, err)
+		return decoded.Digest
+	}
+
+	tests := []struct {
+		name    string
+		cid     []byte
+		want    *ContentHash_Raw
+		wantErr string
+	}{
+		{
+			"blake2b-256",
+			cid(1, gocid.Raw, multihash.BLAKE2B_MIN+31),
+			&ContentHash_Raw{Hash: digest(multihash.BLAKE2B_MIN + 31), DigestAlgorithm: DigestAlgorithm_DIGEST_ALGORITHM_BLAKE2B_256},
+			"",
+		},
+		{
+			"sha3-256",
+			cid(1, gocid.Raw, multihash.SHA3_256),
+			&ContentHash_Raw{Hash: digest(multihash.SHA3_256), DigestAlgorithm: DigestAlgorithm_DIGEST_ALGORITHM_SHA3_256},
+			"",
+		},
+		{
+			"sha2-512",
+			cid(1, gocid.Raw, multihash.SHA2_512),
+			&ContentHash_Raw{Hash: digest(multihash.SHA2_512), DigestAlgorithm: DigestAlgorithm_DIGEST_ALGORITHM_SHA2_512},
+			"",
+		},
+		{
+			"unsupported hash function",
+			cid(1, gocid.Raw, multihash.SHA2_256),
+			nil,
+			"unsupported multihash function 0x12",
+		},
+		{
+			"cid v0",
+			cid(0, gocid.DagProtobuf, multihash.SHA2_256),
+			nil,
+			"expected CID v1, got v0",
+		},
+		{
+			"other codec",
+			cid(1, gocid.DagCBOR, multihash.SHA3_256),
+			nil,
+			"expected raw multicodec 0x55, got 0x71",
+		},
+		{
+			"invalid bytes",
+			[]byte("foo"),
+			nil,
+			"expected 1 as the cid version number, got: 102",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ContentHashFromCID(tt.cid)
+			if len(tt.wantErr) != 0 {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, &ContentHash{Sum: &ContentHash_Raw_{Raw: tt.want}}, got)
+		})
+	}
+}
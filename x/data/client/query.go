@@ -8,6 +8,8 @@ import (
 	"github.com/cosmos/cosmos-sdk/client/flags"
 	gocid "github.com/ipfs/go-cid"
 	"github.com/spf13/cobra"
+
+	"github.com/regen-network/regen-ledger/x/data"
 )
 
 // QueryCmd returns the parent command for all x/data CLI query commands
@@ -47,32 +49,36 @@ $ regen query data by-cid bafzbeigai3eoy2ccc7ybwjfz5r3rdxqrinwi4rwytly24tdbh6yk7
 	return cmd
 }
 
-// QueryByCidCmd creates a CLI command for Query/Data.
+// QueryByCidCmd creates a CLI command for Query/ByHash.
 func QueryByCidCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "by-cid [cid]",
 		Short: "Query for CID timestamp, signers and content (if available)",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return fmt.Errorf("not implemented")
-			//clientCtx, err := client.GetClientQueryContext(cmd)
-			//if err != nil {
-			//	return err
-			//}
-			//
-			//cid, err := gocid.Decode(args[0])
-			//if err != nil {
-			//	return err
-			//}
-			//
-			//queryClient := data.NewQueryClient(clientCtx)
-			//
-			//res, err := queryClient.ByCid(cmd.Context(), &data.QueryByCidRequest{Cid: cid.Bytes()})
-			//if err != nil {
-			//	return err
-			//}
-			//
-			//return clientCtx.PrintProto(res)
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			cid, err := gocid.Decode(args[0])
+			if err != nil {
+				return err
+			}
+
+			hash, err := data.ContentHashFromCID(cid.Bytes())
+			if err != nil {
+				return err
+			}
+
+			queryClient := data.NewQueryClient(clientCtx)
+
+			res, err := queryClient.ByHash(cmd.Context(), &data.QueryByHashRequest{Hash: hash})
+			if err != nil {
+				return err
+			}
+
+			return clientCtx.PrintProto(res)
 		},
 	}
 
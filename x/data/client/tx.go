@@ -5,7 +5,12 @@ import (
 
 	sdkclient "github.com/cosmos/cosmos-sdk/client"
 	"github.com/cosmos/cosmos-sdk/client/flags"
+	"github.com/cosmos/cosmos-sdk/client/tx"
+	gocid "github.com/ipfs/go-cid"
 	"github.com/spf13/cobra"
+
+	"github.com/regen-network/regen-ledger/client"
+	"github.com/regen-network/regen-ledger/x/data"
 )
 
 // TxCmd returns a root CLI command handler for all x/data transaction commands.
@@ -35,34 +40,38 @@ func MsgAnchorDataCmd() *cobra.Command {
 			"hash, effectively providing a tamper resistant timestamp.",
 		Args: cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return fmt.Errorf("not implemented")
-			//err := cmd.Flags().Set(flags.FlagFrom, args[0])
-			//if err != nil {
-			//	return err
-			//}
-			//
-			//clientCtx, err := sdkclient.GetClientTxContext(cmd)
-			//if err != nil {
-			//	return err
-			//}
-			//
-			//cid, err := gocid.Decode(args[1])
-			//if err != nil {
-			//	return err
-			//}
-			//
-			//msg := data.MsgAnchorDataRequest{
-			//	Sender: clientCtx.GetFromAddress().String(),
-			//	Cid:    cid.Bytes(),
-			//}
-			//svcMsgClientConn := &client.ServiceMsgClientConn{}
-			//msgClient := data.NewMsgClient(svcMsgClientConn)
-			//_, err = msgClient.AnchorData(cmd.Context(), &msg)
-			//if err != nil {
-			//	return err
-			//}
-			//
-			//return tx.GenerateOrBroadcastTxCLI(clientCtx, cmd.Flags(), svcMsgClientConn.Msgs...)
+			err := cmd.Flags().Set(flags.FlagFrom, args[0])
+			if err != nil {
+				return err
+			}
+
+			clientCtx, err := sdkclient.GetClientTxContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			cid, err := gocid.Decode(args[1])
+			if err != nil {
+				return err
+			}
+
+			hash, err := data.ContentHashFromCID(cid.Bytes())
+			if err != nil {
+				return err
+			}
+
+			msg := data.MsgAnchorDataRequest{
+				Sender: clientCtx.GetFromAddress().String(),
+				Hash:   hash,
+			}
+			svcMsgClientConn := &client.ServiceMsgClientConn{}
+			msgClient := data.NewMsgClient(svcMsgClientConn)
+			_, err = msgClient.AnchorData(cmd.Context(), &msg)
+			if err != nil {
+				return err
+			}
+
+			return tx.GenerateOrBroadcastTxCLI(clientCtx, cmd.Flags(), svcMsgClientConn.Msgs...)
 		},
 	}
 
@@ -6,6 +6,7 @@ package data
 import (
 	fmt "fmt"
 	proto "github.com/gogo/protobuf/proto"
+	types "github.com/gogo/protobuf/types"
 	io "io"
 	math "math"
 	math_bits "math/bits"
@@ -26,6 +27,14 @@ const _ = proto.GoGoProtoPackageIsVersion3 // please upgrade the proto package
 type EventAnchorData struct {
 	// iri is the data IRI
 	Iri string `protobuf:"bytes,1,opt,name=iri,proto3" json:"iri,omitempty"`
+	// hash is the hash-based identifier of the anchored content.
+	Hash *ContentHash `protobuf:"bytes,2,opt,name=hash,proto3" json:"hash,omitempty"`
+	// sender is the address of the sender of the transaction.
+	Sender string `protobuf:"bytes,3,opt,name=sender,proto3" json:"sender,omitempty"`
+	// timestamp is the timestamp of the block at which the data was anchored.
+	Timestamp *types.Timestamp `protobuf:"bytes,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	// media_type is the media type of raw data. It is unspecified for graph data.
+	MediaType MediaType `protobuf:"varint,5,opt,name=media_type,json=mediaType,proto3,enum=regen.data.v1alpha2.MediaType" json:"media_type,omitempty"`
 }
 
 func (m *EventAnchorData) Reset()         { *m = EventAnchorData{} }
@@ -68,6 +77,81 @@ func (m *EventAnchorData) GetIri() string {
 	return ""
 }
 
+func (m *EventAnchorData) GetHash() *ContentHash {
+	if m != nil {
+		return m.Hash
+	}
+	return nil
+}
+
+func (m *EventAnchorData) GetSender() string {
+	if m != nil {
+		return m.Sender
+	}
+	return ""
+}
+
+func (m *EventAnchorData) GetTimestamp() *types.Timestamp {
+	if m != nil {
+		return m.Timestamp
+	}
+	return nil
+}
+
+func (m *EventAnchorData) GetMediaType() MediaType {
+	if m != nil {
+		return m.MediaType
+	}
+	return MediaType_MEDIA_TYPE_UNSPECIFIED
+}
+
+// EventAnchorBatch is an event emitted when multiple pieces of data are anchored
+// on-chain in a batch.
+type EventAnchorBatch struct {
+	// iris are the data IRIs
+	Iris []string `protobuf:"bytes,1,rep,name=iris,proto3" json:"iris,omitempty"`
+}
+
+func (m *EventAnchorBatch) Reset()         { *m = EventAnchorBatch{} }
+func (m *EventAnchorBatch) String() string { return proto.CompactTextString(m) }
+func (*EventAnchorBatch) ProtoMessage()    {}
+func (*EventAnchorBatch) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2f405832eebe356f, []int{1}
+}
+func (m *EventAnchorBatch) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *EventAnchorBatch) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_EventAnchorBatch.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *EventAnchorBatch) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EventAnchorBatch.Merge(m, src)
+}
+func (m *EventAnchorBatch) XXX_Size() int {
+	return m.Size()
+}
+func (m *EventAnchorBatch) XXX_DiscardUnknown() {
+	xxx_messageInfo_EventAnchorBatch.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_EventAnchorBatch proto.InternalMessageInfo
+
+func (m *EventAnchorBatch) GetIris() []string {
+	if m != nil {
+		return m.Iris
+	}
+	return nil
+}
+
 // EventSignData is an event emitted when data is signed on-chain.
 type EventSignData struct {
 	// iri is the data IRI
@@ -80,7 +164,7 @@ func (m *EventSignData) Reset()         { *m = EventSignData{} }
 func (m *EventSignData) String() string { return proto.CompactTextString(m) }
 func (*EventSignData) ProtoMessage()    {}
 func (*EventSignData) Descriptor() ([]byte, []int) {
-	return fileDescriptor_2f405832eebe356f, []int{1}
+	return fileDescriptor_2f405832eebe356f, []int{2}
 }
 func (m *EventSignData) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -133,7 +217,7 @@ func (m *EventStoreRawData) Reset()         { *m = EventStoreRawData{} }
 func (m *EventStoreRawData) String() string { return proto.CompactTextString(m) }
 func (*EventStoreRawData) ProtoMessage()    {}
 func (*EventStoreRawData) Descriptor() ([]byte, []int) {
-	return fileDescriptor_2f405832eebe356f, []int{2}
+	return fileDescriptor_2f405832eebe356f, []int{3}
 }
 func (m *EventStoreRawData) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -171,6 +255,7 @@ func (m *EventStoreRawData) GetIri() string {
 
 func init() {
 	proto.RegisterType((*EventAnchorData)(nil), "regen.data.v1alpha2.EventAnchorData")
+	proto.RegisterType((*EventAnchorBatch)(nil), "regen.data.v1alpha2.EventAnchorBatch")
 	proto.RegisterType((*EventSignData)(nil), "regen.data.v1alpha2.EventSignData")
 	proto.RegisterType((*EventStoreRawData)(nil), "regen.data.v1alpha2.EventStoreRawData")
 }
@@ -178,22 +263,31 @@ func init() {
 func init() { proto.RegisterFile("regen/data/v1alpha2/events.proto", fileDescriptor_2f405832eebe356f) }
 
 var fileDescriptor_2f405832eebe356f = []byte{
-	// 227 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0x52, 0x28, 0x4a, 0x4d, 0x4f,
-	0xcd, 0xd3, 0x4f, 0x49, 0x2c, 0x49, 0xd4, 0x2f, 0x33, 0x4c, 0xcc, 0x29, 0xc8, 0x48, 0x34, 0xd2,
-	0x4f, 0x2d, 0x4b, 0xcd, 0x2b, 0x29, 0xd6, 0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x17, 0x12, 0x06, 0xab,
-	0xd0, 0x03, 0xa9, 0xd0, 0x83, 0xa9, 0x90, 0x92, 0xc7, 0xa6, 0xad, 0xa4, 0xb2, 0x20, 0x15, 0xaa,
-	0x4b, 0x49, 0x99, 0x8b, 0xdf, 0x15, 0x64, 0x8a, 0x63, 0x5e, 0x72, 0x46, 0x7e, 0x91, 0x4b, 0x62,
-	0x49, 0xa2, 0x90, 0x00, 0x17, 0x73, 0x66, 0x51, 0xa6, 0x04, 0xa3, 0x02, 0xa3, 0x06, 0x67, 0x10,
-	0x88, 0xa9, 0x64, 0xcd, 0xc5, 0x0b, 0x56, 0x14, 0x9c, 0x99, 0x9e, 0x87, 0x5d, 0x89, 0x90, 0x04,
-	0x17, 0x7b, 0x71, 0x66, 0x7a, 0x5e, 0x6a, 0x51, 0xb1, 0x04, 0x93, 0x02, 0xb3, 0x06, 0x67, 0x10,
-	0x8c, 0xab, 0xa4, 0xca, 0x25, 0x08, 0xd1, 0x5c, 0x92, 0x5f, 0x94, 0x1a, 0x94, 0x58, 0x8e, 0xdd,
-	0x00, 0x27, 0xb7, 0x13, 0x8f, 0xe4, 0x18, 0x2f, 0x3c, 0x92, 0x63, 0x7c, 0xf0, 0x48, 0x8e, 0x71,
-	0xc2, 0x63, 0x39, 0x86, 0x0b, 0x8f, 0xe5, 0x18, 0x6e, 0x3c, 0x96, 0x63, 0x88, 0xd2, 0x49, 0xcf,
-	0x2c, 0xc9, 0x28, 0x4d, 0xd2, 0x4b, 0xce, 0xcf, 0xd5, 0x07, 0x7b, 0x47, 0x37, 0x2f, 0xb5, 0xa4,
-	0x3c, 0xbf, 0x28, 0x1b, 0xca, 0xcb, 0x49, 0x4d, 0x49, 0x4f, 0x2d, 0xd2, 0xaf, 0x00, 0xfb, 0x32,
-	0x89, 0x0d, 0xec, 0x2f, 0x63, 0x40, 0x00, 0x00, 0x00, 0xff, 0xff, 0x1f, 0x13, 0x87, 0x5e, 0x31,
-	0x01, 0x00, 0x00,
+	// 371 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x91, 0xbd, 0x6e, 0xdb, 0x30,
+	0x14, 0x85, 0x4d, 0xdb, 0x75, 0x21, 0x16, 0x6d, 0x5d, 0x16, 0x28, 0x08, 0x0f, 0xb2, 0x20, 0xa0,
+	0x85, 0x86, 0x96, 0x42, 0xdd, 0x0e, 0x05, 0x8a, 0x0e, 0x75, 0x7f, 0x90, 0x25, 0x8b, 0xe2, 0x29,
+	0x4b, 0x40, 0x5b, 0x37, 0x12, 0x11, 0x8b, 0x14, 0x48, 0xda, 0x8e, 0xdf, 0x22, 0x8f, 0x95, 0xd1,
+	0x63, 0xc6, 0xc0, 0xde, 0xf2, 0x14, 0x81, 0x68, 0x2b, 0xc9, 0xa0, 0x6c, 0xf7, 0x02, 0xdf, 0x39,
+	0x3c, 0xe7, 0x12, 0x07, 0x1a, 0x32, 0x90, 0x71, 0xca, 0x2d, 0x8f, 0x97, 0x5f, 0xf9, 0xbc, 0xcc,
+	0xf9, 0x28, 0x86, 0x25, 0x48, 0x6b, 0x58, 0xa9, 0x95, 0x55, 0xe4, 0xbd, 0x23, 0x58, 0x45, 0xb0,
+	0x9a, 0x18, 0x0c, 0x33, 0xa5, 0xb2, 0x39, 0xc4, 0x0e, 0x99, 0x2e, 0xce, 0x63, 0x2b, 0x0a, 0x30,
+	0x96, 0x17, 0xe5, 0x5e, 0x35, 0x18, 0x36, 0xf9, 0xda, 0x75, 0x09, 0x07, 0xdb, 0xf0, 0x0e, 0xe1,
+	0xb7, 0xff, 0xaa, 0x77, 0x7e, 0xcb, 0x59, 0xae, 0xf4, 0x5f, 0x6e, 0x39, 0xe9, 0xe3, 0x8e, 0xd0,
+	0x82, 0xa2, 0x00, 0x45, 0x5e, 0x52, 0x8d, 0xe4, 0x3b, 0xee, 0xe6, 0xdc, 0xe4, 0xb4, 0x1d, 0xa0,
+	0xe8, 0xd5, 0x28, 0x60, 0x0d, 0x59, 0xd8, 0x1f, 0x25, 0x2d, 0x48, 0x7b, 0xc4, 0x4d, 0x9e, 0x38,
+	0x9a, 0x7c, 0xc0, 0x3d, 0x03, 0x32, 0x05, 0x4d, 0x3b, 0xce, 0xea, 0xb0, 0x91, 0x1f, 0xd8, 0x7b,
+	0xc8, 0x49, 0xbb, 0xce, 0x72, 0xc0, 0xf6, 0x4d, 0x58, 0xdd, 0x84, 0x4d, 0x6a, 0x22, 0x79, 0x84,
+	0xc9, 0x2f, 0x8c, 0x0b, 0x48, 0x05, 0x3f, 0xab, 0x2a, 0xd0, 0x17, 0x01, 0x8a, 0xde, 0x8c, 0xfc,
+	0xc6, 0x34, 0xc7, 0x15, 0x36, 0x59, 0x97, 0x90, 0x78, 0x45, 0x3d, 0x86, 0x9f, 0x70, 0xff, 0x49,
+	0xd7, 0x31, 0xb7, 0xb3, 0x9c, 0x10, 0xdc, 0x15, 0x5a, 0x18, 0x8a, 0x82, 0x4e, 0xe4, 0x25, 0x6e,
+	0x0e, 0x7f, 0xe2, 0xd7, 0x8e, 0x3b, 0x11, 0x99, 0x7c, 0xe6, 0x22, 0x14, 0xbf, 0x34, 0x22, 0x93,
+	0xa0, 0x0d, 0x6d, 0x3b, 0x65, 0xbd, 0x86, 0x1f, 0xf1, 0xbb, 0xbd, 0xd8, 0x2a, 0x0d, 0x09, 0x5f,
+	0x35, 0x1b, 0x8c, 0xff, 0x5f, 0x6f, 0x7d, 0xb4, 0xd9, 0xfa, 0xe8, 0x76, 0xeb, 0xa3, 0xab, 0x9d,
+	0xdf, 0xda, 0xec, 0xfc, 0xd6, 0xcd, 0xce, 0x6f, 0x9d, 0x7e, 0xce, 0x84, 0xcd, 0x17, 0x53, 0x36,
+	0x53, 0x45, 0xec, 0xaa, 0x7d, 0x91, 0x60, 0x57, 0x4a, 0x5f, 0x1c, 0xb6, 0x39, 0xa4, 0x19, 0xe8,
+	0xf8, 0xd2, 0xfd, 0xea, 0xb4, 0xe7, 0x2e, 0xf6, 0xed, 0x7e, 0x00, 0x2f, 0x36, 0x86, 0x86, 0x42,
+	0x02, 0x00, 0x00,
 }
 
 func (m *EventAnchorData) Marshal() (dAtA []byte, err error) {
@@ -216,6 +310,42 @@ func (m *EventAnchorData) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if m.MediaType != 0 {
+		i = encodeVarintEvents(dAtA, i, uint64(m.MediaType))
+		i--
+		dAtA[i] = 0x28
+	}
+	if m.Timestamp != nil {
+		{
+			size, err := m.Timestamp.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintEvents(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x22
+	}
+	if len(m.Sender) > 0 {
+		i -= len(m.Sender)
+		copy(dAtA[i:], m.Sender)
+		i = encodeVarintEvents(dAtA, i, uint64(len(m.Sender)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if m.Hash != nil {
+		{
+			size, err := m.Hash.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintEvents(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x12
+	}
 	if len(m.Iri) > 0 {
 		i -= len(m.Iri)
 		copy(dAtA[i:], m.Iri)
@@ -226,6 +356,38 @@ func (m *EventAnchorData) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
+func (m *EventAnchorBatch) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *EventAnchorBatch) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *EventAnchorBatch) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Iris) > 0 {
+		for iNdEx := len(m.Iris) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Iris[iNdEx])
+			copy(dAtA[i:], m.Iris[iNdEx])
+			i = encodeVarintEvents(dAtA, i, uint64(len(m.Iris[iNdEx])))
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
 func (m *EventSignData) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
@@ -316,6 +478,36 @@ func (m *EventAnchorData) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovEvents(uint64(l))
 	}
+	if m.Hash != nil {
+		l = m.Hash.Size()
+		n += 1 + l + sovEvents(uint64(l))
+	}
+	l = len(m.Sender)
+	if l > 0 {
+		n += 1 + l + sovEvents(uint64(l))
+	}
+	if m.Timestamp != nil {
+		l = m.Timestamp.Size()
+		n += 1 + l + sovEvents(uint64(l))
+	}
+	if m.MediaType != 0 {
+		n += 1 + sovEvents(uint64(m.MediaType))
+	}
+	return n
+}
+
+func (m *EventAnchorBatch) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Iris) > 0 {
+		for _, s := range m.Iris {
+			l = len(s)
+			n += 1 + l + sovEvents(uint64(l))
+		}
+	}
 	return n
 }
 
@@ -418,6 +610,214 @@ func (m *EventAnchorData) Unmarshal(dAtA []byte) error {
 			}
 			m.Iri = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Hash", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEvents
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthEvents
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthEvents
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Hash == nil {
+				m.Hash = &ContentHash{}
+			}
+			if err := m.Hash.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Sender", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEvents
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthEvents
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthEvents
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Sender = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Timestamp", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEvents
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthEvents
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthEvents
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Timestamp == nil {
+				m.Timestamp = &types.Timestamp{}
+			}
+			if err := m.Timestamp.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MediaType", wireType)
+			}
+			m.MediaType = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEvents
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MediaType |= MediaType(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipEvents(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthEvents
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthEvents
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *EventAnchorBatch) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowEvents
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: EventAnchorBatch: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: EventAnchorBatch: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Iris", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEvents
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthEvents
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthEvents
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Iris = append(m.Iris, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipEvents(dAtA[iNdEx:])
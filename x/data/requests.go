@@ -11,7 +11,7 @@ import (
 )
 
 var (
-	_, _, _ sdk.MsgRequest = &MsgAnchorDataRequest{}, &MsgSignDataRequest{}, &MsgStoreRawDataRequest{}
+	_, _, _, _ sdk.MsgRequest = &MsgAnchorDataRequest{}, &MsgAnchorBatchRequest{}, &MsgSignDataRequest{}, &MsgStoreRawDataRequest{}
 )
 
 func (m *MsgAnchorDataRequest) ValidateBasic() error {
@@ -27,6 +27,45 @@ func (m *MsgAnchorDataRequest) GetSigners() []sdk.AccAddress {
 	return []sdk.AccAddress{addr}
 }
 
+func (m *MsgAnchorBatchRequest) ValidateBasic() error {
+	if len(m.Hashes) == 0 {
+		return sdkerrors.Wrap(sdkerrors.ErrInvalidRequest, "hashes cannot be empty")
+	}
+
+	iris := make(map[string]bool, len(m.Hashes))
+	for i, hash := range m.Hashes {
+		if hash == nil {
+			return sdkerrors.Wrap(sdkerrors.ErrInvalidRequest, fmt.Sprintf("hash %d cannot be empty", i))
+		}
+
+		err := hash.Validate()
+		if err != nil {
+			return err
+		}
+
+		iri, err := hash.ToIRI()
+		if err != nil {
+			return sdkerrors.Wrap(sdkerrors.ErrInvalidRequest, err.Error())
+		}
+
+		if iris[iri] {
+			return sdkerrors.Wrap(sdkerrors.ErrInvalidRequest, fmt.Sprintf("duplicate hash %s", iri))
+		}
+		iris[iri] = true
+	}
+
+	return nil
+}
+
+func (m *MsgAnchorBatchRequest) GetSigners() []sdk.AccAddress {
+	addr, err := sdk.AccAddressFromBech32(m.Sender)
+	if err != nil {
+		panic(err)
+	}
+
+	return []sdk.AccAddress{addr}
+}
+
 func (m *MsgSignDataRequest) ValidateBasic() error {
 	return m.Hash.Validate()
 }
@@ -76,6 +76,76 @@ func TestMsgAnchorDataRequest_ValidateBasic(t *testing.T) {
 	}
 }
 
+func TestMsgAnchorBatchRequest_GetSigners(t *testing.T) {
+	_, _, addr := testdata.KeyTestPubAddr()
+
+	msg := &MsgAnchorBatchRequest{Sender: addr.String()}
+	require.Equal(t, []sdk.AccAddress{addr}, msg.GetSigners())
+
+	msg = &MsgAnchorBatchRequest{Sender: ""}
+	require.Panics(t, func() {
+		msg.GetSigners()
+	})
+}
+
+func TestMsgAnchorBatchRequest_ValidateBasic(t *testing.T) {
+	rawHash := func(hash []byte) *ContentHash {
+		return &ContentHash{Sum: &ContentHash_Raw_{Raw: &ContentHash_Raw{
+			Hash:            hash,
+			DigestAlgorithm: DigestAlgorithm_DIGEST_ALGORITHM_BLAKE2B_256,
+			MediaType:       MediaType_MEDIA_TYPE_UNSPECIFIED,
+		}}}
+	}
+	otherHash := make([]byte, 32)
+	otherHash[0] = 1
+
+	tests := []struct {
+		name    string
+		hashes  []*ContentHash
+		wantErr string
+	}{
+		{
+			name:    "good",
+			hashes:  []*ContentHash{rawHash(make([]byte, 32)), rawHash(otherHash)},
+			wantErr: "",
+		},
+		{
+			name:    "no hashes",
+			hashes:  nil,
+			wantErr: "hashes cannot be empty: invalid request",
+		},
+		{
+			name:    "nil hash",
+			hashes:  []*ContentHash{rawHash(make([]byte, 32)), nil},
+			wantErr: "hash 1 cannot be empty: invalid request",
+		},
+		{
+			name:    "bad hash",
+			hashes:  []*ContentHash{rawHash(make([]byte, 31))},
+			wantErr: "expected 32 bytes for DIGEST_ALGORITHM_BLAKE2B_256, got 31: unknown request",
+		},
+		{
+			name:    "duplicate hash",
+			hashes:  []*ContentHash{rawHash(otherHash), rawHash(make([]byte, 32)), rawHash(otherHash)},
+			wantErr: "duplicate hash regen:112xBimKCq2tcciegw9NsFXgScCQAsK7vhqKQ2yJPyJ5vPveZQAK.bin: invalid request",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &MsgAnchorBatchRequest{
+				Sender: "",
+				Hashes: tt.hashes,
+			}
+			err := m.ValidateBasic()
+			if len(tt.wantErr) != 0 {
+				require.EqualError(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
 func TestMsgSignDataRequest_GetSigners(t *testing.T) {
 	_, _, addr := testdata.KeyTestPubAddr()
 	_, _, addr2 := testdata.KeyTestPubAddr()
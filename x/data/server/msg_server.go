@@ -3,6 +3,9 @@ package server
 import (
 	"fmt"
 
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	gogotypes "github.com/gogo/protobuf/types"
+
 	"github.com/regen-network/regen-ledger/types"
 
 	"github.com/regen-network/regen-ledger/x/data"
@@ -11,58 +14,105 @@ import (
 var _ data.MsgServer = serverImpl{}
 
 func (s serverImpl) AnchorData(ctx types.Context, request *data.MsgAnchorDataRequest) (*data.MsgAnchorDataResponse, error) {
-	return nil, fmt.Errorf("not implemented")
-	//cidBz := request.Cid
-	//key := AnchorKey(cidBz)
-	//store := ctx.KVStore(s.storeKey)
-	//if store.Has(key) {
-	//	return nil, sdkerrors.Wrap(sdkerrors.ErrInvalidRequest, fmt.Sprintf("CID f%x is already anchored", cidBz))
-	//}
-	//
-	//timestamp, err := blockTimestamp(ctx)
-	//if err != nil {
-	//	return nil, err
-	//}
-	//
-	//err = s.anchorCid(ctx, timestamp, cidBz)
-	//if err != nil {
-	//	return nil, err
-	//}
-	//
-	//return &data.MsgAnchorDataResponse{Timestamp: timestamp}, nil
+	iri, err := request.Hash.ToIRI()
+	if err != nil {
+		return nil, sdkerrors.Wrap(sdkerrors.ErrInvalidRequest, err.Error())
+	}
+
+	timestamps, anchored, err := s.anchor(ctx, iri)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(anchored) != 0 {
+		err = ctx.EventManager().EmitTypedEvent(&data.EventAnchorData{
+			Iri:       iri,
+			Hash:      request.Hash,
+			Sender:    request.Sender,
+			Timestamp: timestamps[0],
+			MediaType: request.Hash.GetRaw().GetMediaType(),
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &data.MsgAnchorDataResponse{Timestamp: timestamps[0]}, nil
+}
+
+func (s serverImpl) AnchorBatch(ctx types.Context, request *data.MsgAnchorBatchRequest) (*data.MsgAnchorBatchResponse, error) {
+	iris := make([]string, len(request.Hashes))
+	for i, hash := range request.Hashes {
+		iri, err := hash.ToIRI()
+		if err != nil {
+			return nil, sdkerrors.Wrap(sdkerrors.ErrInvalidRequest, err.Error())
+		}
+		iris[i] = iri
+	}
+
+	timestamps, anchored, err := s.anchor(ctx, iris...)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(anchored) != 0 {
+		err = ctx.EventManager().EmitTypedEvent(&data.EventAnchorBatch{Iris: anchored})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	timestamp, err := blockTimestamp(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &data.MsgAnchorBatchResponse{Timestamp: timestamp, Timestamps: timestamps}, nil
+}
+
+// anchor stores the block timestamp for all IRIs that are not anchored yet and returns the anchor
+// timestamps of all IRIs together with the newly anchored IRIs. Already anchored IRIs keep their
+// original timestamp.
+func (s serverImpl) anchor(ctx types.Context, iris ...string) ([]*gogotypes.Timestamp, []string, error) {
+	timestamp, err := blockTimestamp(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	bz, err := timestamp.Marshal()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	store := ctx.KVStore(s.storeKey)
+	timestamps := make([]*gogotypes.Timestamp, len(iris))
+	var anchored []string
+	for i, iri := range iris {
+		key := AnchorKey([]byte(iri))
+		if existing := store.Get(key); existing != nil {
+			var original gogotypes.Timestamp
+			if err := original.Unmarshal(existing); err != nil {
+				return nil, nil, sdkerrors.Wrapf(err, "anchor timestamp of %s", iri)
+			}
+			timestamps[i] = &original
+			continue
+		}
+
+		store.Set(key, bz)
+		timestamps[i] = timestamp
+		anchored = append(anchored, iri)
+	}
+
+	return timestamps, anchored, nil
 }
 
-//func blockTimestamp(ctx types.Context) (*gogotypes.Timestamp, error) {
-//	timestamp, err := gogotypes.TimestampProto(ctx.BlockTime())
-//	if err != nil {
-//		return nil, sdkerrors.Wrap(err, "invalid block time")
-//	}
-//
-//	return timestamp, err
-//}
-//
-//func (s serverImpl) anchorCidIfNeeded(ctx types.Context, timestamp *gogotypes.Timestamp, cid []byte) error {
-//	store := ctx.KVStore(s.storeKey)
-//	key := AnchorKey(cid)
-//	if store.Has(key) {
-//		return nil
-//	}
-//
-//	return s.anchorCid(ctx, timestamp, cid)
-//}
-//
-//func (s serverImpl) anchorCid(ctx types.Context, timestamp *gogotypes.Timestamp, cidBytes []byte) error {
-//	bz, err := timestamp.Marshal()
-//	if err != nil {
-//		return err
-//	}
-//
-//	store := ctx.KVStore(s.storeKey)
-//	key := AnchorKey(cidBytes)
-//	store.Set(key, bz)
-//
-//	return ctx.EventManager().EmitTypedEvent(&data.EventAnchorData{Cid: cidBytes})
-//}
+func blockTimestamp(ctx types.Context) (*gogotypes.Timestamp, error) {
+	timestamp, err := gogotypes.TimestampProto(ctx.BlockTime())
+	if err != nil {
+		return nil, sdkerrors.Wrap(err, "invalid block time")
+	}
+	return timestamp, nil
+}
 
 //var emptyBz = []byte{0}
 
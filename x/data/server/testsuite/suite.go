@@ -2,12 +2,15 @@ package testsuite
 
 import (
 	"context"
+	"time"
 
 	"github.com/regen-network/regen-ledger/testutil"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	gogotypes "github.com/gogo/protobuf/types"
 	"github.com/stretchr/testify/suite"
 
+	"github.com/regen-network/regen-ledger/types"
 	"github.com/regen-network/regen-ledger/x/data"
 )
 
@@ -145,3 +148,98 @@ func (s *IntegrationTestSuite) TestScenario() {
 	//s.Require().Contains(queryRes.Signers, s.addr2.String())
 	//s.Require().Equal(testContent, queryRes.Content)
 }
+
+func (s *IntegrationTestSuite) TestAnchorBatch() {
+	hash := func(b byte) *data.ContentHash {
+		bz := make([]byte, 32)
+		bz[0] = b
+		return &data.ContentHash{Sum: &data.ContentHash_Raw_{Raw: &data.ContentHash_Raw{
+			Hash:            bz,
+			DigestAlgorithm: data.DigestAlgorithm_DIGEST_ALGORITHM_BLAKE2B_256,
+			MediaType:       data.MediaType_MEDIA_TYPE_UNSPECIFIED,
+		}}}
+	}
+	blockTime := time.Now().UTC()
+	ctx := types.Context{Context: s.ctx.(types.Context).WithBlockTime(blockTime)}
+	laterCtx := types.Context{Context: s.ctx.(types.Context).WithBlockTime(blockTime.Add(time.Hour))}
+	timestamp, err := gogotypes.TimestampProto(blockTime)
+	s.Require().NoError(err)
+	laterTimestamp, err := gogotypes.TimestampProto(blockTime.Add(time.Hour))
+	s.Require().NoError(err)
+
+	// anchor a batch of data
+	batchRes, err := s.msgClient.AnchorBatch(ctx, &data.MsgAnchorBatchRequest{
+		Sender: s.addr1.String(),
+		Hashes: []*data.ContentHash{hash(1), hash(2)},
+	})
+	s.Require().NoError(err)
+	s.Require().NotNil(batchRes)
+	s.Require().Equal(timestamp, batchRes.Timestamp)
+	s.Require().Equal([]*gogotypes.Timestamp{timestamp, timestamp}, batchRes.Timestamps)
+
+	// anchoring data of a batch again returns the original timestamp
+	anchorRes, err := s.msgClient.AnchorData(laterCtx, &data.MsgAnchorDataRequest{
+		Sender: s.addr2.String(),
+		Hash:   hash(2),
+	})
+	s.Require().NoError(err)
+	s.Require().NotNil(anchorRes)
+	s.Require().Equal(timestamp, anchorRes.Timestamp)
+
+	// a batch with already anchored data anchors the other data only
+	batchRes, err = s.msgClient.AnchorBatch(laterCtx, &data.MsgAnchorBatchRequest{
+		Sender: s.addr1.String(),
+		Hashes: []*data.ContentHash{hash(3), hash(1)},
+	})
+	s.Require().NoError(err)
+	s.Require().NotNil(batchRes)
+	s.Require().Equal(laterTimestamp, batchRes.Timestamp)
+	s.Require().Equal([]*gogotypes.Timestamp{laterTimestamp, timestamp}, batchRes.Timestamps)
+
+	anchorRes, err = s.msgClient.AnchorData(ctx, &data.MsgAnchorDataRequest{
+		Sender: s.addr1.String(),
+		Hash:   hash(3),
+	})
+	s.Require().NoError(err)
+	s.Require().Equal(laterTimestamp, anchorRes.Timestamp)
+}
+
+func (s *IntegrationTestSuite) TestAnchorDataEvent() {
+	rawHash := &data.ContentHash_Raw{
+		Hash:            make([]byte, 32),
+		DigestAlgorithm: data.DigestAlgorithm_DIGEST_ALGORITHM_SHA3_256,
+		MediaType:       data.MediaType_MEDIA_TYPE_JSON,
+	}
+	hash := &data.ContentHash{Sum: &data.ContentHash_Raw_{Raw: rawHash}}
+	iri, err := hash.ToIRI()
+	s.Require().NoError(err)
+	blockTime := time.Now().UTC()
+	sdkCtx := s.ctx.(types.Context).WithBlockTime(blockTime).WithEventManager(sdk.NewEventManager())
+
+	anchorRes, err := s.msgClient.AnchorData(types.Context{Context: sdkCtx}, &data.MsgAnchorDataRequest{
+		Sender: s.addr1.String(),
+		Hash:   hash,
+	})
+	s.Require().NoError(err)
+
+	events := sdkCtx.EventManager().ABCIEvents()
+	s.Require().Len(events, 1)
+	msg, err := sdk.ParseTypedEvent(events[0])
+	s.Require().NoError(err)
+	s.Require().Equal(&data.EventAnchorData{
+		Iri:       iri,
+		Hash:      hash,
+		Sender:    s.addr1.String(),
+		Timestamp: anchorRes.Timestamp,
+		MediaType: data.MediaType_MEDIA_TYPE_JSON,
+	}, msg)
+
+	// no event is emitted for data that is already anchored
+	sdkCtx = sdkCtx.WithEventManager(sdk.NewEventManager())
+	_, err = s.msgClient.AnchorData(types.Context{Context: sdkCtx}, &data.MsgAnchorDataRequest{
+		Sender: s.addr2.String(),
+		Hash:   hash,
+	})
+	s.Require().NoError(err)
+	s.Require().Empty(sdkCtx.EventManager().ABCIEvents())
+}
@@ -84,6 +84,8 @@ func (m *MsgAnchorDataRequest) GetHash() *ContentHash {
 // MsgAnchorDataRequest is the Msg/AnchorData response type.
 type MsgAnchorDataResponse struct {
 	// timestamp is the timestamp of the block at which the data was anchored.
+	// For data that was already anchored it is the timestamp of the original
+	// anchor.
 	Timestamp *types.Timestamp `protobuf:"bytes,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
 }
 
@@ -127,6 +129,120 @@ func (m *MsgAnchorDataResponse) GetTimestamp() *types.Timestamp {
 	return nil
 }
 
+// MsgAnchorBatchRequest is the Msg/AnchorBatch request type.
+type MsgAnchorBatchRequest struct {
+	// sender is the address of the sender of the transaction.
+	// The sender in AnchorBatch is not attesting to the veracity of the underlying
+	// data. They can simply be a intermediary providing services.
+	Sender string `protobuf:"bytes,1,opt,name=sender,proto3" json:"sender,omitempty"`
+	// hashes are the hash-based identifiers for the anchored contents.
+	Hashes []*ContentHash `protobuf:"bytes,2,rep,name=hashes,proto3" json:"hashes,omitempty"`
+}
+
+func (m *MsgAnchorBatchRequest) Reset()         { *m = MsgAnchorBatchRequest{} }
+func (m *MsgAnchorBatchRequest) String() string { return proto.CompactTextString(m) }
+func (*MsgAnchorBatchRequest) ProtoMessage()    {}
+func (*MsgAnchorBatchRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ff31907a513a4b24, []int{2}
+}
+func (m *MsgAnchorBatchRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *MsgAnchorBatchRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_MsgAnchorBatchRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *MsgAnchorBatchRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MsgAnchorBatchRequest.Merge(m, src)
+}
+func (m *MsgAnchorBatchRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *MsgAnchorBatchRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_MsgAnchorBatchRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MsgAnchorBatchRequest proto.InternalMessageInfo
+
+func (m *MsgAnchorBatchRequest) GetSender() string {
+	if m != nil {
+		return m.Sender
+	}
+	return ""
+}
+
+func (m *MsgAnchorBatchRequest) GetHashes() []*ContentHash {
+	if m != nil {
+		return m.Hashes
+	}
+	return nil
+}
+
+// MsgAnchorBatchResponse is the Msg/AnchorBatch response type.
+type MsgAnchorBatchResponse struct {
+	// timestamp is the timestamp of the block at which the data was anchored.
+	Timestamp *types.Timestamp `protobuf:"bytes,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	// timestamps are the timestamps at which the data of the hashes was anchored,
+	// in the order of the request. For data that was already anchored it is the
+	// timestamp of the original anchor.
+	Timestamps []*types.Timestamp `protobuf:"bytes,2,rep,name=timestamps,proto3" json:"timestamps,omitempty"`
+}
+
+func (m *MsgAnchorBatchResponse) Reset()         { *m = MsgAnchorBatchResponse{} }
+func (m *MsgAnchorBatchResponse) String() string { return proto.CompactTextString(m) }
+func (*MsgAnchorBatchResponse) ProtoMessage()    {}
+func (*MsgAnchorBatchResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ff31907a513a4b24, []int{3}
+}
+func (m *MsgAnchorBatchResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *MsgAnchorBatchResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_MsgAnchorBatchResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *MsgAnchorBatchResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MsgAnchorBatchResponse.Merge(m, src)
+}
+func (m *MsgAnchorBatchResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *MsgAnchorBatchResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_MsgAnchorBatchResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MsgAnchorBatchResponse proto.InternalMessageInfo
+
+func (m *MsgAnchorBatchResponse) GetTimestamp() *types.Timestamp {
+	if m != nil {
+		return m.Timestamp
+	}
+	return nil
+}
+
+func (m *MsgAnchorBatchResponse) GetTimestamps() []*types.Timestamp {
+	if m != nil {
+		return m.Timestamps
+	}
+	return nil
+}
+
 // MsgSignDataRequest is the Msg/SignData request type.
 type MsgSignDataRequest struct {
 	// signers are the addresses of the accounts signing the data.
@@ -143,7 +259,7 @@ func (m *MsgSignDataRequest) Reset()         { *m = MsgSignDataRequest{} }
 func (m *MsgSignDataRequest) String() string { return proto.CompactTextString(m) }
 func (*MsgSignDataRequest) ProtoMessage()    {}
 func (*MsgSignDataRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_ff31907a513a4b24, []int{2}
+	return fileDescriptor_ff31907a513a4b24, []int{4}
 }
 func (m *MsgSignDataRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -180,7 +296,7 @@ func (m *MsgSignDataResponse) Reset()         { *m = MsgSignDataResponse{} }
 func (m *MsgSignDataResponse) String() string { return proto.CompactTextString(m) }
 func (*MsgSignDataResponse) ProtoMessage()    {}
 func (*MsgSignDataResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_ff31907a513a4b24, []int{3}
+	return fileDescriptor_ff31907a513a4b24, []int{5}
 }
 func (m *MsgSignDataResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -225,7 +341,7 @@ func (m *MsgStoreRawDataRequest) Reset()         { *m = MsgStoreRawDataRequest{}
 func (m *MsgStoreRawDataRequest) String() string { return proto.CompactTextString(m) }
 func (*MsgStoreRawDataRequest) ProtoMessage()    {}
 func (*MsgStoreRawDataRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_ff31907a513a4b24, []int{4}
+	return fileDescriptor_ff31907a513a4b24, []int{6}
 }
 func (m *MsgStoreRawDataRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -283,7 +399,7 @@ func (m *MsgStoreRawDataResponse) Reset()         { *m = MsgStoreRawDataResponse
 func (m *MsgStoreRawDataResponse) String() string { return proto.CompactTextString(m) }
 func (*MsgStoreRawDataResponse) ProtoMessage()    {}
 func (*MsgStoreRawDataResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_ff31907a513a4b24, []int{5}
+	return fileDescriptor_ff31907a513a4b24, []int{7}
 }
 func (m *MsgStoreRawDataResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -315,6 +431,8 @@ var xxx_messageInfo_MsgStoreRawDataResponse proto.InternalMessageInfo
 func init() {
 	proto.RegisterType((*MsgAnchorDataRequest)(nil), "regen.data.v1alpha2.MsgAnchorDataRequest")
 	proto.RegisterType((*MsgAnchorDataResponse)(nil), "regen.data.v1alpha2.MsgAnchorDataResponse")
+	proto.RegisterType((*MsgAnchorBatchRequest)(nil), "regen.data.v1alpha2.MsgAnchorBatchRequest")
+	proto.RegisterType((*MsgAnchorBatchResponse)(nil), "regen.data.v1alpha2.MsgAnchorBatchResponse")
 	proto.RegisterType((*MsgSignDataRequest)(nil), "regen.data.v1alpha2.MsgSignDataRequest")
 	proto.RegisterType((*MsgSignDataResponse)(nil), "regen.data.v1alpha2.MsgSignDataResponse")
 	proto.RegisterType((*MsgStoreRawDataRequest)(nil), "regen.data.v1alpha2.MsgStoreRawDataRequest")
@@ -324,37 +442,40 @@ func init() {
 func init() { proto.RegisterFile("regen/data/v1alpha2/tx.proto", fileDescriptor_ff31907a513a4b24) }
 
 var fileDescriptor_ff31907a513a4b24 = []byte{
-	// 466 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x93, 0xcb, 0x8e, 0xd3, 0x30,
-	0x14, 0x86, 0xeb, 0xe9, 0x68, 0xa0, 0xa7, 0x5d, 0x79, 0x2e, 0x84, 0x08, 0x65, 0xa2, 0x08, 0x41,
-	0x80, 0xc1, 0x11, 0x85, 0x05, 0x9a, 0x1d, 0x17, 0x31, 0x6c, 0xba, 0xc0, 0xb0, 0x42, 0x42, 0xc8,
-	0x4d, 0x8d, 0x13, 0xd1, 0xda, 0xc1, 0x76, 0xe9, 0xf0, 0x06, 0x2c, 0x91, 0x78, 0x01, 0x1e, 0x82,
-	0x87, 0x60, 0x39, 0x4b, 0x96, 0xa8, 0x7d, 0x11, 0xd4, 0x5c, 0xa6, 0x9d, 0x99, 0x54, 0xed, 0x2e,
-	0xbf, 0xcf, 0x6f, 0xff, 0xdf, 0xf1, 0x71, 0xe0, 0x96, 0xe6, 0x82, 0xcb, 0x68, 0xc0, 0x2c, 0x8b,
-	0xbe, 0x3e, 0x62, 0xc3, 0x2c, 0x61, 0xdd, 0xc8, 0x9e, 0x92, 0x4c, 0x2b, 0xab, 0xf0, 0x6e, 0x5e,
-	0x25, 0xf3, 0x2a, 0xa9, 0xaa, 0xee, 0x9e, 0x50, 0x42, 0xe5, 0xf5, 0x68, 0xfe, 0x55, 0x58, 0xdd,
-	0x43, 0xa1, 0x94, 0x18, 0xf2, 0x28, 0x57, 0xfd, 0xf1, 0xa7, 0xc8, 0xa6, 0x23, 0x6e, 0x2c, 0x1b,
-	0x65, 0x95, 0xa1, 0x36, 0xe9, 0x5b, 0xc6, 0x4d, 0x61, 0x08, 0x06, 0xb0, 0xd7, 0x33, 0xe2, 0x99,
-	0x8c, 0x13, 0xa5, 0x5f, 0x32, 0xcb, 0x28, 0xff, 0x32, 0xe6, 0xc6, 0xe2, 0x03, 0xd8, 0x31, 0x5c,
-	0x0e, 0xb8, 0x76, 0x90, 0x8f, 0xc2, 0x16, 0x2d, 0x15, 0x7e, 0x02, 0xdb, 0x09, 0x33, 0x89, 0xb3,
-	0xe5, 0xa3, 0xb0, 0xdd, 0xf5, 0x49, 0x0d, 0x2b, 0x79, 0xa1, 0xa4, 0xe5, 0xd2, 0xbe, 0x66, 0x26,
-	0xa1, 0xb9, 0x3b, 0x78, 0x03, 0xfb, 0x97, 0x52, 0x4c, 0xa6, 0xa4, 0xe1, 0xf8, 0x29, 0xb4, 0xce,
-	0x91, 0xf3, 0xa4, 0x76, 0xd7, 0x25, 0x45, 0x53, 0xa4, 0x6a, 0x8a, 0xbc, 0xab, 0x1c, 0x74, 0x61,
-	0x0e, 0x32, 0xc0, 0x3d, 0x23, 0xde, 0xa6, 0x42, 0x2e, 0x63, 0x3b, 0x70, 0xcd, 0xa4, 0x42, 0x72,
-	0x6d, 0x1c, 0xe4, 0x37, 0xc3, 0x16, 0xad, 0x24, 0x3e, 0xbe, 0x00, 0x7e, 0x67, 0x1d, 0x38, 0x39,
-	0xd1, 0x2c, 0x2b, 0xf1, 0x8f, 0xb7, 0xbf, 0xff, 0x3a, 0x6c, 0x04, 0xfb, 0xb0, 0x7b, 0x21, 0xb1,
-	0x68, 0x21, 0xf8, 0x89, 0xe0, 0x60, 0xbe, 0x6e, 0x95, 0xe6, 0x94, 0x4d, 0x36, 0xb9, 0xc4, 0x13,
-	0xe8, 0xc4, 0x45, 0xd4, 0xc7, 0x25, 0xa6, 0xdb, 0x6b, 0x99, 0x28, 0x9b, 0xd0, 0x76, 0xbc, 0x58,
-	0x98, 0xb7, 0x5b, 0x4a, 0xa7, 0xe9, 0xa3, 0xb0, 0x43, 0x2b, 0x19, 0xdc, 0x84, 0x1b, 0x57, 0xa0,
-	0x0a, 0xe0, 0xee, 0xef, 0x2d, 0x68, 0xf6, 0x8c, 0xc0, 0x31, 0xc0, 0x62, 0x22, 0xf8, 0x5e, 0x6d,
-	0x7a, 0xdd, 0xdb, 0x70, 0xef, 0x6f, 0x62, 0x2d, 0x07, 0xfc, 0x01, 0xae, 0x57, 0x37, 0x86, 0xef,
-	0xae, 0xda, 0x77, 0x69, 0x8a, 0x6e, 0xb8, 0xde, 0x58, 0x1e, 0x9f, 0x42, 0x67, 0xb9, 0x47, 0xfc,
-	0x60, 0xe5, 0xce, 0xab, 0xe3, 0x71, 0x8f, 0x36, 0x33, 0x17, 0x51, 0xcf, 0x5f, 0xfd, 0x99, 0x7a,
-	0xe8, 0x6c, 0xea, 0xa1, 0x7f, 0x53, 0x0f, 0xfd, 0x98, 0x79, 0x8d, 0xb3, 0x99, 0xd7, 0xf8, 0x3b,
-	0xf3, 0x1a, 0xef, 0x8f, 0x44, 0x6a, 0x93, 0x71, 0x9f, 0xc4, 0x6a, 0x14, 0xe5, 0x27, 0x3e, 0x94,
-	0xdc, 0x4e, 0x94, 0xfe, 0x5c, 0xaa, 0x21, 0x1f, 0x08, 0xae, 0xa3, 0xd3, 0xfc, 0x37, 0xec, 0xef,
-	0xe4, 0xef, 0xfa, 0xf1, 0xff, 0x00, 0x00, 0x00, 0xff, 0xff, 0xc7, 0xa9, 0xd2, 0xbe, 0x05, 0x04,
-	0x00, 0x00,
+	// 520 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x54, 0x4d, 0x6f, 0xd3, 0x40,
+	0x10, 0x8d, 0xeb, 0xaa, 0x90, 0x49, 0x4e, 0xdb, 0x0f, 0x8c, 0x85, 0x5c, 0xcb, 0x42, 0x60, 0xa0,
+	0xac, 0x45, 0xe0, 0x50, 0xe5, 0x46, 0x41, 0x94, 0x4b, 0x0e, 0xb8, 0x9c, 0x90, 0x10, 0xda, 0x38,
+	0xdb, 0xb5, 0x45, 0xe2, 0x35, 0xbb, 0x1b, 0x52, 0xfe, 0x01, 0x27, 0x84, 0xc4, 0x95, 0x03, 0x3f,
+	0x87, 0x63, 0x8f, 0x1c, 0x51, 0xf2, 0x47, 0x50, 0xfc, 0xd1, 0xb8, 0xc1, 0xc5, 0x96, 0xb8, 0xe5,
+	0x65, 0xde, 0xce, 0x7b, 0xb3, 0xf3, 0xd6, 0x70, 0x4b, 0x50, 0x46, 0x63, 0x6f, 0x44, 0x14, 0xf1,
+	0x3e, 0x3e, 0x22, 0xe3, 0x24, 0x24, 0x3d, 0x4f, 0x9d, 0xe1, 0x44, 0x70, 0xc5, 0xd1, 0x76, 0x5a,
+	0xc5, 0xcb, 0x2a, 0x2e, 0xaa, 0xe6, 0x0e, 0xe3, 0x8c, 0xa7, 0x75, 0x6f, 0xf9, 0x2b, 0xa3, 0x9a,
+	0xfb, 0x8c, 0x73, 0x36, 0xa6, 0x5e, 0x8a, 0x86, 0xd3, 0x53, 0x4f, 0x45, 0x13, 0x2a, 0x15, 0x99,
+	0x24, 0x05, 0xa1, 0x52, 0xe9, 0x53, 0x42, 0x65, 0x46, 0x70, 0x46, 0xb0, 0x33, 0x90, 0xec, 0x69,
+	0x1c, 0x84, 0x5c, 0x3c, 0x27, 0x8a, 0xf8, 0xf4, 0xc3, 0x94, 0x4a, 0x85, 0xf6, 0x60, 0x4b, 0xd2,
+	0x78, 0x44, 0x85, 0xa1, 0xd9, 0x9a, 0xdb, 0xf6, 0x73, 0x84, 0x9e, 0xc0, 0x66, 0x48, 0x64, 0x68,
+	0x6c, 0xd8, 0x9a, 0xdb, 0xe9, 0xd9, 0xb8, 0xc2, 0x2b, 0x7e, 0xc6, 0x63, 0x45, 0x63, 0xf5, 0x92,
+	0xc8, 0xd0, 0x4f, 0xd9, 0xce, 0x2b, 0xd8, 0x5d, 0x53, 0x91, 0x09, 0x8f, 0x25, 0x45, 0x87, 0xd0,
+	0xbe, 0xb0, 0x9c, 0x2a, 0x75, 0x7a, 0x26, 0xce, 0x86, 0xc2, 0xc5, 0x50, 0xf8, 0x75, 0xc1, 0xf0,
+	0x57, 0x64, 0x27, 0x2a, 0xb5, 0x3c, 0x22, 0x2a, 0x08, 0xeb, 0x9c, 0x1f, 0xc2, 0xd6, 0xd2, 0x0b,
+	0x95, 0xc6, 0x86, 0xad, 0x37, 0xf2, 0x9e, 0xf3, 0x9d, 0x2f, 0x1a, 0xec, 0xad, 0x6b, 0xfd, 0xaf,
+	0x7f, 0xd4, 0x07, 0xb8, 0x00, 0x85, 0xa5, 0x7f, 0x1d, 0x2d, 0xb1, 0x9d, 0x04, 0xd0, 0x40, 0xb2,
+	0x93, 0x88, 0xc5, 0xe5, 0x95, 0x19, 0x70, 0x4d, 0x46, 0x2c, 0xa6, 0x42, 0x1a, 0x9a, 0xad, 0xbb,
+	0x6d, 0xbf, 0x80, 0xa8, 0x7f, 0x69, 0x69, 0x77, 0xea, 0x06, 0xc7, 0xc7, 0x82, 0x24, 0xf9, 0xea,
+	0xfa, 0x9b, 0x9f, 0x7f, 0xec, 0xb7, 0x9c, 0x5d, 0xd8, 0xbe, 0xa4, 0x98, 0x8d, 0xef, 0x7c, 0xcb,
+	0x6e, 0xe6, 0x44, 0x71, 0x41, 0x7d, 0x32, 0x6b, 0x12, 0xa0, 0x63, 0xe8, 0x06, 0x99, 0xd4, 0xbb,
+	0x92, 0xa7, 0xdb, 0xb5, 0x9e, 0x7c, 0x32, 0xf3, 0x3b, 0xc1, 0xea, 0x8f, 0xe5, 0xb8, 0x39, 0x34,
+	0x74, 0x5b, 0x73, 0xbb, 0x7e, 0x01, 0x9d, 0x9b, 0x70, 0xe3, 0x2f, 0x53, 0x99, 0xe1, 0xde, 0x77,
+	0x1d, 0xf4, 0x81, 0x64, 0x28, 0x00, 0x58, 0xa5, 0x11, 0xdd, 0xab, 0x54, 0xaf, 0x7a, 0x17, 0xe6,
+	0xfd, 0x26, 0xd4, 0x3c, 0x1c, 0xa7, 0xd0, 0x29, 0x65, 0x06, 0xd5, 0x1c, 0x2d, 0x87, 0xd8, 0x7c,
+	0xd0, 0x88, 0x9b, 0xeb, 0xbc, 0x85, 0xeb, 0xc5, 0x66, 0xd0, 0xdd, 0xab, 0x0e, 0xae, 0xa5, 0xc5,
+	0x74, 0xeb, 0x89, 0x79, 0xfb, 0x08, 0xba, 0xe5, 0xbb, 0x44, 0x57, 0x7a, 0xab, 0x88, 0x81, 0x79,
+	0xd0, 0x8c, 0x9c, 0x49, 0x1d, 0xbd, 0xf8, 0x39, 0xb7, 0xb4, 0xf3, 0xb9, 0xa5, 0xfd, 0x9e, 0x5b,
+	0xda, 0xd7, 0x85, 0xd5, 0x3a, 0x5f, 0x58, 0xad, 0x5f, 0x0b, 0xab, 0xf5, 0xe6, 0x80, 0x45, 0x2a,
+	0x9c, 0x0e, 0x71, 0xc0, 0x27, 0x5e, 0xda, 0xf1, 0x61, 0x4c, 0xd5, 0x8c, 0x8b, 0xf7, 0x39, 0x1a,
+	0xd3, 0x11, 0xa3, 0xc2, 0x3b, 0x4b, 0x3f, 0x75, 0xc3, 0xad, 0xf4, 0x01, 0x3d, 0xfe, 0x33, 0x00,
+	0x81, 0x74, 0xf3, 0xe1, 0x69, 0x05, 0x00, 0x00,
 }
 
 func (m *MsgAnchorDataRequest) Marshal() (dAtA []byte, err error) {
@@ -434,6 +555,99 @@ func (m *MsgAnchorDataResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
+func (m *MsgAnchorBatchRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *MsgAnchorBatchRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *MsgAnchorBatchRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Hashes) > 0 {
+		for iNdEx := len(m.Hashes) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Hashes[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintTx(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	if len(m.Sender) > 0 {
+		i -= len(m.Sender)
+		copy(dAtA[i:], m.Sender)
+		i = encodeVarintTx(dAtA, i, uint64(len(m.Sender)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *MsgAnchorBatchResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *MsgAnchorBatchResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *MsgAnchorBatchResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Timestamps) > 0 {
+		for iNdEx := len(m.Timestamps) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Timestamps[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintTx(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	if m.Timestamp != nil {
+		{
+			size, err := m.Timestamp.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintTx(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
 func (m *MsgSignDataRequest) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
@@ -614,6 +828,44 @@ func (m *MsgAnchorDataResponse) Size() (n int) {
 	return n
 }
 
+func (m *MsgAnchorBatchRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Sender)
+	if l > 0 {
+		n += 1 + l + sovTx(uint64(l))
+	}
+	if len(m.Hashes) > 0 {
+		for _, e := range m.Hashes {
+			l = e.Size()
+			n += 1 + l + sovTx(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *MsgAnchorBatchResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Timestamp != nil {
+		l = m.Timestamp.Size()
+		n += 1 + l + sovTx(uint64(l))
+	}
+	if len(m.Timestamps) > 0 {
+		for _, e := range m.Timestamps {
+			l = e.Size()
+			n += 1 + l + sovTx(uint64(l))
+		}
+	}
+	return n
+}
+
 func (m *MsgSignDataRequest) Size() (n int) {
 	if m == nil {
 		return 0
@@ -888,6 +1140,248 @@ func (m *MsgAnchorDataResponse) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
+func (m *MsgAnchorBatchRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTx
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: MsgAnchorBatchRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: MsgAnchorBatchRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Sender", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTx
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTx
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTx
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Sender = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Hashes", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTx
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTx
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTx
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Hashes = append(m.Hashes, &ContentHash{})
+			if err := m.Hashes[len(m.Hashes)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTx(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthTx
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthTx
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *MsgAnchorBatchResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTx
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: MsgAnchorBatchResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: MsgAnchorBatchResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Timestamp", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTx
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTx
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTx
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Timestamp == nil {
+				m.Timestamp = &types.Timestamp{}
+			}
+			if err := m.Timestamp.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Timestamps", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTx
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTx
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTx
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Timestamps = append(m.Timestamps, &types.Timestamp{})
+			if err := m.Timestamps[len(m.Timestamps)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTx(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthTx
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthTx
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func (m *MsgSignDataRequest) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
@@ -23,7 +23,16 @@ type MsgClient interface {
 	// data. They can simply be a intermediary providing timestamp services.
 	// SignData should be used to create a digital signature attesting to the
 	// veracity of some piece of data.
+	//
+	// AnchorData is idempotent. Anchoring data that was already anchored succeeds
+	// without changes and returns the timestamp of the original anchor, so that
+	// clients can anchor data without checking whether it was anchored before.
 	AnchorData(ctx context.Context, in *MsgAnchorDataRequest, opts ...grpc.CallOption) (*MsgAnchorDataResponse, error)
+	// AnchorBatch anchors multiple pieces of data to the blockchain in one
+	// transaction like AnchorData. All hashes that were not anchored yet are
+	// anchored with the same timestamp and a single EventAnchorBatch is emitted
+	// for them. Hashes that were already anchored keep their original timestamp.
+	AnchorBatch(ctx context.Context, in *MsgAnchorBatchRequest, opts ...grpc.CallOption) (*MsgAnchorBatchResponse, error)
 	// SignData allows for signing of an arbitrary piece of data on the
 	// blockchain. By "signing" data the signers are making a statement about the
 	// veracity of the data itself. It is like signing a legal document, meaning
@@ -58,6 +67,7 @@ type MsgClient interface {
 type msgClient struct {
 	cc            grpc.ClientConnInterface
 	_AnchorData   types.Invoker
+	_AnchorBatch  types.Invoker
 	_SignData     types.Invoker
 	_StoreRawData types.Invoker
 }
@@ -89,6 +99,29 @@ func (c *msgClient) AnchorData(ctx context.Context, in *MsgAnchorDataRequest, op
 	return out, nil
 }
 
+func (c *msgClient) AnchorBatch(ctx context.Context, in *MsgAnchorBatchRequest, opts ...grpc.CallOption) (*MsgAnchorBatchResponse, error) {
+	if invoker := c._AnchorBatch; invoker != nil {
+		var out MsgAnchorBatchResponse
+		err := invoker(ctx, in, &out)
+		return &out, err
+	}
+	if invokerConn, ok := c.cc.(types.InvokerConn); ok {
+		var err error
+		c._AnchorBatch, err = invokerConn.Invoker("/regen.data.v1alpha2.Msg/AnchorBatch")
+		if err != nil {
+			var out MsgAnchorBatchResponse
+			err = c._AnchorBatch(ctx, in, &out)
+			return &out, err
+		}
+	}
+	out := new(MsgAnchorBatchResponse)
+	err := c.cc.Invoke(ctx, "/regen.data.v1alpha2.Msg/AnchorBatch", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *msgClient) SignData(ctx context.Context, in *MsgSignDataRequest, opts ...grpc.CallOption) (*MsgSignDataResponse, error) {
 	if invoker := c._SignData; invoker != nil {
 		var out MsgSignDataResponse
@@ -144,7 +177,16 @@ type MsgServer interface {
 	// data. They can simply be a intermediary providing timestamp services.
 	// SignData should be used to create a digital signature attesting to the
 	// veracity of some piece of data.
+	//
+	// AnchorData is idempotent. Anchoring data that was already anchored succeeds
+	// without changes and returns the timestamp of the original anchor, so that
+	// clients can anchor data without checking whether it was anchored before.
 	AnchorData(types.Context, *MsgAnchorDataRequest) (*MsgAnchorDataResponse, error)
+	// AnchorBatch anchors multiple pieces of data to the blockchain in one
+	// transaction like AnchorData. All hashes that were not anchored yet are
+	// anchored with the same timestamp and a single EventAnchorBatch is emitted
+	// for them. Hashes that were already anchored keep their original timestamp.
+	AnchorBatch(types.Context, *MsgAnchorBatchRequest) (*MsgAnchorBatchResponse, error)
 	// SignData allows for signing of an arbitrary piece of data on the
 	// blockchain. By "signing" data the signers are making a statement about the
 	// veracity of the data itself. It is like signing a legal document, meaning
@@ -198,6 +240,24 @@ func _Msg_AnchorData_Handler(srv interface{}, ctx context.Context, dec func(inte
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Msg_AnchorBatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MsgAnchorBatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MsgServer).AnchorBatch(types.UnwrapSDKContext(ctx), in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/regen.data.v1alpha2.Msg/AnchorBatch",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MsgServer).AnchorBatch(types.UnwrapSDKContext(ctx), req.(*MsgAnchorBatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Msg_SignData_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(MsgSignDataRequest)
 	if err := dec(in); err != nil {
@@ -245,6 +305,10 @@ var Msg_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "AnchorData",
 			Handler:    _Msg_AnchorData_Handler,
 		},
+		{
+			MethodName: "AnchorBatch",
+			Handler:    _Msg_AnchorBatch_Handler,
+		},
 		{
 			MethodName: "SignData",
 			Handler:    _Msg_SignData_Handler,
@@ -259,6 +323,7 @@ var Msg_ServiceDesc = grpc.ServiceDesc{
 
 const (
 	MsgAnchorDataMethod   = "/regen.data.v1alpha2.Msg/AnchorData"
+	MsgAnchorBatchMethod  = "/regen.data.v1alpha2.Msg/AnchorBatch"
 	MsgSignDataMethod     = "/regen.data.v1alpha2.Msg/SignData"
 	MsgStoreRawDataMethod = "/regen.data.v1alpha2.Msg/StoreRawData"
 )
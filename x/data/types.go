@@ -49,11 +49,19 @@ func (x MediaType) Validate() error {
 }
 
 func (x DigestAlgorithm) Validate(hash []byte) error {
+	return x.validate(hash, minDigestAlgorithmBits)
+}
+
+func (x DigestAlgorithm) validate(hash []byte, minBits int) error {
 	nBits, ok := DigestalgorithmLength[x]
 	if !ok {
 		return sdkerrors.Wrap(sdkerrors.ErrUnknownRequest, fmt.Sprintf("invalid or unknown %T %s", x, x))
 	}
 
+	if nBits < minBits {
+		return sdkerrors.Wrap(sdkerrors.ErrUnknownRequest, fmt.Sprintf("%s is weaker than the minimum of %d bits", x, minBits))
+	}
+
 	nBytes := nBits / 8
 	if len(hash) != nBytes {
 		return sdkerrors.Wrap(sdkerrors.ErrUnknownRequest, fmt.Sprintf("expected %d bytes for %s, got %d", nBytes, x, len(hash)))
@@ -64,8 +72,15 @@ func (x DigestAlgorithm) Validate(hash []byte) error {
 
 var DigestalgorithmLength = map[DigestAlgorithm]int{
 	DigestAlgorithm_DIGEST_ALGORITHM_BLAKE2B_256: 256,
+	DigestAlgorithm_DIGEST_ALGORITHM_SHA3_256:    256,
+	DigestAlgorithm_DIGEST_ALGORITHM_SHA2_512:    512,
 }
 
+// minDigestAlgorithmBits is the security threshold for content hashes. Digest algorithms with
+// shorter digests are rejected as weak. It is a constant because it is used in ValidateBasic and
+// must be the same on all nodes.
+const minDigestAlgorithmBits = 256
+
 func (x GraphCanonicalizationAlgorithm) Validate() error {
 	if _, ok := GraphCanonicalizationAlgorithm_name[int32(x)]; !ok {
 		return sdkerrors.Wrap(sdkerrors.ErrUnknownRequest, fmt.Sprintf("unknown %T %d", x, x))
@@ -175,16 +175,24 @@ const (
 	DigestAlgorithm_DIGEST_ALGORITHM_UNSPECIFIED DigestAlgorithm = 0
 	// BLAKE2b-256
 	DigestAlgorithm_DIGEST_ALGORITHM_BLAKE2B_256 DigestAlgorithm = 1
+	// SHA3-256
+	DigestAlgorithm_DIGEST_ALGORITHM_SHA3_256 DigestAlgorithm = 2
+	// SHA2-512
+	DigestAlgorithm_DIGEST_ALGORITHM_SHA2_512 DigestAlgorithm = 3
 )
 
 var DigestAlgorithm_name = map[int32]string{
 	0: "DIGEST_ALGORITHM_UNSPECIFIED",
 	1: "DIGEST_ALGORITHM_BLAKE2B_256",
+	2: "DIGEST_ALGORITHM_SHA3_256",
+	3: "DIGEST_ALGORITHM_SHA2_512",
 }
 
 var DigestAlgorithm_value = map[string]int32{
 	"DIGEST_ALGORITHM_UNSPECIFIED": 0,
 	"DIGEST_ALGORITHM_BLAKE2B_256": 1,
+	"DIGEST_ALGORITHM_SHA3_256":    2,
+	"DIGEST_ALGORITHM_SHA2_512":    3,
 }
 
 func (x DigestAlgorithm) String() string {
@@ -565,55 +573,56 @@ func init() {
 func init() { proto.RegisterFile("regen/data/v1alpha2/types.proto", fileDescriptor_e68eefb44eeab1df) }
 
 var fileDescriptor_e68eefb44eeab1df = []byte{
-	// 754 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xbc, 0x95, 0x41, 0x73, 0xf2, 0x44,
-	0x18, 0xc7, 0x49, 0x81, 0xf7, 0x95, 0xc5, 0x79, 0x59, 0xb7, 0xbe, 0x95, 0xa2, 0x93, 0x56, 0x74,
-	0x3a, 0x0e, 0xd3, 0x86, 0x96, 0x5a, 0xa7, 0x1e, 0x74, 0x26, 0x40, 0x08, 0x69, 0x49, 0xc8, 0x2c,
-	0x69, 0xad, 0xbd, 0x64, 0xb6, 0xb0, 0x86, 0x4c, 0x49, 0xc2, 0x2c, 0x41, 0xac, 0x47, 0x6f, 0xde,
-	0xfc, 0x16, 0x8e, 0xdf, 0xc4, 0x63, 0x8f, 0x1e, 0x9d, 0xd6, 0x0f, 0xe2, 0xb0, 0x40, 0x8b, 0x2b,
-	0x6d, 0x6f, 0xef, 0x6d, 0xf7, 0xd9, 0xdf, 0xff, 0xff, 0xfc, 0x27, 0xfb, 0xec, 0x04, 0x6c, 0x31,
-	0xea, 0xd1, 0xb0, 0xdc, 0x23, 0x31, 0x29, 0xff, 0x78, 0x40, 0x06, 0xc3, 0x3e, 0xa9, 0x94, 0xe3,
-	0x9b, 0x21, 0x1d, 0x29, 0x43, 0x16, 0xc5, 0x11, 0x5a, 0xe7, 0x80, 0x32, 0x05, 0x94, 0x05, 0x50,
-	0xd8, 0xf2, 0xa2, 0xc8, 0x1b, 0xd0, 0x32, 0x47, 0xae, 0xc6, 0x3f, 0x94, 0x63, 0x3f, 0xa0, 0xa3,
-	0x98, 0x04, 0xc3, 0x99, 0xaa, 0x20, 0x8b, 0x40, 0x6f, 0xcc, 0x48, 0xec, 0x47, 0xe1, 0xec, 0xbc,
-	0xf8, 0x4f, 0x0a, 0x64, 0x6b, 0x51, 0x18, 0xd3, 0x30, 0x6e, 0x92, 0x51, 0x1f, 0x1d, 0x83, 0x24,
-	0x23, 0x93, 0xbc, 0xb4, 0x2d, 0x7d, 0x91, 0xad, 0x7c, 0xae, 0xac, 0xe8, 0xa9, 0x2c, 0xe1, 0x0a,
-	0x26, 0x93, 0x66, 0x02, 0x4f, 0x25, 0xe8, 0x5b, 0x90, 0xf6, 0x18, 0x19, 0xf6, 0xf3, 0x6b, 0x5c,
-	0xbb, 0xf3, 0xa2, 0x56, 0x9f, 0xd2, 0xcd, 0x04, 0x9e, 0xc9, 0x0a, 0x7f, 0x48, 0x20, 0x89, 0xc9,
-	0x04, 0x21, 0x90, 0xea, 0x93, 0x51, 0x9f, 0x47, 0x78, 0x1f, 0xf3, 0x35, 0x6a, 0x03, 0xd8, 0xf3,
-	0x3d, 0x3a, 0x8a, 0x5d, 0x32, 0xf0, 0x22, 0xe6, 0xc7, 0xfd, 0x80, 0xb7, 0x79, 0xf3, 0x44, 0xc4,
-	0x3a, 0x87, 0xd5, 0x05, 0x8b, 0x73, 0xbd, 0xff, 0x16, 0xd0, 0x37, 0x00, 0x04, 0xb4, 0xe7, 0x13,
-	0x77, 0xfa, 0x85, 0xf3, 0x49, 0x6e, 0x25, 0xaf, 0xb4, 0x32, 0xa7, 0x98, 0x73, 0x33, 0xa4, 0x38,
-	0x13, 0x2c, 0x96, 0x85, 0xdf, 0xd7, 0x40, 0x9a, 0xc7, 0x7f, 0x37, 0x69, 0x19, 0x28, 0x74, 0x49,
-	0x18, 0x85, 0x7e, 0x97, 0x0c, 0xfc, 0x9f, 0xf9, 0xf5, 0x2d, 0x59, 0xcf, 0xd2, 0x1f, 0xae, 0xb4,
-	0xe6, 0x21, 0x6b, 0x82, 0xf6, 0xb1, 0xd3, 0x66, 0xf7, 0xa9, 0x23, 0xa4, 0x81, 0x6c, 0x40, 0xd9,
-	0xf5, 0x80, 0xba, 0x31, 0xa3, 0x34, 0x9f, 0x7a, 0x26, 0x3f, 0x6f, 0x62, 0x72, 0xd8, 0x61, 0x94,
-	0x62, 0x10, 0x3c, 0xac, 0xab, 0x69, 0x90, 0x1c, 0x8d, 0x83, 0xe2, 0x1e, 0x78, 0x3d, 0xbf, 0x7a,
-	0xf4, 0x31, 0x78, 0x8f, 0x91, 0x89, 0x3b, 0xb5, 0x98, 0x7d, 0xb5, 0x66, 0x02, 0xbf, 0x66, 0x64,
-	0x52, 0x27, 0x31, 0x59, 0xe0, 0x2e, 0xc8, 0x76, 0x7c, 0x2f, 0xa4, 0x4c, 0x0b, 0x63, 0x76, 0x83,
-	0x36, 0xc0, 0xab, 0x11, 0xdf, 0x72, 0x41, 0x06, 0xcf, 0x77, 0xe8, 0x18, 0x64, 0x1e, 0xe6, 0x7d,
-	0x3e, 0x76, 0x05, 0x65, 0x36, 0xf0, 0xca, 0x62, 0xe0, 0x15, 0x67, 0x41, 0xe0, 0x47, 0xb8, 0xf4,
-	0x6b, 0x12, 0x64, 0x1e, 0x6e, 0x16, 0x15, 0xc0, 0x86, 0xa9, 0xd5, 0x0d, 0xd5, 0x75, 0xbe, 0xb7,
-	0x35, 0xf7, 0xcc, 0xea, 0xd8, 0x5a, 0xcd, 0x68, 0x18, 0x5a, 0x1d, 0x26, 0xd0, 0x26, 0x78, 0xbb,
-	0x74, 0xe6, 0x68, 0x17, 0x8e, 0x6b, 0xb7, 0x54, 0xc3, 0x82, 0x12, 0x5a, 0x07, 0xb9, 0xa5, 0xa3,
-	0x93, 0x4e, 0xdb, 0x82, 0x6b, 0x08, 0x81, 0x37, 0x4b, 0xc5, 0x5a, 0xe7, 0x1c, 0x26, 0x85, 0xda,
-	0x85, 0xd9, 0x82, 0x29, 0xa1, 0x66, 0xd7, 0x1b, 0x30, 0x2d, 0x18, 0x3a, 0x46, 0xa3, 0x01, 0xa1,
-	0x00, 0x9e, 0xd8, 0x3a, 0xfc, 0x40, 0x14, 0x5b, 0x3a, 0x44, 0x42, 0xad, 0x73, 0xae, 0xc3, 0x75,
-	0xc1, 0xf0, 0x3b, 0xad, 0x6a, 0xc3, 0x0f, 0x85, 0xa2, 0x7a, 0x6e, 0x34, 0xe0, 0x5b, 0x41, 0xad,
-	0x1b, 0x0d, 0xb8, 0x21, 0x82, 0xd3, 0x36, 0x1f, 0x09, 0x45, 0xd3, 0xd6, 0x74, 0xb8, 0x2d, 0xa8,
-	0x4d, 0xfb, 0x4b, 0xf8, 0xe9, 0xff, 0x7b, 0x9b, 0xb0, 0x28, 0x80, 0x6d, 0x5d, 0x87, 0x9f, 0x95,
-	0x7e, 0x91, 0x80, 0xfc, 0xfc, 0x9c, 0xa2, 0x7d, 0xb0, 0xab, 0x63, 0xd5, 0x6e, 0xba, 0x35, 0xd5,
-	0x6a, 0x5b, 0x46, 0x4d, 0x6d, 0x19, 0x97, 0xaa, 0x63, 0xb4, 0x2d, 0x57, 0x6d, 0xe9, 0x6d, 0x6c,
-	0x38, 0x4d, 0x53, 0xb8, 0x36, 0x05, 0x94, 0x5e, 0x56, 0xe0, 0xba, 0xa5, 0x56, 0xf6, 0x0f, 0x8e,
-	0xa0, 0x54, 0xfa, 0x1a, 0xe4, 0x84, 0x31, 0x46, 0x3b, 0xa0, 0x38, 0xb3, 0x30, 0x35, 0x7c, 0xda,
-	0xd2, 0x5c, 0x07, 0x6b, 0x9a, 0x6b, 0xb5, 0x2d, 0x61, 0x42, 0x4a, 0x67, 0x20, 0x27, 0xbc, 0x60,
-	0xb4, 0x0d, 0x3e, 0xa9, 0x1b, 0xba, 0xd6, 0x71, 0x9e, 0xcc, 0xb7, 0x8a, 0xa8, 0xb6, 0xd4, 0x53,
-	0xad, 0x52, 0x75, 0x2b, 0x47, 0x5f, 0x41, 0xa9, 0xda, 0xf8, 0xf3, 0x4e, 0x96, 0x6e, 0xef, 0x64,
-	0xe9, 0xef, 0x3b, 0x59, 0xfa, 0xed, 0x5e, 0x4e, 0xdc, 0xde, 0xcb, 0x89, 0xbf, 0xee, 0xe5, 0xc4,
-	0xe5, 0xae, 0xe7, 0xc7, 0xfd, 0xf1, 0x95, 0xd2, 0x8d, 0x82, 0x32, 0x7f, 0x8f, 0x7b, 0x21, 0x8d,
-	0x27, 0x11, 0xbb, 0x9e, 0xef, 0x06, 0xb4, 0xe7, 0x51, 0x56, 0xfe, 0x89, 0xff, 0x4c, 0xae, 0x5e,
-	0xf1, 0x97, 0x70, 0xf8, 0x6f, 0x00, 0x00, 0x00, 0xff, 0xff, 0x65, 0x79, 0xe8, 0x04, 0x61, 0x06,
-	0x00, 0x00,
+	// 774 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xbc, 0x95, 0x41, 0x8f, 0xdb, 0x44,
+	0x14, 0xc7, 0xe3, 0xcd, 0x6e, 0x4b, 0x5e, 0x50, 0x33, 0xcc, 0xd2, 0x25, 0x1b, 0xc0, 0x5d, 0x02,
+	0xaa, 0x50, 0xd4, 0x3a, 0xdd, 0x2c, 0x8b, 0xca, 0x01, 0x24, 0x27, 0x71, 0x1c, 0xb7, 0xb1, 0x63,
+	0x4d, 0xcc, 0x52, 0x7a, 0xb1, 0x66, 0x93, 0xc1, 0xb1, 0x1a, 0xdb, 0xd1, 0xc4, 0x21, 0x2c, 0x47,
+	0x6e, 0xdc, 0x90, 0xf8, 0x10, 0x88, 0x6f, 0xc2, 0xb1, 0x47, 0x8e, 0x68, 0x97, 0x0f, 0x82, 0x32,
+	0x49, 0xb6, 0x61, 0x9a, 0x74, 0x6f, 0xbd, 0xcd, 0xbc, 0xf7, 0xfb, 0xbf, 0xf7, 0x97, 0xdf, 0x1b,
+	0x19, 0xee, 0x71, 0x16, 0xb0, 0xb8, 0x3a, 0xa0, 0x29, 0xad, 0xfe, 0x78, 0x4c, 0x47, 0xe3, 0x21,
+	0xad, 0x55, 0xd3, 0x8b, 0x31, 0x9b, 0x68, 0x63, 0x9e, 0xa4, 0x09, 0xde, 0x17, 0x80, 0x36, 0x07,
+	0xb4, 0x15, 0x50, 0xba, 0x17, 0x24, 0x49, 0x30, 0x62, 0x55, 0x81, 0x9c, 0x4f, 0x7f, 0xa8, 0xa6,
+	0x61, 0xc4, 0x26, 0x29, 0x8d, 0xc6, 0x0b, 0x55, 0x49, 0x95, 0x81, 0xc1, 0x94, 0xd3, 0x34, 0x4c,
+	0xe2, 0x45, 0xbe, 0xfc, 0xef, 0x2e, 0xe4, 0x1b, 0x49, 0x9c, 0xb2, 0x38, 0x6d, 0xd3, 0xc9, 0x10,
+	0x3f, 0x86, 0x2c, 0xa7, 0xb3, 0xa2, 0x72, 0xa4, 0x7c, 0x9e, 0xaf, 0x7d, 0xa6, 0x6d, 0xe8, 0xa9,
+	0xad, 0xe1, 0x1a, 0xa1, 0xb3, 0x76, 0x86, 0xcc, 0x25, 0xf8, 0x1b, 0xd8, 0x0b, 0x38, 0x1d, 0x0f,
+	0x8b, 0x3b, 0x42, 0x7b, 0xff, 0x46, 0xad, 0x39, 0xa7, 0xdb, 0x19, 0xb2, 0x90, 0x95, 0xfe, 0x54,
+	0x20, 0x4b, 0xe8, 0x0c, 0x63, 0xd8, 0x1d, 0xd2, 0xc9, 0x50, 0x58, 0x78, 0x97, 0x88, 0x33, 0xee,
+	0x02, 0x1a, 0x84, 0x01, 0x9b, 0xa4, 0x3e, 0x1d, 0x05, 0x09, 0x0f, 0xd3, 0x61, 0x24, 0xda, 0xdc,
+	0xd9, 0x62, 0xb1, 0x29, 0x60, 0x7d, 0xc5, 0x92, 0xc2, 0xe0, 0xff, 0x01, 0xfc, 0x35, 0x40, 0xc4,
+	0x06, 0x21, 0xf5, 0xe7, 0x5f, 0xb8, 0x98, 0x15, 0xa5, 0xd4, 0x8d, 0xa5, 0xec, 0x39, 0xe6, 0x5d,
+	0x8c, 0x19, 0xc9, 0x45, 0xab, 0x63, 0xe9, 0x8f, 0x1d, 0xd8, 0x13, 0xf6, 0xdf, 0x8e, 0x5b, 0x0e,
+	0xa5, 0x3e, 0x8d, 0x93, 0x38, 0xec, 0xd3, 0x51, 0xf8, 0xb3, 0x18, 0xdf, 0x5a, 0xe9, 0x85, 0xfb,
+	0x93, 0x8d, 0xa5, 0x85, 0xc9, 0x86, 0xa4, 0x7d, 0xd5, 0xe9, 0xb0, 0xbf, 0x2d, 0x85, 0x0d, 0xc8,
+	0x47, 0x8c, 0xbf, 0x18, 0x31, 0x3f, 0xe5, 0x8c, 0x15, 0x77, 0xdf, 0xe0, 0x5f, 0x34, 0xb1, 0x05,
+	0xec, 0x71, 0xc6, 0x08, 0x44, 0xd7, 0xe7, 0xfa, 0x1e, 0x64, 0x27, 0xd3, 0xa8, 0xfc, 0x10, 0x6e,
+	0x2f, 0x47, 0x8f, 0x3f, 0x84, 0x77, 0x38, 0x9d, 0xf9, 0xf3, 0x12, 0x8b, 0xaf, 0xd6, 0xce, 0x90,
+	0xdb, 0x9c, 0xce, 0x9a, 0x34, 0xa5, 0x2b, 0xdc, 0x87, 0x7c, 0x2f, 0x0c, 0x62, 0xc6, 0x8d, 0x38,
+	0xe5, 0x17, 0xf8, 0x00, 0x6e, 0x4d, 0xc4, 0x55, 0x08, 0x72, 0x64, 0x79, 0xc3, 0x8f, 0x21, 0x77,
+	0xbd, 0xef, 0xcb, 0xb5, 0x2b, 0x69, 0x8b, 0x85, 0xd7, 0x56, 0x0b, 0xaf, 0x79, 0x2b, 0x82, 0xbc,
+	0x82, 0x2b, 0xbf, 0x66, 0x21, 0x77, 0x3d, 0x59, 0x5c, 0x82, 0x03, 0xdb, 0x68, 0x5a, 0xba, 0xef,
+	0x7d, 0xef, 0x1a, 0xfe, 0xb7, 0x4e, 0xcf, 0x35, 0x1a, 0x56, 0xcb, 0x32, 0x9a, 0x28, 0x83, 0x0f,
+	0xe1, 0xee, 0x5a, 0xce, 0x33, 0x9e, 0x79, 0xbe, 0xdb, 0xd1, 0x2d, 0x07, 0x29, 0x78, 0x1f, 0x0a,
+	0x6b, 0xa9, 0x27, 0xbd, 0xae, 0x83, 0x76, 0x30, 0x86, 0x3b, 0x6b, 0xc1, 0x46, 0xef, 0x0c, 0x65,
+	0xa5, 0xd8, 0x33, 0xbb, 0x83, 0x76, 0xa5, 0x98, 0xdb, 0x6c, 0xa1, 0x3d, 0xa9, 0xa0, 0x67, 0xb5,
+	0x5a, 0x08, 0x49, 0xe0, 0x13, 0xd7, 0x44, 0xef, 0xc9, 0x62, 0xc7, 0x44, 0x58, 0x8a, 0xf5, 0xce,
+	0x4c, 0xb4, 0x2f, 0x15, 0xfc, 0xce, 0xa8, 0xbb, 0xe8, 0x7d, 0x29, 0xa8, 0x9f, 0x59, 0x2d, 0x74,
+	0x57, 0x52, 0x9b, 0x56, 0x0b, 0x1d, 0xc8, 0xe0, 0xbc, 0xcd, 0x07, 0x52, 0xd0, 0x76, 0x0d, 0x13,
+	0x1d, 0x49, 0x6a, 0xdb, 0xfd, 0x02, 0x7d, 0xf2, 0x7a, 0x6f, 0x1b, 0x95, 0x25, 0xb0, 0x6b, 0x9a,
+	0xe8, 0xd3, 0xca, 0x2f, 0x0a, 0xa8, 0x6f, 0xde, 0x53, 0xfc, 0x08, 0x1e, 0x98, 0x44, 0x77, 0xdb,
+	0x7e, 0x43, 0x77, 0xba, 0x8e, 0xd5, 0xd0, 0x3b, 0xd6, 0x73, 0xdd, 0xb3, 0xba, 0x8e, 0xaf, 0x77,
+	0xcc, 0x2e, 0xb1, 0xbc, 0xb6, 0x2d, 0x8d, 0x4d, 0x83, 0xca, 0xcd, 0x0a, 0xd2, 0x74, 0xf4, 0xda,
+	0xa3, 0xe3, 0x53, 0xa4, 0x54, 0xbe, 0x82, 0x82, 0xb4, 0xc6, 0xf8, 0x3e, 0x94, 0x17, 0x25, 0x6c,
+	0x83, 0x3c, 0xed, 0x18, 0xbe, 0x47, 0x0c, 0xc3, 0x77, 0xba, 0x8e, 0xb4, 0x21, 0x95, 0xdf, 0x15,
+	0x28, 0x48, 0x4f, 0x18, 0x1f, 0xc1, 0x47, 0x4d, 0xcb, 0x34, 0x7a, 0xde, 0x56, 0x83, 0x9b, 0x88,
+	0x7a, 0x47, 0x7f, 0x6a, 0xd4, 0xea, 0x7e, 0xed, 0xf4, 0x4b, 0xa4, 0xe0, 0x8f, 0xe1, 0xf0, 0x35,
+	0xa2, 0xd7, 0xd6, 0x4f, 0x44, 0x7a, 0x67, 0x5b, 0xba, 0xe6, 0x9f, 0x1e, 0xd7, 0x50, 0xb6, 0xde,
+	0xfa, 0xeb, 0x52, 0x55, 0x5e, 0x5e, 0xaa, 0xca, 0x3f, 0x97, 0xaa, 0xf2, 0xdb, 0x95, 0x9a, 0x79,
+	0x79, 0xa5, 0x66, 0xfe, 0xbe, 0x52, 0x33, 0xcf, 0x1f, 0x04, 0x61, 0x3a, 0x9c, 0x9e, 0x6b, 0xfd,
+	0x24, 0xaa, 0x8a, 0xe7, 0xfc, 0x30, 0x66, 0xe9, 0x2c, 0xe1, 0x2f, 0x96, 0xb7, 0x11, 0x1b, 0x04,
+	0x8c, 0x57, 0x7f, 0x12, 0xff, 0xa2, 0xf3, 0x5b, 0xe2, 0x21, 0x9d, 0xfc, 0x37, 0x00, 0x84, 0xb6,
+	0x21, 0x1b, 0xa0, 0x06, 0x00, 0x00,
 }
 
 func (m *ContentHash) Marshal() (dAtA []byte, err error) {
@@ -196,6 +196,24 @@ func TestDigestAlgorithm_Validate(t *testing.T) {
 			make([]byte, 31),
 			"expected 32 bytes for DIGEST_ALGORITHM_BLAKE2B_256, got 31: unknown request",
 		},
+		{
+			"sha3-256",
+			DigestAlgorithm_DIGEST_ALGORITHM_SHA3_256,
+			make([]byte, 32),
+			"",
+		},
+		{
+			"sha2-512",
+			DigestAlgorithm_DIGEST_ALGORITHM_SHA2_512,
+			make([]byte, 64),
+			"",
+		},
+		{
+			"sha2-512 wrong len",
+			DigestAlgorithm_DIGEST_ALGORITHM_SHA2_512,
+			make([]byte, 32),
+			"expected 64 bytes for DIGEST_ALGORITHM_SHA2_512, got 32: unknown request",
+		},
 		{
 			"unspecified",
 			DigestAlgorithm_DIGEST_ALGORITHM_UNSPECIFIED,
@@ -221,6 +239,14 @@ func TestDigestAlgorithm_Validate(t *testing.T) {
 	}
 }
 
+func TestDigestAlgorithm_ValidateMinBits(t *testing.T) {
+	err := DigestAlgorithm_DIGEST_ALGORITHM_SHA3_256.validate(make([]byte, 32), 512)
+	require.EqualError(t, err, "DIGEST_ALGORITHM_SHA3_256 is weaker than the minimum of 512 bits: unknown request")
+
+	err = DigestAlgorithm_DIGEST_ALGORITHM_SHA2_512.validate(make([]byte, 64), 512)
+	require.NoError(t, err)
+}
+
 func TestGraphCanonicalizationAlgorithm_Validate(t *testing.T) {
 	tests := []struct {
 		name    string